@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/spf13/cobra"
+
+	"github.com/serpro69/gh-arc/internal/amend"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+var amendShow bool
+
+var amendCmd = &cobra.Command{
+	Use:   "amend",
+	Short: "Rewrite the HEAD commit message from the PR's reviewed metadata",
+	Long: `Rebuild the HEAD commit message from the pull request of the current branch.
+
+After review, the title, summary, test plan and approvals live on GitHub.
+This command brings them back into the commit:
+
+  <PR title>
+
+  Summary:
+  <summary>
+
+  Test Plan:
+  <test plan>
+
+  Ref: <linear issues>
+
+  Reviewed-by: <login> <login@users.noreply.github.com>
+  Differential-PR: <PR URL>
+
+A Reviewed-by trailer is added for every reviewer whose latest review is an
+approval. Only the message is changed; staged changes are not committed.
+
+If HEAD was already pushed, amending it diverges from the PR branch and it
+has to be force-pushed afterwards.
+
+Examples:
+  # Amend HEAD from the PR of the current branch
+  gh arc amend
+
+  # Preview the message without amending
+  gh arc amend --show`,
+	RunE: runAmend,
+}
+
+func init() {
+	rootCmd.AddCommand(amendCmd)
+
+	amendCmd.Flags().BoolVar(&amendShow, "show", false, "Print the rebuilt commit message without amending")
+}
+
+func runAmend(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	logger.Debug().
+		Bool("show", amendShow).
+		Msg("Starting amend command")
+
+	currentRepo, err := repository.Current()
+	if err != nil {
+		return fmt.Errorf("failed to determine current repository: %w", err)
+	}
+
+	gitRepo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	client, err := newGitHubClient()
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	workflow := amend.NewAmendWorkflow(gitRepo, client, currentRepo.Owner, currentRepo.Name)
+
+	result, err := workflow.Execute(ctx, &amend.AmendOptions{Show: amendShow})
+	if err != nil {
+		if errors.Is(err, amend.ErrNoPR) {
+			fmt.Fprintln(os.Stderr, "✗ No pull request found for the current branch")
+			fmt.Fprintln(os.Stderr, "  Create one with 'gh arc diff'")
+			return ErrSilentExit
+		}
+		return fmt.Errorf("amend failed: %w", err)
+	}
+
+	if GetJSON() {
+		data, err := amend.FormatJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	amend.PrintResult(os.Stdout, result, amendShow)
+	return nil
+}
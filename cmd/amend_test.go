@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestAmendCommand(t *testing.T) {
+	t.Run("command initialization", func(t *testing.T) {
+		if amendCmd.Use != "amend" {
+			t.Errorf("Expected Use to be 'amend', got '%s'", amendCmd.Use)
+		}
+
+		if amendCmd.Short == "" {
+			t.Error("Expected Short description to be set")
+		}
+
+		if amendCmd.RunE == nil {
+			t.Error("Expected RunE to be set")
+		}
+	})
+
+	t.Run("amend command is registered", func(t *testing.T) {
+		found := false
+		for _, cmd := range rootCmd.Commands() {
+			if cmd.Name() == "amend" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected amend command to be registered with root command")
+		}
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		if amendCmd.Flags().Lookup("show") == nil {
+			t.Error("Expected --show flag to be defined")
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/spf13/cobra"
+
+	"github.com/serpro69/gh-arc/internal/branch"
+	"github.com/serpro69/gh-arc/internal/format"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+var (
+	branchCleanup bool
+	branchDryRun  bool
+)
+
+var branchCmd = &cobra.Command{
+	Use:   "branch",
+	Short: "List local branches with their pull request status",
+	Args:  cobra.NoArgs,
+	Long: `List local branches joined with their open or merged pull requests.
+
+For each local branch shows:
+  - The PR number and state (open, draft, merged)
+  - The stack parent, when the PR targets a branch other than the default
+  - Commits ahead/behind the default branch (origin/<default> when available)
+  - Review status and CI status of open PRs
+
+All PRs are looked up in a single batched GraphQL query rather than one
+request per branch. PRs from forks and closed-unmerged PRs are ignored.
+
+Cleanup:
+  With --cleanup, local branches whose PRs are merged are deleted. A branch
+  is kept if it is currently checked out, or if its tip is not contained in
+  the merged PR head (i.e. it has commits that were never pushed/merged).
+
+Examples:
+  # List local branches with PR status
+  gh arc branch
+
+  # Show which merged branches would be deleted
+  gh arc branch --cleanup --dry-run
+
+  # Delete branches whose PRs are merged
+  gh arc branch --cleanup
+
+  # Machine-readable output
+  gh arc branch --json`,
+	RunE: runBranch,
+}
+
+func init() {
+	rootCmd.AddCommand(branchCmd)
+
+	branchCmd.Flags().BoolVar(&branchCleanup, "cleanup", false, "Delete local branches whose PRs are merged")
+	branchCmd.Flags().BoolVar(&branchDryRun, "dry-run", false, "With --cleanup, show what would be deleted without deleting")
+}
+
+func runBranch(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	logger.Debug().
+		Bool("cleanup", branchCleanup).
+		Bool("dryRun", branchDryRun).
+		Msg("Starting branch command")
+
+	if branchDryRun && !branchCleanup {
+		return fmt.Errorf("--dry-run can only be used with --cleanup")
+	}
+
+	currentRepo, err := repository.Current()
+	if err != nil {
+		return fmt.Errorf("failed to determine current repository: %w", err)
+	}
+
+	gitRepo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	client, err := newGitHubClient()
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	workflow := branch.NewBranchWorkflow(gitRepo, client, currentRepo.Owner, currentRepo.Name)
+
+	result, err := workflow.Execute(ctx, &branch.BranchOptions{
+		Cleanup: branchCleanup,
+		DryRun:  branchDryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("branch failed: %w", err)
+	}
+
+	if GetJSON() {
+		data, err := branch.FormatJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	branch.PrintResult(os.Stdout, result, format.DefaultPRFormatterOptions().UseColor)
+	return nil
+}
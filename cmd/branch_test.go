@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestBranchCommand(t *testing.T) {
+	t.Run("command initialization", func(t *testing.T) {
+		if branchCmd.Use != "branch" {
+			t.Errorf("Expected Use to be 'branch', got '%s'", branchCmd.Use)
+		}
+
+		if branchCmd.Short == "" {
+			t.Error("Expected Short description to be set")
+		}
+
+		if branchCmd.RunE == nil {
+			t.Error("Expected RunE to be set")
+		}
+	})
+
+	t.Run("branch command is registered", func(t *testing.T) {
+		found := false
+		for _, cmd := range rootCmd.Commands() {
+			if cmd.Name() == "branch" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected branch command to be registered with root command")
+		}
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		for _, name := range []string{"cleanup", "dry-run"} {
+			if branchCmd.Flags().Lookup(name) == nil {
+				t.Errorf("Expected --%s flag to be defined", name)
+			}
+		}
+
+		if err := branchCmd.Args(branchCmd, []string{"extra"}); err == nil {
+			t.Error("Expected error for positional arguments")
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+// CacheStatus describes the response cache of the GitHub client
+type CacheStatus struct {
+	Dir       string  `json:"dir,omitempty"`
+	Entries   int     `json:"entries"`
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	Evictions int64   `json:"evictions"`
+	HitRate   float64 `json:"hit_rate"`
+	Cleared   bool    `json:"cleared,omitempty"`
+}
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the GitHub response cache",
+	Long: `Inspect or clear the cache of GitHub API responses.
+
+gh-arc keeps GitHub responses and their ETags on disk, per host and user, so
+later invocations can send conditional requests. A response that has not
+changed costs no rate limit. Writes such as creating, updating or merging a
+pull request invalidate the responses they make stale automatically.
+
+Examples:
+  # Show where the cache lives and how many responses it holds
+  gh arc cache stats
+
+  # Drop every cached response and the cached PR list of gh arc list
+  gh arc cache clear`,
+}
+
+// cacheStatsCmd represents the cache stats command
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show response cache statistics",
+	Long: `Show where the response cache lives and how many responses it holds.
+
+Evictions count the entries removed because they were too old or the cache
+grew beyond its size limit. Hits and misses cover the current invocation.
+
+Use --json flag for machine-readable JSON output.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Debug().Msg("Reading cache statistics")
+
+		client, err := newGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		defer client.Close()
+
+		return outputCacheStatus(newCacheStatus(client.CacheDir(), client.CacheStats()))
+	},
+}
+
+// cacheClearCmd represents the cache clear command
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached responses",
+	Long: `Remove all cached GitHub responses of the current host and user, and
+the PR list cached by gh arc list. The next commands fetch everything
+from GitHub again.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger.Debug().Msg("Clearing cache")
+
+		client, err := newGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		defer client.Close()
+
+		status := newCacheStatus(client.CacheDir(), client.CacheStats())
+		client.ClearCache()
+		status.Cleared = true
+
+		return outputCacheStatus(status)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
+
+// newCacheStatus builds the status of the cache in dir
+func newCacheStatus(dir string, stats github.CacheStats) CacheStatus {
+	return CacheStatus{
+		Dir:       dir,
+		Entries:   stats.Size,
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+		Evictions: stats.Evictions,
+		HitRate:   stats.HitRate,
+	}
+}
+
+// outputCacheStatus outputs the cache status based on the JSON flag
+func outputCacheStatus(status CacheStatus) error {
+	if GetJSON() {
+		output, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	location := status.Dir
+	if location == "" {
+		location = "memory only"
+	}
+
+	if status.Cleared {
+		fmt.Printf("✓ Cleared %d cached responses\n", status.Entries)
+		fmt.Printf("  Location: %s\n", location)
+		return nil
+	}
+
+	fmt.Printf("Location:  %s\n", location)
+	fmt.Printf("Entries:   %d\n", status.Entries)
+	fmt.Printf("Evictions: %d\n", status.Evictions)
+	if status.Hits+status.Misses > 0 {
+		fmt.Printf("Hit rate:  %.1f%% (%d hits, %d misses)\n", status.HitRate, status.Hits, status.Misses)
+	}
+	return nil
+}
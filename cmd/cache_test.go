@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+func TestCacheCommand(t *testing.T) {
+	t.Run("cache command is registered", func(t *testing.T) {
+		found := false
+		for _, cmd := range rootCmd.Commands() {
+			if cmd.Name() == "cache" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected cache command to be registered with root command")
+		}
+	})
+
+	t.Run("subcommands", func(t *testing.T) {
+		for _, name := range []string{"stats", "clear"} {
+			found := false
+			for _, cmd := range cacheCmd.Commands() {
+				if cmd.Name() == name && cmd.RunE != nil {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				t.Errorf("Expected %s subcommand to be registered with cache command", name)
+			}
+		}
+	})
+
+	t.Run("status from stats", func(t *testing.T) {
+		status := newCacheStatus("/tmp/cache", github.CacheStats{Size: 3, Hits: 1, Misses: 1, Evictions: 2, HitRate: 50})
+
+		if status.Dir != "/tmp/cache" || status.Entries != 3 || status.Evictions != 2 || status.HitRate != 50 {
+			t.Errorf("unexpected status: %+v", status)
+		}
+		if status.Cleared {
+			t.Error("Expected status not to be cleared")
+		}
+	})
+}
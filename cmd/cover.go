@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/serpro69/gh-arc/internal/codeowners"
+	"github.com/serpro69/gh-arc/internal/cover"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+var (
+	coverSince      string
+	coverMaxCommits int
+	coverLimit      int
+)
+
+var coverCmd = &cobra.Command{
+	Use:   "cover [base]",
+	Short: "Suggest reviewers for the current change set",
+	Args:  cobra.MaximumNArgs(1),
+	Long: `Suggest reviewers for the files changed on the current branch.
+
+Candidates come from two sources:
+  - CODEOWNERS: the owners of each touched path
+  - History: the most frequent recent committers of the touched paths,
+    read from local git history on the base branch
+
+Each candidate is ranked by score (3 points per owned file, 1 point per
+recent commit) and listed with the reasons it was suggested. Committers
+using a GitHub noreply address are matched to their login and merged with
+their CODEOWNERS entries. You are excluded from the suggestions.
+
+Changed files are computed against the merge-base with base, which defaults
+to the repository's default branch.
+
+Examples:
+  # Suggest reviewers for changes against the default branch
+  gh arc cover
+
+  # Suggest reviewers for a stacked branch
+  gh arc cover feature/parent
+
+  # Consider only the last month of history
+  gh arc cover --since "1 month ago"
+
+  # Machine-readable output
+  gh arc cover --json`,
+	RunE: runCover,
+}
+
+func init() {
+	rootCmd.AddCommand(coverCmd)
+
+	coverCmd.Flags().StringVar(&coverSince, "since", "6 months ago", "Only consider commits newer than this (git log --since format)")
+	coverCmd.Flags().IntVar(&coverMaxCommits, "max-commits", 500, "Maximum number of history commits to inspect (0 for no limit)")
+	coverCmd.Flags().IntVar(&coverLimit, "limit", 10, "Maximum number of reviewers to suggest (0 for no limit)")
+}
+
+func runCover(cmd *cobra.Command, args []string) error {
+	var base string
+	if len(args) > 0 {
+		base = args[0]
+	}
+
+	logger.Debug().
+		Str("base", base).
+		Str("since", coverSince).
+		Int("maxCommits", coverMaxCommits).
+		Int("limit", coverLimit).
+		Msg("Starting cover command")
+
+	gitRepo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	co, err := codeowners.ParseCodeowners(gitRepo.Path())
+	if err != nil {
+		return fmt.Errorf("failed to parse CODEOWNERS: %w", err)
+	}
+	var owners cover.OwnersResolver
+	if co.Path != "" {
+		owners = co
+	}
+
+	workflow := cover.NewCoverWorkflow(gitRepo, owners)
+
+	result, err := workflow.Execute(&cover.CoverOptions{
+		Base:         base,
+		Since:        coverSince,
+		MaxCommits:   coverMaxCommits,
+		Limit:        coverLimit,
+		CurrentUser:  currentUserLogin(),
+		CurrentEmail: currentUserEmail(gitRepo),
+	})
+	if err != nil {
+		if errors.Is(err, cover.ErrNoChanges) {
+			fmt.Println("No changed files, nothing to review")
+			return nil
+		}
+		return fmt.Errorf("cover failed: %w", err)
+	}
+
+	if GetJSON() {
+		data, err := cover.FormatJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	cover.PrintResult(os.Stdout, result)
+	return nil
+}
+
+// currentUserLogin returns the authenticated GitHub login, or an empty string
+// if it can't be determined. Cover works offline, so this is best-effort.
+func currentUserLogin() string {
+	client, err := newGitHubClient()
+	if err != nil {
+		logger.Debug().Err(err).Msg("GitHub client unavailable, not excluding current user by login")
+		return ""
+	}
+
+	login, err := client.GetCurrentUser(context.Background())
+	if err != nil {
+		logger.Debug().Err(err).Msg("Failed to get current user, not excluding current user by login")
+		return ""
+	}
+	return login
+}
+
+// currentUserEmail returns the configured git user.email, or an empty string.
+func currentUserEmail(repo *git.Repository) string {
+	email, err := repo.GetGitConfig("user.email")
+	if err != nil {
+		logger.Debug().Err(err).Msg("Failed to read user.email")
+		return ""
+	}
+	return email
+}
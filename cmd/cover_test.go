@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestCoverCommand(t *testing.T) {
+	t.Run("command initialization", func(t *testing.T) {
+		if coverCmd.Use != "cover [base]" {
+			t.Errorf("Expected Use to be 'cover [base]', got '%s'", coverCmd.Use)
+		}
+
+		if coverCmd.Short == "" {
+			t.Error("Expected Short description to be set")
+		}
+
+		if coverCmd.RunE == nil {
+			t.Error("Expected RunE to be set")
+		}
+	})
+
+	t.Run("cover command is registered", func(t *testing.T) {
+		found := false
+		for _, cmd := range rootCmd.Commands() {
+			if cmd.Name() == "cover" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected cover command to be registered with root command")
+		}
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		for _, name := range []string{"since", "max-commits", "limit"} {
+			if coverCmd.Flags().Lookup(name) == nil {
+				t.Errorf("Expected --%s flag to be defined", name)
+			}
+		}
+
+		if err := coverCmd.Args(coverCmd, []string{"main", "extra"}); err == nil {
+			t.Error("Expected error for more than one argument")
+		}
+	})
+}
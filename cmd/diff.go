@@ -11,19 +11,19 @@ import (
 	"github.com/serpro69/gh-arc/internal/config"
 	"github.com/serpro69/gh-arc/internal/diff"
 	"github.com/serpro69/gh-arc/internal/git"
-	"github.com/serpro69/gh-arc/internal/github"
 	"github.com/serpro69/gh-arc/internal/logger"
 	"github.com/serpro69/gh-arc/internal/template"
 )
 
 var (
 	// diffCmd flags
-	diffDraft    bool
-	diffReady    bool
-	diffEdit     bool
-	diffNoEdit   bool
-	diffContinue bool
-	diffBase     string
+	diffDraft      bool
+	diffReady      bool
+	diffEdit       bool
+	diffNoEdit     bool
+	diffContinue   bool
+	diffBase       string
+	diffSkipChecks bool
 )
 
 // diffCmd represents the diff command
@@ -81,6 +81,9 @@ Examples:
   # Retry editing after validation error
   gh arc diff --continue
 
+  # Push without running lint and unit checks
+  gh arc diff --skip-checks
+
 Stacking Examples:
   # Create base PR
   git checkout -b feature/auth
@@ -106,6 +109,7 @@ func init() {
 	diffCmd.Flags().BoolVar(&diffNoEdit, "no-edit", false, "Skip editor and accept pre-filled template as-is (only for new PRs or with --edit)")
 	diffCmd.Flags().BoolVar(&diffContinue, "continue", false, "Retry template editing after validation failure")
 	diffCmd.Flags().StringVar(&diffBase, "base", "", "Override detected base branch for stacking (e.g., --base=main)")
+	diffCmd.Flags().BoolVar(&diffSkipChecks, "skip-checks", false, "Skip pre-submit lint and unit checks (when diff.runChecks is enabled)")
 
 	// Mark mutually exclusive flags
 	diffCmd.MarkFlagsMutuallyExclusive("draft", "ready")
@@ -125,6 +129,7 @@ func runDiff(cmd *cobra.Command, args []string) error {
 		Bool("no-edit", diffNoEdit).
 		Bool("continue", diffContinue).
 		Str("base", diffBase).
+		Bool("skip-checks", diffSkipChecks).
 		Msg("Starting diff command")
 
 	// Load configuration
@@ -151,7 +156,7 @@ func runDiff(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create GitHub client
-	client, err := github.NewClient()
+	client, err := newGitHubClient()
 	if err != nil {
 		return fmt.Errorf("failed to create GitHub client: %w", err)
 	}
@@ -165,12 +170,13 @@ func runDiff(cmd *cobra.Command, args []string) error {
 
 	// Execute workflow
 	result, err := workflow.Execute(ctx, &diff.DiffOptions{
-		Draft:    diffDraft,
-		Ready:    diffReady,
-		Edit:     diffEdit,
-		NoEdit:   diffNoEdit,
-		Continue: diffContinue,
-		Base:     diffBase,
+		Draft:      diffDraft,
+		Ready:      diffReady,
+		Edit:       diffEdit,
+		NoEdit:     diffNoEdit,
+		Continue:   diffContinue,
+		Base:       diffBase,
+		SkipChecks: diffSkipChecks,
 	})
 	if err != nil {
 		// Check for specific error types to provide better error messages
@@ -198,6 +204,13 @@ func runDiff(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 
+		if errors.Is(err, diff.ErrChecksFailed) {
+			fmt.Println("\n✗ " + err.Error())
+			fmt.Println("Nothing was pushed. Fix the issues above, or push anyway with:")
+			fmt.Printf("  gh arc diff --skip-checks\n")
+			return ErrSilentExit
+		}
+
 		if errors.Is(err, template.ErrEditorCancelled) {
 			fmt.Println("✗ Editor cancelled, no changes made")
 			return nil
@@ -218,5 +231,9 @@ func runDiff(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	fmt.Println(diff.FormatDiffResult(result, output))
 
+	if result.PR != nil {
+		updateStackLinks(ctx, client, repo.Owner, repo.Name, result.PR.Head.Ref)
+	}
+
 	return nil
 }
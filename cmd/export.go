@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/spf13/cobra"
+
+	"github.com/serpro69/gh-arc/internal/export"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+var (
+	exportDiff      bool
+	exportStdout    bool
+	exportOutputDir string
+	exportNoCover   bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [pr|range]",
+	Short: "Write a PR or commit range as a patch series or diff",
+	Args:  cobra.MaximumNArgs(1),
+	Long: `Export a pull request or a local commit range for transfer to another
+repository, e.g. to send changes to an air-gapped partner.
+
+The argument may be:
+  - A PR number (123 or #123) or URL: the PR head is fetched from origin
+  - A range (base..head, base.. for base..HEAD)
+  - Nothing: the current branch against the default branch
+
+By default a git format-patch compatible mailbox series is produced, one
+message per commit (oldest first, merges skipped) preceded by a cover letter
+holding the PR title and summary, or a title and summary derived from the
+commits for local ranges. With --diff a single unified diff is produced
+instead.
+
+Output goes to files in the current directory (0000-cover-letter.patch,
+0001-<subject>.patch, ...), to another directory with -o, or to stdout as
+one mailbox stream with --stdout.
+
+Use 'gh arc import' to apply an exported series, or plain 'git am' (skip
+the cover letter).
+
+Examples:
+  # Export PR #123 as a patch series into ./outgoing
+  gh arc export 123 -o outgoing
+
+  # Export the current branch as one mailbox file
+  gh arc export --stdout > feature.mbox
+
+  # Export a range as a single diff
+  gh arc export main..feature --diff --stdout > feature.diff`,
+	RunE: runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().BoolVar(&exportDiff, "diff", false, "Write a single unified diff instead of a patch series")
+	exportCmd.Flags().BoolVar(&exportStdout, "stdout", false, "Write to stdout instead of files")
+	exportCmd.Flags().StringVarP(&exportOutputDir, "output-directory", "o", ".", "Directory to write files into")
+	exportCmd.Flags().BoolVar(&exportNoCover, "no-cover-letter", false, "Omit the cover letter from a patch series")
+
+	exportCmd.MarkFlagsMutuallyExclusive("stdout", "output-directory")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	var target string
+	if len(args) > 0 {
+		target = args[0]
+	}
+
+	logger.Debug().
+		Str("target", target).
+		Bool("diff", exportDiff).
+		Bool("stdout", exportStdout).
+		Str("outputDir", exportOutputDir).
+		Msg("Starting export command")
+
+	gitRepo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	// Local ranges are exported without touching GitHub
+	var client export.ExportGitHubClient
+	var owner, name string
+	if !export.IsRangeTarget(target) {
+		currentRepo, err := repository.Current()
+		if err != nil {
+			return fmt.Errorf("failed to determine current repository: %w", err)
+		}
+		owner, name = currentRepo.Owner, currentRepo.Name
+
+		ghClient, err := newGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		client = ghClient
+	}
+
+	workflow := export.NewExportWorkflow(gitRepo, client, owner, name)
+
+	result, err := workflow.Execute(ctx, &export.ExportOptions{
+		Target:  target,
+		Diff:    exportDiff,
+		NoCover: exportNoCover,
+	})
+	if err != nil {
+		if errors.Is(err, export.ErrNoCommits) {
+			fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			return ErrSilentExit
+		}
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	if exportStdout {
+		return export.WriteMailbox(os.Stdout, result)
+	}
+
+	paths, err := export.WriteFiles(exportOutputDir, result)
+	if err != nil {
+		return err
+	}
+
+	if GetJSON() {
+		data, err := export.FormatJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, path := range paths {
+		fmt.Println(path)
+	}
+	return nil
+}
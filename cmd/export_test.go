@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestExportCommand(t *testing.T) {
+	t.Run("command initialization", func(t *testing.T) {
+		if exportCmd.Use != "export [pr|range]" {
+			t.Errorf("Expected Use to be 'export [pr|range]', got '%s'", exportCmd.Use)
+		}
+
+		if exportCmd.Short == "" {
+			t.Error("Expected Short description to be set")
+		}
+
+		if exportCmd.RunE == nil {
+			t.Error("Expected RunE to be set")
+		}
+	})
+
+	t.Run("export command is registered", func(t *testing.T) {
+		found := false
+		for _, cmd := range rootCmd.Commands() {
+			if cmd.Name() == "export" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected export command to be registered with root command")
+		}
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		for _, name := range []string{"diff", "stdout", "output-directory", "no-cover-letter"} {
+			if exportCmd.Flags().Lookup(name) == nil {
+				t.Errorf("Expected --%s flag to be defined", name)
+			}
+		}
+
+		if exportCmd.Flags().ShorthandLookup("o") == nil {
+			t.Error("Expected -o shorthand to be defined")
+		}
+
+		if err := exportCmd.Args(exportCmd, []string{}); err != nil {
+			t.Errorf("Expected no error without arguments, got %v", err)
+		}
+		if err := exportCmd.Args(exportCmd, []string{"1", "2"}); err == nil {
+			t.Error("Expected error for more than one argument")
+		}
+	})
+}
+
+func TestImportCommand(t *testing.T) {
+	t.Run("command initialization", func(t *testing.T) {
+		if importCmd.Short == "" {
+			t.Error("Expected Short description to be set")
+		}
+
+		if importCmd.RunE == nil {
+			t.Error("Expected RunE to be set")
+		}
+	})
+
+	t.Run("import command is registered", func(t *testing.T) {
+		found := false
+		for _, cmd := range rootCmd.Commands() {
+			if cmd.Name() == "import" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected import command to be registered with root command")
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/serpro69/gh-arc/internal/export"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [file|dir|-]...",
+	Short: "Apply a patch series or diff written by gh arc export",
+	Long: `Apply a series produced by 'gh arc export' onto the current branch.
+
+Inputs may be mailbox or .patch files, directories of .patch files (as
+written by 'gh arc export -o'), .diff files, or - for stdin (the default).
+
+Mailbox series are applied as commits with 'git am --3way', preserving
+authors, dates and messages; cover letters are skipped. A plain diff is
+applied to the working tree and index without committing.
+
+The working directory must be clean.
+
+Examples:
+  # Apply a series exported into a directory
+  gh arc import outgoing/
+
+  # Apply a mailbox from stdin
+  gh arc import < feature.mbox
+
+  # Apply a single diff
+  gh arc import feature.diff`,
+	RunE: runImport,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	logger.Debug().
+		Strs("paths", args).
+		Msg("Starting import command")
+
+	gitRepo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	result, err := export.NewImportWorkflow(gitRepo).Execute(args)
+	if err != nil {
+		switch {
+		case errors.Is(err, export.ErrDirtyWorkingDir):
+			fmt.Println("✗ Working directory has uncommitted changes")
+			fmt.Println("  Commit or stash your changes before importing")
+			return ErrSilentExit
+		case errors.Is(err, export.ErrNoPatches):
+			fmt.Println("✗ No patches found in input")
+			return ErrSilentExit
+		case errors.Is(err, export.ErrImportFailed):
+			fmt.Printf("✗ %v\n", err)
+			fmt.Println("  Resolve and run 'git am --continue', or 'git am --abort' to give up")
+			return ErrSilentExit
+		}
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	if result.Diff {
+		fmt.Println("✓ Applied diff (staged, not committed)")
+		return nil
+	}
+
+	noun := "patches"
+	if result.Patches == 1 {
+		noun = "patch"
+	}
+	fmt.Printf("✓ Applied %d %s\n", result.Patches, noun)
+	if result.SkippedCovers > 0 {
+		fmt.Println("  Skipped cover letter")
+	}
+	return nil
+}
@@ -4,23 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 
 	"github.com/cli/go-gh/v2/pkg/repository"
 	"github.com/spf13/cobra"
 
 	"github.com/serpro69/gh-arc/internal/config"
 	"github.com/serpro69/gh-arc/internal/git"
-	"github.com/serpro69/gh-arc/internal/github"
 	"github.com/serpro69/gh-arc/internal/land"
 	"github.com/serpro69/gh-arc/internal/logger"
 )
 
 var (
-	landSquash   bool
-	landRebase   bool
-	landForce    bool
-	landEdit     bool
-	landNoDelete bool
+	landSquash     bool
+	landRebase     bool
+	landForce      bool
+	landEdit       bool
+	landNoDelete   bool
+	landNoRetarget bool
+	landStack      bool
 )
 
 var landCmd = &cobra.Command{
@@ -41,6 +44,20 @@ Pre-merge checks (in order):
   5. Approval status (configurable: strict, prompt, none)
   6. CI status (configurable: required, all, none)
 
+Dependent PRs:
+  PRs that target the landed branch are retargeted to the default branch.
+  Their local branches are rebased onto the updated default branch, dropping
+  the landed commits, and pushed. Branches that are not checked out locally
+  or that conflict are only retargeted and reported. Use --no-retarget to
+  leave dependent PRs alone.
+
+Landing a stack:
+  With --stack, the current PR and every PR below it in the stack are landed
+  bottom-up. Each PR goes through the checks above before it is merged. The
+  next PR is then retargeted, rebased and pushed, and its CI is awaited until
+  it passes, fails, or land.stackCITimeout (default: 30m) runs out. Landing
+  stops at the first failure and reports what was landed and what is left.
+
 Merge methods:
   Only squash and rebase are supported (no merge commits). The default
   method is configured via land.defaultMergeMethod (default: squash).
@@ -59,7 +76,13 @@ Examples:
   gh arc land --force
 
   # Keep the local branch after merging
-  gh arc land --no-delete`,
+  gh arc land --no-delete
+
+  # Don't retarget or rebase PRs stacked on this one
+  gh arc land --no-retarget
+
+  # Land the whole stack below and including the current branch
+  gh arc land --stack`,
 	RunE: runLand,
 }
 
@@ -71,12 +94,17 @@ func init() {
 	landCmd.Flags().BoolVar(&landForce, "force", false, "Bypass approval and CI checks")
 	landCmd.Flags().BoolVar(&landEdit, "edit", false, "Open $EDITOR to customize the merge commit message")
 	landCmd.Flags().BoolVar(&landNoDelete, "no-delete", false, "Keep the local branch after merge")
+	landCmd.Flags().BoolVar(&landNoRetarget, "no-retarget", false, "Don't retarget and rebase dependent PRs onto the default branch")
+	landCmd.Flags().BoolVar(&landStack, "stack", false, "Land every PR below and including the current one, bottom-up")
 
 	landCmd.MarkFlagsMutuallyExclusive("squash", "rebase")
+	landCmd.MarkFlagsMutuallyExclusive("stack", "no-retarget")
 }
 
 func runLand(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	// Cancel on Ctrl-C so waiting for CI with --stack stops promptly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
 	logger.Debug().
 		Bool("squash", landSquash).
@@ -84,6 +112,8 @@ func runLand(cmd *cobra.Command, args []string) error {
 		Bool("force", landForce).
 		Bool("edit", landEdit).
 		Bool("no-delete", landNoDelete).
+		Bool("no-retarget", landNoRetarget).
+		Bool("stack", landStack).
 		Msg("Starting land command")
 
 	cfg, err := config.Load()
@@ -106,20 +136,42 @@ func runLand(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to open git repository: %w", err)
 	}
 
-	client, err := github.NewClient()
+	client, err := newGitHubClient()
 	if err != nil {
 		return fmt.Errorf("failed to create GitHub client: %w", err)
 	}
 
 	workflow := land.NewLandWorkflow(gitRepo, client, cfg, currentRepo.Owner, currentRepo.Name)
 
-	_, err = workflow.Execute(ctx, &land.LandOptions{
-		Squash:   landSquash,
-		Rebase:   landRebase,
-		Force:    landForce,
-		Edit:     landEdit,
-		NoDelete: landNoDelete,
-	})
+	opts := &land.LandOptions{
+		Squash:     landSquash,
+		Rebase:     landRebase,
+		Force:      landForce,
+		Edit:       landEdit,
+		NoDelete:   landNoDelete,
+		NoRetarget: landNoRetarget,
+	}
+	// Landing retargets the PRs stacked on the landed ones, so their stack
+	// tables are rewritten even when a stack only partially landed
+	var restacked []string
+	if landStack {
+		var result *land.StackLandResult
+		result, err = workflow.ExecuteStack(ctx, opts)
+		if result != nil {
+			for _, landed := range result.Landed {
+				restacked = append(restacked, dependentBranches(landed)...)
+			}
+			for _, pr := range result.Remaining {
+				restacked = append(restacked, pr.Head.Ref)
+			}
+		}
+	} else {
+		var result *land.LandResult
+		result, err = workflow.Execute(ctx, opts)
+		restacked = dependentBranches(result)
+	}
+	updateStackLinks(ctx, client, currentRepo.Owner, currentRepo.Name, restacked...)
+
 	if err != nil {
 		if errors.Is(err, land.ErrMergeAborted) {
 			fmt.Println("✗ Merge aborted — commit message empty or unchanged")
@@ -143,6 +195,9 @@ func runLand(cmd *cobra.Command, args []string) error {
 			errors.Is(err, land.ErrLocalHeadMismatch) ||
 			errors.Is(err, land.ErrApprovalFailed) ||
 			errors.Is(err, land.ErrCIFailed) ||
+			errors.Is(err, land.ErrCITimeout) ||
+			errors.Is(err, land.ErrStackNotRebased) ||
+			errors.Is(err, land.ErrStackCycle) ||
 			errors.Is(err, land.ErrNonInteractive) {
 			return fmt.Errorf("land failed: %w", err)
 		}
@@ -152,3 +207,16 @@ func runLand(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// dependentBranches returns the head branches of the PRs that were stacked on
+// a landed PR.
+func dependentBranches(result *land.LandResult) []string {
+	if result == nil {
+		return nil
+	}
+	branches := make([]string, 0, len(result.Dependents))
+	for _, dep := range result.Dependents {
+		branches = append(branches, dep.PR.Head.Ref)
+	}
+	return branches
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/lint"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/runner"
+)
+
+var (
+	lintFix bool
+	lintAll bool
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Run configured linters against changed files",
+	Args:  cobra.NoArgs,
+	Long: `Run the linters configured in lint.runners against the files changed on the
+current branch.
+
+Changed files are computed against the merge-base with the default branch
+(the same diff GitHub shows for a pull request). Deleted files are ignored.
+Each runner's output is streamed as-is, followed by a pass/fail summary.
+All runners execute even if an earlier one fails.
+
+Each runner is configured in .arc.json:
+  "lint": {
+    "runners": [
+      {
+        "name": "golangci-lint",
+        "command": "golangci-lint",
+        "args": ["run"],
+        "fixArgs": ["--fix"],
+        "autoFix": true,
+        "timeout": "5m"
+      }
+    ]
+  }
+
+MegaLinter can be used instead of (or in addition to) custom runners via
+lint.megaLinter.enabled:
+  auto   use MegaLinter when no runners are configured and mega-linter-runner,
+         Docker or Podman is installed (default)
+  true   always run MegaLinter, alongside any custom runners
+  false  never run MegaLinter
+
+MegaLinter uses lint.megaLinter.config if that file exists, otherwise gh-arc's
+embedded default configuration. Only changed files are linted unless --all is
+given, and results are summarized per linter from megalinter-reports/.
+
+Exit status is 1 if any runner reports issues.
+
+Examples:
+  # Lint files changed on the current branch
+  gh arc lint
+
+  # Lint the whole project
+  gh arc lint --all
+
+  # Apply automatic fixes for runners with autoFix enabled
+  gh arc lint --fix
+
+  # Machine-readable results
+  gh arc lint --json`,
+	RunE: runLint,
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().BoolVar(&lintFix, "fix", false, "Auto-fix issues where supported (uses runner fixArgs)")
+	lintCmd.Flags().BoolVar(&lintAll, "all", false, "Lint all files, not just changed ones")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	// Cancel on Ctrl-C so running linters (in their own process group) are stopped
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	logger.Debug().
+		Bool("fix", lintFix).
+		Bool("all", lintAll).
+		Msg("Starting lint command")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	gitRepo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	jsonMode := GetJSON()
+	engine := runner.NewEngine(runner.EngineOptions{
+		JSONMode: jsonMode,
+		Verbose:  GetVerbose(),
+	})
+
+	workflow := lint.NewLintWorkflow(gitRepo, engine, cfg)
+
+	result, err := workflow.Execute(ctx, &lint.LintOptions{
+		Fix:      lintFix,
+		All:      lintAll,
+		JSONMode: jsonMode,
+		Verbose:  GetVerbose(),
+	})
+	if err != nil {
+		if errors.Is(err, lint.ErrMegaLinterUnavailable) {
+			fmt.Println("✗ MegaLinter is enabled but cannot be started")
+			fmt.Println("Install mega-linter-runner (npm install -g mega-linter-runner) or Docker/Podman,")
+			fmt.Println("or set lint.megaLinter.enabled to \"auto\" or \"false\".")
+		}
+		return fmt.Errorf("lint failed: %w", err)
+	}
+
+	return reportRunnerResult(result.ExecutionResult, "lint", jsonMode)
+}
+
+// reportRunnerResult prints the JSON result when requested and maps a failed
+// execution to ErrSilentExit, since the runner summary already reported it.
+func reportRunnerResult(result *runner.ExecutionResult, command string, jsonMode bool) error {
+	if jsonMode {
+		data, err := runner.FormatJSON(result, command)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %w", err)
+		}
+		fmt.Println(string(data))
+	}
+
+	if !result.Success {
+		return ErrSilentExit
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/serpro69/gh-arc/internal/runner"
+)
+
+func TestLintCommand(t *testing.T) {
+	t.Run("command initialization", func(t *testing.T) {
+		if lintCmd.Use != "lint" {
+			t.Errorf("Expected Use to be 'lint', got '%s'", lintCmd.Use)
+		}
+
+		if lintCmd.Short == "" {
+			t.Error("Expected Short description to be set")
+		}
+
+		if lintCmd.Long == "" {
+			t.Error("Expected Long description to be set")
+		}
+
+		if lintCmd.RunE == nil {
+			t.Error("Expected RunE to be set")
+		}
+	})
+
+	t.Run("lint command is registered", func(t *testing.T) {
+		found := false
+		for _, cmd := range rootCmd.Commands() {
+			if cmd.Name() == "lint" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected lint command to be registered with root command")
+		}
+	})
+
+	t.Run("rejects positional arguments", func(t *testing.T) {
+		if err := lintCmd.Args(lintCmd, []string{"extra"}); err == nil {
+			t.Error("Expected error for positional arguments")
+		}
+		if err := lintCmd.Args(lintCmd, []string{}); err != nil {
+			t.Errorf("Expected no error without arguments, got: %v", err)
+		}
+	})
+}
+
+func TestLintFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantFix bool
+		wantAll bool
+	}{
+		{
+			name:    "no flags",
+			args:    []string{},
+			wantFix: false,
+			wantAll: false,
+		},
+		{
+			name:    "fix flag",
+			args:    []string{"--fix"},
+			wantFix: true,
+			wantAll: false,
+		},
+		{
+			name:    "all flag",
+			args:    []string{"--all"},
+			wantFix: false,
+			wantAll: true,
+		},
+		{
+			name:    "both flags",
+			args:    []string{"--fix", "--all"},
+			wantFix: true,
+			wantAll: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lintFix = false
+			lintAll = false
+
+			if err := lintCmd.Flags().Parse(tt.args); err != nil {
+				t.Fatalf("Unexpected parse error: %v", err)
+			}
+
+			if lintFix != tt.wantFix {
+				t.Errorf("Expected fix %t, got %t", tt.wantFix, lintFix)
+			}
+			if lintAll != tt.wantAll {
+				t.Errorf("Expected all %t, got %t", tt.wantAll, lintAll)
+			}
+		})
+	}
+}
+
+func TestReportRunnerResult(t *testing.T) {
+	t.Run("failure returns ErrSilentExit", func(t *testing.T) {
+		result := &runner.ExecutionResult{
+			Success: false,
+			Runners: []runner.RunResult{{Name: "golangci-lint", Status: runner.StatusFailed, ExitCode: 1}},
+		}
+
+		err := reportRunnerResult(result, "lint", false)
+		if !errors.Is(err, ErrSilentExit) {
+			t.Errorf("Expected ErrSilentExit, got: %v", err)
+		}
+	})
+
+	t.Run("success returns nil", func(t *testing.T) {
+		result := &runner.ExecutionResult{Success: true}
+
+		if err := reportRunnerResult(result, "lint", false); err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+	})
+}
@@ -99,7 +99,7 @@ func runList(cmd *cobra.Command, args []string) error {
 	owner, repoName := repo.Owner, repo.Name
 
 	// Create GitHub client
-	client, err := github.NewClient()
+	client, err := newGitHubClient()
 	if err != nil {
 		return fmt.Errorf("failed to create GitHub client: %w", err)
 	}
@@ -133,7 +133,7 @@ func runList(cmd *cobra.Command, args []string) error {
 	_ = prCache.CleanExpired()
 
 	// Generate cache key
-	cacheKey := cache.GenerateKey("prs", owner, repoName, "open")
+	cacheKey := cache.PullRequestsKey(owner, repoName)
 
 	var prs []*github.PullRequest
 
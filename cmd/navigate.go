@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/spf13/cobra"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/stack"
+)
+
+const navigateLong = `
+
+A branch's parent is its recorded stack parent (see 'gh arc stack parent'),
+otherwise the base of its open PR. Its children are the open PRs targeting
+it and the local branches that record it as their parent. When a branch has
+several children you are asked which one to follow.
+
+The working directory must be clean.`
+
+var upCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Check out the branch stacked on the current one",
+	Args:  cobra.NoArgs,
+	Long: `Check out the child of the current branch in its stack.` + navigateLong + `
+
+Examples:
+  gh arc up`,
+	RunE: runNavigate(stack.DirectionUp),
+}
+
+var downCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Check out the branch the current one is stacked on",
+	Args:  cobra.NoArgs,
+	Long: `Check out the parent of the current branch in its stack. From the bottom
+branch of a stack this is the default branch.` + navigateLong + `
+
+Examples:
+  gh arc down`,
+	RunE: runNavigate(stack.DirectionDown),
+}
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Check out the last branch of the current stack",
+	Args:  cobra.NoArgs,
+	Long: `Follow children from the current branch until the last branch of its stack
+and check it out.` + navigateLong + `
+
+Examples:
+  gh arc top`,
+	RunE: runNavigate(stack.DirectionTop),
+}
+
+var bottomCmd = &cobra.Command{
+	Use:   "bottom",
+	Short: "Check out the first branch of the current stack",
+	Args:  cobra.NoArgs,
+	Long: `Follow parents from the current branch down to the first branch above the
+default branch and check it out.` + navigateLong + `
+
+Examples:
+  gh arc bottom`,
+	RunE: runNavigate(stack.DirectionBottom),
+}
+
+func init() {
+	rootCmd.AddCommand(upCmd)
+	rootCmd.AddCommand(downCmd)
+	rootCmd.AddCommand(topCmd)
+	rootCmd.AddCommand(bottomCmd)
+}
+
+func runNavigate(direction stack.Direction) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		logger.Debug().
+			Str("direction", string(direction)).
+			Msg("Starting navigate command")
+
+		currentRepo, err := repository.Current()
+		if err != nil {
+			return fmt.Errorf("failed to determine current repository: %w", err)
+		}
+
+		gitRepo, err := git.OpenRepository(".")
+		if err != nil {
+			return fmt.Errorf("failed to open git repository: %w", err)
+		}
+
+		client, err := newGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+
+		workflow := stack.NewNavigateWorkflow(gitRepo, client, currentRepo.Owner, currentRepo.Name)
+
+		result, err := workflow.Execute(ctx, &stack.NavigateOptions{Direction: direction})
+		if err != nil {
+			switch {
+			case errors.Is(err, stack.ErrDirtyWorkingDir):
+				fmt.Println("✗ Working directory has uncommitted changes")
+				fmt.Println("  Commit or stash them before switching branches")
+				return ErrSilentExit
+			case errors.Is(err, stack.ErrOnTrunk):
+				fmt.Println("✗ You are on the default branch, which is not part of a stack")
+				return ErrSilentExit
+			case errors.Is(err, stack.ErrStackEnd), errors.Is(err, stack.ErrAmbiguousChild):
+				fmt.Printf("✗ %v\n", err)
+				return ErrSilentExit
+			}
+			return fmt.Errorf("%s failed: %w", direction, err)
+		}
+
+		if GetJSON() {
+			data, err := stack.FormatNavigateJSON(result)
+			if err != nil {
+				return fmt.Errorf("failed to format JSON output: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		stack.PrintNavigateResult(os.Stdout, result)
+		return nil
+	}
+}
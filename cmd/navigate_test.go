@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestNavigateCommands(t *testing.T) {
+	for _, name := range []string{"up", "down", "top", "bottom"} {
+		t.Run(name, func(t *testing.T) {
+			var found bool
+			for _, cmd := range rootCmd.Commands() {
+				if cmd.Name() != name {
+					continue
+				}
+				found = true
+
+				if cmd.Short == "" {
+					t.Error("Expected Short description to be set")
+				}
+				if cmd.RunE == nil {
+					t.Error("Expected RunE to be set")
+				}
+				if err := cmd.Args(cmd, []string{"extra"}); err == nil {
+					t.Error("Expected arguments to be rejected")
+				}
+			}
+
+			if !found {
+				t.Errorf("Expected %s command to be registered with root command", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/spf13/cobra"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/patch"
+)
+
+var (
+	patchApply   bool
+	patchBranch  string
+	patchStack   bool
+	patchNoStack bool
+)
+
+var patchCmd = &cobra.Command{
+	Use:   "patch <pr|url>",
+	Short: "Check out or apply another author's pull request locally",
+	Args:  cobra.ExactArgs(1),
+	Long: `Bring a pull request into your local working copy for testing and review.
+
+The pull request can be given as a number (123 or #123) or as a URL.
+
+By default the PR head is fetched and checked out as a local tracking branch
+named after the PR head branch. If the branch already exists it is
+fast-forwarded to the PR head; a branch that has diverged is left untouched.
+
+PRs from forks are fetched from a remote named after the fork owner, which is
+added on first use (using the same protocol and host as origin). An existing
+remote of that name must point at the fork, otherwise patch stops. Fork branches
+are checked out as <owner>-<branch> to avoid clashing with local branches.
+
+With --apply, the PR's changes are applied onto the current branch instead,
+staged but not committed, so you can try them on top of your own work.
+
+Stacked PRs:
+  If the PR targets another open PR's branch rather than the default branch,
+  you are asked whether to patch the parent PRs too. Parents are patched
+  bottom-up, before the requested PR. Use --stack or --no-stack to answer
+  without prompting.
+
+The working directory must be clean.
+
+Examples:
+  # Check out PR #123 as a local branch
+  gh arc patch 123
+
+  # Check out a PR from its URL under a custom branch name
+  gh arc patch https://github.com/owner/repo/pull/123 --branch review/123
+
+  # Apply a PR's changes onto the current branch
+  gh arc patch 123 --apply
+
+  # Check out a stacked PR together with its parents
+  gh arc patch 123 --stack`,
+	RunE: runPatch,
+}
+
+func init() {
+	rootCmd.AddCommand(patchCmd)
+
+	patchCmd.Flags().BoolVar(&patchApply, "apply", false, "Apply the PR's diff onto the current branch instead of checking out a branch")
+	patchCmd.Flags().StringVarP(&patchBranch, "branch", "b", "", "Local branch name for the PR (default: derived from the PR head branch)")
+	patchCmd.Flags().BoolVar(&patchStack, "stack", false, "Also patch stacked parent PRs without prompting")
+	patchCmd.Flags().BoolVar(&patchNoStack, "no-stack", false, "Never patch stacked parent PRs")
+
+	patchCmd.MarkFlagsMutuallyExclusive("stack", "no-stack")
+	patchCmd.MarkFlagsMutuallyExclusive("apply", "branch")
+}
+
+func runPatch(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	logger.Debug().
+		Str("ref", args[0]).
+		Bool("apply", patchApply).
+		Str("branch", patchBranch).
+		Bool("stack", patchStack).
+		Bool("noStack", patchNoStack).
+		Msg("Starting patch command")
+
+	currentRepo, err := repository.Current()
+	if err != nil {
+		return fmt.Errorf("failed to determine current repository: %w", err)
+	}
+
+	gitRepo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	client, err := newGitHubClient()
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	workflow := patch.NewPatchWorkflow(gitRepo, client, currentRepo.Owner, currentRepo.Name)
+
+	result, err := workflow.Execute(ctx, &patch.PatchOptions{
+		Ref:     args[0],
+		Apply:   patchApply,
+		Branch:  patchBranch,
+		Stack:   patchStack,
+		NoStack: patchNoStack,
+	})
+	if err != nil {
+		if result != nil && len(result.Patched) > 0 && !GetJSON() {
+			patch.PrintResult(os.Stdout, result)
+		}
+
+		switch {
+		case errors.Is(err, patch.ErrDirtyWorkingDir):
+			fmt.Println("✗ Working directory has uncommitted changes")
+			fmt.Println("  Commit or stash your changes before patching")
+			return ErrSilentExit
+		case errors.Is(err, patch.ErrBranchDiverged):
+			fmt.Printf("✗ %v\n", err)
+			return ErrSilentExit
+		case errors.Is(err, patch.ErrForkRemoteMismatch):
+			fmt.Printf("✗ %v\n", err)
+			fmt.Println("  Point the remote at the PR's head repository with 'git remote set-url', or rename it")
+			return ErrSilentExit
+		case errors.Is(err, patch.ErrApplyFailed):
+			fmt.Printf("✗ %v\n", err)
+			fmt.Println("  Resolve the conflicts and commit, or discard with 'git reset --hard'")
+			return ErrSilentExit
+		case errors.Is(err, git.ErrAuthenticationFailed):
+			fmt.Println("✗ Authentication failed while fetching")
+			fmt.Println("  Check your git credentials for the PR's repository")
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+		return fmt.Errorf("patch failed: %w", err)
+	}
+
+	if GetJSON() {
+		data, err := patch.FormatJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	patch.PrintResult(os.Stdout, result)
+	return nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestPatchCommand(t *testing.T) {
+	t.Run("command initialization", func(t *testing.T) {
+		if patchCmd.Use != "patch <pr|url>" {
+			t.Errorf("Expected Use to be 'patch <pr|url>', got '%s'", patchCmd.Use)
+		}
+
+		if patchCmd.Short == "" {
+			t.Error("Expected Short description to be set")
+		}
+
+		if patchCmd.RunE == nil {
+			t.Error("Expected RunE to be set")
+		}
+	})
+
+	t.Run("patch command is registered", func(t *testing.T) {
+		found := false
+		for _, cmd := range rootCmd.Commands() {
+			if cmd.Name() == "patch" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected patch command to be registered with root command")
+		}
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		for _, name := range []string{"apply", "branch", "stack", "no-stack"} {
+			if patchCmd.Flags().Lookup(name) == nil {
+				t.Errorf("Expected --%s flag to be defined", name)
+			}
+		}
+
+		if err := patchCmd.Args(patchCmd, []string{}); err == nil {
+			t.Error("Expected error when no PR is given")
+		}
+		if err := patchCmd.Args(patchCmd, []string{"1", "2"}); err == nil {
+			t.Error("Expected error for more than one argument")
+		}
+	})
+}
@@ -1,19 +1,30 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 
 	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/github"
 	"github.com/serpro69/gh-arc/internal/logger"
 	"github.com/spf13/cobra"
 )
 
+// ErrSilentExit signals that the command already reported its failure to the
+// user (e.g. a lint summary) and the process should exit 1 without printing the error.
+var ErrSilentExit = errors.New("silent exit")
+
 var (
 	// Global flags
-	verbosity int // Verbosity level: 0=warn, 1=info, 2=debug, 3=trace
-	quiet     bool
-	jsonOut   bool
+	verbosity        int // Verbosity level: 0=warn, 1=info, 2=debug, 3=trace
+	quiet            bool
+	jsonOut          bool
+	waitForRateLimit bool
+	offline          bool
+	cassette         string // Records or, offline, replays GitHub API calls
 
 	// cfg holds the loaded configuration
 	cfg *config.Config
@@ -53,7 +64,7 @@ external tools for code-review processes.`,
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		if !quiet {
+		if !quiet && !errors.Is(err, ErrSilentExit) {
 			fmt.Fprintln(os.Stderr, err)
 		}
 		os.Exit(1)
@@ -67,6 +78,10 @@ func init() {
 	rootCmd.PersistentFlags().CountP("verbose", "v", "Increase verbosity level (can be repeated: -v=info, -vv=debug, -vvv=trace)")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress non-error output")
 	rootCmd.PersistentFlags().BoolVar(&jsonOut, "json", false, "Output in JSON format")
+	rootCmd.PersistentFlags().BoolVar(&waitForRateLimit, "wait-for-rate-limit", false, "Sleep until a used-up GitHub rate limit resets instead of failing")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Answer GitHub API calls from the cache or --cassette only, never the network")
+	rootCmd.PersistentFlags().StringVar(&cassette, "cassette", "", "Record GitHub API calls to this file, or replay them with --offline")
+	_ = rootCmd.PersistentFlags().MarkHidden("cassette")
 
 	// Bind verbosity flag to variable
 	verbosity, _ = rootCmd.PersistentFlags().GetCount("verbose")
@@ -102,6 +117,9 @@ func initConfig() {
 	if !rootCmd.PersistentFlags().Changed("json") {
 		jsonOut = cfg.Output.JSON
 	}
+	if !rootCmd.PersistentFlags().Changed("wait-for-rate-limit") {
+		waitForRateLimit = cfg.GitHub.WaitForRateLimit
+	}
 
 	// Print config file used if verbosity > 0
 	if verbosity > 0 && config.GetConfigFilePath() != "" {
@@ -137,3 +155,35 @@ func GetConfig() *config.Config {
 	}
 	return cfg
 }
+
+// newGitHubClient creates the GitHub client of a command, set up by the
+// global flags and configuration
+func newGitHubClient() (*github.Client, error) {
+	var opts []github.ClientOption
+	if waitForRateLimit {
+		var countdown io.Writer = os.Stderr
+		if quiet {
+			countdown = io.Discard
+		}
+		opts = append(opts, github.WithRateLimitWait(countdown))
+	}
+
+	if cassette != "" {
+		var transport http.RoundTripper
+		if offline {
+			replayer, err := github.NewReplayer(cassette)
+			if err != nil {
+				return nil, err
+			}
+			transport = replayer
+		} else {
+			transport = github.NewRecorder(cassette, http.DefaultTransport)
+		}
+		opts = append(opts, github.WithHTTPClient(&http.Client{Transport: transport}))
+	}
+	if offline {
+		opts = append(opts, github.WithOffline())
+	}
+
+	return github.NewClient(opts...)
+}
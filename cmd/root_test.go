@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
 	"testing"
 
+	"github.com/serpro69/gh-arc/internal/github"
 	"github.com/spf13/cobra"
 )
 
@@ -50,6 +54,22 @@ func TestRootCommand(t *testing.T) {
 		if jsonFlag == nil {
 			t.Fatal("Expected 'json' flag to be registered")
 		}
+
+		if rootCmd.PersistentFlags().Lookup("wait-for-rate-limit") == nil {
+			t.Fatal("Expected 'wait-for-rate-limit' flag to be registered")
+		}
+
+		if rootCmd.PersistentFlags().Lookup("offline") == nil {
+			t.Fatal("Expected 'offline' flag to be registered")
+		}
+
+		flag := rootCmd.PersistentFlags().Lookup("cassette")
+		if flag == nil {
+			t.Fatal("Expected 'cassette' flag to be registered")
+		}
+		if !flag.Hidden {
+			t.Error("Expected 'cassette' flag to be hidden")
+		}
 	})
 }
 
@@ -233,3 +253,52 @@ func TestExecute(t *testing.T) {
 		_ = Execute
 	})
 }
+
+func TestNewGitHubClientOffline(t *testing.T) {
+	// Keep cached responses out of the user's cache
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	defer func() {
+		offline = false
+		cassette = ""
+	}()
+
+	t.Run("replays a cassette", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cassette.json")
+		recorded := &github.Cassette{Interactions: []github.Interaction{{
+			Request:  github.RecordedRequest{Method: "GET", URL: "/user"},
+			Response: github.RecordedResponse{Status: 200, Body: `{"login":"octocat"}`},
+		}}}
+		if err := recorded.Save(path); err != nil {
+			t.Fatalf("Failed to save cassette: %v", err)
+		}
+		offline, cassette = true, path
+
+		client, err := newGitHubClient()
+		if err != nil {
+			t.Fatalf("newGitHubClient() error = %v", err)
+		}
+
+		var user struct{ Login string }
+		if err := client.Do(context.Background(), "GET", "user", nil, &user); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		if user.Login != "octocat" {
+			t.Errorf("Login = %q, want %q", user.Login, "octocat")
+		}
+
+		err = client.Do(context.Background(), "POST", "user/repos", map[string]string{"name": "x"}, nil)
+		if !errors.Is(err, github.ErrOffline) {
+			t.Errorf("Do() error = %v, want ErrOffline", err)
+		}
+	})
+
+	t.Run("missing cassette", func(t *testing.T) {
+		offline, cassette = true, filepath.Join(t.TempDir(), "missing.json")
+
+		if _, err := newGitHubClient(); err == nil {
+			t.Error("Expected an error for a missing cassette")
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/spf13/cobra"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/diff"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/stack"
+	"github.com/serpro69/gh-arc/internal/template"
+)
+
+var (
+	splitNoSubmit bool
+	splitNoEdit   bool
+	splitDraft    bool
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Split the current branch into a stack of PRs",
+	Args:  cobra.NoArgs,
+	Long: `Split the commits of the current branch into a stack of branches and submit
+each one as a stacked pull request.
+
+The commits on top of the branch's parent (its recorded stack parent, or the
+default branch) open in your editor, oldest first, like an interactive rebase:
+
+  branch feature/big
+  pick 1a2b3c4 Add the API
+  pick 5d6e7f8 Use the API in the UI
+
+Add 'branch <name>' lines to start new branches; the commits below a branch
+line go into it. Branches are stacked in the order they appear. The current
+branch name may be reused for one of them, any other name must be new.
+
+When the commits keep their order, the branches simply point into the
+existing history. Reordering or removing lines cherry-picks the commits into
+the new order; if that conflicts, nothing is changed. The original tip of the
+branch is always saved under refs/gh-arc/backup/<branch>.
+
+The branches are then submitted bottom-up like 'gh arc stack submit', with
+one editor session describing all new PRs.
+
+Examples:
+  # Split the current branch and submit the stack
+  gh arc split
+
+  # Only create the branches
+  gh arc split --no-submit
+
+  # Submit the new PRs as drafts without describing them
+  gh arc split --draft --no-edit`,
+	RunE: runSplit,
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+
+	splitCmd.Flags().BoolVar(&splitNoSubmit, "no-submit", false, "Only create the branches, do not create PRs")
+	splitCmd.Flags().BoolVar(&splitNoEdit, "no-edit", false, "Create PRs from the pre-filled templates without opening an editor")
+	splitCmd.Flags().BoolVar(&splitDraft, "draft", false, "Create the PRs as drafts")
+	splitCmd.MarkFlagsMutuallyExclusive("no-submit", "no-edit")
+	splitCmd.MarkFlagsMutuallyExclusive("no-submit", "draft")
+}
+
+func runSplit(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	logger.Debug().
+		Bool("noSubmit", splitNoSubmit).
+		Bool("noEdit", splitNoEdit).
+		Bool("draft", splitDraft).
+		Msg("Starting split command")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	currentRepo, err := repository.Current()
+	if err != nil {
+		return fmt.Errorf("failed to determine current repository: %w", err)
+	}
+
+	gitRepo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	client, err := newGitHubClient()
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	executor := diff.NewPRExecutor(client, gitRepo, currentRepo.Owner, currentRepo.Name)
+	workflow := stack.NewSplitWorkflow(gitRepo, client, executor, cfg, currentRepo.Owner, currentRepo.Name)
+
+	result, err := workflow.Execute(ctx, &stack.SplitOptions{
+		NoSubmit: splitNoSubmit,
+		NoEdit:   splitNoEdit,
+		Draft:    splitDraft,
+	})
+	if err != nil && result != nil {
+		// The branches exist; only submitting them failed
+		stack.PrintSplitResult(os.Stdout, result)
+		fmt.Printf("\n✗ %v\n", err)
+		fmt.Println("  Run 'gh arc stack submit' to try again")
+		return ErrSilentExit
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, stack.ErrDirtyWorkingDir):
+			fmt.Println("✗ Working directory has uncommitted changes")
+			fmt.Println("  Commit or stash them before splitting")
+			return ErrSilentExit
+		case errors.Is(err, stack.ErrOnTrunk):
+			fmt.Println("✗ Cannot split the default branch")
+			return ErrSilentExit
+		case errors.Is(err, stack.ErrNothingToSplit),
+			errors.Is(err, stack.ErrInvalidSplitPlan):
+			fmt.Printf("✗ %v\n", err)
+			return ErrSilentExit
+		case errors.Is(err, stack.ErrSplitConflict):
+			fmt.Printf("✗ %v\n", err)
+			fmt.Println("  Nothing was changed. Keep conflicting commits in their original order, or")
+			fmt.Println("  resolve the split by hand starting from refs/gh-arc/backup/")
+			return ErrSilentExit
+		case errors.Is(err, template.ErrEditorCancelled):
+			fmt.Println("✗ Editor cancelled, no changes made")
+			return nil
+		}
+		return fmt.Errorf("split failed: %w", err)
+	}
+
+	if GetJSON() {
+		data, err := stack.FormatSplitJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	stack.PrintSplitResult(os.Stdout, result)
+	return nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	t.Run("command initialization", func(t *testing.T) {
+		if splitCmd.Use != "split" {
+			t.Errorf("Expected Use to be 'split', got '%s'", splitCmd.Use)
+		}
+
+		if splitCmd.Short == "" {
+			t.Error("Expected Short description to be set")
+		}
+
+		if splitCmd.RunE == nil {
+			t.Error("Expected RunE to be set")
+		}
+
+		if err := splitCmd.Args(splitCmd, []string{"extra"}); err == nil {
+			t.Error("Expected arguments to be rejected")
+		}
+	})
+
+	t.Run("split command is registered", func(t *testing.T) {
+		found := false
+		for _, cmd := range rootCmd.Commands() {
+			if cmd.Name() == "split" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected split command to be registered with root command")
+		}
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		for _, name := range []string{"no-submit", "no-edit", "draft"} {
+			if splitCmd.Flags().Lookup(name) == nil {
+				t.Errorf("Expected --%s flag to be defined", name)
+			}
+		}
+	})
+}
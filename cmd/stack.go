@@ -0,0 +1,606 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/spf13/cobra"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/diff"
+	"github.com/serpro69/gh-arc/internal/format"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/stack"
+	"github.com/serpro69/gh-arc/internal/template"
+)
+
+var (
+	stackShowAll         bool
+	stackRestackContinue bool
+	stackRestackAbort    bool
+	stackSubmitNoEdit    bool
+	stackSubmitDraft     bool
+	stackSubmitReady     bool
+	stackParentSet       string
+	stackParentUnset     bool
+)
+
+const stackShowLong = `Show the stack of pull requests containing the current branch as a tree.
+
+A stack is a chain of open pull requests where each PR targets the head
+branch of another. The tree starts at the branch the bottom PR targets
+(usually trunk) and shows for every PR:
+  - The current branch, marked with *
+  - Draft state
+  - Review and CI status
+  - "needs rebase" when the PR does not contain the head of its parent PR
+
+When the current branch has no open PR, or with --all, every open PR is
+shown.
+
+Every PR of a stack carries a table of the whole stack in its description,
+between <!-- gh-arc:stack --> markers. gh arc diff, land, stack restack and
+stack submit rewrite it whenever the stack changes; text outside the markers
+is never touched.
+
+Examples:
+  # Show the current stack
+  gh arc stack
+
+  # Show all stacks in the repository
+  gh arc stack --all
+
+  # Node/edge list for scripting
+  gh arc stack --json`
+
+var stackCmd = &cobra.Command{
+	Use:   "stack",
+	Short: "Show and manage stacks of dependent pull requests",
+	Args:  cobra.NoArgs,
+	Long:  stackShowLong,
+	RunE:  runStackShow,
+}
+
+var stackShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the PR stack as a tree",
+	Args:  cobra.NoArgs,
+	Long:  stackShowLong,
+	RunE:  runStackShow,
+}
+
+var stackRestackCmd = &cobra.Command{
+	Use:   "restack [branch]",
+	Short: "Rebase every branch stacked on a branch onto its updated parent",
+	Args:  cobra.MaximumNArgs(1),
+	Long: `Rebase the branches of all PRs stacked on a branch, directly or
+transitively, onto their updated parents and push them.
+
+After amending or rebasing a branch in the middle of a stack, its children
+still contain the old commits. Restack walks the dependent PRs breadth-first
+and runs 'git rebase --onto <parent> <old parent> <child>' for each one, so
+only the child's own commits are replayed. Branches that already contain
+their parent, and branches not checked out locally, are left alone.
+
+If a rebase stops on conflicts, resolve them, stage the files and run
+'gh arc stack restack --continue'. Use --abort to stop and reset every
+branch to where it was before the restack.
+
+When every branch has been rebased, the starting branch and the rebased
+branches are pushed; rewritten branches are pushed with --force-with-lease.
+
+On the default branch, the branch to restack from must be named explicitly:
+restacking trunk rebases every PR that targets it.
+
+Examples:
+  # Restack everything above the current branch
+  gh arc stack restack
+
+  # Restack everything above feature/auth
+  gh arc stack restack feature/auth
+
+  # Resume after resolving conflicts
+  gh arc stack restack --continue
+
+  # Give up and restore all branches
+  gh arc stack restack --abort`,
+	RunE: runStackRestack,
+}
+
+var stackSubmitCmd = &cobra.Command{
+	Use:   "submit",
+	Short: "Create or update PRs for every branch in the local stack",
+	Args:  cobra.NoArgs,
+	Long: `Create or update the pull requests of every branch from trunk to the
+current branch in one go.
+
+The stack is followed down from the current branch using the parent recorded
+by 'gh arc work --stack', falling back to the base of each branch's open PR.
+PRs are then created or updated bottom-up, so every PR targets its parent:
+  - Existing PRs get new commits pushed and their base corrected if needed
+  - Branches without a PR are described in a single editor session with one
+    section per branch, or from the pre-filled templates with --no-edit
+
+Finally, the stack table in every PR description is brought up to date.
+
+Examples:
+  # Submit the whole stack, describing new PRs in one editor session
+  gh arc stack submit
+
+  # Submit without opening an editor
+  gh arc stack submit --no-edit
+
+  # Submit all PRs as drafts
+  gh arc stack submit --draft`,
+	RunE: runStackSubmit,
+}
+
+var stackParentCmd = &cobra.Command{
+	Use:   "parent [branch]",
+	Short: "Show or change the recorded stack parent of a branch",
+	Args:  cobra.MaximumNArgs(1),
+	Long: `Show or change the parent branch recorded for a branch.
+
+gh arc work, the auto-branch flow of gh arc diff, and gh arc diff --base
+record the branch a branch was started from in git config, together with
+the parent commit it forked from:
+
+  branch.<name>.arc-parent      the parent branch
+  branch.<name>.arc-parent-sha  the parent commit the branch forked from
+
+gh arc diff uses the recorded parent as the PR base instead of guessing it
+from merge-bases, and gh arc stack restack uses the recorded commit to find
+where the branch's own commits start. Restack and land keep both up to date.
+
+Without flags the recorded parent of the branch (default: the current
+branch) is shown. A parent may be a local branch or one that only exists
+on origin; a parent that already stacks on the branch is refused.
+
+Examples:
+  # Show the parent of the current branch
+  gh arc stack parent
+
+  # Stack the current branch on feature/api
+  gh arc stack parent --set feature/api
+
+  # Forget the parent of another branch
+  gh arc stack parent feature/ui --unset`,
+	RunE: runStackParent,
+}
+
+const stackFoldLong = `Take a branch out of the middle of a stack, either folding its commits
+into its parent (fold) or removing them from the stack (drop).
+
+Use this when a PR in the middle of a stack was merged into its parent,
+closed, or abandoned, leaving the PRs stacked on it pointing at a dead base.
+The branch (default: the current branch) is removed and every branch stacked
+on it is moved onto its parent:
+  - fold of an open PR fast-forwards the parent to the branch and pushes it;
+    the children already contain the commits and are not rewritten
+  - fold of a PR that was merged into its parent first brings the parent up
+    to date with origin, then rebases the children onto it without the
+    branch's original commits
+  - drop rebases the children onto the parent without the branch's commits
+
+If a child does not rebase cleanly, every branch is restored and nothing is
+pushed. Otherwise the rebased children are pushed, their PRs retargeted to
+the parent, and the PR of the removed branch gets a comment saying where its
+changes went and is closed. The local branch is deleted; its tip is kept
+under refs/gh-arc/backup/<branch>.
+
+An open PR cannot be folded into trunk; land it with gh arc land instead.
+Branches stacked on a rebased child are reported and can be updated with
+gh arc stack restack.
+
+Examples:
+  # Fold the current branch into its parent
+  gh arc stack fold
+
+  # Fold feature/api, which was merged into its parent out of order
+  gh arc stack fold feature/api
+
+  # Drop an abandoned branch from the middle of a stack
+  gh arc stack drop feature/spike`
+
+var stackFoldCmd = &cobra.Command{
+	Use:   "fold [branch]",
+	Short: "Fold a branch into its parent and restack its children",
+	Args:  cobra.MaximumNArgs(1),
+	Long:  stackFoldLong,
+	RunE:  runStackFold(stack.FoldModeFold),
+}
+
+var stackDropCmd = &cobra.Command{
+	Use:   "drop [branch]",
+	Short: "Drop a branch from its stack and restack its children",
+	Args:  cobra.MaximumNArgs(1),
+	Long:  stackFoldLong,
+	RunE:  runStackFold(stack.FoldModeDrop),
+}
+
+func init() {
+	rootCmd.AddCommand(stackCmd)
+	stackCmd.AddCommand(stackShowCmd)
+	stackCmd.AddCommand(stackRestackCmd)
+	stackCmd.AddCommand(stackSubmitCmd)
+	stackCmd.AddCommand(stackParentCmd)
+	stackCmd.AddCommand(stackFoldCmd)
+	stackCmd.AddCommand(stackDropCmd)
+
+	stackCmd.Flags().BoolVar(&stackShowAll, "all", false, "Show every stack, not only the current branch's")
+	stackShowCmd.Flags().BoolVar(&stackShowAll, "all", false, "Show every stack, not only the current branch's")
+
+	stackRestackCmd.Flags().BoolVar(&stackRestackContinue, "continue", false, "Continue after resolving conflicts")
+	stackRestackCmd.Flags().BoolVar(&stackRestackAbort, "abort", false, "Abort and restore all branches")
+	stackRestackCmd.MarkFlagsMutuallyExclusive("continue", "abort")
+
+	stackSubmitCmd.Flags().BoolVar(&stackSubmitNoEdit, "no-edit", false, "Create new PRs from the pre-filled templates without opening an editor")
+	stackSubmitCmd.Flags().BoolVar(&stackSubmitDraft, "draft", false, "Create or update all PRs as drafts")
+	stackSubmitCmd.Flags().BoolVar(&stackSubmitReady, "ready", false, "Create or update all PRs as ready for review")
+	stackSubmitCmd.MarkFlagsMutuallyExclusive("draft", "ready")
+
+	stackParentCmd.Flags().StringVar(&stackParentSet, "set", "", "Record `branch` as the parent")
+	stackParentCmd.Flags().BoolVar(&stackParentUnset, "unset", false, "Remove the recorded parent")
+	stackParentCmd.MarkFlagsMutuallyExclusive("set", "unset")
+}
+
+func runStackShow(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	logger.Debug().
+		Bool("all", stackShowAll).
+		Msg("Starting stack show command")
+
+	currentRepo, err := repository.Current()
+	if err != nil {
+		return fmt.Errorf("failed to determine current repository: %w", err)
+	}
+
+	gitRepo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	client, err := newGitHubClient()
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	workflow := stack.NewStackWorkflow(gitRepo, client, currentRepo.Owner, currentRepo.Name)
+
+	result, err := workflow.Execute(ctx, &stack.StackOptions{All: stackShowAll})
+	if err != nil {
+		return fmt.Errorf("stack failed: %w", err)
+	}
+
+	if GetJSON() {
+		data, err := stack.FormatJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	stack.PrintResult(os.Stdout, result, format.DefaultPRFormatterOptions().UseColor)
+	return nil
+}
+
+func runStackRestack(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	var branch string
+	if len(args) > 0 {
+		branch = args[0]
+	}
+	if branch != "" && (stackRestackContinue || stackRestackAbort) {
+		return fmt.Errorf("a branch cannot be given with --continue or --abort")
+	}
+
+	logger.Debug().
+		Str("branch", branch).
+		Bool("continue", stackRestackContinue).
+		Bool("abort", stackRestackAbort).
+		Msg("Starting stack restack command")
+
+	currentRepo, err := repository.Current()
+	if err != nil {
+		return fmt.Errorf("failed to determine current repository: %w", err)
+	}
+
+	gitRepo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	client, err := newGitHubClient()
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	workflow := stack.NewRestackWorkflow(gitRepo, client, currentRepo.Owner, currentRepo.Name)
+
+	result, err := workflow.Execute(ctx, &stack.RestackOptions{
+		Branch:   branch,
+		Continue: stackRestackContinue,
+		Abort:    stackRestackAbort,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, stack.ErrRestackConflict):
+			fmt.Printf("✗ %v\n", err)
+			fmt.Println("  Resolve the conflicts, stage the files, then run:")
+			fmt.Println("    gh arc stack restack --continue")
+			fmt.Println("  To give up and restore all branches:")
+			fmt.Println("    gh arc stack restack --abort")
+			return ErrSilentExit
+		case errors.Is(err, stack.ErrRestackInProgress):
+			fmt.Printf("✗ %v\n", err)
+			fmt.Println("  Run 'gh arc stack restack --continue' or 'gh arc stack restack --abort'")
+			return ErrSilentExit
+		case errors.Is(err, stack.ErrNoRestackInProgress):
+			fmt.Println("✗ No restack in progress")
+			return ErrSilentExit
+		case errors.Is(err, stack.ErrDirtyWorkingDir):
+			fmt.Println("✗ Working directory has uncommitted changes")
+			fmt.Println("  Commit or stash them before restacking")
+			return ErrSilentExit
+		case errors.Is(err, stack.ErrBranchNotFound):
+			fmt.Printf("✗ Branch %s does not exist locally\n", branch)
+			return ErrSilentExit
+		case errors.Is(err, stack.ErrOnTrunk):
+			fmt.Println("✗ You are on the default branch, restacking it would rebase every PR that targets it")
+			fmt.Println("  Check out a stacked branch, or name the branch to restack from")
+			return ErrSilentExit
+		}
+		return fmt.Errorf("restack failed: %w", err)
+	}
+
+	if !result.Aborted {
+		updateStackLinks(ctx, client, currentRepo.Owner, currentRepo.Name, result.Root)
+	}
+
+	if GetJSON() {
+		data, err := stack.FormatRestackJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	stack.PrintRestackResult(os.Stdout, result)
+	return nil
+}
+
+func runStackSubmit(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	logger.Debug().
+		Bool("noEdit", stackSubmitNoEdit).
+		Bool("draft", stackSubmitDraft).
+		Bool("ready", stackSubmitReady).
+		Msg("Starting stack submit command")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	currentRepo, err := repository.Current()
+	if err != nil {
+		return fmt.Errorf("failed to determine current repository: %w", err)
+	}
+
+	gitRepo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	client, err := newGitHubClient()
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	executor := diff.NewPRExecutor(client, gitRepo, currentRepo.Owner, currentRepo.Name)
+	workflow := stack.NewSubmitWorkflow(gitRepo, client, executor, cfg, currentRepo.Owner, currentRepo.Name)
+
+	result, err := workflow.Execute(ctx, &stack.SubmitOptions{
+		NoEdit: stackSubmitNoEdit,
+		Draft:  stackSubmitDraft,
+		Ready:  stackSubmitReady,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, stack.ErrOnTrunk):
+			fmt.Println("✗ Cannot submit a stack from the default branch")
+			fmt.Println("  Check out the top branch of the stack first")
+			return ErrSilentExit
+		case errors.Is(err, stack.ErrBranchMerged):
+			fmt.Printf("✗ %v\n", err)
+			fmt.Println("  Restack the remaining branches onto trunk first")
+			return ErrSilentExit
+		case errors.Is(err, template.ErrEditorCancelled):
+			fmt.Println("✗ Editor cancelled, no changes made")
+			return nil
+		case errors.Is(err, template.ErrTemplateValidationFailed):
+			fmt.Println("\n" + err.Error())
+			return ErrSilentExit
+		case errors.Is(err, git.ErrAuthenticationFailed):
+			fmt.Println("\n✗ Authentication failed")
+			fmt.Println("Please refresh your GitHub authentication:")
+			fmt.Printf("  gh auth refresh --scopes \"repo,read:user\"\n")
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+		return fmt.Errorf("stack submit failed: %w", err)
+	}
+
+	if GetJSON() {
+		data, err := stack.FormatSubmitJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	stack.PrintSubmitResult(os.Stdout, result)
+	return nil
+}
+
+func runStackParent(cmd *cobra.Command, args []string) error {
+	var branch string
+	if len(args) > 0 {
+		branch = args[0]
+	}
+
+	logger.Debug().
+		Str("branch", branch).
+		Str("set", stackParentSet).
+		Bool("unset", stackParentUnset).
+		Msg("Starting stack parent command")
+
+	gitRepo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	result, err := stack.NewParentWorkflow(gitRepo).Execute(&stack.ParentOptions{
+		Branch: branch,
+		Set:    stackParentSet,
+		Unset:  stackParentUnset,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, stack.ErrBranchNotFound), errors.Is(err, stack.ErrParentCycle):
+			fmt.Printf("✗ %v\n", err)
+			return ErrSilentExit
+		}
+		return fmt.Errorf("stack parent failed: %w", err)
+	}
+
+	if GetJSON() {
+		data, err := stack.FormatParentJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	stack.PrintParentResult(os.Stdout, result)
+	return nil
+}
+
+func runStackFold(mode stack.FoldMode) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		var branch string
+		if len(args) > 0 {
+			branch = args[0]
+		}
+
+		logger.Debug().
+			Str("mode", string(mode)).
+			Str("branch", branch).
+			Msg("Starting stack fold command")
+
+		currentRepo, err := repository.Current()
+		if err != nil {
+			return fmt.Errorf("failed to determine current repository: %w", err)
+		}
+
+		gitRepo, err := git.OpenRepository(".")
+		if err != nil {
+			return fmt.Errorf("failed to open git repository: %w", err)
+		}
+
+		client, err := newGitHubClient()
+		if err != nil {
+			return fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+
+		workflow := stack.NewFoldWorkflow(gitRepo, client, currentRepo.Owner, currentRepo.Name)
+
+		result, err := workflow.Execute(ctx, &stack.FoldOptions{
+			Mode:   mode,
+			Branch: branch,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, stack.ErrDirtyWorkingDir):
+				fmt.Println("✗ Working directory has uncommitted changes")
+				fmt.Printf("  Commit or stash them before running stack %s\n", mode)
+				return ErrSilentExit
+			case errors.Is(err, stack.ErrOnTrunk):
+				fmt.Printf("✗ Cannot %s trunk\n", mode)
+				return ErrSilentExit
+			case errors.Is(err, stack.ErrBranchNotFound),
+				errors.Is(err, stack.ErrFoldIntoTrunk),
+				errors.Is(err, stack.ErrFoldDiverged):
+				fmt.Printf("✗ %v\n", err)
+				return ErrSilentExit
+			case errors.Is(err, stack.ErrFoldConflict):
+				fmt.Printf("✗ %v\n", err)
+				fmt.Println("  Every branch was restored and nothing was pushed")
+				return ErrSilentExit
+			}
+			return fmt.Errorf("stack %s failed: %w", mode, err)
+		}
+
+		// The children now stack on the parent, which may be trunk
+		moved := append([]string{result.Parent}, result.Rebased...)
+		moved = append(moved, result.UpToDate...)
+		for _, skip := range result.Skipped {
+			moved = append(moved, skip.Branch)
+		}
+		updateStackLinks(ctx, client, currentRepo.Owner, currentRepo.Name, moved...)
+
+		if GetJSON() {
+			data, err := stack.FormatFoldJSON(result)
+			if err != nil {
+				return fmt.Errorf("failed to format JSON output: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		stack.PrintFoldResult(os.Stdout, result)
+		return nil
+	}
+}
+
+// updateStackLinks rewrites the stack table in the PRs of the stacks that
+// contain branches. The table is a convenience for reviewers, so failures
+// are reported without failing the command.
+func updateStackLinks(ctx context.Context, client *github.Client, owner, name string, branches ...string) {
+	if len(branches) == 0 {
+		return
+	}
+
+	updated, err := stack.NewLinkSyncer(client, owner, name).Sync(ctx, branches...)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to update stack table")
+	}
+	if GetJSON() {
+		return
+	}
+
+	if len(updated) > 0 {
+		numbers := make([]string, len(updated))
+		for i, number := range updated {
+			numbers[i] = fmt.Sprintf("#%d", number)
+		}
+		fmt.Printf("✓ Updated the stack table in %s\n", strings.Join(numbers, ", "))
+	}
+	if err != nil {
+		fmt.Printf("⚠ Failed to update the stack table: %v\n", err)
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestStackCommand(t *testing.T) {
+	t.Run("command initialization", func(t *testing.T) {
+		if stackCmd.Use != "stack" {
+			t.Errorf("Expected Use to be 'stack', got '%s'", stackCmd.Use)
+		}
+
+		if stackCmd.Short == "" {
+			t.Error("Expected Short description to be set")
+		}
+
+		if stackCmd.RunE == nil {
+			t.Error("Expected RunE to be set")
+		}
+	})
+
+	t.Run("stack command is registered", func(t *testing.T) {
+		found := false
+		for _, cmd := range rootCmd.Commands() {
+			if cmd.Name() == "stack" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected stack command to be registered with root command")
+		}
+	})
+
+	t.Run("show subcommand", func(t *testing.T) {
+		found := false
+		for _, cmd := range stackCmd.Commands() {
+			if cmd.Name() == "show" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected show subcommand to be registered with stack command")
+		}
+
+		if stackShowCmd.RunE == nil {
+			t.Error("Expected show RunE to be set")
+		}
+	})
+
+	t.Run("restack subcommand", func(t *testing.T) {
+		found := false
+		for _, cmd := range stackCmd.Commands() {
+			if cmd.Name() == "restack" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected restack subcommand to be registered with stack command")
+		}
+
+		if stackRestackCmd.RunE == nil {
+			t.Error("Expected restack RunE to be set")
+		}
+
+		if err := stackRestackCmd.Args(stackRestackCmd, []string{"a", "b"}); err == nil {
+			t.Error("Expected restack to accept at most one branch")
+		}
+
+		for _, name := range []string{"continue", "abort"} {
+			if stackRestackCmd.Flags().Lookup(name) == nil {
+				t.Errorf("Expected --%s flag to be defined on stack restack", name)
+			}
+		}
+	})
+
+	t.Run("submit subcommand", func(t *testing.T) {
+		found := false
+		for _, cmd := range stackCmd.Commands() {
+			if cmd.Name() == "submit" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected submit subcommand to be registered with stack command")
+		}
+
+		if stackSubmitCmd.RunE == nil {
+			t.Error("Expected submit RunE to be set")
+		}
+
+		for _, name := range []string{"no-edit", "draft", "ready"} {
+			if stackSubmitCmd.Flags().Lookup(name) == nil {
+				t.Errorf("Expected --%s flag to be defined on stack submit", name)
+			}
+		}
+	})
+
+	t.Run("parent subcommand", func(t *testing.T) {
+		found := false
+		for _, cmd := range stackCmd.Commands() {
+			if cmd.Name() == "parent" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected parent subcommand to be registered with stack command")
+		}
+
+		if stackParentCmd.RunE == nil {
+			t.Error("Expected parent RunE to be set")
+		}
+
+		if err := stackParentCmd.Args(stackParentCmd, []string{"a", "b"}); err == nil {
+			t.Error("Expected parent to accept at most one branch")
+		}
+
+		for _, name := range []string{"set", "unset"} {
+			if stackParentCmd.Flags().Lookup(name) == nil {
+				t.Errorf("Expected --%s flag to be defined on stack parent", name)
+			}
+		}
+	})
+
+	t.Run("fold and drop subcommands", func(t *testing.T) {
+		for _, want := range []*cobra.Command{stackFoldCmd, stackDropCmd} {
+			found := false
+			for _, cmd := range stackCmd.Commands() {
+				if cmd == want {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				t.Errorf("Expected %s subcommand to be registered with stack command", want.Name())
+			}
+
+			if want.RunE == nil {
+				t.Errorf("Expected %s RunE to be set", want.Name())
+			}
+
+			if err := want.Args(want, []string{"a", "b"}); err == nil {
+				t.Errorf("Expected %s to accept at most one branch", want.Name())
+			}
+		}
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		if stackCmd.Flags().Lookup("all") == nil {
+			t.Error("Expected --all flag to be defined on stack")
+		}
+		if stackShowCmd.Flags().Lookup("all") == nil {
+			t.Error("Expected --all flag to be defined on stack show")
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/runner"
+	"github.com/serpro69/gh-arc/internal/unit"
+)
+
+var unitCmd = &cobra.Command{
+	Use:   "unit",
+	Short: "Run configured test runners",
+	Args:  cobra.NoArgs,
+	Long: `Run every test runner configured in test.runners.
+
+Runners execute sequentially with their output streamed as-is, followed by a
+summary that distinguishes passed, failed, timed out, and command-not-found
+runners. All runners execute even if an earlier one fails.
+
+Each runner may set a timeout using Go duration syntax (e.g. "30s", "5m").
+A runner that exceeds its timeout is killed and reported as timed out.
+
+  "test": {
+    "runners": [
+      { "name": "go-test", "command": "go", "args": ["test", "./..."], "timeout": "5m" }
+    ]
+  }
+
+Exit status is 1 if any runner does not pass.
+
+Examples:
+  # Run all configured test runners
+  gh arc unit
+
+  # Machine-readable results
+  gh arc unit --json`,
+	RunE: runUnit,
+}
+
+func init() {
+	rootCmd.AddCommand(unitCmd)
+}
+
+func runUnit(cmd *cobra.Command, args []string) error {
+	// Cancel on Ctrl-C so running tests (in their own process group) are stopped
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	logger.Debug().Msg("Starting unit command")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	jsonMode := GetJSON()
+	engine := runner.NewEngine(runner.EngineOptions{
+		JSONMode: jsonMode,
+		Verbose:  GetVerbose(),
+	})
+
+	// Working directories are relative to the repository root, if there is one
+	var repo unit.UnitRepository
+	if gitRepo, err := git.OpenRepository("."); err == nil {
+		repo = gitRepo
+	} else {
+		logger.Debug().Err(err).Msg("Not in a git repository, running tests from the current directory")
+	}
+
+	workflow := unit.NewUnitWorkflow(repo, engine, cfg)
+
+	result, err := workflow.Execute(ctx, &unit.UnitOptions{
+		JSONMode: jsonMode,
+		Verbose:  GetVerbose(),
+	})
+	if err != nil {
+		return fmt.Errorf("unit failed: %w", err)
+	}
+
+	return reportRunnerResult(result.ExecutionResult, "unit", jsonMode)
+}
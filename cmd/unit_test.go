@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestUnitCommand(t *testing.T) {
+	t.Run("command initialization", func(t *testing.T) {
+		if unitCmd.Use != "unit" {
+			t.Errorf("Expected Use to be 'unit', got '%s'", unitCmd.Use)
+		}
+
+		if unitCmd.Short == "" {
+			t.Error("Expected Short description to be set")
+		}
+
+		if unitCmd.RunE == nil {
+			t.Error("Expected RunE to be set")
+		}
+	})
+
+	t.Run("unit command is registered", func(t *testing.T) {
+		found := false
+		for _, cmd := range rootCmd.Commands() {
+			if cmd.Name() == "unit" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected unit command to be registered with root command")
+		}
+	})
+
+	t.Run("rejects positional arguments", func(t *testing.T) {
+		if err := unitCmd.Args(unitCmd, []string{"./..."}); err == nil {
+			t.Error("Expected error for positional arguments")
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/diff"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/work"
+)
+
+var workStack bool
+
+var workCmd = &cobra.Command{
+	Use:   "work [name]",
+	Short: "Start a new short-lived branch from an up-to-date trunk",
+	Args:  cobra.MaximumNArgs(1),
+	Long: `Start a new feature branch and switch to it.
+
+The branch is created from origin/<default branch> after fetching origin, so
+it starts from the latest trunk regardless of the state of your local copy.
+If origin cannot be fetched and the remote tracking branch is stale, you are
+asked whether to continue.
+
+Without a name, the branch is named by diff.autoBranchNamePattern, the same
+pattern used when 'gh arc diff' moves commits off main.
+
+With --stack the branch is created from the current feature branch instead,
+for a change that depends on it.
+
+The branch it was started from is recorded, so 'gh arc diff' targets the
+right base straight away.
+
+Examples:
+  # Start a branch from the latest origin/main
+  gh arc work feature/login
+
+  # Start a branch named by the configured pattern
+  gh arc work
+
+  # Start a branch stacked on the current one
+  gh arc work --stack feature/login-tests`,
+	RunE: runWork,
+}
+
+func init() {
+	rootCmd.AddCommand(workCmd)
+
+	workCmd.Flags().BoolVar(&workStack, "stack", false, "Branch from the current feature branch instead of trunk")
+}
+
+func runWork(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	logger.Debug().
+		Str("name", name).
+		Bool("stack", workStack).
+		Msg("Starting work command")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	gitRepo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	namer := diff.NewAutoBranchDetector(gitRepo, &cfg.Diff)
+	workflow := work.NewWorkWorkflow(gitRepo, namer)
+
+	result, err := workflow.Execute(ctx, &work.WorkOptions{
+		Name:  name,
+		Stack: workStack,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, work.ErrBranchExists):
+			fmt.Printf("✗ Branch %s already exists\n", name)
+			fmt.Printf("  Switch to it with 'git checkout %s'\n", name)
+			return ErrSilentExit
+		case errors.Is(err, work.ErrStackOnTrunk):
+			fmt.Println("✗ Cannot stack on the default branch")
+			fmt.Println("  Check out the feature branch to stack on, or drop --stack")
+			return ErrSilentExit
+		case errors.Is(err, diff.ErrStaleRemote):
+			fmt.Println("\n✗ Operation aborted due to stale remote tracking branch.")
+			fmt.Println("Please update your local repository:")
+			fmt.Printf("  git fetch origin\n")
+			return ErrSilentExit
+		}
+		return fmt.Errorf("work failed: %w", err)
+	}
+
+	if GetJSON() {
+		data, err := work.FormatJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	work.PrintResult(os.Stdout, result)
+	return nil
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestWorkCommand(t *testing.T) {
+	t.Run("command initialization", func(t *testing.T) {
+		if workCmd.Use != "work [name]" {
+			t.Errorf("Expected Use to be 'work [name]', got '%s'", workCmd.Use)
+		}
+
+		if workCmd.Short == "" {
+			t.Error("Expected Short description to be set")
+		}
+
+		if workCmd.RunE == nil {
+			t.Error("Expected RunE to be set")
+		}
+	})
+
+	t.Run("work command is registered", func(t *testing.T) {
+		found := false
+		for _, cmd := range rootCmd.Commands() {
+			if cmd.Name() == "work" {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Error("Expected work command to be registered with root command")
+		}
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		if workCmd.Flags().Lookup("stack") == nil {
+			t.Error("Expected --stack flag to be defined")
+		}
+
+		if err := workCmd.Args(workCmd, []string{}); err != nil {
+			t.Errorf("Expected no error without a name, got %v", err)
+		}
+		if err := workCmd.Args(workCmd, []string{"a", "b"}); err == nil {
+			t.Error("Expected error for more than one argument")
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package amend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/stack"
+	"github.com/serpro69/gh-arc/internal/template"
+)
+
+const (
+	// PRTrailer is the trailer key linking a commit to its pull request,
+	// after Arcanist's "Differential Revision:".
+	PRTrailer = "Differential-PR"
+
+	// ReviewedByTrailer is the trailer key recording an approving reviewer.
+	ReviewedByTrailer = "Reviewed-by"
+
+	// Headings and markers written into PR bodies by gh arc diff
+	testPlanHeading = "## Test Plan"
+	checksHeading   = "## Checks"
+	refPrefix       = "**Ref:**"
+)
+
+// FieldsFromPR recovers the structured template fields from a PR created by
+// gh arc diff. The body is converted back into template form and parsed
+// with template.ParseTemplate, so lines starting with # are dropped just as
+// they are when the template is edited. The checks block and the stack table
+// are not part of the commit message and are skipped.
+func FieldsFromPR(pr *github.PullRequest) (*template.TemplateFields, error) {
+	var summary, testPlan, refs []string
+	section := &summary
+	inChecks := false
+
+	body := stack.RemoveStackLinks(strings.ReplaceAll(pr.Body, "\r\n", "\n"))
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if inChecks {
+			// The checks block ends at the first blank line
+			if trimmed == "" {
+				inChecks = false
+			}
+			continue
+		}
+
+		switch {
+		case trimmed == testPlanHeading:
+			section = &testPlan
+		case trimmed == checksHeading:
+			inChecks = true
+		case strings.HasPrefix(trimmed, refPrefix):
+			refs = append(refs, strings.TrimSpace(strings.TrimPrefix(trimmed, refPrefix)))
+		default:
+			*section = append(*section, line)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Title:\n" + pr.Title + "\n\n")
+	sb.WriteString("# Summary:\n" + strings.Join(summary, "\n") + "\n\n")
+	sb.WriteString("# Test Plan:\n" + strings.Join(testPlan, "\n") + "\n\n")
+	sb.WriteString("# Ref:\n" + strings.Join(refs, ", ") + "\n")
+
+	return template.ParseTemplate(sb.String())
+}
+
+// BuildMessage assembles an Arcanist-style commit message from template
+// fields, followed by a Reviewed-by trailer per approver and the PR trailer.
+func BuildMessage(fields *template.TemplateFields, approvers []string, prURL string) string {
+	var sb strings.Builder
+	sb.WriteString(fields.Title)
+
+	if fields.Summary != "" {
+		sb.WriteString("\n\nSummary:\n" + fields.Summary)
+	}
+	if fields.TestPlan != "" {
+		sb.WriteString("\n\nTest Plan:\n" + fields.TestPlan)
+	}
+	if len(fields.Ref) > 0 {
+		sb.WriteString("\n\nRef: " + strings.Join(fields.Ref, ", "))
+	}
+
+	sb.WriteString("\n")
+	for _, login := range approvers {
+		sb.WriteString(fmt.Sprintf("\n%s: %s <%s@users.noreply.github.com>", ReviewedByTrailer, login, login))
+	}
+	if prURL != "" {
+		sb.WriteString(fmt.Sprintf("\n%s: %s", PRTrailer, prURL))
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// Approvers returns the sorted logins whose latest actionable review is an
+// approval. COMMENTED, DISMISSED and PENDING reviews do not override a prior
+// approval or change request.
+func Approvers(reviews []github.PRReview) []string {
+	latest := make(map[string]github.PRReview)
+	for _, r := range reviews {
+		if r.State != "APPROVED" && r.State != "CHANGES_REQUESTED" {
+			continue
+		}
+		if existing, ok := latest[r.User.Login]; !ok || r.SubmittedAt.After(existing.SubmittedAt) {
+			latest[r.User.Login] = r
+		}
+	}
+
+	approvers := []string{}
+	for login, r := range latest {
+		if r.State == "APPROVED" {
+			approvers = append(approvers, login)
+		}
+	}
+	sort.Strings(approvers)
+	return approvers
+}
@@ -0,0 +1,123 @@
+package amend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/stack"
+	"github.com/serpro69/gh-arc/internal/template"
+)
+
+func TestFieldsFromPR(t *testing.T) {
+	t.Run("body written by gh arc diff", func(t *testing.T) {
+		pr := &github.PullRequest{
+			Title: "Add widget",
+			Body: "Adds the widget.\n\nIt is blue.\n\n" +
+				"## Test Plan\nRan the widget tests.\n\n" +
+				"## Checks\n- ✓ lint\n- ✓ unit\n\n" +
+				"**Ref:** ENG-123",
+		}
+
+		fields, err := FieldsFromPR(pr)
+		require.NoError(t, err)
+		assert.Equal(t, "Add widget", fields.Title)
+		assert.Equal(t, "Adds the widget.\n\nIt is blue.", fields.Summary)
+		assert.Equal(t, "Ran the widget tests.", fields.TestPlan)
+		assert.Equal(t, []string{"ENG-123"}, fields.Ref)
+	})
+
+	t.Run("summary only with CRLF line endings", func(t *testing.T) {
+		pr := &github.PullRequest{Title: "Fix", Body: "Line one\r\nLine two"}
+
+		fields, err := FieldsFromPR(pr)
+		require.NoError(t, err)
+		assert.Equal(t, "Line one\nLine two", fields.Summary)
+		assert.Empty(t, fields.TestPlan)
+		assert.Empty(t, fields.Ref)
+	})
+
+	t.Run("checks block without test plan", func(t *testing.T) {
+		pr := &github.PullRequest{Title: "Fix", Body: "Summary\n\n## Checks\n- ✓ lint"}
+
+		fields, err := FieldsFromPR(pr)
+		require.NoError(t, err)
+		assert.Equal(t, "Summary", fields.Summary)
+		assert.Empty(t, fields.TestPlan)
+	})
+
+	t.Run("stack table is skipped", func(t *testing.T) {
+		table := stack.FormatStackLinks([]stack.StackLink{
+			{Number: 1, Title: "Add widget", State: "Open"},
+			{Number: 2, Title: "Paint widget", State: "Open", Depth: 1},
+		}, 1)
+		pr := &github.PullRequest{
+			Title: "Add widget",
+			Body:  "Adds the widget.\n\n## Test Plan\nRan the tests.\n\n" + table,
+		}
+
+		fields, err := FieldsFromPR(pr)
+		require.NoError(t, err)
+		assert.Equal(t, "Adds the widget.", fields.Summary)
+		assert.Equal(t, "Ran the tests.", fields.TestPlan)
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		fields, err := FieldsFromPR(&github.PullRequest{Title: "Fix"})
+		require.NoError(t, err)
+		assert.Equal(t, "Fix", fields.Title)
+		assert.Empty(t, fields.Summary)
+	})
+}
+
+func TestBuildMessage(t *testing.T) {
+	t.Run("all fields", func(t *testing.T) {
+		fields := &template.TemplateFields{
+			Title:    "Add widget",
+			Summary:  "Adds the widget.",
+			TestPlan: "Ran the tests.",
+			Ref:      []string{"ENG-1", "ENG-2"},
+		}
+
+		msg := BuildMessage(fields, []string{"alice", "bob"}, "https://github.com/o/r/pull/7")
+		assert.Equal(t, "Add widget\n\n"+
+			"Summary:\nAdds the widget.\n\n"+
+			"Test Plan:\nRan the tests.\n\n"+
+			"Ref: ENG-1, ENG-2\n\n"+
+			"Reviewed-by: alice <alice@users.noreply.github.com>\n"+
+			"Reviewed-by: bob <bob@users.noreply.github.com>\n"+
+			"Differential-PR: https://github.com/o/r/pull/7", msg)
+	})
+
+	t.Run("title and trailer only", func(t *testing.T) {
+		msg := BuildMessage(&template.TemplateFields{Title: "Fix"}, nil, "https://github.com/o/r/pull/7")
+		assert.Equal(t, "Fix\n\nDifferential-PR: https://github.com/o/r/pull/7", msg)
+	})
+
+	t.Run("title only", func(t *testing.T) {
+		assert.Equal(t, "Fix", BuildMessage(&template.TemplateFields{Title: "Fix"}, nil, ""))
+	})
+}
+
+func TestApprovers(t *testing.T) {
+	now := time.Now()
+	review := func(login, state string, offset time.Duration) github.PRReview {
+		return github.PRReview{User: github.PRUser{Login: login}, State: state, SubmittedAt: now.Add(offset)}
+	}
+
+	reviews := []github.PRReview{
+		review("carol", "APPROVED", 0),
+		review("alice", "CHANGES_REQUESTED", -time.Hour),
+		review("alice", "APPROVED", 0),
+		review("alice", "COMMENTED", time.Hour),
+		review("bob", "APPROVED", -time.Hour),
+		review("bob", "CHANGES_REQUESTED", 0),
+		review("dave", "COMMENTED", 0),
+	}
+
+	assert.Equal(t, []string{"alice", "carol"}, Approvers(reviews))
+	assert.Equal(t, []string{}, Approvers(nil))
+}
@@ -0,0 +1,39 @@
+package amend
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PrintResult writes a human-readable report of the amend result. With show
+// set only the rebuilt message is written, so it can be piped elsewhere.
+func PrintResult(out io.Writer, result *AmendResult, show bool) {
+	if show {
+		fmt.Fprintln(out, result.Message)
+		return
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(out, "⚠ %s\n", warning)
+	}
+
+	if result.Unchanged {
+		fmt.Fprintf(out, "✓ HEAD already matches PR #%d\n", result.PRNumber)
+		return
+	}
+
+	fmt.Fprintf(out, "✓ Amended HEAD with metadata from PR #%d\n", result.PRNumber)
+	if len(result.ReviewedBy) > 0 {
+		fmt.Fprintf(out, "  Reviewed by: @%s\n", strings.Join(result.ReviewedBy, ", @"))
+	}
+	if result.Pushed {
+		fmt.Fprintln(out, "⚠ HEAD was already pushed; update the PR with 'git push --force-with-lease'")
+	}
+}
+
+// FormatJSON returns the result as indented JSON.
+func FormatJSON(result *AmendResult) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
@@ -0,0 +1,43 @@
+package amend
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintResult(t *testing.T) {
+	t.Run("show prints only the message", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintResult(&buf, &AmendResult{Message: "Fix\n\nDifferential-PR: url", Warnings: []string{"w"}}, true)
+		assert.Equal(t, "Fix\n\nDifferential-PR: url\n", buf.String())
+	})
+
+	t.Run("amended", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintResult(&buf, &AmendResult{PRNumber: 7, Amended: true, ReviewedBy: []string{"alice", "bob"}, Pushed: true}, false)
+		out := buf.String()
+		assert.Contains(t, out, "✓ Amended HEAD with metadata from PR #7")
+		assert.Contains(t, out, "Reviewed by: @alice, @bob")
+		assert.Contains(t, out, "--force-with-lease")
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintResult(&buf, &AmendResult{PRNumber: 7, Unchanged: true}, false)
+		assert.Equal(t, "✓ HEAD already matches PR #7\n", buf.String())
+	})
+}
+
+func TestFormatJSON(t *testing.T) {
+	data, err := FormatJSON(&AmendResult{PRNumber: 7, ReviewedBy: []string{"alice"}})
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, float64(7), decoded["pr_number"])
+	assert.Equal(t, []interface{}{"alice"}, decoded["reviewed_by"])
+}
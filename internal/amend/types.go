@@ -0,0 +1,20 @@
+package amend
+
+// AmendOptions contains options for the amend command
+type AmendOptions struct {
+	Show bool // Print the rebuilt message without amending
+}
+
+// AmendResult contains the rebuilt commit message and what was done with it.
+type AmendResult struct {
+	Branch     string   `json:"branch"`
+	PRNumber   int      `json:"pr_number"`
+	PRURL      string   `json:"pr_url"`
+	Message    string   `json:"message"`
+	OldMessage string   `json:"old_message"`
+	ReviewedBy []string `json:"reviewed_by"`        // GitHub logins of approving reviewers
+	Amended    bool     `json:"amended"`            // HEAD was rewritten
+	Unchanged  bool     `json:"unchanged"`          // HEAD already carries the rebuilt message
+	Pushed     bool     `json:"pushed"`             // HEAD is the PR head, so amending diverges from the remote
+	Warnings   []string `json:"warnings,omitempty"` // Non-fatal problems, e.g. reviews could not be fetched
+}
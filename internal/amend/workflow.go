@@ -0,0 +1,134 @@
+package amend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+var (
+	// ErrNoPR is returned when the current branch has no open pull request.
+	ErrNoPR = errors.New("no pull request found for current branch")
+
+	// ErrEmptyTitle is returned when the PR has no title to use as subject.
+	ErrEmptyTitle = errors.New("pull request has no title")
+)
+
+// AmendRepository defines git operations needed by the amend workflow.
+type AmendRepository interface {
+	GetCurrentBranch() (string, error)
+	GetHeadSHA() (string, error)
+	GetCommitMessage(ref string) (string, error)
+	AmendCommitMessage(message string) error
+}
+
+// AmendGitHubClient defines GitHub operations needed by the amend workflow.
+type AmendGitHubClient interface {
+	FindExistingPRForCurrentBranch(ctx context.Context, branchName string) (*github.PullRequest, error)
+	GetPullRequestReviews(ctx context.Context, owner, repo string, number int) ([]github.PRReview, error)
+}
+
+// AmendWorkflow rewrites the HEAD commit message from the reviewed metadata
+// of the branch's pull request.
+type AmendWorkflow struct {
+	repo   AmendRepository
+	client AmendGitHubClient
+	owner  string
+	name   string
+}
+
+// NewAmendWorkflow creates a new AmendWorkflow.
+func NewAmendWorkflow(repo AmendRepository, client AmendGitHubClient, owner, name string) *AmendWorkflow {
+	return &AmendWorkflow{
+		repo:   repo,
+		client: client,
+		owner:  owner,
+		name:   name,
+	}
+}
+
+// Execute runs the amend workflow.
+func (w *AmendWorkflow) Execute(ctx context.Context, opts *AmendOptions) (*AmendResult, error) {
+	if opts == nil {
+		opts = &AmendOptions{}
+	}
+
+	logger.Debug().
+		Bool("show", opts.Show).
+		Msg("Executing amend workflow")
+
+	// Step 1: Find the PR for the current branch
+	branch, err := w.repo.GetCurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	pr, err := w.client.FindExistingPRForCurrentBranch(ctx, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pull request: %w", err)
+	}
+	if pr == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoPR, branch)
+	}
+	if pr.Title == "" {
+		return nil, fmt.Errorf("%w: PR #%d", ErrEmptyTitle, pr.Number)
+	}
+
+	result := &AmendResult{
+		Branch:     branch,
+		PRNumber:   pr.Number,
+		PRURL:      pr.HTMLURL,
+		ReviewedBy: []string{},
+	}
+
+	// Step 2: Collect approvers. Missing reviews only lose the trailers.
+	reviews, err := w.client.GetPullRequestReviews(ctx, w.owner, w.name, pr.Number)
+	if err != nil {
+		logger.Warn().Err(err).Int("pr", pr.Number).Msg("Failed to fetch PR reviews")
+		result.Warnings = append(result.Warnings, "could not fetch reviews; Reviewed-by trailers omitted")
+	} else {
+		result.ReviewedBy = Approvers(reviews)
+	}
+
+	// Step 3: Rebuild the message from the PR fields
+	fields, err := FieldsFromPR(pr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pull request fields: %w", err)
+	}
+	result.Message = BuildMessage(fields, result.ReviewedBy, pr.HTMLURL)
+
+	result.OldMessage, err = w.repo.GetCommitMessage("HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if result.OldMessage == result.Message {
+		result.Unchanged = true
+		return result, nil
+	}
+
+	if opts.Show {
+		return result, nil
+	}
+
+	// Step 4: Amend HEAD
+	head, err := w.repo.GetHeadSHA()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	result.Pushed = head == pr.Head.SHA
+
+	if err := w.repo.AmendCommitMessage(result.Message); err != nil {
+		return nil, err
+	}
+	result.Amended = true
+
+	logger.Info().
+		Int("pr", pr.Number).
+		Int("reviewedBy", len(result.ReviewedBy)).
+		Msg("Amended HEAD commit message from PR")
+
+	return result, nil
+}
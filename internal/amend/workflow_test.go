@@ -0,0 +1,133 @@
+package amend
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+type mockAmendRepo struct {
+	branch  string
+	head    string
+	message string
+	amended []string
+}
+
+func (m *mockAmendRepo) GetCurrentBranch() (string, error) { return m.branch, nil }
+func (m *mockAmendRepo) GetHeadSHA() (string, error)       { return m.head, nil }
+
+func (m *mockAmendRepo) GetCommitMessage(ref string) (string, error) { return m.message, nil }
+
+func (m *mockAmendRepo) AmendCommitMessage(message string) error {
+	m.amended = append(m.amended, message)
+	m.message = message
+	return nil
+}
+
+type mockAmendClient struct {
+	pr         *github.PullRequest
+	reviews    []github.PRReview
+	reviewsErr error
+}
+
+func (m *mockAmendClient) FindExistingPRForCurrentBranch(ctx context.Context, branchName string) (*github.PullRequest, error) {
+	return m.pr, nil
+}
+
+func (m *mockAmendClient) GetPullRequestReviews(ctx context.Context, owner, repo string, number int) ([]github.PRReview, error) {
+	return m.reviews, m.reviewsErr
+}
+
+func newTestPR() *github.PullRequest {
+	return &github.PullRequest{
+		Number:  42,
+		Title:   "Add widget",
+		Body:    "Adds the widget.\n\n## Test Plan\nRan it.",
+		HTMLURL: "https://github.com/owner/repo/pull/42",
+		Head:    github.PRBranch{Ref: "feature", SHA: "pushed"},
+	}
+}
+
+const expectedMessage = "Add widget\n\nSummary:\nAdds the widget.\n\nTest Plan:\nRan it.\n\n" +
+	"Reviewed-by: alice <alice@users.noreply.github.com>\n" +
+	"Differential-PR: https://github.com/owner/repo/pull/42"
+
+func TestAmendWorkflow(t *testing.T) {
+	approved := []github.PRReview{{User: github.PRUser{Login: "alice"}, State: "APPROVED"}}
+
+	t.Run("amends HEAD", func(t *testing.T) {
+		repo := &mockAmendRepo{branch: "feature", head: "local", message: "wip"}
+		client := &mockAmendClient{pr: newTestPR(), reviews: approved}
+
+		result, err := NewAmendWorkflow(repo, client, "owner", "repo").Execute(context.Background(), nil)
+		require.NoError(t, err)
+		assert.True(t, result.Amended)
+		assert.False(t, result.Pushed)
+		assert.Equal(t, "wip", result.OldMessage)
+		assert.Equal(t, []string{expectedMessage}, repo.amended)
+		assert.Equal(t, []string{"alice"}, result.ReviewedBy)
+	})
+
+	t.Run("show does not amend", func(t *testing.T) {
+		repo := &mockAmendRepo{branch: "feature", head: "local", message: "wip"}
+		client := &mockAmendClient{pr: newTestPR(), reviews: approved}
+
+		result, err := NewAmendWorkflow(repo, client, "owner", "repo").Execute(context.Background(), &AmendOptions{Show: true})
+		require.NoError(t, err)
+		assert.False(t, result.Amended)
+		assert.Equal(t, expectedMessage, result.Message)
+		assert.Empty(t, repo.amended)
+	})
+
+	t.Run("unchanged message is not rewritten", func(t *testing.T) {
+		repo := &mockAmendRepo{branch: "feature", head: "local", message: expectedMessage}
+		client := &mockAmendClient{pr: newTestPR(), reviews: approved}
+
+		result, err := NewAmendWorkflow(repo, client, "owner", "repo").Execute(context.Background(), nil)
+		require.NoError(t, err)
+		assert.True(t, result.Unchanged)
+		assert.False(t, result.Amended)
+		assert.Empty(t, repo.amended)
+	})
+
+	t.Run("pushed HEAD is flagged", func(t *testing.T) {
+		repo := &mockAmendRepo{branch: "feature", head: "pushed", message: "wip"}
+		client := &mockAmendClient{pr: newTestPR(), reviews: approved}
+
+		result, err := NewAmendWorkflow(repo, client, "owner", "repo").Execute(context.Background(), nil)
+		require.NoError(t, err)
+		assert.True(t, result.Pushed)
+	})
+
+	t.Run("review fetch failure only drops trailers", func(t *testing.T) {
+		repo := &mockAmendRepo{branch: "feature", head: "local", message: "wip"}
+		client := &mockAmendClient{pr: newTestPR(), reviewsErr: errors.New("boom")}
+
+		result, err := NewAmendWorkflow(repo, client, "owner", "repo").Execute(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Len(t, result.Warnings, 1)
+		assert.NotContains(t, result.Message, "Reviewed-by")
+		assert.Contains(t, result.Message, "Differential-PR:")
+	})
+
+	t.Run("no PR", func(t *testing.T) {
+		repo := &mockAmendRepo{branch: "feature"}
+
+		_, err := NewAmendWorkflow(repo, &mockAmendClient{}, "owner", "repo").Execute(context.Background(), nil)
+		assert.ErrorIs(t, err, ErrNoPR)
+	})
+
+	t.Run("PR without title", func(t *testing.T) {
+		repo := &mockAmendRepo{branch: "feature"}
+		pr := newTestPR()
+		pr.Title = ""
+
+		_, err := NewAmendWorkflow(repo, &mockAmendClient{pr: pr}, "owner", "repo").Execute(context.Background(), nil)
+		assert.ErrorIs(t, err, ErrEmptyTitle)
+	})
+}
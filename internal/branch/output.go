@@ -0,0 +1,81 @@
+package branch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/serpro69/gh-arc/internal/format"
+)
+
+// PrintResult writes the branch table followed by the cleanup report, if any.
+func PrintResult(out io.Writer, result *BranchResult, useColor bool) {
+	if len(result.Branches) == 0 {
+		fmt.Fprintln(out, "No local branches")
+		return
+	}
+
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"", "Branch", "PR", "Parent", "Ahead/Behind", "Review", "Checks"})
+	table.SetBorder(false)
+	table.SetColumnSeparator("")
+	table.SetCenterSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+
+	for _, entry := range result.Branches {
+		marker := ""
+		if entry.Current {
+			marker = "*"
+		}
+
+		pr, review, checks := "-", "", ""
+		if entry.PRNumber > 0 {
+			pr = fmt.Sprintf("#%d %s", entry.PRNumber, entry.PRState)
+		}
+		if entry.ReviewStatus != "" {
+			review = format.ReviewStatusLabel(entry.ReviewStatus, useColor)
+		}
+		if entry.CheckStatus != "" {
+			checks = format.CheckStatusLabel(entry.CheckStatus, useColor)
+		}
+
+		aheadBehind := ""
+		if entry.Name != result.DefaultBranch {
+			aheadBehind = fmt.Sprintf("↑%d ↓%d", entry.Ahead, entry.Behind)
+		}
+
+		table.Append([]string{marker, entry.Name, pr, entry.Parent, aheadBehind, review, checks})
+	}
+
+	table.Render()
+
+	printCleanup(out, result)
+}
+
+func printCleanup(out io.Writer, result *BranchResult) {
+	if len(result.Deleted) == 0 && len(result.CleanupSkipped) == 0 {
+		return
+	}
+
+	fmt.Fprintln(out)
+	for _, name := range result.Deleted {
+		if result.DryRun {
+			fmt.Fprintf(out, "Would delete %s (PR merged)\n", name)
+		} else {
+			fmt.Fprintf(out, "✓ Deleted %s (PR merged)\n", name)
+		}
+	}
+	for _, skip := range result.CleanupSkipped {
+		fmt.Fprintf(out, "⚠ Kept %s: %s\n", skip.Branch, skip.Reason)
+	}
+}
+
+// FormatJSON returns the result as indented JSON.
+func FormatJSON(result *BranchResult) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
@@ -0,0 +1,80 @@
+package branch
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleResult() *BranchResult {
+	return &BranchResult{
+		DefaultBranch: "main",
+		CompareRef:    "origin/main",
+		Branches: []BranchEntry{
+			{Name: "done", PRNumber: 9, PRState: PRStateMerged},
+			{Name: "feature/a", Ahead: 3, Behind: 1, PRNumber: 10, PRState: PRStateOpen, ReviewStatus: "approved", CheckStatus: "success"},
+			{Name: "feature/b", Current: true, Ahead: 1, PRNumber: 11, PRState: PRStateDraft, Parent: "feature/a", ReviewStatus: "review_required", CheckStatus: "pending"},
+			{Name: "main"},
+		},
+	}
+}
+
+func TestPrintResult(t *testing.T) {
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintResult(&buf, sampleResult(), false)
+		out := buf.String()
+
+		assert.Contains(t, out, "BRANCH")
+		assert.Contains(t, out, "#10 open")
+		assert.Contains(t, out, "#11 draft")
+		assert.Contains(t, out, "#9 merged")
+		assert.Contains(t, out, "↑3 ↓1")
+		assert.Contains(t, out, "feature/a")
+		assert.Contains(t, out, "*")
+		assert.NotContains(t, out, "Deleted")
+	})
+
+	t.Run("cleanup report", func(t *testing.T) {
+		result := sampleResult()
+		result.Deleted = []string{"done"}
+		result.CleanupSkipped = []CleanupSkip{{Branch: "old", Reason: "has commits not in merged PR #3"}}
+
+		var buf bytes.Buffer
+		PrintResult(&buf, result, false)
+		out := buf.String()
+
+		assert.Contains(t, out, "✓ Deleted done (PR merged)")
+		assert.Contains(t, out, "⚠ Kept old: has commits not in merged PR #3")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		result := sampleResult()
+		result.Deleted = []string{"done"}
+		result.DryRun = true
+
+		var buf bytes.Buffer
+		PrintResult(&buf, result, false)
+		assert.Contains(t, buf.String(), "Would delete done")
+	})
+
+	t.Run("no branches", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintResult(&buf, &BranchResult{}, false)
+		assert.Equal(t, "No local branches\n", buf.String())
+	})
+}
+
+func TestFormatJSON(t *testing.T) {
+	data, err := FormatJSON(sampleResult())
+	require.NoError(t, err)
+
+	var decoded BranchResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Len(t, decoded.Branches, 4)
+	assert.Equal(t, "feature/a", decoded.Branches[2].Parent)
+	assert.NotContains(t, string(data), "\"deleted\"")
+}
@@ -0,0 +1,49 @@
+package branch
+
+import "github.com/serpro69/gh-arc/internal/github"
+
+// PR states reported for a branch
+const (
+	PRStateOpen   = "open"
+	PRStateDraft  = "draft"
+	PRStateMerged = "merged"
+)
+
+// BranchOptions contains options for the branch command
+type BranchOptions struct {
+	Cleanup bool // Delete local branches whose PRs are merged
+	DryRun  bool // With Cleanup, report what would be deleted without deleting
+}
+
+// BranchEntry is a local branch joined with its pull request.
+type BranchEntry struct {
+	Name         string              `json:"name"`
+	Current      bool                `json:"current"`
+	SHA          string              `json:"sha"`
+	Parent       string              `json:"parent,omitempty"` // Stack parent (PR base) when not the default branch
+	Ahead        int                 `json:"ahead"`            // Commits not on the default branch
+	Behind       int                 `json:"behind"`           // Default branch commits not on this branch
+	PRNumber     int                 `json:"pr_number,omitempty"`
+	PRState      string              `json:"pr_state,omitempty"` // open, draft, merged
+	PRTitle      string              `json:"pr_title,omitempty"`
+	PRURL        string              `json:"pr_url,omitempty"`
+	ReviewStatus string              `json:"review_status,omitempty"` // From github.DeterminePRStatus, open PRs only
+	CheckStatus  string              `json:"check_status,omitempty"`  // From github.DeterminePRStatus, open PRs only
+	PR           *github.PullRequest `json:"-"`
+}
+
+// CleanupSkip records a merged branch that was not deleted, and why.
+type CleanupSkip struct {
+	Branch string `json:"branch"`
+	Reason string `json:"reason"`
+}
+
+// BranchResult contains the branch listing and any cleanup outcome.
+type BranchResult struct {
+	DefaultBranch  string        `json:"default_branch"`
+	CompareRef     string        `json:"compare_ref"` // Ref used for ahead/behind counts
+	Branches       []BranchEntry `json:"branches"`
+	Deleted        []string      `json:"deleted,omitempty"`
+	CleanupSkipped []CleanupSkip `json:"cleanup_skipped,omitempty"`
+	DryRun         bool          `json:"dry_run,omitempty"`
+}
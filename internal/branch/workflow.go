@@ -0,0 +1,220 @@
+package branch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+// BranchRepository defines git operations needed by the branch workflow.
+type BranchRepository interface {
+	ListBranches(includeRemote bool) ([]git.BranchInfo, error)
+	GetCurrentBranch() (string, error)
+	GetDefaultBranch() (string, error)
+	BranchExists(branchName string) (bool, error)
+	GetAheadBehind(branch, base string) (int, int, error)
+	IsAncestor(ancestorRef, descendantRef string) (bool, error)
+	DeleteLocalBranch(branch string) error
+}
+
+// BranchGitHubClient defines GitHub operations needed by the branch workflow.
+type BranchGitHubClient interface {
+	FindPRsForBranches(ctx context.Context, owner, repo string, branches []string) (map[string]*github.PullRequest, error)
+}
+
+// BranchWorkflow lists local branches with their PR, stack and CI state,
+// and optionally cleans up branches whose PRs have been merged.
+type BranchWorkflow struct {
+	repo   BranchRepository
+	client BranchGitHubClient
+	owner  string
+	name   string
+}
+
+// NewBranchWorkflow creates a new BranchWorkflow.
+func NewBranchWorkflow(repo BranchRepository, client BranchGitHubClient, owner, name string) *BranchWorkflow {
+	return &BranchWorkflow{
+		repo:   repo,
+		client: client,
+		owner:  owner,
+		name:   name,
+	}
+}
+
+// Execute runs the branch workflow.
+func (w *BranchWorkflow) Execute(ctx context.Context, opts *BranchOptions) (*BranchResult, error) {
+	if opts == nil {
+		opts = &BranchOptions{}
+	}
+
+	logger.Debug().
+		Bool("cleanup", opts.Cleanup).
+		Bool("dryRun", opts.DryRun).
+		Msg("Executing branch workflow")
+
+	// Step 1: Collect local branches
+	branches, err := w.repo.ListBranches(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	currentBranch, err := w.repo.GetCurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	defaultBranch, err := w.repo.GetDefaultBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine default branch: %w", err)
+	}
+
+	result := &BranchResult{
+		DefaultBranch: defaultBranch,
+		CompareRef:    w.compareRef(defaultBranch),
+		Branches:      make([]BranchEntry, 0, len(branches)),
+		DryRun:        opts.DryRun,
+	}
+
+	// Step 2: Join branches to PRs in one batched lookup
+	names := make([]string, 0, len(branches))
+	for _, b := range branches {
+		if b.Name != defaultBranch {
+			names = append(names, b.Name)
+		}
+	}
+	prs, err := w.client.FindPRsForBranches(ctx, w.owner, w.name, names)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: Build entries
+	for _, b := range branches {
+		entry := BranchEntry{
+			Name:    b.Name,
+			Current: b.Name == currentBranch,
+			SHA:     b.Hash,
+		}
+
+		if b.Name != result.CompareRef {
+			ahead, behind, err := w.repo.GetAheadBehind(b.Name, result.CompareRef)
+			if err != nil {
+				logger.Debug().Err(err).Str("branch", b.Name).Msg("Failed to count commits ahead/behind")
+			}
+			entry.Ahead, entry.Behind = ahead, behind
+		}
+
+		if pr := prs[b.Name]; pr != nil {
+			applyPR(&entry, pr, defaultBranch)
+		}
+
+		result.Branches = append(result.Branches, entry)
+	}
+
+	sort.Slice(result.Branches, func(i, j int) bool {
+		return result.Branches[i].Name < result.Branches[j].Name
+	})
+
+	// Step 4: Cleanup merged branches
+	if opts.Cleanup {
+		w.cleanup(result, opts.DryRun)
+	}
+
+	return result, nil
+}
+
+// compareRef prefers the remote-tracking default branch, which reflects what
+// has actually been merged, and falls back to the local default branch.
+func (w *BranchWorkflow) compareRef(defaultBranch string) string {
+	remote := "origin/" + defaultBranch
+	if exists, err := w.repo.BranchExists(remote); err == nil && exists {
+		return remote
+	}
+	return defaultBranch
+}
+
+// applyPR fills the PR-derived fields of entry.
+func applyPR(entry *BranchEntry, pr *github.PullRequest, defaultBranch string) {
+	entry.PR = pr
+	entry.PRNumber = pr.Number
+	entry.PRTitle = pr.Title
+	entry.PRURL = pr.HTMLURL
+
+	switch {
+	case pr.IsMerged():
+		entry.PRState = PRStateMerged
+	case pr.Draft:
+		entry.PRState = PRStateDraft
+	default:
+		entry.PRState = PRStateOpen
+	}
+
+	if entry.PRState != PRStateMerged {
+		if pr.Base.Ref != defaultBranch {
+			entry.Parent = pr.Base.Ref
+		}
+		status := github.DeterminePRStatus(pr.Reviews, pr.Checks)
+		entry.ReviewStatus = status.ReviewStatus
+		entry.CheckStatus = status.CheckStatus
+	}
+}
+
+// cleanup deletes branches whose PRs are merged. The current branch and
+// branches with commits that were not part of the merged PR are kept.
+func (w *BranchWorkflow) cleanup(result *BranchResult, dryRun bool) {
+	for _, entry := range result.Branches {
+		if entry.PRState != PRStateMerged {
+			continue
+		}
+
+		if entry.Current {
+			result.CleanupSkipped = append(result.CleanupSkipped, CleanupSkip{
+				Branch: entry.Name,
+				Reason: "currently checked out",
+			})
+			continue
+		}
+
+		if !w.containedInPR(entry) {
+			result.CleanupSkipped = append(result.CleanupSkipped, CleanupSkip{
+				Branch: entry.Name,
+				Reason: fmt.Sprintf("has commits not in merged PR #%d", entry.PRNumber),
+			})
+			continue
+		}
+
+		if !dryRun {
+			if err := w.repo.DeleteLocalBranch(entry.Name); err != nil {
+				logger.Warn().Err(err).Str("branch", entry.Name).Msg("Failed to delete merged branch")
+				result.CleanupSkipped = append(result.CleanupSkipped, CleanupSkip{
+					Branch: entry.Name,
+					Reason: "delete failed: " + err.Error(),
+				})
+				continue
+			}
+		}
+		result.Deleted = append(result.Deleted, entry.Name)
+	}
+}
+
+// containedInPR reports whether the local branch tip is the merged PR head or
+// one of its ancestors, so deleting it loses no work.
+func (w *BranchWorkflow) containedInPR(entry BranchEntry) bool {
+	if entry.PR.Head.SHA == "" {
+		return false
+	}
+	if entry.SHA == entry.PR.Head.SHA {
+		return true
+	}
+
+	contained, err := w.repo.IsAncestor(entry.SHA, entry.PR.Head.SHA)
+	if err != nil {
+		// The PR head may not exist locally (e.g. the remote branch was deleted)
+		logger.Debug().Err(err).Str("branch", entry.Name).Msg("Failed to compare branch with merged PR head")
+		return false
+	}
+	return contained
+}
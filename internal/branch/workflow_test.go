@@ -0,0 +1,278 @@
+package branch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+type aheadBehind struct{ ahead, behind int }
+
+type mockBranchRepo struct {
+	branches      []git.BranchInfo
+	current       string
+	defaultBranch string
+	existing      map[string]bool
+	counts        map[string]aheadBehind
+	gotCountBase  string
+	ancestors     map[string]bool // "ancestor..descendant"
+	deleteErr     error
+	deleted       []string
+}
+
+func (m *mockBranchRepo) ListBranches(includeRemote bool) ([]git.BranchInfo, error) {
+	return m.branches, nil
+}
+
+func (m *mockBranchRepo) GetCurrentBranch() (string, error) {
+	return m.current, nil
+}
+
+func (m *mockBranchRepo) GetDefaultBranch() (string, error) {
+	return m.defaultBranch, nil
+}
+
+func (m *mockBranchRepo) BranchExists(branchName string) (bool, error) {
+	return m.existing[branchName], nil
+}
+
+func (m *mockBranchRepo) GetAheadBehind(branch, base string) (int, int, error) {
+	m.gotCountBase = base
+	c := m.counts[branch]
+	return c.ahead, c.behind, nil
+}
+
+func (m *mockBranchRepo) IsAncestor(ancestorRef, descendantRef string) (bool, error) {
+	return m.ancestors[ancestorRef+".."+descendantRef], nil
+}
+
+func (m *mockBranchRepo) DeleteLocalBranch(branch string) error {
+	if m.deleteErr != nil {
+		return m.deleteErr
+	}
+	m.deleted = append(m.deleted, branch)
+	return nil
+}
+
+type mockBranchClient struct {
+	prs         map[string]*github.PullRequest
+	err         error
+	calls       int
+	gotBranches []string
+}
+
+func (m *mockBranchClient) FindPRsForBranches(ctx context.Context, owner, repo string, branches []string) (map[string]*github.PullRequest, error) {
+	m.calls++
+	m.gotBranches = branches
+	return m.prs, m.err
+}
+
+func mergedPR(number int, head, sha string) *github.PullRequest {
+	mergedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &github.PullRequest{
+		Number:   number,
+		State:    "closed",
+		MergedAt: &mergedAt,
+		Head:     github.PRBranch{Ref: head, SHA: sha},
+		Base:     github.PRBranch{Ref: "main"},
+	}
+}
+
+func defaultBranchRepo() *mockBranchRepo {
+	return &mockBranchRepo{
+		branches: []git.BranchInfo{
+			{Name: "main", Hash: "m0"},
+			{Name: "feature/b", Hash: "b1"},
+			{Name: "feature/a", Hash: "a1"},
+			{Name: "done", Hash: "d1"},
+		},
+		current:       "feature/b",
+		defaultBranch: "main",
+		existing:      map[string]bool{"origin/main": true},
+		counts: map[string]aheadBehind{
+			"main":      {0, 2},
+			"feature/a": {3, 1},
+			"feature/b": {1, 0},
+		},
+	}
+}
+
+func defaultBranchClient() *mockBranchClient {
+	return &mockBranchClient{
+		prs: map[string]*github.PullRequest{
+			"feature/a": {
+				Number:  10,
+				State:   "open",
+				Head:    github.PRBranch{Ref: "feature/a", SHA: "a1"},
+				Base:    github.PRBranch{Ref: "main"},
+				Reviews: []github.PRReview{{State: "APPROVED", User: github.PRUser{Login: "alice"}}},
+				Checks:  []github.PRCheck{{Name: "ci", Status: "completed", Conclusion: "failure"}},
+			},
+			"feature/b": {
+				Number: 11,
+				State:  "open",
+				Draft:  true,
+				Head:   github.PRBranch{Ref: "feature/b", SHA: "b1"},
+				Base:   github.PRBranch{Ref: "feature/a"},
+			},
+			"done": mergedPR(9, "done", "d1"),
+		},
+	}
+}
+
+func findEntry(t *testing.T, result *BranchResult, name string) BranchEntry {
+	t.Helper()
+	for _, e := range result.Branches {
+		if e.Name == name {
+			return e
+		}
+	}
+	t.Fatalf("branch %s not in result", name)
+	return BranchEntry{}
+}
+
+func TestBranchWorkflowExecute(t *testing.T) {
+	t.Run("joins branches to PRs in one lookup", func(t *testing.T) {
+		repo := defaultBranchRepo()
+		client := defaultBranchClient()
+
+		result, err := NewBranchWorkflow(repo, client, "owner", "repo").Execute(context.Background(), nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, client.calls)
+		assert.ElementsMatch(t, []string{"feature/b", "feature/a", "done"}, client.gotBranches, "default branch is not looked up")
+		assert.Equal(t, "origin/main", result.CompareRef)
+		assert.Equal(t, "origin/main", repo.gotCountBase)
+
+		names := make([]string, 0, len(result.Branches))
+		for _, e := range result.Branches {
+			names = append(names, e.Name)
+		}
+		assert.Equal(t, []string{"done", "feature/a", "feature/b", "main"}, names)
+
+		a := findEntry(t, result, "feature/a")
+		assert.Equal(t, 10, a.PRNumber)
+		assert.Equal(t, PRStateOpen, a.PRState)
+		assert.Empty(t, a.Parent)
+		assert.Equal(t, 3, a.Ahead)
+		assert.Equal(t, 1, a.Behind)
+		assert.Equal(t, "approved", a.ReviewStatus)
+		assert.Equal(t, "failure", a.CheckStatus)
+
+		b := findEntry(t, result, "feature/b")
+		assert.True(t, b.Current)
+		assert.Equal(t, PRStateDraft, b.PRState)
+		assert.Equal(t, "feature/a", b.Parent)
+
+		done := findEntry(t, result, "done")
+		assert.Equal(t, PRStateMerged, done.PRState)
+		assert.Empty(t, done.ReviewStatus)
+
+		main := findEntry(t, result, "main")
+		assert.Zero(t, main.PRNumber)
+		assert.Equal(t, 2, main.Behind)
+
+		assert.Empty(t, result.Deleted)
+	})
+
+	t.Run("falls back to local default branch", func(t *testing.T) {
+		repo := defaultBranchRepo()
+		repo.existing = nil
+
+		result, err := NewBranchWorkflow(repo, defaultBranchClient(), "owner", "repo").Execute(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "main", result.CompareRef)
+		assert.Equal(t, "main", repo.gotCountBase)
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		client := &mockBranchClient{err: errors.New("boom")}
+
+		_, err := NewBranchWorkflow(defaultBranchRepo(), client, "owner", "repo").Execute(context.Background(), nil)
+		require.Error(t, err)
+	})
+}
+
+func TestBranchWorkflowCleanup(t *testing.T) {
+	t.Run("deletes merged branches", func(t *testing.T) {
+		repo := defaultBranchRepo()
+
+		result, err := NewBranchWorkflow(repo, defaultBranchClient(), "owner", "repo").
+			Execute(context.Background(), &BranchOptions{Cleanup: true})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"done"}, result.Deleted)
+		assert.Equal(t, []string{"done"}, repo.deleted)
+		assert.Empty(t, result.CleanupSkipped)
+	})
+
+	t.Run("dry run deletes nothing", func(t *testing.T) {
+		repo := defaultBranchRepo()
+
+		result, err := NewBranchWorkflow(repo, defaultBranchClient(), "owner", "repo").
+			Execute(context.Background(), &BranchOptions{Cleanup: true, DryRun: true})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"done"}, result.Deleted)
+		assert.True(t, result.DryRun)
+		assert.Empty(t, repo.deleted)
+	})
+
+	t.Run("keeps current branch", func(t *testing.T) {
+		repo := defaultBranchRepo()
+		repo.current = "done"
+
+		result, err := NewBranchWorkflow(repo, defaultBranchClient(), "owner", "repo").
+			Execute(context.Background(), &BranchOptions{Cleanup: true})
+		require.NoError(t, err)
+
+		assert.Empty(t, repo.deleted)
+		require.Len(t, result.CleanupSkipped, 1)
+		assert.Equal(t, "currently checked out", result.CleanupSkipped[0].Reason)
+	})
+
+	t.Run("ancestor of merged head is deleted", func(t *testing.T) {
+		repo := defaultBranchRepo()
+		repo.branches[3].Hash = "d0"
+		repo.ancestors = map[string]bool{"d0..d1": true}
+
+		result, err := NewBranchWorkflow(repo, defaultBranchClient(), "owner", "repo").
+			Execute(context.Background(), &BranchOptions{Cleanup: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"done"}, result.Deleted)
+	})
+
+	t.Run("keeps branch with unmerged commits", func(t *testing.T) {
+		repo := defaultBranchRepo()
+		repo.branches[3].Hash = "d2"
+
+		result, err := NewBranchWorkflow(repo, defaultBranchClient(), "owner", "repo").
+			Execute(context.Background(), &BranchOptions{Cleanup: true})
+		require.NoError(t, err)
+
+		assert.Empty(t, repo.deleted)
+		require.Len(t, result.CleanupSkipped, 1)
+		assert.Equal(t, "done", result.CleanupSkipped[0].Branch)
+		assert.Contains(t, result.CleanupSkipped[0].Reason, "#9")
+	})
+
+	t.Run("delete failure is reported", func(t *testing.T) {
+		repo := defaultBranchRepo()
+		repo.deleteErr = errors.New("locked")
+
+		result, err := NewBranchWorkflow(repo, defaultBranchClient(), "owner", "repo").
+			Execute(context.Background(), &BranchOptions{Cleanup: true})
+		require.NoError(t, err)
+
+		assert.Empty(t, result.Deleted)
+		require.Len(t, result.CleanupSkipped, 1)
+		assert.Contains(t, result.CleanupSkipped[0].Reason, "locked")
+	})
+}
@@ -200,3 +200,9 @@ func GenerateKey(components ...string) string {
 	}
 	return key
 }
+
+// PullRequestsKey returns the key of the open pull requests of a repository
+// as cached by gh arc list
+func PullRequestsKey(owner, repo string) string {
+	return GenerateKey("prs", owner, repo, "open")
+}
@@ -227,6 +227,10 @@ func TestGenerateKey(t *testing.T) {
 	}
 }
 
+func TestPullRequestsKey(t *testing.T) {
+	assert.Equal(t, "prs:owner:repo:open", PullRequestsKey("owner", "repo"))
+}
+
 func TestGetPath(t *testing.T) {
 	c, err := New()
 	require.NoError(t, err)
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/serpro69/gh-arc/internal/git"
 	"github.com/spf13/viper"
@@ -25,6 +26,7 @@ type GitHubConfig struct {
 	DefaultBranch      string   `mapstructure:"defaultBranch"`
 	DefaultReviewers   []string `mapstructure:"defaultReviewers"`
 	AutoAssignReviewer bool     `mapstructure:"autoAssignReviewer"`
+	WaitForRateLimit   bool     `mapstructure:"waitForRateLimit"` // Sleep until a used-up rate limit resets instead of failing
 }
 
 // DiffConfig contains PR creation settings
@@ -39,6 +41,7 @@ type DiffConfig struct {
 	RequireTestPlan       bool   `mapstructure:"requireTestPlan"`
 	LinearEnabled         bool   `mapstructure:"linearEnabled"`
 	LinearDefaultProject  string `mapstructure:"linearDefaultProject"`
+	RunChecks             bool   `mapstructure:"runChecks"`
 	// Auto-branch from main settings
 	AutoCreateBranchFromMain  bool   `mapstructure:"autoCreateBranchFromMain"`
 	AutoBranchNamePattern     string `mapstructure:"autoBranchNamePattern"`
@@ -71,6 +74,7 @@ type LandConfig struct {
 	DeleteLocalBranch  bool   `mapstructure:"deleteLocalBranch"`
 	RequireApproval    string `mapstructure:"requireApproval"`
 	RequireCI          string `mapstructure:"requireCI"`
+	StackCITimeout     string `mapstructure:"stackCITimeout"`
 }
 
 // TestConfig contains test execution settings
@@ -100,6 +104,8 @@ type LintRunner struct {
 	Args       []string `mapstructure:"args"`
 	WorkingDir string   `mapstructure:"workingDir"`
 	AutoFix    bool     `mapstructure:"autoFix"`
+	FixArgs    []string `mapstructure:"fixArgs"`
+	Timeout    string   `mapstructure:"timeout"`
 }
 
 // MegaLinterConfig contains mega-linter specific settings
@@ -200,6 +206,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("github.defaultBranch", "main")
 	v.SetDefault("github.defaultReviewers", []string{})
 	v.SetDefault("github.autoAssignReviewer", false)
+	v.SetDefault("github.waitForRateLimit", false)
 
 	// Diff defaults
 	v.SetDefault("diff.createAsDraft", false)
@@ -212,6 +219,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("diff.requireTestPlan", true)    // Enforce test plan by default
 	v.SetDefault("diff.linearEnabled", false)     // Linear integration disabled by default
 	v.SetDefault("diff.linearDefaultProject", "") // No default Linear project
+	v.SetDefault("diff.runChecks", false)         // Pre-submit lint/unit gate is opt-in
 	// Auto-branch from main defaults
 	v.SetDefault("diff.autoCreateBranchFromMain", true) // Enabled by default for seamless workflow
 	v.SetDefault("diff.autoBranchNamePattern", "")      // Empty = use default pattern (feature/auto-from-main-{timestamp})
@@ -222,6 +230,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("land.deleteLocalBranch", true)
 	v.SetDefault("land.requireApproval", ApprovalStrict)
 	v.SetDefault("land.requireCI", CIModeRequired)
+	v.SetDefault("land.stackCITimeout", "30m")
 
 	// Test defaults (empty runners - auto-detect)
 	v.SetDefault("test.runners", []TestRunner{})
@@ -270,6 +279,13 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid land.requireCI value: %q (must be required, all, or none)", c.Land.RequireCI)
 	}
 
+	// Validate stack CI timeout
+	if c.Land.StackCITimeout != "" {
+		if d, err := time.ParseDuration(c.Land.StackCITimeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid land.stackCITimeout value: %q (must be a positive duration such as 30m)", c.Land.StackCITimeout)
+		}
+	}
+
 	// Validate mega-linter enabled value
 	validEnabledValues := map[string]bool{
 		"auto":  true,
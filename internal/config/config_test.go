@@ -31,6 +31,9 @@ func TestLoad(t *testing.T) {
 		if cfg.Land.RequireCI != "required" {
 			t.Errorf("Expected default requireCI 'required', got '%s'", cfg.Land.RequireCI)
 		}
+		if cfg.Land.StackCITimeout != "30m" {
+			t.Errorf("Expected default stackCITimeout '30m', got '%s'", cfg.Land.StackCITimeout)
+		}
 		if cfg.Diff.CreateAsDraft {
 			t.Error("Expected createAsDraft to be false by default")
 		}
@@ -46,6 +49,9 @@ func TestLoad(t *testing.T) {
 		if cfg.Diff.LinearEnabled {
 			t.Error("Expected linearEnabled to be false by default")
 		}
+		if cfg.Diff.RunChecks {
+			t.Error("Expected runChecks to be false by default")
+		}
 		// Auto-branch defaults
 		if !cfg.Diff.AutoCreateBranchFromMain {
 			t.Error("Expected autoCreateBranchFromMain to be true by default")
@@ -193,6 +199,50 @@ output:
 		}
 	})
 
+	t.Run("load lint runner with fixArgs and timeout", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		os.Chdir(tmpDir)
+
+		configContent := `{
+			"lint": {
+				"runners": [
+					{
+						"name": "golangci-lint",
+						"command": "golangci-lint",
+						"args": ["run"],
+						"fixArgs": ["--fix"],
+						"autoFix": true,
+						"timeout": "5m"
+					}
+				]
+			}
+		}`
+
+		err := os.WriteFile(".arc.json", []byte(configContent), 0o644)
+		if err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if len(cfg.Lint.Runners) != 1 {
+			t.Fatalf("Expected 1 lint runner, got %d", len(cfg.Lint.Runners))
+		}
+		runner := cfg.Lint.Runners[0]
+		if !slices.Equal(runner.FixArgs, []string{"--fix"}) {
+			t.Errorf("Expected fixArgs [--fix], got %v", runner.FixArgs)
+		}
+		if runner.Timeout != "5m" {
+			t.Errorf("Expected timeout '5m', got '%s'", runner.Timeout)
+		}
+		if !runner.AutoFix {
+			t.Error("Expected autoFix to be true")
+		}
+	})
+
 	t.Run("load with environment variables", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		os.Chdir(tmpDir)
@@ -335,6 +385,27 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid land.requireCI value",
 		},
+		{
+			name: "valid stackCITimeout",
+			config: Config{
+				Land: LandConfig{DefaultMergeMethod: "squash", RequireApproval: "strict", RequireCI: "required", StackCITimeout: "45m"},
+				Lint: LintConfig{
+					MegaLinter: MegaLinterConfig{Enabled: "auto"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid stackCITimeout value",
+			config: Config{
+				Land: LandConfig{DefaultMergeMethod: "squash", RequireApproval: "strict", RequireCI: "required", StackCITimeout: "soon"},
+				Lint: LintConfig{
+					MegaLinter: MegaLinterConfig{Enabled: "auto"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid land.stackCITimeout value",
+		},
 		{
 			name: "invalid mega-linter enabled value",
 			config: Config{
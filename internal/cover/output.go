@@ -0,0 +1,48 @@
+package cover
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PrintResult writes the ranked candidates and the reasons behind them.
+func PrintResult(out io.Writer, result *CoverResult) {
+	fmt.Fprintf(out, "Reviewer suggestions for %d changed %s (vs %s)\n",
+		len(result.Files), plural(len(result.Files), "file", "files"), result.Base)
+	fmt.Fprintln(out, strings.Repeat("━", 50))
+
+	if len(result.Candidates) == 0 {
+		fmt.Fprintln(out, "No candidates found in CODEOWNERS or recent history")
+	}
+
+	for i, c := range result.Candidates {
+		label := c.Reviewer
+		if c.Type == "team" {
+			label += " (team)"
+		}
+		fmt.Fprintf(out, "%2d. %s  [score %d]\n", i+1, label, c.Score)
+		for _, reason := range c.Reasons {
+			fmt.Fprintf(out, "      %s\n", reason)
+		}
+	}
+
+	if len(result.UnownedFiles) > 0 {
+		fmt.Fprintln(out)
+		if !result.HasCodeowners {
+			fmt.Fprintln(out, "⚠ No CODEOWNERS file found, suggestions are based on history only")
+			return
+		}
+		fmt.Fprintf(out, "⚠ %d of %d changed files have no CODEOWNERS owner:\n",
+			len(result.UnownedFiles), len(result.Files))
+		for _, file := range result.UnownedFiles {
+			fmt.Fprintf(out, "    %s\n", file)
+		}
+	}
+}
+
+// FormatJSON returns the result as indented JSON.
+func FormatJSON(result *CoverResult) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
@@ -0,0 +1,89 @@
+package cover
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleResult() *CoverResult {
+	return &CoverResult{
+		Base:          "main",
+		MergeBase:     "abc123",
+		Files:         []string{"api/handler.go", "web/app.ts"},
+		UnownedFiles:  []string{"web/app.ts"},
+		HasCodeowners: true,
+		Candidates: []Candidate{
+			{
+				Reviewer:   "@alice",
+				Type:       "user",
+				Score:      3,
+				OwnedFiles: []string{"api/handler.go"},
+				Reasons:    []string{"owns 1 of 2 changed files (CODEOWNERS)"},
+			},
+			{
+				Reviewer: "@org/web",
+				Type:     "team",
+				Score:    1,
+				Reasons:  []string{"1 recent commit touching 1 changed file"},
+			},
+		},
+	}
+}
+
+func TestPrintResult(t *testing.T) {
+	t.Run("candidates with reasons", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintResult(&buf, sampleResult())
+		out := buf.String()
+
+		assert.Contains(t, out, "Reviewer suggestions for 2 changed files (vs main)")
+		assert.Contains(t, out, " 1. @alice  [score 3]")
+		assert.Contains(t, out, "owns 1 of 2 changed files (CODEOWNERS)")
+		assert.Contains(t, out, " 2. @org/web (team)  [score 1]")
+		assert.Contains(t, out, "⚠ 1 of 2 changed files have no CODEOWNERS owner")
+		assert.Contains(t, out, "    web/app.ts")
+	})
+
+	t.Run("no CODEOWNERS file", func(t *testing.T) {
+		result := sampleResult()
+		result.HasCodeowners = false
+
+		var buf bytes.Buffer
+		PrintResult(&buf, result)
+
+		assert.Contains(t, buf.String(), "⚠ No CODEOWNERS file found")
+		assert.NotContains(t, buf.String(), "have no CODEOWNERS owner")
+	})
+
+	t.Run("no candidates", func(t *testing.T) {
+		result := sampleResult()
+		result.Candidates = nil
+
+		var buf bytes.Buffer
+		PrintResult(&buf, result)
+
+		assert.Contains(t, buf.String(), "No candidates found")
+	})
+}
+
+func TestFormatJSON(t *testing.T) {
+	data, err := FormatJSON(sampleResult())
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, "main", decoded["base"])
+	candidates := decoded["candidates"].([]any)
+	require.Len(t, candidates, 2)
+
+	first := candidates[0].(map[string]any)
+	assert.Equal(t, "@alice", first["reviewer"])
+	assert.Equal(t, float64(3), first["score"])
+	assert.NotContains(t, first, "last_commit")
+	assert.NotContains(t, first, "commits")
+}
@@ -0,0 +1,35 @@
+package cover
+
+import "time"
+
+// CoverOptions contains options for the cover command
+type CoverOptions struct {
+	Base         string // Base ref to compare against; empty means the default branch
+	Since        string // History window passed to git log --since (e.g. "6 months ago")
+	MaxCommits   int    // Maximum number of history commits to inspect; 0 means no limit
+	Limit        int    // Maximum number of candidates to return; 0 means no limit
+	CurrentUser  string // GitHub login of the current user, excluded from suggestions
+	CurrentEmail string // Git email of the current user, excluded from suggestions
+}
+
+// Candidate is a suggested reviewer together with the evidence behind it.
+type Candidate struct {
+	Reviewer     string     `json:"reviewer"`                // @login, @org/team, or "Name <email>"
+	Type         string     `json:"type"`                    // "user" or "team"
+	Score        int        `json:"score"`                   // Ranking score, higher is better
+	OwnedFiles   []string   `json:"owned_files,omitempty"`   // Changed files owned via CODEOWNERS
+	Commits      int        `json:"commits,omitempty"`       // Recent commits touching changed files
+	TouchedFiles []string   `json:"touched_files,omitempty"` // Changed files touched by those commits
+	LastCommit   *time.Time `json:"last_commit,omitempty"`   // Most recent of those commits
+	Reasons      []string   `json:"reasons"`                 // Human-readable explanations
+}
+
+// CoverResult contains the ranked reviewer candidates for a change set.
+type CoverResult struct {
+	Base          string      `json:"base"`
+	MergeBase     string      `json:"merge_base"`
+	Files         []string    `json:"files"`
+	UnownedFiles  []string    `json:"unowned_files"`
+	HasCodeowners bool        `json:"has_codeowners"`
+	Candidates    []Candidate `json:"candidates"`
+}
@@ -0,0 +1,343 @@
+package cover
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/serpro69/gh-arc/internal/codeowners"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+const (
+	// ownerFileWeight is the score for each changed file a candidate owns via
+	// CODEOWNERS. Ownership outweighs a few incidental commits.
+	ownerFileWeight = 3
+
+	// commitWeight is the score for each recent commit touching changed files.
+	commitWeight = 1
+)
+
+var (
+	// ErrNoChanges is returned when there are no changed files to cover.
+	ErrNoChanges = errors.New("no changed files")
+)
+
+// noreplyEmailPattern matches GitHub noreply addresses, which embed the login:
+// 12345+login@users.noreply.github.com or login@users.noreply.github.com
+var noreplyEmailPattern = regexp.MustCompile(`(?i)^(?:\d+\+)?([A-Za-z0-9-]+)@users\.noreply\.github\.com$`)
+
+// CoverRepository defines git operations needed by the cover workflow.
+type CoverRepository interface {
+	GetDefaultBranch() (string, error)
+	GetMergeBase(ref1, ref2 string) (string, error)
+	GetFilesChanged(base, head string) ([]git.FileChange, error)
+	GetFileHistory(ref string, paths []string, since string, maxCount int) ([]git.FileCommit, error)
+}
+
+// OwnersResolver looks up CODEOWNERS owners for a file.
+type OwnersResolver interface {
+	GetOwnersForFile(filePath string) []codeowners.Owner
+}
+
+// CoverWorkflow suggests reviewers for the current change set from
+// CODEOWNERS and the recent history of the touched files.
+type CoverWorkflow struct {
+	repo          CoverRepository
+	owners        OwnersResolver
+	hasCodeowners bool
+}
+
+// NewCoverWorkflow creates a new CoverWorkflow. owners may be nil when the
+// repository has no CODEOWNERS file.
+func NewCoverWorkflow(repo CoverRepository, owners OwnersResolver) *CoverWorkflow {
+	return &CoverWorkflow{
+		repo:          repo,
+		owners:        owners,
+		hasCodeowners: owners != nil,
+	}
+}
+
+// Execute runs the cover workflow.
+func (w *CoverWorkflow) Execute(opts *CoverOptions) (*CoverResult, error) {
+	if opts == nil {
+		opts = &CoverOptions{}
+	}
+
+	logger.Debug().
+		Str("base", opts.Base).
+		Str("since", opts.Since).
+		Int("maxCommits", opts.MaxCommits).
+		Msg("Executing cover workflow")
+
+	// Step 1: Resolve the base of the change set
+	base, mergeBase, err := w.resolveBase(opts.Base)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Collect changed files (including deleted and renamed-from paths)
+	files, err := w.changedFiles(mergeBase)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w between %s and HEAD", ErrNoChanges, base)
+	}
+
+	result := &CoverResult{
+		Base:          base,
+		MergeBase:     mergeBase,
+		Files:         files,
+		UnownedFiles:  []string{},
+		HasCodeowners: w.hasCodeowners,
+	}
+
+	candidates := newCandidateSet(opts)
+
+	// Step 3: CODEOWNERS owners of each touched path
+	for _, file := range files {
+		owners := w.ownersForFile(file)
+		if len(owners) == 0 {
+			result.UnownedFiles = append(result.UnownedFiles, file)
+			continue
+		}
+		for _, owner := range owners {
+			candidates.addOwner(owner, file)
+		}
+	}
+
+	// Step 4: Recent committers of the touched paths on the base
+	history, err := w.repo.GetFileHistory(mergeBase, files, opts.Since, opts.MaxCommits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file history: %w", err)
+	}
+	for i := range history {
+		candidates.addCommit(&history[i])
+	}
+
+	// Step 5: Rank
+	result.Candidates = candidates.ranked(len(files), opts.Limit)
+
+	logger.Debug().
+		Int("files", len(files)).
+		Int("unowned", len(result.UnownedFiles)).
+		Int("commits", len(history)).
+		Int("candidates", len(result.Candidates)).
+		Msg("Computed reviewer candidates")
+
+	return result, nil
+}
+
+// resolveBase returns the display name of the base and the merge-base SHA
+// between it and HEAD. Without an explicit base, the default branch is used,
+// preferring its remote-tracking ref.
+func (w *CoverWorkflow) resolveBase(base string) (string, string, error) {
+	if base != "" {
+		mergeBase, err := w.repo.GetMergeBase(base, "HEAD")
+		if err != nil {
+			return "", "", fmt.Errorf("failed to resolve base '%s': %w", base, err)
+		}
+		return base, mergeBase, nil
+	}
+
+	defaultBranch, err := w.repo.GetDefaultBranch()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to determine default branch: %w", err)
+	}
+
+	mergeBase, err := w.repo.GetMergeBase("origin/"+defaultBranch, "HEAD")
+	if err == nil {
+		return defaultBranch, mergeBase, nil
+	}
+	if errors.Is(err, git.ErrNoCommonAncestor) {
+		return "", "", err
+	}
+
+	logger.Debug().
+		Err(err).
+		Str("defaultBranch", defaultBranch).
+		Msg("Remote default branch not resolvable, falling back to local branch")
+
+	mergeBase, err = w.repo.GetMergeBase(defaultBranch, "HEAD")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve default branch '%s' (try 'git fetch origin'): %w", defaultBranch, err)
+	}
+	return defaultBranch, mergeBase, nil
+}
+
+// changedFiles returns the paths touched between mergeBase and HEAD. Deleted
+// files and the old side of renames are kept, since their owners and
+// previous authors are relevant reviewers too.
+func (w *CoverWorkflow) changedFiles(mergeBase string) ([]string, error) {
+	changes, err := w.repo.GetFilesChanged(mergeBase, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changed files: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	files := make([]string, 0, len(changes))
+	add := func(path string) {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+	for _, change := range changes {
+		add(change.Path)
+		if change.IsRenamed {
+			add(change.OldPath)
+		}
+	}
+
+	return files, nil
+}
+
+func (w *CoverWorkflow) ownersForFile(file string) []codeowners.Owner {
+	if w.owners == nil {
+		return nil
+	}
+	return w.owners.GetOwnersForFile(file)
+}
+
+// candidateSet accumulates evidence per reviewer, merging CODEOWNERS entries
+// and committers that resolve to the same GitHub login.
+type candidateSet struct {
+	opts  *CoverOptions
+	byKey map[string]*candidateEvidence
+}
+
+type candidateEvidence struct {
+	candidate Candidate
+	owned     map[string]bool
+	touched   map[string]bool
+	commits   map[string]bool
+}
+
+func newCandidateSet(opts *CoverOptions) *candidateSet {
+	return &candidateSet{
+		opts:  opts,
+		byKey: make(map[string]*candidateEvidence),
+	}
+}
+
+func (s *candidateSet) get(key, reviewer, kind string) *candidateEvidence {
+	ev, ok := s.byKey[key]
+	if !ok {
+		ev = &candidateEvidence{
+			candidate: Candidate{Reviewer: reviewer, Type: kind},
+			owned:     make(map[string]bool),
+			touched:   make(map[string]bool),
+			commits:   make(map[string]bool),
+		}
+		s.byKey[key] = ev
+	}
+	return ev
+}
+
+func (s *candidateSet) isCurrentUser(login, email string) bool {
+	if login != "" && s.opts.CurrentUser != "" && strings.EqualFold(strings.TrimPrefix(login, "@"), s.opts.CurrentUser) {
+		return true
+	}
+	return email != "" && s.opts.CurrentEmail != "" && strings.EqualFold(email, s.opts.CurrentEmail)
+}
+
+func (s *candidateSet) addOwner(owner codeowners.Owner, file string) {
+	// Email owners can't be requested as reviewers
+	if !strings.HasPrefix(owner.Name, "@") || s.isCurrentUser(owner.Name, "") {
+		return
+	}
+	ev := s.get(strings.ToLower(owner.Name), owner.Name, owner.Type)
+	ev.owned[file] = true
+}
+
+func (s *candidateSet) addCommit(commit *git.FileCommit) {
+	login := loginFromEmail(commit.Email)
+	if s.isCurrentUser(login, commit.Email) {
+		return
+	}
+
+	var ev *candidateEvidence
+	if login != "" {
+		ev = s.get("@"+strings.ToLower(login), "@"+login, "user")
+	} else {
+		ev = s.get(strings.ToLower(commit.Email), fmt.Sprintf("%s <%s>", commit.Author, commit.Email), "user")
+	}
+
+	ev.commits[commit.SHA] = true
+	for _, file := range commit.Files {
+		ev.touched[file] = true
+	}
+	if !commit.Date.IsZero() && (ev.candidate.LastCommit == nil || commit.Date.After(*ev.candidate.LastCommit)) {
+		date := commit.Date
+		ev.candidate.LastCommit = &date
+	}
+}
+
+// ranked finalizes scores and reasons and returns candidates ordered by score.
+func (s *candidateSet) ranked(totalFiles, limit int) []Candidate {
+	candidates := make([]Candidate, 0, len(s.byKey))
+	for _, ev := range s.byKey {
+		c := ev.candidate
+		c.OwnedFiles = sortedKeys(ev.owned)
+		c.TouchedFiles = sortedKeys(ev.touched)
+		c.Commits = len(ev.commits)
+		c.Score = len(c.OwnedFiles)*ownerFileWeight + c.Commits*commitWeight
+
+		if len(c.OwnedFiles) > 0 {
+			c.Reasons = append(c.Reasons, fmt.Sprintf("owns %d of %d changed files (CODEOWNERS)", len(c.OwnedFiles), totalFiles))
+		}
+		if c.Commits > 0 {
+			reason := fmt.Sprintf("%d recent %s touching %d changed %s",
+				c.Commits, plural(c.Commits, "commit", "commits"),
+				len(c.TouchedFiles), plural(len(c.TouchedFiles), "file", "files"))
+			if c.LastCommit != nil {
+				reason += ", last on " + c.LastCommit.Format("2006-01-02")
+			}
+			c.Reasons = append(c.Reasons, reason)
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return strings.ToLower(candidates[i].Reviewer) < strings.ToLower(candidates[j].Reviewer)
+	})
+
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
+
+// loginFromEmail extracts the GitHub login from a noreply address.
+// Returns an empty string for other addresses.
+func loginFromEmail(email string) string {
+	m := noreplyEmailPattern.FindStringSubmatch(email)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
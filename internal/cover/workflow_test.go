@@ -0,0 +1,239 @@
+package cover
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/codeowners"
+	"github.com/serpro69/gh-arc/internal/git"
+)
+
+type mockCoverRepo struct {
+	defaultBranch string
+	mergeBases    map[string]string
+	changes       []git.FileChange
+	history       []git.FileCommit
+	historyErr    error
+	gotRef        string
+	gotPaths      []string
+	gotSince      string
+	gotMaxCount   int
+}
+
+func (m *mockCoverRepo) GetDefaultBranch() (string, error) {
+	return m.defaultBranch, nil
+}
+
+func (m *mockCoverRepo) GetMergeBase(ref1, ref2 string) (string, error) {
+	if sha, ok := m.mergeBases[ref1]; ok {
+		return sha, nil
+	}
+	return "", fmt.Errorf("failed to find merge-base: fatal: Not a valid object name %s", ref1)
+}
+
+func (m *mockCoverRepo) GetFilesChanged(base, head string) ([]git.FileChange, error) {
+	return m.changes, nil
+}
+
+func (m *mockCoverRepo) GetFileHistory(ref string, paths []string, since string, maxCount int) ([]git.FileCommit, error) {
+	m.gotRef = ref
+	m.gotPaths = paths
+	m.gotSince = since
+	m.gotMaxCount = maxCount
+	return m.history, m.historyErr
+}
+
+type mockOwners map[string][]codeowners.Owner
+
+func (m mockOwners) GetOwnersForFile(filePath string) []codeowners.Owner {
+	return m[filePath]
+}
+
+func user(name string) codeowners.Owner { return codeowners.Owner{Name: name, Type: "user"} }
+
+func fileCommit(sha, author, email string, daysAgo int, files ...string) git.FileCommit {
+	return git.FileCommit{
+		CommitInfo: git.CommitInfo{
+			SHA:    sha,
+			Author: author,
+			Email:  email,
+			Date:   time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -daysAgo),
+		},
+		Files: files,
+	}
+}
+
+func defaultCoverRepo() *mockCoverRepo {
+	return &mockCoverRepo{
+		defaultBranch: "main",
+		mergeBases:    map[string]string{"origin/main": "abc123"},
+		changes: []git.FileChange{
+			{Path: "api/handler.go"},
+			{Path: "api/routes.go"},
+			{Path: "web/app.ts", OldPath: "web/old.ts", IsRenamed: true},
+		},
+	}
+}
+
+func TestCoverWorkflow_RanksOwnersAndCommitters(t *testing.T) {
+	repo := defaultCoverRepo()
+	repo.history = []git.FileCommit{
+		fileCommit("c1", "Bob", "bob@example.com", 1, "web/app.ts"),
+		fileCommit("c2", "Bob", "bob@example.com", 10, "web/old.ts"),
+		fileCommit("c3", "Alice", "4242+alice@users.noreply.github.com", 5, "api/handler.go"),
+	}
+	owners := mockOwners{
+		"api/handler.go": {user("@alice"), {Name: "@org/backend", Type: "team"}},
+		"api/routes.go":  {user("@alice")},
+	}
+
+	w := NewCoverWorkflow(repo, owners)
+	result, err := w.Execute(&CoverOptions{Since: "6 months ago", MaxCommits: 100})
+	require.NoError(t, err)
+
+	assert.Equal(t, "main", result.Base)
+	assert.Equal(t, "abc123", result.MergeBase)
+	assert.Equal(t, []string{"api/handler.go", "api/routes.go", "web/app.ts", "web/old.ts"}, result.Files)
+	assert.Equal(t, []string{"web/app.ts", "web/old.ts"}, result.UnownedFiles)
+	assert.True(t, result.HasCodeowners)
+
+	// History is read from the merge-base for all touched paths
+	assert.Equal(t, "abc123", repo.gotRef)
+	assert.Equal(t, result.Files, repo.gotPaths)
+	assert.Equal(t, "6 months ago", repo.gotSince)
+	assert.Equal(t, 100, repo.gotMaxCount)
+
+	require.Len(t, result.Candidates, 3)
+
+	// Owner and noreply committer are merged into one candidate
+	alice := result.Candidates[0]
+	assert.Equal(t, "@alice", alice.Reviewer)
+	assert.Equal(t, 2*ownerFileWeight+1*commitWeight, alice.Score)
+	assert.Equal(t, []string{"api/handler.go", "api/routes.go"}, alice.OwnedFiles)
+	assert.Equal(t, 1, alice.Commits)
+	require.Len(t, alice.Reasons, 2)
+	assert.Equal(t, "owns 2 of 4 changed files (CODEOWNERS)", alice.Reasons[0])
+	assert.Equal(t, "1 recent commit touching 1 changed file, last on 2026-01-26", alice.Reasons[1])
+
+	team := result.Candidates[1]
+	assert.Equal(t, "@org/backend", team.Reviewer)
+	assert.Equal(t, "team", team.Type)
+	assert.Equal(t, ownerFileWeight, team.Score)
+
+	bob := result.Candidates[2]
+	assert.Equal(t, "Bob <bob@example.com>", bob.Reviewer)
+	assert.Equal(t, 2, bob.Commits)
+	assert.Equal(t, []string{"web/app.ts", "web/old.ts"}, bob.TouchedFiles)
+	require.NotNil(t, bob.LastCommit)
+	assert.Equal(t, "2026-01-30", bob.LastCommit.Format("2006-01-02"))
+	assert.Equal(t, []string{"2 recent commits touching 2 changed files, last on 2026-01-30"}, bob.Reasons)
+}
+
+func TestCoverWorkflow_ExcludesCurrentUser(t *testing.T) {
+	repo := defaultCoverRepo()
+	repo.history = []git.FileCommit{
+		fileCommit("c1", "Me", "me@example.com", 1, "api/handler.go"),
+		fileCommit("c2", "Me", "me@users.noreply.github.com", 1, "api/handler.go"),
+		fileCommit("c3", "Bob", "bob@example.com", 1, "api/handler.go"),
+	}
+	owners := mockOwners{"api/handler.go": {user("@Me"), user("owner@example.com")}}
+
+	w := NewCoverWorkflow(repo, owners)
+	result, err := w.Execute(&CoverOptions{CurrentUser: "me", CurrentEmail: "ME@example.com"})
+	require.NoError(t, err)
+
+	require.Len(t, result.Candidates, 1)
+	assert.Equal(t, "Bob <bob@example.com>", result.Candidates[0].Reviewer)
+}
+
+func TestCoverWorkflow_Limit(t *testing.T) {
+	repo := defaultCoverRepo()
+	repo.history = []git.FileCommit{
+		fileCommit("c1", "A", "a@example.com", 1, "api/handler.go"),
+		fileCommit("c2", "B", "b@example.com", 1, "api/handler.go"),
+		fileCommit("c3", "B", "b@example.com", 2, "api/routes.go"),
+	}
+
+	w := NewCoverWorkflow(repo, nil)
+	result, err := w.Execute(&CoverOptions{Limit: 1})
+	require.NoError(t, err)
+
+	require.Len(t, result.Candidates, 1)
+	assert.Equal(t, "B <b@example.com>", result.Candidates[0].Reviewer)
+	assert.False(t, result.HasCodeowners)
+}
+
+func TestCoverWorkflow_ExplicitBase(t *testing.T) {
+	repo := defaultCoverRepo()
+	repo.mergeBases = map[string]string{"feature/parent": "def456"}
+
+	w := NewCoverWorkflow(repo, nil)
+	result, err := w.Execute(&CoverOptions{Base: "feature/parent"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "feature/parent", result.Base)
+	assert.Equal(t, "def456", repo.gotRef)
+}
+
+func TestCoverWorkflow_BaseFallbackToLocal(t *testing.T) {
+	repo := defaultCoverRepo()
+	repo.mergeBases = map[string]string{"main": "local123"}
+
+	w := NewCoverWorkflow(repo, nil)
+	result, err := w.Execute(&CoverOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "local123", result.MergeBase)
+}
+
+func TestCoverWorkflow_Errors(t *testing.T) {
+	t.Run("unresolvable base", func(t *testing.T) {
+		repo := defaultCoverRepo()
+		repo.mergeBases = map[string]string{}
+
+		_, err := NewCoverWorkflow(repo, nil).Execute(&CoverOptions{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "git fetch origin")
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		repo := defaultCoverRepo()
+		repo.changes = nil
+
+		_, err := NewCoverWorkflow(repo, nil).Execute(&CoverOptions{})
+		assert.True(t, errors.Is(err, ErrNoChanges))
+	})
+
+	t.Run("history failure", func(t *testing.T) {
+		repo := defaultCoverRepo()
+		repo.historyErr = errors.New("boom")
+
+		_, err := NewCoverWorkflow(repo, nil).Execute(&CoverOptions{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read file history")
+	})
+}
+
+func TestLoginFromEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"12345+octocat@users.noreply.github.com", "octocat"},
+		{"octocat@users.noreply.github.com", "octocat"},
+		{"Octo-Cat@Users.NoReply.GitHub.com", "Octo-Cat"},
+		{"octocat@example.com", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			assert.Equal(t, tt.want, loginFromEmail(tt.email))
+		})
+	}
+}
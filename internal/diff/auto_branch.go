@@ -490,17 +490,35 @@ func (d *AutoBranchDetector) PrepareAutoBranch(ctx context.Context, detection *D
 		}
 	}
 
+	uniqueName, err := d.ResolveBranchName()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug().
+		Str("branchName", uniqueName).
+		Msg("Prepared auto-branch operation")
+
+	return &AutoBranchContext{
+		BranchName:    uniqueName,
+		ShouldProceed: true,
+	}, nil
+}
+
+// ResolveBranchName generates a unique branch name from the configured
+// pattern, prompting the user when the pattern is "null".
+func (d *AutoBranchDetector) ResolveBranchName() (string, error) {
 	// Generate branch name (or prompt if pattern is "null")
 	branchName, shouldPrompt, err := d.GenerateBranchName()
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate branch name: %w", err)
+		return "", fmt.Errorf("failed to generate branch name: %w", err)
 	}
 
 	if shouldPrompt {
 		// Pattern is "null", prompt user for branch name
 		input, err := promptBranchName()
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
 		if input == "" {
@@ -515,17 +533,10 @@ func (d *AutoBranchDetector) PrepareAutoBranch(ctx context.Context, detection *D
 	// Ensure branch name is unique
 	uniqueName, err := d.EnsureUniqueBranchName(branchName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to ensure unique branch name: %w", err)
+		return "", fmt.Errorf("failed to ensure unique branch name: %w", err)
 	}
 
-	logger.Debug().
-		Str("branchName", uniqueName).
-		Msg("Prepared auto-branch operation")
-
-	return &AutoBranchContext{
-		BranchName:    uniqueName,
-		ShouldProceed: true,
-	}, nil
+	return uniqueName, nil
 }
 
 // PushWithRetry pushes the auto-created branch to remote with collision retry logic.
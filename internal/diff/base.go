@@ -20,6 +20,8 @@ type GitRepository interface {
 	GetCommitRange(from, to string) ([]git.CommitInfo, error)
 	GetCommitsBetween(base, head string) ([]git.CommitInfo, error)
 	IsAncestor(ancestorRef, descendantRef string) (bool, error)
+	BranchExists(branchName string) (bool, error)
+	GetBranchParent(branch string) (string, error)
 }
 
 // GitHubClient defines the interface for GitHub operations needed by base detection
@@ -94,7 +96,22 @@ func (d *BaseBranchDetector) DetectBaseBranch(
 		}, nil
 	}
 
-	// Priority 2: If config has defaultBase, use it
+	// Priority 2: Use the parent recorded when the branch was started
+	if d.config.EnableStacking {
+		recordedResult, err := d.detectRecordedParent(ctx, currentBranch)
+		if err != nil {
+			return nil, err
+		}
+		if recordedResult != nil {
+			logger.Info().
+				Str("base", recordedResult.Base).
+				Bool("isStacking", recordedResult.IsStacking).
+				Msg("Using recorded parent branch")
+			return recordedResult, nil
+		}
+	}
+
+	// Priority 3: If config has defaultBase, use it
 	if d.config.DefaultBase != "" {
 		logger.Info().
 			Str("base", d.config.DefaultBase).
@@ -107,7 +124,7 @@ func (d *BaseBranchDetector) DetectBaseBranch(
 		}, nil
 	}
 
-	// Priority 3: If stacking is disabled, use default branch
+	// Priority 4: If stacking is disabled, use default branch
 	if !d.config.EnableStacking {
 		defaultBranch, err := d.repo.GetDefaultBranch()
 		if err != nil {
@@ -124,7 +141,7 @@ func (d *BaseBranchDetector) DetectBaseBranch(
 		}, nil
 	}
 
-	// Priority 4: Auto-detect stacking opportunity
+	// Priority 5: Auto-detect stacking opportunity
 	stackingResult, err := d.detectStackingBase(ctx, currentBranch)
 	if err != nil {
 		return nil, fmt.Errorf("failed to detect stacking base: %w", err)
@@ -138,7 +155,7 @@ func (d *BaseBranchDetector) DetectBaseBranch(
 		return stackingResult, nil
 	}
 
-	// Priority 5: No stacking detected, use default branch
+	// Priority 6: No stacking detected, use default branch
 	defaultBranch, err := d.repo.GetDefaultBranch()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get default branch: %w", err)
@@ -154,6 +171,77 @@ func (d *BaseBranchDetector) DetectBaseBranch(
 	}, nil
 }
 
+// detectRecordedParent returns the parent recorded for currentBranch by
+// gh arc work, auto-branch, diff --base or gh arc stack parent. A recorded
+// feature branch is stacked on while it has an open PR or, before it has one,
+// while it exists on origin. Otherwise detection falls through to the other
+// methods.
+func (d *BaseBranchDetector) detectRecordedParent(
+	ctx context.Context,
+	currentBranch string,
+) (*BaseBranchResult, error) {
+	parent, err := d.repo.GetBranchParent(currentBranch)
+	if err != nil {
+		logger.Debug().
+			Err(err).
+			Str("currentBranch", currentBranch).
+			Msg("Failed to read recorded parent branch")
+		return nil, nil
+	}
+	if parent == "" {
+		return nil, nil
+	}
+
+	defaultBranch, err := d.repo.GetDefaultBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get default branch: %w", err)
+	}
+	if parent == defaultBranch {
+		return &BaseBranchResult{
+			Base:       defaultBranch,
+			IsStacking: false,
+			ParentPR:   nil,
+			Method:     "recorded-parent",
+		}, nil
+	}
+
+	openPRs, err := d.gitHubClient.GetPullRequests(ctx, d.owner, d.repoName, &github.PullRequestListOptions{
+		State:   "open",
+		PerPage: 100,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open PRs: %w", err)
+	}
+
+	for _, pr := range openPRs {
+		if pr.Head.Ref == parent {
+			return &BaseBranchResult{
+				Base:       parent,
+				IsStacking: true,
+				ParentPR:   pr,
+				Method:     "recorded-parent",
+			}, nil
+		}
+	}
+
+	// The parent has no PR yet, but a PR can already target it once pushed
+	pushed, err := d.repo.BranchExists("origin/" + parent)
+	if err == nil && pushed {
+		return &BaseBranchResult{
+			Base:       parent,
+			IsStacking: true,
+			ParentPR:   nil,
+			Method:     "recorded-parent",
+		}, nil
+	}
+
+	logger.Debug().
+		Str("currentBranch", currentBranch).
+		Str("parent", parent).
+		Msg("Recorded parent has no open PR and is not on origin, ignoring it")
+	return nil, nil
+}
+
 // detectStackingBase attempts to detect if current branch should stack on another feature branch
 func (d *BaseBranchDetector) detectStackingBase(
 	ctx context.Context,
@@ -16,6 +16,8 @@ type mockRepository struct {
 	mergeBaseFunc  func(ref1, ref2 string) (string, error)
 	commitRange    func(from, to string) ([]git.CommitInfo, error)
 	isAncestorFunc func(ancestorRef, descendantRef string) (bool, error)
+	parents        map[string]string
+	remoteBranches map[string]bool
 }
 
 func (m *mockRepository) Path() string {
@@ -66,6 +68,14 @@ func (m *mockRepository) IsAncestor(ancestorRef, descendantRef string) (bool, er
 	return true, nil
 }
 
+func (m *mockRepository) BranchExists(branchName string) (bool, error) {
+	return m.remoteBranches[branchName], nil
+}
+
+func (m *mockRepository) GetBranchParent(branch string) (string, error) {
+	return m.parents[branch], nil
+}
+
 // mockGitHubClient implements a minimal mock for github.Client
 type mockGitHubClient struct {
 	pullRequests []*github.PullRequest
@@ -157,6 +167,93 @@ func TestDetectBaseBranch_StackingDisabled(t *testing.T) {
 	}
 }
 
+func TestDetectBaseBranch_RecordedParent(t *testing.T) {
+	parentPR := &github.PullRequest{Number: 7, Head: github.PRBranch{Ref: "parent-branch"}}
+
+	tests := []struct {
+		name           string
+		parent         string
+		prs            []*github.PullRequest
+		pushed         bool
+		enableStacking bool
+		expectedBase   string
+		expectStacking bool
+		expectedMethod string
+	}{
+		{
+			name:           "recorded feature branch with open PR",
+			parent:         "parent-branch",
+			prs:            []*github.PullRequest{parentPR},
+			enableStacking: true,
+			expectedBase:   "parent-branch",
+			expectStacking: true,
+			expectedMethod: "recorded-parent",
+		},
+		{
+			name:           "recorded default branch",
+			parent:         "main",
+			enableStacking: true,
+			expectedBase:   "main",
+			expectedMethod: "recorded-parent",
+		},
+		{
+			name:           "recorded pushed feature branch without PR",
+			parent:         "parent-branch",
+			pushed:         true,
+			enableStacking: true,
+			expectedBase:   "parent-branch",
+			expectStacking: true,
+			expectedMethod: "recorded-parent",
+		},
+		{
+			name:           "recorded unpushed feature branch without PR falls through",
+			parent:         "parent-branch",
+			enableStacking: true,
+			expectedBase:   "main",
+			expectedMethod: "default-branch",
+		},
+		{
+			name:           "ignored when stacking disabled",
+			parent:         "parent-branch",
+			prs:            []*github.PullRequest{parentPR},
+			expectedBase:   "main",
+			expectedMethod: "default-branch-no-stacking",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockRepository{
+				defaultBranch:  "main",
+				parents:        map[string]string{"feature-branch": tt.parent},
+				remoteBranches: map[string]bool{"origin/parent-branch": tt.pushed},
+			}
+			mockClient := &mockGitHubClient{pullRequests: tt.prs}
+			cfg := &config.DiffConfig{EnableStacking: tt.enableStacking}
+
+			detector := NewBaseBranchDetector(mockRepo, mockClient, cfg, "owner", "repo")
+
+			result, err := detector.DetectBaseBranch(context.Background(), "feature-branch", "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Base != tt.expectedBase {
+				t.Errorf("expected base '%s', got '%s'", tt.expectedBase, result.Base)
+			}
+			if result.IsStacking != tt.expectStacking {
+				t.Errorf("expected IsStacking %v, got %v", tt.expectStacking, result.IsStacking)
+			}
+			if result.Method != tt.expectedMethod {
+				t.Errorf("expected method '%s', got '%s'", tt.expectedMethod, result.Method)
+			}
+			if tt.expectStacking && tt.prs != nil && result.ParentPR != parentPR {
+				t.Error("expected ParentPR to be the parent's open PR")
+			}
+		})
+	}
+}
+
 func TestDetectBaseBranch_NoStackingOpportunity(t *testing.T) {
 	mockRepo := &mockRepository{
 		defaultBranch: "main",
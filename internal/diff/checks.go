@@ -0,0 +1,193 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/serpro69/gh-arc/internal/lint"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/runner"
+	"github.com/serpro69/gh-arc/internal/unit"
+)
+
+// checksHeading starts the checks block in the PR body.
+const checksHeading = "## Checks"
+
+var (
+	// ErrChecksFailed is returned when the pre-submit lint or unit checks fail
+	// and --skip-checks was not given.
+	ErrChecksFailed = errors.New("pre-submit checks failed")
+)
+
+// LintChecker runs the lint workflow.
+type LintChecker interface {
+	Execute(ctx context.Context, opts *lint.LintOptions) (*lint.LintResult, error)
+}
+
+// UnitChecker runs the unit test workflow.
+type UnitChecker interface {
+	Execute(ctx context.Context, opts *unit.UnitOptions) (*unit.UnitResult, error)
+}
+
+// CheckOutcome records the result of a single pre-submit check.
+type CheckOutcome struct {
+	Name       string // "Lint" or "Unit"
+	Configured bool   // false when no runners are configured for the check
+	Skipped    bool   // true when the check was skipped via --skip-checks
+	Result     *runner.ExecutionResult
+}
+
+// Summary returns a short status such as "passed", "3 failed" or "skipped".
+func (o *CheckOutcome) Summary() string {
+	switch {
+	case o.Skipped:
+		return "skipped"
+	case o.Result == nil:
+		return "not run"
+	case !o.Result.Success:
+		return fmt.Sprintf("%d failed", o.Result.FailedCount())
+	case o.Result.SkipReason != "":
+		return "skipped (" + o.Result.SkipReason + ")"
+	default:
+		return "passed"
+	}
+}
+
+// Failed returns true if the check ran and did not succeed.
+func (o *CheckOutcome) Failed() bool {
+	return !o.Skipped && o.Result != nil && !o.Result.Success
+}
+
+// ChecksResult contains the outcomes of the pre-submit checks.
+type ChecksResult struct {
+	Lint CheckOutcome
+	Unit CheckOutcome
+}
+
+// Failed returns true if any configured check failed.
+func (r *ChecksResult) Failed() bool {
+	return r.Lint.Failed() || r.Unit.Failed()
+}
+
+// Summary returns a one-line summary like "Lint: passed, Unit: 3 failed",
+// or an empty string if no checks are configured.
+func (r *ChecksResult) Summary() string {
+	if r == nil {
+		return ""
+	}
+	var parts []string
+	for _, o := range r.outcomes() {
+		parts = append(parts, o.Name+": "+o.Summary())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatBody returns the checks block appended to the PR body, or an empty
+// string if no checks are configured.
+func (r *ChecksResult) FormatBody() string {
+	if r == nil {
+		return ""
+	}
+	outcomes := r.outcomes()
+	if len(outcomes) == 0 {
+		return ""
+	}
+
+	lines := []string{checksHeading}
+	for _, o := range outcomes {
+		lines = append(lines, o.Name+": "+o.Summary())
+	}
+	// Two trailing spaces force line breaks in GitHub markdown
+	return strings.Join(lines, "  \n")
+}
+
+// outcomes returns the checks that have runners configured.
+func (r *ChecksResult) outcomes() []*CheckOutcome {
+	var outcomes []*CheckOutcome
+	for _, o := range []*CheckOutcome{&r.Lint, &r.Unit} {
+		if o.Configured {
+			outcomes = append(outcomes, o)
+		}
+	}
+	return outcomes
+}
+
+// ChecksRunner runs the configured lint and unit runners before diff pushes.
+type ChecksRunner struct {
+	lint           LintChecker
+	unit           UnitChecker
+	lintConfigured bool
+	unitConfigured bool
+	out            io.Writer
+}
+
+// NewChecksRunner creates a new pre-submit checks runner. lintConfigured and
+// unitConfigured report whether the respective checks have runners to execute.
+func NewChecksRunner(lintChecker LintChecker, unitChecker UnitChecker, lintConfigured, unitConfigured bool) *ChecksRunner {
+	return &ChecksRunner{
+		lint:           lintChecker,
+		unit:           unitChecker,
+		lintConfigured: lintConfigured,
+		unitConfigured: unitConfigured,
+		out:            os.Stdout,
+	}
+}
+
+// Run executes the lint and unit checks. Both checks run even if the first
+// fails, so the PR body reflects every outcome. With skip set, nothing is
+// executed and configured checks are recorded as skipped.
+func (c *ChecksRunner) Run(ctx context.Context, skip bool) (*ChecksResult, error) {
+	result := &ChecksResult{
+		Lint: CheckOutcome{Name: "Lint", Configured: c.lintConfigured, Skipped: skip},
+		Unit: CheckOutcome{Name: "Unit", Configured: c.unitConfigured, Skipped: skip},
+	}
+	if skip {
+		logger.Debug().Msg("Skipping pre-submit checks")
+		return result, nil
+	}
+
+	if c.lintConfigured {
+		fmt.Fprintln(c.out, "Running lint checks...")
+		lintResult, err := c.lint.Execute(ctx, &lint.LintOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to run lint checks: %w", err)
+		}
+		result.Lint.Result = lintResult.ExecutionResult
+	}
+
+	if c.unitConfigured {
+		fmt.Fprintln(c.out, "Running unit checks...")
+		unitResult, err := c.unit.Execute(ctx, &unit.UnitOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to run unit checks: %w", err)
+		}
+		result.Unit.Result = unitResult.ExecutionResult
+	}
+
+	logger.Debug().
+		Str("lint", result.Lint.Summary()).
+		Str("unit", result.Unit.Summary()).
+		Msg("Pre-submit checks completed")
+
+	return result, nil
+}
+
+// ReplaceChecksBlock replaces an existing checks block in body with block.
+// The second return value is false if body contains no checks block.
+func ReplaceChecksBlock(body, block string) (string, bool) {
+	start := strings.Index(body, checksHeading)
+	if start == -1 || block == "" {
+		return body, false
+	}
+
+	end := len(body)
+	if idx := strings.Index(body[start:], "\n\n"); idx != -1 {
+		end = start + idx
+	}
+
+	return body[:start] + block + body[end:], true
+}
@@ -0,0 +1,237 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/serpro69/gh-arc/internal/lint"
+	"github.com/serpro69/gh-arc/internal/runner"
+	"github.com/serpro69/gh-arc/internal/template"
+	"github.com/serpro69/gh-arc/internal/unit"
+)
+
+type mockLintChecker struct {
+	result *runner.ExecutionResult
+	err    error
+	called bool
+}
+
+func (m *mockLintChecker) Execute(ctx context.Context, opts *lint.LintOptions) (*lint.LintResult, error) {
+	m.called = true
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &lint.LintResult{ExecutionResult: m.result}, nil
+}
+
+type mockUnitChecker struct {
+	result *runner.ExecutionResult
+	err    error
+	called bool
+}
+
+func (m *mockUnitChecker) Execute(ctx context.Context, opts *unit.UnitOptions) (*unit.UnitResult, error) {
+	m.called = true
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &unit.UnitResult{ExecutionResult: m.result}, nil
+}
+
+func passedResult() *runner.ExecutionResult {
+	return &runner.ExecutionResult{
+		Success: true,
+		Runners: []runner.RunResult{{Name: "ok", Status: runner.StatusPassed}},
+	}
+}
+
+func failedResult(n int) *runner.ExecutionResult {
+	result := &runner.ExecutionResult{Success: false}
+	for i := 0; i < n; i++ {
+		result.Runners = append(result.Runners, runner.RunResult{Name: "bad", Status: runner.StatusFailed, ExitCode: 1})
+	}
+	return result
+}
+
+func newTestChecksRunner(l *mockLintChecker, u *mockUnitChecker, lintConfigured, unitConfigured bool) *ChecksRunner {
+	c := NewChecksRunner(l, u, lintConfigured, unitConfigured)
+	c.out = &bytes.Buffer{}
+	return c
+}
+
+func TestChecksRunner_Run(t *testing.T) {
+	t.Run("records outcomes of both checks", func(t *testing.T) {
+		l := &mockLintChecker{result: passedResult()}
+		u := &mockUnitChecker{result: failedResult(3)}
+		c := newTestChecksRunner(l, u, true, true)
+
+		result, err := c.Run(context.Background(), false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !l.called || !u.called {
+			t.Error("Expected both checks to run")
+		}
+		if !result.Failed() {
+			t.Error("Expected result to be failed")
+		}
+		if got := result.Summary(); got != "Lint: passed, Unit: 3 failed" {
+			t.Errorf("Unexpected summary: %q", got)
+		}
+	})
+
+	t.Run("unconfigured checks are not run", func(t *testing.T) {
+		l := &mockLintChecker{result: passedResult()}
+		u := &mockUnitChecker{result: passedResult()}
+		c := newTestChecksRunner(l, u, true, false)
+
+		result, err := c.Run(context.Background(), false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if u.called {
+			t.Error("Expected unit check not to run")
+		}
+		if got := result.Summary(); got != "Lint: passed" {
+			t.Errorf("Unexpected summary: %q", got)
+		}
+	})
+
+	t.Run("skip records configured checks as skipped", func(t *testing.T) {
+		l := &mockLintChecker{result: failedResult(1)}
+		u := &mockUnitChecker{result: failedResult(1)}
+		c := newTestChecksRunner(l, u, true, true)
+
+		result, err := c.Run(context.Background(), true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if l.called || u.called {
+			t.Error("Expected no checks to run")
+		}
+		if result.Failed() {
+			t.Error("Skipped checks must not fail")
+		}
+		if got := result.Summary(); got != "Lint: skipped, Unit: skipped" {
+			t.Errorf("Unexpected summary: %q", got)
+		}
+	})
+
+	t.Run("workflow errors are returned", func(t *testing.T) {
+		l := &mockLintChecker{err: errors.New("boom")}
+		c := newTestChecksRunner(l, &mockUnitChecker{}, true, true)
+
+		_, err := c.Run(context.Background(), false)
+		if err == nil || !strings.Contains(err.Error(), "failed to run lint checks") {
+			t.Errorf("Expected lint error, got %v", err)
+		}
+	})
+}
+
+func TestCheckOutcome_Summary(t *testing.T) {
+	tests := []struct {
+		name    string
+		outcome CheckOutcome
+		want    string
+	}{
+		{"passed", CheckOutcome{Result: passedResult()}, "passed"},
+		{"failed", CheckOutcome{Result: failedResult(2)}, "2 failed"},
+		{"skipped", CheckOutcome{Skipped: true}, "skipped"},
+		{
+			"nothing to check",
+			CheckOutcome{Result: &runner.ExecutionResult{Success: true, SkipReason: lint.SkipReasonNoChangedFiles}},
+			"skipped (no changed files)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.outcome.Summary(); got != tt.want {
+				t.Errorf("Summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChecksResult_FormatBody(t *testing.T) {
+	t.Run("nil result", func(t *testing.T) {
+		var result *ChecksResult
+		if got := result.FormatBody(); got != "" {
+			t.Errorf("Expected empty block, got %q", got)
+		}
+	})
+
+	t.Run("no configured checks", func(t *testing.T) {
+		result := &ChecksResult{}
+		if got := result.FormatBody(); got != "" {
+			t.Errorf("Expected empty block, got %q", got)
+		}
+	})
+
+	t.Run("lint and unit", func(t *testing.T) {
+		result := &ChecksResult{
+			Lint: CheckOutcome{Name: "Lint", Configured: true, Result: passedResult()},
+			Unit: CheckOutcome{Name: "Unit", Configured: true, Result: failedResult(3)},
+		}
+		want := "## Checks  \nLint: passed  \nUnit: 3 failed"
+		if got := result.FormatBody(); got != want {
+			t.Errorf("FormatBody() = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestBuildPRBody(t *testing.T) {
+	fields := &template.TemplateFields{
+		Summary:  "Adds a thing",
+		TestPlan: "go test ./...",
+		Ref:      []string{"ENG-1"},
+	}
+
+	got := BuildPRBody(fields, "## Checks  \nLint: passed")
+	want := "Adds a thing\n\n## Test Plan\ngo test ./...\n\n## Checks  \nLint: passed\n\n**Ref:** ENG-1"
+	if got != want {
+		t.Errorf("BuildPRBody() = %q, want %q", got, want)
+	}
+
+	if got := BuildPRBody(fields, ""); strings.Contains(got, "## Checks") {
+		t.Errorf("Expected no checks block, got %q", got)
+	}
+}
+
+func TestReplaceChecksBlock(t *testing.T) {
+	block := "## Checks  \nLint: passed  \nUnit: passed"
+
+	t.Run("replaces existing block", func(t *testing.T) {
+		body := "Summary\n\n## Checks  \nLint: 2 failed  \nUnit: passed\n\n**Ref:** ENG-1"
+		got, ok := ReplaceChecksBlock(body, block)
+		if !ok {
+			t.Fatal("Expected block to be replaced")
+		}
+		want := "Summary\n\n" + block + "\n\n**Ref:** ENG-1"
+		if got != want {
+			t.Errorf("ReplaceChecksBlock() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("block at end of body", func(t *testing.T) {
+		body := "Summary\n\n## Checks  \nLint: 2 failed"
+		got, ok := ReplaceChecksBlock(body, block)
+		if !ok || got != "Summary\n\n"+block {
+			t.Errorf("ReplaceChecksBlock() = %q, %v", got, ok)
+		}
+	})
+
+	t.Run("no existing block", func(t *testing.T) {
+		body := "Summary"
+		got, ok := ReplaceChecksBlock(body, block)
+		if ok || got != body {
+			t.Errorf("Expected body unchanged, got %q, %v", got, ok)
+		}
+	})
+}
@@ -30,8 +30,9 @@ type ContinueModeOptions struct {
 	CurrentBranch   string
 	NoEdit          bool
 	RequireTestPlan bool
-	Draft           bool // Override draft status from template
-	Ready           bool // Override ready status from template
+	Draft           bool   // Override draft status from template
+	Ready           bool   // Override ready status from template
+	ChecksBlock     string // Pre-submit checks block for the PR body, if any
 }
 
 // ContinueModeResult contains the results of continue mode execution
@@ -241,13 +242,7 @@ func (e *ContinueModeExecutor) Execute(ctx context.Context, opts *ContinueModeOp
 
 	// Step 7: Build PR title and body
 	prTitle := parsedFields.Title
-	prBody := parsedFields.Summary
-	if parsedFields.TestPlan != "" {
-		prBody += "\n\n## Test Plan\n" + parsedFields.TestPlan
-	}
-	if len(parsedFields.Ref) > 0 {
-		prBody += "\n\n**Ref:** " + parsedFields.Ref[0]
-	}
+	prBody := BuildPRBody(parsedFields, opts.ChecksBlock)
 
 	// Step 7.5: Determine draft status (flags override template)
 	isDraft := parsedFields.Draft
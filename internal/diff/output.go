@@ -320,6 +320,11 @@ func FormatErrorWithContext(err error, style *OutputStyle) string {
 		lines = append(lines, "")
 		if e.ParentState == "closed" || e.ParentState == "merged" {
 			lines = append(lines, style.Dim("Tip: Rebase onto trunk or choose a different parent branch"))
+			if e.ParentState == "merged" {
+				lines = append(lines, style.Dim("     gh arc stack fold <parent branch> retargets and rebases every PR stacked on it"))
+			} else {
+				lines = append(lines, style.Dim("     gh arc stack drop <parent branch> retargets and rebases every PR stacked on it"))
+			}
 		}
 
 	case *github.StackingError:
@@ -455,6 +460,12 @@ func FormatDiffResult(result *DiffResult, style *OutputStyle) string {
 		lines = append(lines, FormatReviewersAssigned(result.ReviewersAdded, style))
 	}
 
+	// Pre-submit checks
+	if summary := result.Checks.Summary(); summary != "" {
+		lines = append(lines, "")
+		lines = append(lines, style.Info("Checks: "+summary))
+	}
+
 	// Additional messages
 	if len(result.Messages) > 0 {
 		lines = append(lines, "")
@@ -551,6 +551,7 @@ func TestFormatErrorWithContext(t *testing.T) {
 			"State:     closed",
 			"Reason:    PR was rejected",
 			"Tip: Rebase onto trunk",
+			"gh arc stack drop <parent branch>",
 		}
 
 		for _, part := range expectedParts {
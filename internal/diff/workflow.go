@@ -9,8 +9,11 @@ import (
 	"github.com/serpro69/gh-arc/internal/config"
 	"github.com/serpro69/gh-arc/internal/git"
 	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/lint"
 	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/runner"
 	"github.com/serpro69/gh-arc/internal/template"
+	"github.com/serpro69/gh-arc/internal/unit"
 )
 
 // DiffWorkflow orchestrates the entire diff command workflow.
@@ -27,16 +30,18 @@ type DiffWorkflow struct {
 	dependentDetector  *DependentPRDetector
 	continueExecutor   *ContinueModeExecutor
 	prExecutor         *PRExecutor
+	checksRunner       *ChecksRunner // nil when diff.runChecks is disabled
 }
 
 // DiffOptions contains all options for the diff command
 type DiffOptions struct {
-	Draft    bool
-	Ready    bool
-	Edit     bool
-	NoEdit   bool
-	Continue bool
-	Base     string
+	Draft      bool
+	Ready      bool
+	Edit       bool
+	NoEdit     bool
+	Continue   bool
+	Base       string
+	SkipChecks bool
 }
 
 // DiffResult contains the complete results of the diff workflow
@@ -51,12 +56,13 @@ type DiffResult struct {
 	BaseBranch               string
 	ParentPR                 *github.PullRequest
 	ReviewersAdded           []string
+	Checks                   *ChecksResult
 	Messages                 []string
 }
 
 // NewDiffWorkflow creates a new diff workflow orchestrator
 func NewDiffWorkflow(repo *git.Repository, client *github.Client, cfg *config.Config, owner, name string) *DiffWorkflow {
-	return &DiffWorkflow{
+	w := &DiffWorkflow{
 		repo:               repo,
 		client:             client,
 		config:             cfg,
@@ -68,6 +74,19 @@ func NewDiffWorkflow(repo *git.Repository, client *github.Client, cfg *config.Co
 		continueExecutor:   NewContinueModeExecutor(repo, client, &cfg.Diff, owner, name),
 		prExecutor:         NewPRExecutor(client, repo, owner, name),
 	}
+
+	if cfg.Diff.RunChecks {
+		engine := runner.NewEngine(runner.EngineOptions{})
+		lintWorkflow := lint.NewLintWorkflow(repo, engine, cfg)
+		w.checksRunner = NewChecksRunner(
+			lintWorkflow,
+			unit.NewUnitWorkflow(repo, engine, cfg),
+			lintWorkflow.Configured(),
+			len(cfg.Test.Runners) > 0,
+		)
+	}
+
+	return w
 }
 
 // Execute runs the diff workflow based on the provided options.
@@ -125,6 +144,12 @@ func (w *DiffWorkflow) executeContinueMode(ctx context.Context, opts *DiffOption
 		return nil, fmt.Errorf("failed to get current branch: %w", err)
 	}
 
+	// Run pre-submit checks before anything is pushed
+	checks, err := w.runChecks(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Execute continue mode
 	result, err := w.continueExecutor.Execute(ctx, &ContinueModeOptions{
 		CurrentBranch:   currentBranch,
@@ -132,6 +157,7 @@ func (w *DiffWorkflow) executeContinueMode(ctx context.Context, opts *DiffOption
 		RequireTestPlan: w.config.Diff.RequireTestPlan,
 		Draft:           opts.Draft,
 		Ready:           opts.Ready,
+		ChecksBlock:     checks.FormatBody(),
 	})
 	if err != nil {
 		return nil, err
@@ -146,6 +172,7 @@ func (w *DiffWorkflow) executeContinueMode(ctx context.Context, opts *DiffOption
 		IsStacking:     false, // Continue mode doesn't provide stacking info
 		BaseBranch:     result.BaseBranch,
 		ReviewersAdded: result.ParsedFields.Reviewers,
+		Checks:         checks,
 		Messages:       result.Messages,
 	}, nil
 }
@@ -177,6 +204,12 @@ func (w *DiffWorkflow) executeNormalMode(ctx context.Context, opts *DiffOptions,
 		Bool("isStacking", baseResult.IsStacking).
 		Msg("Base branch detected")
 
+	// An explicit --base becomes the recorded parent for later runs. An
+	// auto-branch does not exist yet; it is recorded once it is created.
+	if opts.Base != "" && autoBranchCtx == nil {
+		w.recordParent(currentBranch, opts.Base)
+	}
+
 	// Step 3: Detect dependent PRs on current branch
 	dependentInfo, err := w.dependentDetector.DetectDependentPRs(ctx, currentBranch)
 	if err != nil {
@@ -246,12 +279,30 @@ func (w *DiffWorkflow) executeFastPath(ctx context.Context, opts *DiffOptions, e
 		hasUnpushed = true // Assume unpushed on error
 	}
 
-	// Push new commits if they exist
+	// Push new commits if they exist, gated by the pre-submit checks
 	if hasUnpushed {
+		checks, err := w.runChecks(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.Checks = checks
+
 		if err := w.repo.Push(ctx, currentBranch); err != nil {
 			return nil, fmt.Errorf("failed to push commits: %w", err)
 		}
 		result.Messages = append(result.Messages, "Pushed new commits")
+
+		// Refresh the checks block so reviewers see results for the new commits
+		if body, ok := ReplaceChecksBlock(existingPR.Body, checks.FormatBody()); ok && body != existingPR.Body {
+			updatedPR, err := w.client.UpdatePullRequest(ctx, w.owner, w.name, existingPR.Number, "", body, nil, nil)
+			if err != nil {
+				logger.Warn().Err(err).Msg("Failed to update checks in PR body")
+			} else {
+				result.PR = updatedPR
+				existingPR = updatedPR
+				result.Messages = append(result.Messages, "Updated checks: "+checks.Summary())
+			}
+		}
 	}
 
 	// Check if base changed and update if needed
@@ -299,6 +350,13 @@ func (w *DiffWorkflow) executeWithTemplateEditing(
 ) (*DiffResult, error) {
 	logger.Debug().Msg("Executing workflow with template editing")
 
+	// Step 0: Run pre-submit checks before the editor, so a failing gate
+	// doesn't discard the user's edits
+	checks, err := w.runChecks(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Step 1: Analyze commits for template pre-filling
 	commitAnalysisBase := "origin/" + baseResult.Base
 	analysis, err := template.AnalyzeCommitsForTemplate(w.repo, commitAnalysisBase, currentBranch)
@@ -396,13 +454,7 @@ func (w *DiffWorkflow) executeWithTemplateEditing(
 		prTitle = fmt.Sprintf("%s [%s]", prTitle, parsedFields.Ref[0])
 	}
 
-	prBody := parsedFields.Summary
-	if parsedFields.TestPlan != "" {
-		prBody += "\n\n## Test Plan\n" + parsedFields.TestPlan
-	}
-	if len(parsedFields.Ref) > 0 {
-		prBody += "\n\n**Ref:** " + parsedFields.Ref[0]
-	}
+	prBody := BuildPRBody(parsedFields, checks.FormatBody())
 
 	// Step 8: Execute auto-branch if needed (push branch to remote)
 	finalHeadBranch := prHeadBranch
@@ -421,6 +473,9 @@ func (w *DiffWorkflow) executeWithTemplateEditing(
 		}
 		finalHeadBranch = finalBranchName
 		prHeadBranch = finalBranchName
+		if !autoBranchCheckoutFailed {
+			w.recordParent(finalBranchName, baseResult.Base)
+		}
 	}
 
 	// Step 9: Get current user for reviewer filtering
@@ -470,10 +525,64 @@ func (w *DiffWorkflow) executeWithTemplateEditing(
 		BaseBranch:               baseResult.Base,
 		ParentPR:                 baseResult.ParentPR,
 		ReviewersAdded:           prResult.ReviewersAdded,
+		Checks:                   checks,
 		Messages:                 prResult.Messages,
 	}, nil
 }
 
+// recordParent stores parent as the stack parent of branch in git config,
+// together with the commit where branch forked from it, so base detection
+// need not re-derive the relationship. Failures are only logged.
+func (w *DiffWorkflow) recordParent(branch, parent string) {
+	forkPoint, err := w.repo.GetMergeBase(branch, "origin/"+parent)
+	if err != nil {
+		forkPoint, err = w.repo.GetMergeBase(branch, parent)
+	}
+	if err != nil {
+		logger.Debug().Err(err).Str("branch", branch).Str("parent", parent).Msg("Failed to find fork point from parent")
+		forkPoint = ""
+	}
+
+	if err := w.repo.SetBranchParent(branch, parent, forkPoint); err != nil {
+		logger.Warn().Err(err).Str("branch", branch).Msg("Failed to record parent branch")
+	}
+}
+
+// runChecks runs the pre-submit lint and unit checks when diff.runChecks is
+// enabled. It returns ErrChecksFailed if a check fails and --skip-checks
+// was not given. The result is nil when checks are disabled.
+func (w *DiffWorkflow) runChecks(ctx context.Context, opts *DiffOptions) (*ChecksResult, error) {
+	if w.checksRunner == nil {
+		return nil, nil
+	}
+
+	checks, err := w.checksRunner.Run(ctx, opts.SkipChecks)
+	if err != nil {
+		return nil, err
+	}
+	if checks.Failed() {
+		return checks, fmt.Errorf("%w: %s", ErrChecksFailed, checks.Summary())
+	}
+
+	return checks, nil
+}
+
+// BuildPRBody assembles the PR body from template fields. The checks block,
+// if any, follows the Test Plan.
+func BuildPRBody(fields *template.TemplateFields, checksBlock string) string {
+	body := fields.Summary
+	if fields.TestPlan != "" {
+		body += "\n\n## Test Plan\n" + fields.TestPlan
+	}
+	if checksBlock != "" {
+		body += "\n\n" + checksBlock
+	}
+	if len(fields.Ref) > 0 {
+		body += "\n\n**Ref:** " + fields.Ref[0]
+	}
+	return body
+}
+
 // getReviewerSuggestions gets reviewer suggestions from CODEOWNERS and config
 func (w *DiffWorkflow) getReviewerSuggestions(ctx context.Context, currentBranch string, baseResult *BaseBranchResult) ([]string, error) {
 	var reviewerSuggestions []string
@@ -0,0 +1,134 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+// ImportRepository defines git operations needed to import a series.
+type ImportRepository interface {
+	GetWorkingDirectoryStatus() (*git.WorkingDirectoryStatus, error)
+	ApplyMailbox(mbox string) error
+	ApplyPatch(patch string) error
+}
+
+// ImportWorkflow applies a series written by export back onto the current
+// branch: mailbox series become commits via git am, with cover letters
+// dropped, and a plain .diff is applied to the index without committing.
+type ImportWorkflow struct {
+	repo  ImportRepository
+	stdin io.Reader
+}
+
+// NewImportWorkflow creates a new ImportWorkflow.
+func NewImportWorkflow(repo ImportRepository) *ImportWorkflow {
+	return &ImportWorkflow{
+		repo:  repo,
+		stdin: os.Stdin,
+	}
+}
+
+// Execute imports the given paths in order. Each path may be an mbox or
+// .patch file, a .diff file, a directory of .patch files (as written by
+// export -o), or "-" for stdin.
+func (w *ImportWorkflow) Execute(paths []string) (*ImportResult, error) {
+	logger.Debug().
+		Strs("paths", paths).
+		Msg("Executing import workflow")
+
+	content, err := w.read(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := w.repo.GetWorkingDirectoryStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check working directory: %w", err)
+	}
+	if !status.IsClean {
+		return nil, ErrDirtyWorkingDir
+	}
+
+	result := &ImportResult{}
+
+	messages := splitMailbox(content)
+	if messages == nil {
+		if strings.TrimSpace(content) == "" {
+			return nil, ErrNoPatches
+		}
+		if err := w.repo.ApplyPatch(content); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrImportFailed, err)
+		}
+		result.Diff = true
+		return result, nil
+	}
+
+	var series strings.Builder
+	for _, m := range messages {
+		if isCoverLetter(m) {
+			result.SkippedCovers++
+			continue
+		}
+		series.WriteString(m)
+		result.Patches++
+	}
+	if result.Patches == 0 {
+		return nil, ErrNoPatches
+	}
+
+	if err := w.repo.ApplyMailbox(series.String()); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImportFailed, err)
+	}
+	return result, nil
+}
+
+// read concatenates the inputs, expanding directories to their .patch files
+// in name order.
+func (w *ImportWorkflow) read(paths []string) (string, error) {
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	var sb strings.Builder
+	for _, path := range paths {
+		if path == "-" {
+			data, err := io.ReadAll(w.stdin)
+			if err != nil {
+				return "", fmt.Errorf("failed to read stdin: %w", err)
+			}
+			sb.Write(data)
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		files := []string{path}
+		if info.IsDir() {
+			files, err = filepath.Glob(filepath.Join(path, "*.patch"))
+			if err != nil {
+				return "", fmt.Errorf("failed to list %s: %w", path, err)
+			}
+			sort.Strings(files)
+		}
+
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return "", fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			sb.Write(data)
+		}
+	}
+
+	return sb.String(), nil
+}
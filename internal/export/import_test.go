@@ -0,0 +1,110 @@
+package export
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/git"
+)
+
+type mockImportRepo struct {
+	dirty   bool
+	mailbox string
+	patch   string
+	amErr   error
+}
+
+func (m *mockImportRepo) GetWorkingDirectoryStatus() (*git.WorkingDirectoryStatus, error) {
+	return &git.WorkingDirectoryStatus{IsClean: !m.dirty}, nil
+}
+
+func (m *mockImportRepo) ApplyMailbox(mbox string) error {
+	m.mailbox = mbox
+	return m.amErr
+}
+
+func (m *mockImportRepo) ApplyPatch(patch string) error {
+	m.patch = patch
+	return nil
+}
+
+func testMessage(subject string) string {
+	return "From " + strings.Repeat("c", 40) + " " + mboxFromLineDate + "\nSubject: " + subject + "\n\nbody\n"
+}
+
+func TestImportWorkflow(t *testing.T) {
+	cover, p1, p2 := testMessage("[PATCH 0/2] Cover"), testMessage("[PATCH 1/2] One"), testMessage("[PATCH 2/2] Two")
+
+	t.Run("directory in name order without cover letter", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "0002-Two.patch"), []byte(p2), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "0000-cover-letter.patch"), []byte(cover), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "0001-One.patch"), []byte(p1), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644))
+
+		repo := &mockImportRepo{}
+		result, err := NewImportWorkflow(repo).Execute([]string{dir})
+		require.NoError(t, err)
+
+		assert.Equal(t, p1+p2, repo.mailbox)
+		assert.Equal(t, 2, result.Patches)
+		assert.Equal(t, 1, result.SkippedCovers)
+	})
+
+	t.Run("stdin", func(t *testing.T) {
+		repo := &mockImportRepo{}
+		w := NewImportWorkflow(repo)
+		w.stdin = strings.NewReader(cover + p1)
+
+		result, err := w.Execute(nil)
+		require.NoError(t, err)
+		assert.Equal(t, p1, repo.mailbox)
+		assert.Equal(t, 1, result.Patches)
+	})
+
+	t.Run("plain diff", func(t *testing.T) {
+		repo := &mockImportRepo{}
+		w := NewImportWorkflow(repo)
+		w.stdin = strings.NewReader("diff --git a/x b/x\n")
+
+		result, err := w.Execute([]string{"-"})
+		require.NoError(t, err)
+		assert.True(t, result.Diff)
+		assert.Equal(t, "diff --git a/x b/x\n", repo.patch)
+	})
+
+	t.Run("only a cover letter", func(t *testing.T) {
+		w := NewImportWorkflow(&mockImportRepo{})
+		w.stdin = strings.NewReader(cover)
+
+		_, err := w.Execute(nil)
+		assert.True(t, errors.Is(err, ErrNoPatches))
+	})
+
+	t.Run("dirty tree", func(t *testing.T) {
+		w := NewImportWorkflow(&mockImportRepo{dirty: true})
+		w.stdin = strings.NewReader(p1)
+
+		_, err := w.Execute(nil)
+		assert.True(t, errors.Is(err, ErrDirtyWorkingDir))
+	})
+
+	t.Run("am failure", func(t *testing.T) {
+		w := NewImportWorkflow(&mockImportRepo{amErr: errors.New("patch does not apply")})
+		w.stdin = strings.NewReader(p1)
+
+		_, err := w.Execute(nil)
+		assert.True(t, errors.Is(err, ErrImportFailed))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewImportWorkflow(&mockImportRepo{}).Execute([]string{filepath.Join(t.TempDir(), "nope.patch")})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,145 @@
+package export
+
+import (
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// mboxFromLineDate is the fixed date git format-patch writes on the mbox
+// separator line, which git mailsplit recognizes.
+const mboxFromLineDate = "Mon Sep 17 00:00:00 2001"
+
+// zeroSHA marks the cover letter, which has no commit.
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// maxSlugLength matches git format-patch's file name subject limit.
+const maxSlugLength = 52
+
+var (
+	// mboxSeparator matches the "From <sha> <date>" line starting each message.
+	mboxSeparator = regexp.MustCompile(`(?m)^From [0-9a-f]{40} ` + mboxFromLineDate + `$`)
+
+	// coverSubject matches the subject of a cover letter: [PATCH 0/N] or [PATCH v2 00/N].
+	coverSubject = regexp.MustCompile(`(?m)^Subject: \[[^\]]*\b0+/\d+\]`)
+
+	slugUnsafe = regexp.MustCompile(`[^A-Za-z0-9._]+`)
+)
+
+// mailHeader describes the headers of one mbox message.
+type mailHeader struct {
+	SHA     string
+	Name    string
+	Email   string
+	Date    time.Time
+	Subject string
+}
+
+// formatMessage renders one format-patch style message. patch is the
+// diffstat and diff placed after the "---" separator; it is empty for the
+// cover letter.
+func formatMessage(h mailHeader, body, patch string) string {
+	var sb strings.Builder
+
+	sha := h.SHA
+	if sha == "" {
+		sha = zeroSHA
+	}
+	fmt.Fprintf(&sb, "From %s %s\n", sha, mboxFromLineDate)
+	fmt.Fprintf(&sb, "From: %s <%s>\n", encodeHeader(h.Name), h.Email)
+	fmt.Fprintf(&sb, "Date: %s\n", h.Date.Format(time.RFC1123Z))
+	fmt.Fprintf(&sb, "Subject: %s\n", encodeHeader(h.Subject))
+	if !isASCII(body) {
+		sb.WriteString("MIME-Version: 1.0\n")
+		sb.WriteString("Content-Type: text/plain; charset=UTF-8\n")
+		sb.WriteString("Content-Transfer-Encoding: 8bit\n")
+	}
+	sb.WriteString("\n")
+
+	body = strings.TrimSpace(body)
+	if body != "" {
+		sb.WriteString(body)
+		sb.WriteString("\n")
+	}
+
+	if patch != "" {
+		sb.WriteString("---\n")
+		sb.WriteString(patch)
+		if !strings.HasSuffix(patch, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("-- \ngh-arc\n\n")
+	return sb.String()
+}
+
+// subjectPrefix returns the [PATCH n/N] prefix, or [PATCH] for a lone patch.
+func subjectPrefix(n, total int, withCover bool) string {
+	if total == 1 && !withCover {
+		return "[PATCH]"
+	}
+	width := len(fmt.Sprint(total))
+	return fmt.Sprintf("[PATCH %0*d/%d]", width, n, total)
+}
+
+// patchFilename returns the format-patch style file name for message n.
+func patchFilename(n int, subject string) string {
+	return fmt.Sprintf("%04d-%s.patch", n, slugify(subject))
+}
+
+// slugify turns a subject into a file name fragment the way format-patch does.
+func slugify(subject string) string {
+	slug := strings.Trim(slugUnsafe.ReplaceAllString(subject, "-"), "-.")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-.")
+	}
+	if slug == "" {
+		slug = "patch"
+	}
+	return slug
+}
+
+// splitMailbox splits an mbox into its messages, each starting with its
+// "From <sha>" separator line. Returns nil if content is not an mbox.
+func splitMailbox(content string) []string {
+	starts := mboxSeparator.FindAllStringIndex(content, -1)
+	if len(starts) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(starts))
+	for i, loc := range starts {
+		end := len(content)
+		if i+1 < len(starts) {
+			end = starts[i+1][0]
+		}
+		messages = append(messages, content[loc[0]:end])
+	}
+	return messages
+}
+
+// isCoverLetter reports whether a message is a [PATCH 0/N] cover letter.
+func isCoverLetter(message string) bool {
+	headers, _, _ := strings.Cut(message, "\n\n")
+	return coverSubject.MatchString(headers)
+}
+
+func encodeHeader(value string) string {
+	if isASCII(value) {
+		return value
+	}
+	return mime.QEncoding.Encode("utf-8", value)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
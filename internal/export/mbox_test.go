@@ -0,0 +1,76 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubjectPrefix(t *testing.T) {
+	assert.Equal(t, "[PATCH]", subjectPrefix(1, 1, false))
+	assert.Equal(t, "[PATCH 1/1]", subjectPrefix(1, 1, true))
+	assert.Equal(t, "[PATCH 0/3]", subjectPrefix(0, 3, true))
+	assert.Equal(t, "[PATCH 02/12]", subjectPrefix(2, 12, false))
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "Fix-the-parser-for-foo.bar", slugify("Fix the parser: for foo.bar!"))
+	assert.Equal(t, "patch", slugify("!!!"))
+	assert.Len(t, slugify(strings.Repeat("word ", 30)), maxSlugLength)
+	assert.Equal(t, "0003-Add-thing.patch", patchFilename(3, "Add thing"))
+}
+
+func TestFormatMessage(t *testing.T) {
+	date := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("patch", func(t *testing.T) {
+		msg := formatMessage(mailHeader{
+			SHA: strings.Repeat("a", 40), Name: "Alice", Email: "alice@example.com", Date: date, Subject: "[PATCH] Fix",
+		}, "Body\n", " x | 1 +\n\ndiff --git a/x b/x\n")
+
+		assert.Equal(t, "From "+strings.Repeat("a", 40)+" Mon Sep 17 00:00:00 2001\n"+
+			"From: Alice <alice@example.com>\n"+
+			"Date: Fri, 02 Jan 2026 03:04:05 +0000\n"+
+			"Subject: [PATCH] Fix\n"+
+			"\n"+
+			"Body\n"+
+			"---\n"+
+			" x | 1 +\n\ndiff --git a/x b/x\n"+
+			"-- \ngh-arc\n\n", msg)
+	})
+
+	t.Run("non-ASCII", func(t *testing.T) {
+		msg := formatMessage(mailHeader{Name: "Zoë", Email: "z@example.com", Date: date, Subject: "[PATCH 0/1] Café"}, "Crème", "")
+
+		assert.True(t, strings.HasPrefix(msg, "From "+zeroSHA))
+		assert.Contains(t, msg, "From: =?utf-8?q?Zo=C3=AB?= <z@example.com>")
+		assert.Contains(t, msg, "Subject: =?utf-8?q?[PATCH_0/1]_Caf=C3=A9?=")
+		assert.Contains(t, msg, "Content-Type: text/plain; charset=UTF-8")
+		assert.NotContains(t, msg, "---\n")
+	})
+}
+
+func TestSplitMailbox(t *testing.T) {
+	sha := strings.Repeat("b", 40)
+	one := "From " + zeroSHA + " " + mboxFromLineDate + "\nSubject: [PATCH 0/2] Cover\n\nFrom the summary\n"
+	two := "From " + sha + " " + mboxFromLineDate + "\nSubject: [PATCH 1/2] One\n\nbody\n"
+	three := "From " + sha + " " + mboxFromLineDate + "\nSubject: [PATCH 2/2] Two\n\nbody\n"
+
+	messages := splitMailbox(one + two + three)
+	assert.Equal(t, []string{one, two, three}, messages)
+
+	assert.True(t, isCoverLetter(messages[0]))
+	assert.False(t, isCoverLetter(messages[1]))
+	assert.True(t, isCoverLetter("Subject: [PATCH v2 00/10] Series\n\nbody"))
+	assert.False(t, isCoverLetter("Subject: [PATCH 1/10] Fix 0/10 issue\n\nbody"))
+
+	assert.Nil(t, splitMailbox("diff --git a/x b/x\n"))
+}
+
+func TestPRSummary(t *testing.T) {
+	assert.Equal(t, "Fixes it.\n\nDetails.", prSummary("Fixes it.\r\n\r\nDetails.\r\n\r\n## Test Plan\r\nRan it."))
+	assert.Equal(t, "Only summary", prSummary("Only summary\n\n**Ref:** #1"))
+	assert.Empty(t, prSummary(""))
+}
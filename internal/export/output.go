@@ -0,0 +1,8 @@
+package export
+
+import "encoding/json"
+
+// FormatJSON returns the result as indented JSON.
+func FormatJSON(result *ExportResult) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
@@ -0,0 +1,33 @@
+package export
+
+// ExportOptions contains options for the export command
+type ExportOptions struct {
+	Target  string // PR number/URL, "base..head" range, or empty for the current branch
+	Diff    bool   // Emit a single unified diff instead of a patch series
+	NoCover bool   // Omit the cover letter from a patch series
+}
+
+// Message is one file of an export: a patch, the cover letter, or the diff.
+type Message struct {
+	Filename string `json:"filename"`
+	Subject  string `json:"subject,omitempty"`
+	SHA      string `json:"sha,omitempty"` // Commit the patch was generated from
+	Content  string `json:"-"`
+}
+
+// ExportResult contains the generated series.
+type ExportResult struct {
+	Base     string    `json:"base"` // Merge-base the series applies on
+	Head     string    `json:"head"`
+	PRNumber int       `json:"pr_number,omitempty"`
+	Title    string    `json:"title"`
+	Messages []Message `json:"messages"`
+	Skipped  []string  `json:"skipped,omitempty"` // Commits without a patch (merges, empty commits)
+}
+
+// ImportResult contains the outcome of importing a series.
+type ImportResult struct {
+	Patches       int  `json:"patches"`        // Commits created via git am
+	Diff          bool `json:"diff"`           // Input was a plain diff, applied without committing
+	SkippedCovers int  `json:"skipped_covers"` // Cover letters dropped from the input
+}
@@ -0,0 +1,376 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/patch"
+	"github.com/serpro69/gh-arc/internal/template"
+)
+
+var (
+	// ErrNoCommits is returned when the range to export contains no commits.
+	ErrNoCommits = errors.New("no commits to export")
+
+	// ErrNoPatches is returned when an import contains nothing to apply.
+	ErrNoPatches = errors.New("no patches found")
+
+	// ErrDirtyWorkingDir is returned when importing into a working directory
+	// with uncommitted changes.
+	ErrDirtyWorkingDir = errors.New("working directory has uncommitted changes")
+
+	// ErrImportFailed is returned when the series doesn't apply.
+	ErrImportFailed = errors.New("failed to import patch series")
+)
+
+// ExportRepository defines git operations needed by the export workflow.
+type ExportRepository interface {
+	GetDefaultBranch() (string, error)
+	GetMergeBase(ref1, ref2 string) (string, error)
+	GetCommitsBetween(base, head string) ([]git.CommitInfo, error)
+	GetCommitPatch(sha string) (string, error)
+	GetBinaryDiff(base, head string) (string, error)
+	GetGitConfig(key string) (string, error)
+	Fetch(ctx context.Context, remote string, refspecs ...string) error
+}
+
+// ExportGitHubClient defines GitHub operations needed to export a PR.
+type ExportGitHubClient interface {
+	GetPullRequest(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error)
+}
+
+// ExportWorkflow renders a PR or a local commit range as a unified diff or
+// a git format-patch compatible mailbox series.
+type ExportWorkflow struct {
+	repo   ExportRepository
+	client ExportGitHubClient
+	owner  string
+	name   string
+	now    func() time.Time
+}
+
+// NewExportWorkflow creates a new ExportWorkflow. client may be nil when
+// only local ranges are exported.
+func NewExportWorkflow(repo ExportRepository, client ExportGitHubClient, owner, name string) *ExportWorkflow {
+	return &ExportWorkflow{
+		repo:   repo,
+		client: client,
+		owner:  owner,
+		name:   name,
+		now:    time.Now,
+	}
+}
+
+// IsRangeTarget reports whether target refers to local commits rather than a
+// pull request, so no GitHub access is needed.
+func IsRangeTarget(target string) bool {
+	return target == "" || strings.Contains(target, "..")
+}
+
+// exportSource is the resolved range plus the cover letter content.
+type exportSource struct {
+	base     string // Merge-base
+	head     string
+	prNumber int
+	title    string
+	summary  string
+	url      string
+}
+
+// Execute runs the export workflow.
+func (w *ExportWorkflow) Execute(ctx context.Context, opts *ExportOptions) (*ExportResult, error) {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+
+	logger.Debug().
+		Str("target", opts.Target).
+		Bool("diff", opts.Diff).
+		Bool("noCover", opts.NoCover).
+		Msg("Executing export workflow")
+
+	// Step 1: Resolve what to export
+	var src *exportSource
+	var err error
+	if IsRangeTarget(opts.Target) {
+		src, err = w.resolveRange(opts.Target)
+	} else {
+		src, err = w.resolvePR(ctx, opts.Target)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExportResult{
+		Base:     src.base,
+		Head:     src.head,
+		PRNumber: src.prNumber,
+		Title:    src.title,
+	}
+
+	// Step 2: Render
+	if opts.Diff {
+		diff, err := w.repo.GetBinaryDiff(src.base, src.head)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get diff: %w", err)
+		}
+		if strings.TrimSpace(diff) == "" {
+			return nil, fmt.Errorf("%w between %s and %s", ErrNoCommits, src.base, src.head)
+		}
+		result.Messages = []Message{{
+			Filename: slugify(src.title) + ".diff",
+			Subject:  src.title,
+			Content:  diff,
+		}}
+		return result, nil
+	}
+
+	if err := w.buildSeries(src, !opts.NoCover, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// resolveRange resolves "base..head", "base..", or "" (the current branch
+// against the default branch) to a merge-base and head.
+func (w *ExportWorkflow) resolveRange(target string) (*exportSource, error) {
+	base, head, _ := strings.Cut(target, "..")
+	head = strings.TrimPrefix(head, ".") // Accept base...head too
+	if head == "" {
+		head = "HEAD"
+	}
+
+	if base == "" {
+		defaultBranch, err := w.repo.GetDefaultBranch()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine default branch: %w", err)
+		}
+		base = defaultBranch
+		if _, err := w.repo.GetMergeBase("origin/"+defaultBranch, head); err == nil {
+			base = "origin/" + defaultBranch
+		}
+	}
+
+	mergeBase, err := w.repo.GetMergeBase(base, head)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve range %s..%s: %w", base, head, err)
+	}
+
+	analysis, err := template.AnalyzeCommitsForTemplate(w.repo, mergeBase, head)
+	if err != nil {
+		return nil, err
+	}
+	if analysis.CommitCount == 0 {
+		return nil, fmt.Errorf("%w in %s..%s", ErrNoCommits, base, head)
+	}
+
+	return &exportSource{
+		base:    mergeBase,
+		head:    head,
+		title:   analysis.Title,
+		summary: analysis.Summary,
+	}, nil
+}
+
+// resolvePR fetches the PR head via the base repository's pull/<n>/head ref,
+// which also covers PRs from forks, and its base branch.
+func (w *ExportWorkflow) resolvePR(ctx context.Context, target string) (*exportSource, error) {
+	number, err := patch.ParsePRRef(target, w.owner, w.name)
+	if err != nil {
+		return nil, err
+	}
+	if w.client == nil {
+		return nil, fmt.Errorf("exporting PR #%d requires a GitHub client", number)
+	}
+
+	pr, err := w.client.GetPullRequest(ctx, w.owner, w.name, number)
+	if err != nil {
+		return nil, err
+	}
+
+	head := fmt.Sprintf("origin/pr/%d", pr.Number)
+	base := "origin/" + pr.Base.Ref
+	if err := w.repo.Fetch(ctx, "origin",
+		fmt.Sprintf("+refs/pull/%d/head:refs/remotes/%s", pr.Number, head),
+		fmt.Sprintf("+refs/heads/%s:refs/remotes/%s", pr.Base.Ref, base),
+	); err != nil {
+		return nil, fmt.Errorf("failed to fetch PR #%d: %w", pr.Number, err)
+	}
+
+	mergeBase, err := w.repo.GetMergeBase(base, head)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge-base of PR #%d: %w", pr.Number, err)
+	}
+
+	return &exportSource{
+		base:     mergeBase,
+		head:     head,
+		prNumber: pr.Number,
+		title:    pr.Title,
+		summary:  prSummary(pr.Body),
+		url:      pr.HTMLURL,
+	}, nil
+}
+
+// buildSeries renders one message per commit, oldest first, plus an
+// optional cover letter.
+func (w *ExportWorkflow) buildSeries(src *exportSource, withCover bool, result *ExportResult) error {
+	commits, err := w.repo.GetCommitsBetween(src.base, src.head)
+	if err != nil {
+		return fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	// Commits come newest first; the series must apply oldest first
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+
+	type entry struct {
+		commit git.CommitInfo
+		patch  string
+	}
+	entries := make([]entry, 0, len(commits))
+	for _, c := range commits {
+		p, err := w.repo.GetCommitPatch(c.SHA)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(p) == "" {
+			logger.Debug().Str("sha", c.SHA).Msg("Skipping commit without a patch")
+			result.Skipped = append(result.Skipped, c.SHA)
+			continue
+		}
+		entries = append(entries, entry{commit: c, patch: p})
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("%w between %s and %s", ErrNoCommits, src.base, src.head)
+	}
+
+	total := len(entries)
+	if withCover {
+		shortlog := make([]git.CommitInfo, 0, total)
+		for _, e := range entries {
+			shortlog = append(shortlog, e.commit)
+		}
+		subject := subjectPrefix(0, total, true) + " " + src.title
+		result.Messages = append(result.Messages, Message{
+			Filename: "0000-cover-letter.patch",
+			Subject:  subject,
+			Content:  formatMessage(w.coverHeader(subject, entries[0].commit), coverBody(src, shortlog), ""),
+		})
+	}
+
+	for i, e := range entries {
+		parsed := git.ParseCommitMessage(e.commit.Message)
+		subject := subjectPrefix(i+1, total, withCover) + " " + parsed.Title
+		result.Messages = append(result.Messages, Message{
+			Filename: patchFilename(i+1, parsed.Title),
+			Subject:  subject,
+			SHA:      e.commit.SHA,
+			Content: formatMessage(mailHeader{
+				SHA:     e.commit.SHA,
+				Name:    e.commit.Author,
+				Email:   e.commit.Email,
+				Date:    e.commit.Date,
+				Subject: subject,
+			}, parsed.Body, e.patch),
+		})
+	}
+
+	return nil
+}
+
+// coverHeader attributes the cover letter to the configured git user,
+// falling back to the author of the first commit.
+func (w *ExportWorkflow) coverHeader(subject string, first git.CommitInfo) mailHeader {
+	h := mailHeader{Name: first.Author, Email: first.Email, Date: w.now(), Subject: subject}
+	if name, err := w.repo.GetGitConfig("user.name"); err == nil && name != "" {
+		h.Name = name
+	}
+	if email, err := w.repo.GetGitConfig("user.email"); err == nil && email != "" {
+		h.Email = email
+	}
+	return h
+}
+
+// coverBody renders the summary followed by a shortlog of the series.
+func coverBody(src *exportSource, commits []git.CommitInfo) string {
+	var sb strings.Builder
+
+	if src.summary != "" {
+		sb.WriteString(strings.TrimSpace(src.summary))
+		sb.WriteString("\n\n")
+	}
+	if src.url != "" {
+		fmt.Fprintf(&sb, "Pull-Request: %s\n\n", src.url)
+	}
+
+	// Shortlog grouped by author, in order of first appearance
+	var authors []string
+	byAuthor := make(map[string][]string)
+	for _, c := range commits {
+		if _, ok := byAuthor[c.Author]; !ok {
+			authors = append(authors, c.Author)
+		}
+		byAuthor[c.Author] = append(byAuthor[c.Author], git.ParseCommitMessage(c.Message).Title)
+	}
+	for _, author := range authors {
+		fmt.Fprintf(&sb, "%s (%d):\n", author, len(byAuthor[author]))
+		for _, title := range byAuthor[author] {
+			fmt.Fprintf(&sb, "  %s\n", title)
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// prSummary returns the summary part of a PR body, dropping the sections
+// gh arc diff appends after it (test plan, checks, ref).
+func prSummary(body string) string {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") || strings.HasPrefix(line, "**Ref:**") {
+			lines = lines[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// WriteMailbox writes all messages to w, concatenated into one stream.
+func WriteMailbox(out io.Writer, result *ExportResult) error {
+	for _, m := range result.Messages {
+		if _, err := io.WriteString(out, m.Content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", m.Filename, err)
+		}
+	}
+	return nil
+}
+
+// WriteFiles writes each message to its own file in dir, creating dir if
+// needed, and returns the written paths.
+func WriteFiles(dir string, result *ExportResult) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	paths := make([]string, 0, len(result.Messages))
+	for _, m := range result.Messages {
+		path := filepath.Join(dir, m.Filename)
+		if err := os.WriteFile(path, []byte(m.Content), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
@@ -0,0 +1,257 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+// runGit runs a git command in dir with a fixed identity and returns its trimmed output.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test User", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test User", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_GLOBAL=/dev/null",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v: %s", args, out)
+	return strings.TrimSpace(string(out))
+}
+
+func commitFile(t *testing.T, dir, file, content, message string, env ...string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0644))
+	runGit(t, dir, "add", file)
+	args := []string{"commit", "-q", "-m", message}
+	if len(env) > 0 {
+		args = append(args, "--author", env[0])
+	}
+	runGit(t, dir, args...)
+}
+
+// setupSeriesRepo creates a repository with main and a feature branch two
+// commits ahead, one of them with a binary file and a non-ASCII subject.
+func setupSeriesRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "-b", "main")
+	runGit(t, dir, "config", "user.name", "Cover Author")
+	runGit(t, dir, "config", "user.email", "cover@example.com")
+	commitFile(t, dir, "a.txt", "one\n", "initial")
+
+	runGit(t, dir, "checkout", "-q", "-b", "feature")
+	commitFile(t, dir, "a.txt", "one\ntwo\n", "Add second line\n\nExplains why.", "Alice <alice@example.com>")
+	commitFile(t, dir, "b.bin", "\x00\x01\x02", "Añadir binario")
+	return dir
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	dir := setupSeriesRepo(t)
+	repo, err := git.OpenRepository(dir)
+	require.NoError(t, err)
+
+	w := NewExportWorkflow(repo, nil, "owner", "repo")
+	w.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	result, err := w.Execute(context.Background(), &ExportOptions{Target: "main..feature"})
+	require.NoError(t, err)
+
+	require.Len(t, result.Messages, 3)
+	assert.Equal(t, "0000-cover-letter.patch", result.Messages[0].Filename)
+	assert.Equal(t, "0001-Add-second-line.patch", result.Messages[1].Filename)
+	assert.Equal(t, "[PATCH 1/2] Add second line", result.Messages[1].Subject)
+
+	cover := result.Messages[0].Content
+	assert.Contains(t, cover, "From "+zeroSHA+" "+mboxFromLineDate)
+	assert.Contains(t, cover, "From: Cover Author <cover@example.com>")
+	assert.Contains(t, cover, "Subject: [PATCH 0/2] Add second line")
+	assert.Contains(t, cover, "Alice (1):\n  Add second line")
+
+	first := result.Messages[1].Content
+	assert.Contains(t, first, "From: Alice <alice@example.com>")
+	assert.Contains(t, first, "Explains why.\n---\n a.txt | 1 +")
+	assert.Contains(t, result.Messages[2].Content, "Subject: =?utf-8?q?")
+	assert.Contains(t, result.Messages[2].Content, "GIT binary patch")
+
+	outDir := filepath.Join(t.TempDir(), "series")
+	paths, err := WriteFiles(outDir, result)
+	require.NoError(t, err)
+	assert.Len(t, paths, 3)
+
+	// Import onto main in a fresh clone and compare trees and messages
+	clone := t.TempDir()
+	runGit(t, clone, "clone", "-q", "-b", "main", dir, ".")
+	cloneRepo, err := git.OpenRepository(clone)
+	require.NoError(t, err)
+
+	t.Setenv("GIT_COMMITTER_NAME", "Importer")
+	t.Setenv("GIT_COMMITTER_EMAIL", "importer@example.com")
+
+	imported, err := NewImportWorkflow(cloneRepo).Execute([]string{outDir})
+	require.NoError(t, err)
+	assert.Equal(t, 2, imported.Patches)
+	assert.Equal(t, 1, imported.SkippedCovers)
+
+	assert.Equal(t, runGit(t, dir, "rev-parse", "feature^{tree}"), runGit(t, clone, "rev-parse", "HEAD^{tree}"))
+	assert.Equal(t,
+		runGit(t, dir, "log", "--format=%an <%ae> %at%n%B", "main..feature"),
+		runGit(t, clone, "log", "--format=%an <%ae> %at%n%B", "origin/main..HEAD"))
+}
+
+func TestExportMailboxStdout(t *testing.T) {
+	dir := setupSeriesRepo(t)
+	repo, err := git.OpenRepository(dir)
+	require.NoError(t, err)
+
+	result, err := NewExportWorkflow(repo, nil, "owner", "repo").
+		Execute(context.Background(), &ExportOptions{Target: "main..feature", NoCover: true})
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 2)
+	assert.Equal(t, "[PATCH 1/2] Add second line", result.Messages[0].Subject)
+
+	var sb strings.Builder
+	require.NoError(t, WriteMailbox(&sb, result))
+
+	// git itself must be able to split and apply the stream
+	clone := t.TempDir()
+	runGit(t, clone, "clone", "-q", "-b", "main", dir, ".")
+	mbox := filepath.Join(t.TempDir(), "series.mbox")
+	require.NoError(t, os.WriteFile(mbox, []byte(sb.String()), 0644))
+	runGit(t, clone, "am", "-q", mbox)
+	assert.Equal(t, runGit(t, dir, "rev-parse", "feature^{tree}"), runGit(t, clone, "rev-parse", "HEAD^{tree}"))
+}
+
+func TestExportDiff(t *testing.T) {
+	dir := setupSeriesRepo(t)
+	repo, err := git.OpenRepository(dir)
+	require.NoError(t, err)
+
+	result, err := NewExportWorkflow(repo, nil, "owner", "repo").
+		Execute(context.Background(), &ExportOptions{Target: "main..feature", Diff: true})
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 1)
+	assert.Equal(t, "Add-second-line.diff", result.Messages[0].Filename)
+	assert.True(t, strings.HasPrefix(result.Messages[0].Content, "diff --git"))
+
+	clone := t.TempDir()
+	runGit(t, clone, "clone", "-q", "-b", "main", dir, ".")
+	cloneRepo, err := git.OpenRepository(clone)
+	require.NoError(t, err)
+
+	diffFile := filepath.Join(t.TempDir(), "change.diff")
+	require.NoError(t, os.WriteFile(diffFile, []byte(result.Messages[0].Content), 0644))
+
+	imported, err := NewImportWorkflow(cloneRepo).Execute([]string{diffFile})
+	require.NoError(t, err)
+	assert.True(t, imported.Diff)
+	assert.Equal(t, runGit(t, dir, "rev-parse", "feature^{tree}"), runGit(t, clone, "write-tree"))
+}
+
+func TestExportRangeResolution(t *testing.T) {
+	dir := setupSeriesRepo(t)
+	repo, err := git.OpenRepository(dir)
+	require.NoError(t, err)
+	w := NewExportWorkflow(repo, nil, "owner", "repo")
+
+	t.Run("empty target exports current branch against default", func(t *testing.T) {
+		result, err := w.Execute(context.Background(), &ExportOptions{NoCover: true})
+		require.NoError(t, err)
+		assert.Len(t, result.Messages, 2)
+		assert.Equal(t, runGit(t, dir, "rev-parse", "main"), result.Base)
+	})
+
+	t.Run("empty range", func(t *testing.T) {
+		_, err := w.Execute(context.Background(), &ExportOptions{Target: "feature..main"})
+		assert.True(t, errors.Is(err, ErrNoCommits), "got %v", err)
+	})
+
+	t.Run("PR without client", func(t *testing.T) {
+		_, err := w.Execute(context.Background(), &ExportOptions{Target: "12"})
+		require.Error(t, err)
+	})
+}
+
+type mockExportRepo struct {
+	fetched  []string
+	patches  map[string]string
+	commits  []git.CommitInfo
+	gotRange string
+}
+
+func (m *mockExportRepo) GetDefaultBranch() (string, error) { return "main", nil }
+func (m *mockExportRepo) GetMergeBase(ref1, ref2 string) (string, error) {
+	return "mb", nil
+}
+func (m *mockExportRepo) GetCommitsBetween(base, head string) ([]git.CommitInfo, error) {
+	m.gotRange = base + ".." + head
+	return m.commits, nil
+}
+func (m *mockExportRepo) GetCommitPatch(sha string) (string, error) { return m.patches[sha], nil }
+func (m *mockExportRepo) GetBinaryDiff(base, head string) (string, error) {
+	return "", nil
+}
+func (m *mockExportRepo) GetGitConfig(key string) (string, error) {
+	return "", fmt.Errorf("config key %s not found", key)
+}
+func (m *mockExportRepo) Fetch(ctx context.Context, remote string, refspecs ...string) error {
+	m.fetched = append(m.fetched, refspecs...)
+	return nil
+}
+
+type mockExportClient struct{ pr *github.PullRequest }
+
+func (m *mockExportClient) GetPullRequest(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error) {
+	return m.pr, nil
+}
+
+func TestExportPR(t *testing.T) {
+	repo := &mockExportRepo{
+		commits: []git.CommitInfo{
+			{SHA: "c2", Author: "Bob", Email: "bob@example.com", Message: "merge main"},
+			{SHA: "c1", Author: "Bob", Email: "bob@example.com", Message: "Fix parser"},
+		},
+		patches: map[string]string{"c1": " x | 1 +\n\ndiff --git a/x b/x\n"},
+	}
+	client := &mockExportClient{pr: &github.PullRequest{
+		Number:  42,
+		Title:   "Parser fixes",
+		Body:    "Fixes the parser.\n\n## Test Plan\nRan it.\n\n**Ref:** #1",
+		HTMLURL: "https://github.com/owner/repo/pull/42",
+		Base:    github.PRBranch{Ref: "main"},
+	}}
+
+	result, err := NewExportWorkflow(repo, client, "owner", "repo").
+		Execute(context.Background(), &ExportOptions{Target: "#42"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"+refs/pull/42/head:refs/remotes/origin/pr/42",
+		"+refs/heads/main:refs/remotes/origin/main",
+	}, repo.fetched)
+	assert.Equal(t, "mb..origin/pr/42", repo.gotRange)
+	assert.Equal(t, 42, result.PRNumber)
+	assert.Equal(t, []string{"c2"}, result.Skipped, "commits without a patch are skipped")
+
+	require.Len(t, result.Messages, 2)
+	cover := result.Messages[0].Content
+	assert.Contains(t, cover, "Subject: [PATCH 0/1] Parser fixes")
+	assert.Contains(t, cover, "Fixes the parser.\n\nPull-Request: https://github.com/owner/repo/pull/42")
+	assert.NotContains(t, cover, "Test Plan")
+	assert.Contains(t, cover, "From: Bob <bob@example.com>")
+	assert.Equal(t, "[PATCH 1/1] Fix parser", result.Messages[1].Subject)
+}
@@ -222,6 +222,18 @@ func formatCheckStatus(status string, opts *PRFormatterOptions) string {
 	return icon + " " + statusName
 }
 
+// ReviewStatusLabel formats a review status (from DeterminePRStatus) with its
+// icon, for use outside the PR table
+func ReviewStatusLabel(status string, useColor bool) string {
+	return formatReviewStatus(status, &PRFormatterOptions{UseColor: useColor})
+}
+
+// CheckStatusLabel formats a check status (from DeterminePRStatus) with its
+// icon, for use outside the PR table
+func CheckStatusLabel(status string, useColor bool) string {
+	return formatCheckStatus(status, &PRFormatterOptions{UseColor: useColor})
+}
+
 // formatBranch formats the branch information
 func formatBranch(head, base string) string {
 	return fmt.Sprintf("%s → %s", head, base)
@@ -34,6 +34,15 @@ var (
 
 	// ErrAuthenticationFailed indicates git push failed due to authentication
 	ErrAuthenticationFailed = errors.New("authentication failed")
+
+	// ErrNoCommonAncestor is returned when two refs share no history
+	ErrNoCommonAncestor = errors.New("no common ancestor")
+
+	// ErrRebaseConflict is returned when a rebase stops on conflicts
+	ErrRebaseConflict = errors.New("rebase stopped on conflicts")
+
+	// ErrCherryPickConflict is returned when a cherry-pick stops on conflicts
+	ErrCherryPickConflict = errors.New("cherry-pick stopped on conflicts")
 )
 
 // Repository represents a Git repository and provides methods for Git operations.
@@ -855,22 +864,26 @@ func (r *Repository) GetFilesChanged(base, head string) ([]FileChange, error) {
 
 		fc := FileChange{}
 
+		// Use the change entry names: they are repository-relative paths,
+		// while object.File names are only the last path element
+		fromPath, toPath := change.From.Name, change.To.Name
+
 		// Determine change type
 		if from == nil && to != nil {
 			// New file
-			fc.Path = to.Name
+			fc.Path = toPath
 			fc.IsNew = true
 			fc.IsBinary = isBinaryFile(to)
 		} else if from != nil && to == nil {
 			// Deleted file
-			fc.Path = from.Name
+			fc.Path = fromPath
 			fc.IsDeleted = true
 			fc.IsBinary = isBinaryFile(from)
 		} else if from != nil && to != nil {
 			// Modified or renamed file
-			fc.Path = to.Name
-			fc.OldPath = from.Name
-			if from.Name != to.Name {
+			fc.Path = toPath
+			fc.OldPath = fromPath
+			if fromPath != toPath {
 				fc.IsRenamed = true
 			}
 			fc.IsBinary = isBinaryFile(to) || isBinaryFile(from)
@@ -911,6 +924,90 @@ func (r *Repository) GetDiffStats(base, head string) (*DiffStats, error) {
 	return stats, nil
 }
 
+// FileCommit represents a commit from file history together with the
+// subset of the requested paths it touched.
+type FileCommit struct {
+	CommitInfo
+	Files []string // Requested paths touched by this commit
+}
+
+// GetFileHistory returns the non-merge commits reachable from ref that touched
+// any of the given paths, newest first. since is passed to git log --since
+// (e.g. "6 months ago") and maxCount limits the number of commits; empty and
+// zero values mean no limit. Only the subject line is populated in Message.
+func (r *Repository) GetFileHistory(ref string, paths []string, since string, maxCount int) ([]FileCommit, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("ref cannot be empty")
+	}
+	if len(paths) == 0 {
+		return []FileCommit{}, nil
+	}
+
+	// Records are separated by \x1e, fields by \x1f; touched files follow the header
+	args := []string{"log", ref, "--no-merges", "--name-only", "--format=%x1e%H%x1f%an%x1f%ae%x1f%aI%x1f%s"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	if maxCount > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", maxCount))
+	}
+	args = append(args, "--")
+	args = append(args, paths...)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("failed to get file history: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("failed to execute git log: %w", err)
+	}
+
+	commits := []FileCommit{}
+	for _, record := range strings.Split(string(output), "\x1e") {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+
+		header, files, _ := strings.Cut(record, "\n")
+		fields := strings.Split(header, "\x1f")
+		if len(fields) < 5 {
+			continue
+		}
+
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			logger.Debug().Err(err).Str("sha", fields[0]).Msg("Failed to parse commit date")
+		}
+
+		commit := FileCommit{
+			CommitInfo: CommitInfo{
+				SHA:     fields[0],
+				Author:  fields[1],
+				Email:   fields[2],
+				Date:    date,
+				Message: fields[4],
+			},
+		}
+		for _, file := range strings.Split(files, "\n") {
+			if file = strings.TrimSpace(file); file != "" {
+				commit.Files = append(commit.Files, file)
+			}
+		}
+		commits = append(commits, commit)
+	}
+
+	logger.Debug().
+		Str("ref", ref).
+		Int("paths", len(paths)).
+		Int("commits", len(commits)).
+		Msg("Retrieved file history")
+
+	return commits, nil
+}
+
 // getDiffViaCLI uses git CLI to get diff output.
 // This is used for working directory and staged diffs where go-git has limitations.
 func (r *Repository) getDiffViaCLI(args ...string) (string, error) {
@@ -1104,7 +1201,7 @@ func (r *Repository) GetMergeBase(ref1, ref2 string) (string, error) {
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			// Exit code 1 means no common ancestor
 			if exitErr.ExitCode() == 1 {
-				return "", fmt.Errorf("no common ancestor between %s and %s", ref1, ref2)
+				return "", fmt.Errorf("%w between %s and %s", ErrNoCommonAncestor, ref1, ref2)
 			}
 			// Other exit codes indicate errors
 			return "", fmt.Errorf("failed to find merge-base: %s", string(exitErr.Stderr))
@@ -1221,6 +1318,33 @@ func (r *Repository) CountCommitsAhead(branchName, baseBranch string) (int, erro
 	return count, nil
 }
 
+// GetAheadBehind returns how many commits branch has that base doesn't (ahead)
+// and how many commits base has that branch doesn't (behind).
+func (r *Repository) GetAheadBehind(branch, base string) (int, int, error) {
+	if branch == "" || base == "" {
+		return 0, 0, fmt.Errorf("both refs must be non-empty")
+	}
+
+	cmd := exec.Command("git", "rev-list", "--left-right", "--count", fmt.Sprintf("%s...%s", base, branch))
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return 0, 0, fmt.Errorf("failed to count commits between %s and %s: %s", base, branch, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return 0, 0, fmt.Errorf("failed to execute git rev-list: %w", err)
+	}
+
+	// Output is "<behind>\t<ahead>": left side is base, right side is branch
+	var behind, ahead int
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(output)), "%d\t%d", &behind, &ahead); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse commit counts %q: %w", strings.TrimSpace(string(output)), err)
+	}
+
+	return ahead, behind, nil
+}
+
 // Push pushes commits from the specified branch to its remote tracking branch.
 // If the branch has no remote tracking branch, it pushes to origin with the same name.
 // Uses context for cancellation support.
@@ -1566,3 +1690,518 @@ func (r *Repository) PruneRemoteRefs() error {
 	logger.Debug().Msg("Pruned stale remote-tracking references")
 	return nil
 }
+
+// Fetch fetches the given refspecs from a remote via git CLI.
+// With no refspecs, the remote's configured refspecs are used.
+func (r *Repository) Fetch(ctx context.Context, remote string, refspecs ...string) error {
+	if remote == "" {
+		return fmt.Errorf("remote name cannot be empty")
+	}
+
+	args := append([]string{"fetch", remote}, refspecs...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.path
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("fetch from %s cancelled: %w", remote, ctx.Err())
+		}
+		if strings.Contains(string(output), "Authentication failed") ||
+			strings.Contains(string(output), "Permission denied") {
+			return fmt.Errorf("%w: %s", ErrAuthenticationFailed, string(output))
+		}
+		return fmt.Errorf("failed to fetch from %s: %w\nOutput: %s",
+			remote, err, string(output))
+	}
+
+	logger.Debug().
+		Str("remote", remote).
+		Strs("refspecs", refspecs).
+		Msg("Fetched from remote")
+	return nil
+}
+
+// AddRemote adds a new remote with the given name and URL via git CLI.
+func (r *Repository) AddRemote(name, url string) error {
+	if name == "" {
+		return fmt.Errorf("remote name cannot be empty")
+	}
+	if url == "" {
+		return fmt.Errorf("remote URL cannot be empty")
+	}
+
+	cmd := exec.Command("git", "remote", "add", name, url)
+	cmd.Dir = r.path
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to add remote %s: %w\nOutput: %s",
+			name, err, string(output))
+	}
+
+	logger.Debug().
+		Str("remote", name).
+		Str("url", url).
+		Msg("Added remote")
+	return nil
+}
+
+// FastForward fast-forwards the current branch to ref, failing if the
+// branches have diverged. Equivalent to: git merge --ff-only <ref>
+func (r *Repository) FastForward(ref string) error {
+	if ref == "" {
+		return fmt.Errorf("ref cannot be empty")
+	}
+
+	cmd := exec.Command("git", "merge", "--ff-only", ref)
+	cmd.Dir = r.path
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to fast-forward to %s: %w\nOutput: %s",
+			ref, err, string(output))
+	}
+
+	logger.Debug().
+		Str("ref", ref).
+		Msg("Fast-forwarded current branch")
+	return nil
+}
+
+// GetBinaryDiff returns the diff between two refs in a form that git apply
+// accepts, including binary file changes.
+func (r *Repository) GetBinaryDiff(base, head string) (string, error) {
+	if base == "" || head == "" {
+		return "", fmt.Errorf("base and head refs cannot be empty")
+	}
+	return r.getDiffViaCLI("--binary", base, head)
+}
+
+// ApplyPatch applies a patch to the working tree and index via git apply.
+// Falls back to a three-way merge for hunks that don't apply cleanly, leaving
+// conflict markers in the affected files.
+func (r *Repository) ApplyPatch(patch string) error {
+	if strings.TrimSpace(patch) == "" {
+		return fmt.Errorf("patch cannot be empty")
+	}
+
+	cmd := exec.Command("git", "apply", "--3way", "--whitespace=nowarn", "-")
+	cmd.Dir = r.path
+	cmd.Stdin = strings.NewReader(patch)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to apply patch: %w\nOutput: %s", err, string(output))
+	}
+
+	logger.Debug().Msg("Applied patch to working tree")
+	return nil
+}
+
+// GetCommitPatch returns the diffstat and binary-safe patch introduced by a
+// single commit, in the layout git format-patch uses below the "---" line.
+// Merge commits produce an empty patch.
+func (r *Repository) GetCommitPatch(sha string) (string, error) {
+	if sha == "" {
+		return "", fmt.Errorf("commit SHA cannot be empty")
+	}
+
+	cmd := exec.Command("git", "diff-tree", "-p", "--stat", "--binary", "--no-commit-id", "--root", sha)
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get patch for commit %s: %w", sha, err)
+	}
+
+	return string(output), nil
+}
+
+// ApplyMailbox applies a mailbox of patches as commits via git am, falling
+// back to a three-way merge where needed. On failure the am session is left
+// in progress so it can be resolved with git am --continue or --abort.
+func (r *Repository) ApplyMailbox(mbox string) error {
+	if strings.TrimSpace(mbox) == "" {
+		return fmt.Errorf("mailbox cannot be empty")
+	}
+
+	cmd := exec.Command("git", "am", "--3way")
+	cmd.Dir = r.path
+	cmd.Stdin = strings.NewReader(mbox)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to apply mailbox: %w\nOutput: %s", err, string(output))
+	}
+
+	logger.Debug().Msg("Applied mailbox via git am")
+	return nil
+}
+
+// GetCommitMessage returns the full message of the given commit.
+func (r *Repository) GetCommitMessage(ref string) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	cmd := exec.Command("git", "log", "-1", "--format=%B", ref)
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit message of %s: %w", ref, err)
+	}
+
+	return strings.TrimRight(string(output), "\n"), nil
+}
+
+// AmendCommitMessage replaces the message of the HEAD commit. Staged changes
+// are left in the index and not folded into the commit.
+func (r *Repository) AmendCommitMessage(message string) error {
+	if strings.TrimSpace(message) == "" {
+		return fmt.Errorf("commit message cannot be empty")
+	}
+
+	cmd := exec.Command("git", "commit", "--amend", "--only", "--allow-empty", "--cleanup=whitespace", "-F", "-")
+	cmd.Dir = r.path
+	cmd.Stdin = strings.NewReader(message)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to amend commit message: %w\nOutput: %s", err, string(output))
+	}
+
+	logger.Debug().Msg("Amended HEAD commit message")
+	return nil
+}
+
+// branchParentOption is the per-branch git config option recording the
+// branch a feature branch was started from, e.g. branch.feature.arc-parent.
+// branchParentSHAOption records the commit of that parent the branch forked
+// from, e.g. branch.feature.arc-parent-sha.
+const (
+	branchParentOption    = "arc-parent"
+	branchParentSHAOption = "arc-parent-sha"
+)
+
+// SetBranchParent records parent as the branch that branch was started from,
+// and parentSHA as the commit of parent it forked from. An empty parentSHA
+// clears any previously recorded commit.
+func (r *Repository) SetBranchParent(branch, parent, parentSHA string) error {
+	if branch == "" || parent == "" {
+		return fmt.Errorf("branch and parent cannot be empty")
+	}
+
+	if err := r.setBranchOption(branch, branchParentOption, parent); err != nil {
+		return fmt.Errorf("failed to record parent of %s: %w", branch, err)
+	}
+	if parentSHA == "" {
+		if err := r.unsetBranchOption(branch, branchParentSHAOption); err != nil {
+			return fmt.Errorf("failed to clear parent commit of %s: %w", branch, err)
+		}
+	} else if err := r.setBranchOption(branch, branchParentSHAOption, parentSHA); err != nil {
+		return fmt.Errorf("failed to record parent commit of %s: %w", branch, err)
+	}
+
+	logger.Debug().
+		Str("branch", branch).
+		Str("parent", parent).
+		Str("parentSHA", parentSHA).
+		Msg("Recorded branch parent")
+	return nil
+}
+
+// GetBranchParent returns the recorded parent of branch, or an empty string
+// if none was recorded.
+func (r *Repository) GetBranchParent(branch string) (string, error) {
+	if branch == "" {
+		return "", fmt.Errorf("branch name cannot be empty")
+	}
+
+	parent, err := r.getBranchOption(branch, branchParentOption)
+	if err != nil {
+		return "", fmt.Errorf("failed to read parent of %s: %w", branch, err)
+	}
+	return parent, nil
+}
+
+// GetBranchParentSHA returns the recorded commit of the parent that branch
+// forked from, or an empty string if none was recorded.
+func (r *Repository) GetBranchParentSHA(branch string) (string, error) {
+	if branch == "" {
+		return "", fmt.Errorf("branch name cannot be empty")
+	}
+
+	sha, err := r.getBranchOption(branch, branchParentSHAOption)
+	if err != nil {
+		return "", fmt.Errorf("failed to read parent commit of %s: %w", branch, err)
+	}
+	return sha, nil
+}
+
+// UnsetBranchParent removes the recorded parent of branch and its commit.
+// Removing a parent that was never recorded is not an error.
+func (r *Repository) UnsetBranchParent(branch string) error {
+	if branch == "" {
+		return fmt.Errorf("branch name cannot be empty")
+	}
+
+	for _, option := range []string{branchParentOption, branchParentSHAOption} {
+		if err := r.unsetBranchOption(branch, option); err != nil {
+			return fmt.Errorf("failed to remove parent of %s: %w", branch, err)
+		}
+	}
+
+	logger.Debug().Str("branch", branch).Msg("Removed branch parent")
+	return nil
+}
+
+func (r *Repository) setBranchOption(branch, option, value string) error {
+	cmd := exec.Command("git", "config", "branch."+branch+"."+option, value)
+	cmd.Dir = r.path
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+func (r *Repository) getBranchOption(branch, option string) (string, error) {
+	cmd := exec.Command("git", "config", "--get", "branch."+branch+"."+option)
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
+	if err != nil {
+		// Exit code 1 means the key is not set
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (r *Repository) unsetBranchOption(branch, option string) error {
+	cmd := exec.Command("git", "config", "--unset", "branch."+branch+"."+option)
+	cmd.Dir = r.path
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		// Exit code 5 means the key is not set
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 5 {
+			return nil
+		}
+		return fmt.Errorf("%w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// GitDir returns the absolute path of the repository's git directory.
+func (r *Repository) GitDir() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--absolute-git-dir")
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git directory: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetForkPoint returns the commit at which branch forked from upstream,
+// consulting the reflog of upstream so that rewritten upstream history is
+// handled. Returns an empty string if no fork point can be determined.
+func (r *Repository) GetForkPoint(upstream, branch string) (string, error) {
+	if upstream == "" || branch == "" {
+		return "", fmt.Errorf("upstream and branch cannot be empty")
+	}
+
+	cmd := exec.Command("git", "merge-base", "--fork-point", upstream, branch)
+	cmd.Dir = r.path
+
+	output, err := cmd.Output()
+	if err != nil {
+		// Exit code 1 means no fork point was found
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to find fork point of %s from %s: %w", branch, upstream, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RebaseOnto replays the commits of branch after upstream on top of newBase
+// (git rebase --onto newBase upstream branch), leaving branch checked out.
+// When the rebase stops on conflicts it is left in progress and
+// ErrRebaseConflict is returned.
+func (r *Repository) RebaseOnto(newBase, upstream, branch string) error {
+	if newBase == "" || upstream == "" || branch == "" {
+		return fmt.Errorf("new base, upstream and branch cannot be empty")
+	}
+
+	cmd := exec.Command("git", "rebase", "--onto", newBase, upstream, branch)
+	cmd.Dir = r.path
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if inProgress, _ := r.IsRebaseInProgress(); inProgress {
+			return fmt.Errorf("%w: %s\nOutput: %s", ErrRebaseConflict, branch, string(output))
+		}
+		return fmt.Errorf("failed to rebase %s onto %s: %w\nOutput: %s", branch, newBase, err, string(output))
+	}
+
+	logger.Debug().
+		Str("branch", branch).
+		Str("newBase", newBase).
+		Str("upstream", upstream).
+		Msg("Rebased branch")
+	return nil
+}
+
+// ContinueRebase continues an in-progress rebase after conflicts were
+// resolved, keeping the original commit messages. Returns ErrRebaseConflict
+// if the rebase stops on conflicts again.
+func (r *Repository) ContinueRebase() error {
+	cmd := exec.Command("git", "rebase", "--continue")
+	cmd.Dir = r.path
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if inProgress, _ := r.IsRebaseInProgress(); inProgress {
+			return fmt.Errorf("%w\nOutput: %s", ErrRebaseConflict, string(output))
+		}
+		return fmt.Errorf("failed to continue rebase: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// AbortRebase aborts an in-progress rebase, restoring the original branch.
+func (r *Repository) AbortRebase() error {
+	cmd := exec.Command("git", "rebase", "--abort")
+	cmd.Dir = r.path
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to abort rebase: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// IsRebaseInProgress reports whether a rebase is stopped in the repository.
+func (r *Repository) IsRebaseInProgress() (bool, error) {
+	gitDir, err := r.GitDir()
+	if err != nil {
+		return false, err
+	}
+
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		if _, err := os.Stat(filepath.Join(gitDir, dir)); err == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SetBranchRef points a local branch at the given commit without touching
+// the working tree. It must not be used on the checked-out branch.
+func (r *Repository) SetBranchRef(branch, sha string) error {
+	if branch == "" || sha == "" {
+		return fmt.Errorf("branch and SHA cannot be empty")
+	}
+
+	cmd := exec.Command("git", "update-ref", "refs/heads/"+branch, sha)
+	cmd.Dir = r.path
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to reset %s to %s: %w\nOutput: %s", branch, sha, err, string(output))
+	}
+
+	return nil
+}
+
+// CheckoutDetached checks out the given commit with a detached HEAD.
+func (r *Repository) CheckoutDetached(ref string) error {
+	if ref == "" {
+		return fmt.Errorf("ref cannot be empty")
+	}
+
+	cmd := exec.Command("git", "checkout", "--detach", ref)
+	cmd.Dir = r.path
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to checkout %s: %w\nOutput: %s", ref, err, string(output))
+	}
+
+	return nil
+}
+
+// CherryPick applies the given commits, in order, on top of HEAD. When a
+// commit does not apply cleanly the cherry-pick is left in progress and
+// ErrCherryPickConflict is returned.
+func (r *Repository) CherryPick(commits ...string) error {
+	if len(commits) == 0 {
+		return nil
+	}
+
+	cmd := exec.Command("git", append([]string{"cherry-pick", "--allow-empty"}, commits...)...)
+	cmd.Dir = r.path
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if inProgress, _ := r.IsCherryPickInProgress(); inProgress {
+			return fmt.Errorf("%w\nOutput: %s", ErrCherryPickConflict, string(output))
+		}
+		return fmt.Errorf("failed to cherry-pick: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// AbortCherryPick aborts an in-progress cherry-pick, restoring HEAD.
+func (r *Repository) AbortCherryPick() error {
+	cmd := exec.Command("git", "cherry-pick", "--abort")
+	cmd.Dir = r.path
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to abort cherry-pick: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// IsCherryPickInProgress reports whether a cherry-pick is stopped in the repository.
+func (r *Repository) IsCherryPickInProgress() (bool, error) {
+	gitDir, err := r.GitDir()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(filepath.Join(gitDir, "CHERRY_PICK_HEAD"))
+	return err == nil, nil
+}
+
+// UpdateRef points an arbitrary ref, such as a backup under refs/gh-arc/,
+// at the given commit.
+func (r *Repository) UpdateRef(ref, sha string) error {
+	if ref == "" || sha == "" {
+		return fmt.Errorf("ref and SHA cannot be empty")
+	}
+
+	cmd := exec.Command("git", "update-ref", ref, sha)
+	cmd.Dir = r.path
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to update %s to %s: %w\nOutput: %s", ref, sha, err, string(output))
+	}
+
+	return nil
+}
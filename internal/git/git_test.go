@@ -1437,6 +1437,94 @@ func TestGetFilesChanged(t *testing.T) {
 		assert.True(t, files[0].IsDeleted)
 		assert.False(t, files[0].IsRenamed)
 	})
+
+	t.Run("nested paths are repository-relative", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		gitRepo, err := git.PlainInit(tmpDir, false)
+		require.NoError(t, err)
+
+		worktree, err := gitRepo.Worktree()
+		require.NoError(t, err)
+
+		sig := &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()}
+
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "src", "pkg"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "src", "pkg", "a.go"), []byte("a"), 0644))
+		_, err = worktree.Add("src/pkg/a.go")
+		require.NoError(t, err)
+		_, err = worktree.Commit("initial commit", &git.CommitOptions{Author: sig})
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "src", "pkg", "a.go"), []byte("a2"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "src", "b.go"), []byte("b"), 0644))
+		_, err = worktree.Add(".")
+		require.NoError(t, err)
+		_, err = worktree.Commit("change nested files", &git.CommitOptions{Author: sig})
+		require.NoError(t, err)
+
+		repo, err := OpenRepository(tmpDir)
+		require.NoError(t, err)
+
+		files, err := repo.GetFilesChanged("HEAD~1", "HEAD")
+		require.NoError(t, err)
+
+		var paths []string
+		for _, f := range files {
+			paths = append(paths, f.Path)
+			if f.OldPath != "" {
+				assert.Equal(t, f.Path, f.OldPath)
+				assert.False(t, f.IsRenamed)
+			}
+		}
+		assert.ElementsMatch(t, []string{"src/pkg/a.go", "src/b.go"}, paths)
+	})
+}
+
+func TestGetAheadBehind(t *testing.T) {
+	tmpDir := t.TempDir()
+	gitRepo, err := git.PlainInit(tmpDir, false)
+	require.NoError(t, err)
+
+	worktree, err := gitRepo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(file string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, file), []byte(file), 0644))
+		_, err := worktree.Add(file)
+		require.NoError(t, err)
+		_, err = worktree.Commit("add "+file, &git.CommitOptions{
+			Author: &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+
+	commit("base.txt")
+
+	repo, err := OpenRepository(tmpDir)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateBranch("feature", "master"))
+
+	// master gains one commit
+	commit("main.txt")
+
+	// feature gains two commits
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: "refs/heads/feature"}))
+	commit("f1.txt")
+	commit("f2.txt")
+
+	ahead, behind, err := repo.GetAheadBehind("feature", "master")
+	require.NoError(t, err)
+	assert.Equal(t, 2, ahead)
+	assert.Equal(t, 1, behind)
+
+	ahead, behind, err = repo.GetAheadBehind("master", "master")
+	require.NoError(t, err)
+	assert.Zero(t, ahead)
+	assert.Zero(t, behind)
+
+	_, _, err = repo.GetAheadBehind("feature", "does-not-exist")
+	assert.Error(t, err)
 }
 
 // TestGetDiffStats tests diff statistics calculation
@@ -1550,6 +1638,66 @@ func TestGetDiffStats(t *testing.T) {
 }
 
 // TestGetWorkingDiff tests getting unstaged changes diff
+func TestGetFileHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	gitRepo, err := git.PlainInit(tmpDir, false)
+	require.NoError(t, err)
+
+	worktree, err := gitRepo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(file, content, author, email string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, file)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, file), []byte(content), 0644))
+		_, err := worktree.Add(file)
+		require.NoError(t, err)
+		_, err = worktree.Commit("update "+file, &git.CommitOptions{
+			Author: &object.Signature{Name: author, Email: email, When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+
+	commit("src/a.go", "a1", "Alice", "alice@example.com")
+	commit("src/b.go", "b1", "Bob", "bob@example.com")
+	commit("src/a.go", "a2", "Bob", "bob@example.com")
+	commit("README.md", "readme", "Carol", "carol@example.com")
+
+	repo, err := OpenRepository(tmpDir)
+	require.NoError(t, err)
+
+	t.Run("returns commits touching paths", func(t *testing.T) {
+		history, err := repo.GetFileHistory("HEAD", []string{"src/a.go", "src/b.go"}, "", 0)
+		require.NoError(t, err)
+
+		require.Len(t, history, 3)
+		assert.Equal(t, "Bob", history[0].Author)
+		assert.Equal(t, "bob@example.com", history[0].Email)
+		assert.Equal(t, "update src/a.go", history[0].Message)
+		assert.Equal(t, []string{"src/a.go"}, history[0].Files)
+		assert.False(t, history[0].Date.IsZero())
+		assert.Equal(t, "Alice", history[2].Author)
+	})
+
+	t.Run("max count", func(t *testing.T) {
+		history, err := repo.GetFileHistory("HEAD", []string{"src/a.go"}, "", 1)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, "Bob", history[0].Author)
+	})
+
+	t.Run("no paths", func(t *testing.T) {
+		history, err := repo.GetFileHistory("HEAD", nil, "", 0)
+		require.NoError(t, err)
+		assert.Empty(t, history)
+	})
+
+	t.Run("invalid ref", func(t *testing.T) {
+		_, err := repo.GetFileHistory("does-not-exist", []string{"src/a.go"}, "", 0)
+		assert.Error(t, err)
+	})
+}
+
 func TestGetWorkingDiff(t *testing.T) {
 	t.Run("working diff with modifications", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -2854,3 +3002,424 @@ func createWorktreeWithRemote(t *testing.T) (mainDir, worktreeDir string) {
 
 	return mainDir, worktreeDir
 }
+
+// TestFetchAndFastForward tests fetching from an added remote and fast-forwarding to it
+func TestFetchAndFastForward(t *testing.T) {
+	upstreamDir := t.TempDir()
+	upstream, err := git.PlainInit(upstreamDir, false)
+	require.NoError(t, err)
+	upstreamTree, err := upstream.Worktree()
+	require.NoError(t, err)
+
+	commit := func(file string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(upstreamDir, file), []byte(file), 0644))
+		_, err := upstreamTree.Add(file)
+		require.NoError(t, err)
+		_, err = upstreamTree.Commit("add "+file, &git.CommitOptions{
+			Author: &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+	commit("base.txt")
+
+	localDir := t.TempDir()
+	_, err = git.PlainClone(localDir, false, &git.CloneOptions{URL: upstreamDir})
+	require.NoError(t, err)
+	repo, err := OpenRepository(localDir)
+	require.NoError(t, err)
+
+	t.Run("validates arguments", func(t *testing.T) {
+		assert.Error(t, repo.Fetch(context.Background(), ""))
+		assert.Error(t, repo.AddRemote("", upstreamDir))
+		assert.Error(t, repo.AddRemote("fork", ""))
+		assert.Error(t, repo.FastForward(""))
+	})
+
+	t.Run("fetches into a new remote and fast-forwards", func(t *testing.T) {
+		commit("next.txt")
+
+		require.NoError(t, repo.AddRemote("fork", upstreamDir))
+		require.NoError(t, repo.Fetch(context.Background(), "fork", "+refs/heads/master:refs/remotes/fork/master"))
+
+		exists, err := repo.BranchExists("fork/master")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		require.NoError(t, repo.FastForward("fork/master"))
+		_, err = os.Stat(filepath.Join(localDir, "next.txt"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("duplicate remote fails", func(t *testing.T) {
+		assert.Error(t, repo.AddRemote("fork", upstreamDir))
+	})
+
+	t.Run("unknown remote fails", func(t *testing.T) {
+		assert.Error(t, repo.Fetch(context.Background(), "nope"))
+	})
+}
+
+// TestApplyPatch tests applying a binary-safe diff between two refs
+func TestApplyPatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	gitRepo, err := git.PlainInit(tmpDir, false)
+	require.NoError(t, err)
+	worktree, err := gitRepo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(file, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, file), []byte(content), 0644))
+		_, err := worktree.Add(file)
+		require.NoError(t, err)
+		_, err = worktree.Commit("update "+file, &git.CommitOptions{
+			Author: &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+
+	commit("a.txt", "one\n")
+	repo, err := OpenRepository(tmpDir)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateBranch("feature", "master"))
+
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: "refs/heads/feature"}))
+	commit("a.txt", "one\ntwo\n")
+	commit("b.bin", "\x00\x01\x02")
+
+	patch, err := repo.GetBinaryDiff("master", "feature")
+	require.NoError(t, err)
+	assert.Contains(t, patch, "GIT binary patch")
+
+	require.NoError(t, repo.CheckoutBranch("master"))
+	require.NoError(t, repo.ApplyPatch(patch))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo\n", string(content))
+
+	status, err := repo.GetWorkingDirectoryStatus()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a.txt", "b.bin"}, status.StagedFiles)
+
+	assert.Error(t, repo.ApplyPatch("  \n"))
+	_, err = repo.GetBinaryDiff("", "feature")
+	assert.Error(t, err)
+}
+
+// TestGetCommitPatchAndApplyMailbox tests rendering a commit patch and applying it with git am
+func TestGetCommitPatchAndApplyMailbox(t *testing.T) {
+	tmpDir := t.TempDir()
+	gitRepo, err := git.PlainInit(tmpDir, false)
+	require.NoError(t, err)
+	worktree, err := gitRepo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(file, content string) string {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, file), []byte(content), 0644))
+		_, err := worktree.Add(file)
+		require.NoError(t, err)
+		hash, err := worktree.Commit("update "+file, &git.CommitOptions{
+			Author: &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		return hash.String()
+	}
+
+	root := commit("a.txt", "one\n")
+	second := commit("a.txt", "one\ntwo\n")
+
+	repo, err := OpenRepository(tmpDir)
+	require.NoError(t, err)
+
+	patch, err := repo.GetCommitPatch(second)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(patch, " a.txt | 1 +\n"), patch)
+	assert.Contains(t, patch, "\n\ndiff --git a/a.txt b/a.txt\n")
+
+	rootPatch, err := repo.GetCommitPatch(root)
+	require.NoError(t, err)
+	assert.Contains(t, rootPatch, "new file mode")
+
+	_, err = repo.GetCommitPatch("")
+	assert.Error(t, err)
+
+	// Re-apply the second commit on a branch at the root
+	require.NoError(t, repo.CreateBranch("replay", root))
+	require.NoError(t, repo.CheckoutBranch("replay"))
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	mbox := "From " + second + " Mon Sep 17 00:00:00 2001\n" +
+		"From: Test User <test@example.com>\n" +
+		"Date: Fri, 02 Jan 2026 03:04:05 +0000\n" +
+		"Subject: [PATCH] update a.txt\n\n---\n" + patch
+	require.NoError(t, repo.ApplyMailbox(mbox))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo\n", string(content))
+
+	assert.Error(t, repo.ApplyMailbox(" "))
+}
+
+func TestGetCommitMessageAndAmend(t *testing.T) {
+	tmpDir := t.TempDir()
+	gitRepo, err := git.PlainInit(tmpDir, false)
+	require.NoError(t, err)
+	worktree, err := gitRepo.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a.txt"), []byte("one\n"), 0644))
+	_, err = worktree.Add("a.txt")
+	require.NoError(t, err)
+	_, err = worktree.Commit("original subject\n\noriginal body", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	repo, err := OpenRepository(tmpDir)
+	require.NoError(t, err)
+
+	message, err := repo.GetCommitMessage("")
+	require.NoError(t, err)
+	assert.Equal(t, "original subject\n\noriginal body", message)
+
+	// Staged changes must not be folded into the amended commit
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a.txt"), []byte("two\n"), 0644))
+	_, err = worktree.Add("a.txt")
+	require.NoError(t, err)
+
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	require.NoError(t, repo.AmendCommitMessage("new subject\n\n## Heading kept\n\nReviewed-by: someone"))
+
+	message, err = repo.GetCommitMessage("HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "new subject\n\n## Heading kept\n\nReviewed-by: someone", message)
+
+	diff, err := repo.GetStagedDiff()
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+two")
+
+	assert.Error(t, repo.AmendCommitMessage("  "))
+}
+
+func TestBranchParent(t *testing.T) {
+	tmpDir := t.TempDir()
+	_, err := git.PlainInit(tmpDir, false)
+	require.NoError(t, err)
+
+	repo, err := OpenRepository(tmpDir)
+	require.NoError(t, err)
+
+	parent, err := repo.GetBranchParent("feature")
+	require.NoError(t, err)
+	assert.Empty(t, parent)
+
+	require.NoError(t, repo.SetBranchParent("feature", "main", "abc123"))
+	parent, err = repo.GetBranchParent("feature")
+	require.NoError(t, err)
+	assert.Equal(t, "main", parent)
+	sha, err := repo.GetBranchParentSHA("feature")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", sha)
+
+	// Recording a parent without a commit drops the stale commit
+	require.NoError(t, repo.SetBranchParent("feature", "develop", ""))
+	parent, err = repo.GetBranchParent("feature")
+	require.NoError(t, err)
+	assert.Equal(t, "develop", parent)
+	sha, err = repo.GetBranchParentSHA("feature")
+	require.NoError(t, err)
+	assert.Empty(t, sha)
+
+	// Branch names with slashes are valid subsections
+	require.NoError(t, repo.SetBranchParent("feature/child", "feature", "def456"))
+	parent, err = repo.GetBranchParent("feature/child")
+	require.NoError(t, err)
+	assert.Equal(t, "feature", parent)
+
+	require.NoError(t, repo.UnsetBranchParent("feature/child"))
+	parent, err = repo.GetBranchParent("feature/child")
+	require.NoError(t, err)
+	assert.Empty(t, parent)
+	sha, err = repo.GetBranchParentSHA("feature/child")
+	require.NoError(t, err)
+	assert.Empty(t, sha)
+
+	// Unsetting twice is fine
+	require.NoError(t, repo.UnsetBranchParent("feature/child"))
+
+	assert.Error(t, repo.SetBranchParent("", "main", ""))
+	_, err = repo.GetBranchParent("")
+	assert.Error(t, err)
+	assert.Error(t, repo.UnsetBranchParent(""))
+}
+
+func TestRebaseOntoAndForkPoint(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@test.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@test.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v failed: %s", args, output)
+		return strings.TrimSpace(string(output))
+	}
+	write := func(file, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0644))
+	}
+
+	run("init", "-b", "main")
+	write("base.txt", "base\n")
+	run("add", ".")
+	run("commit", "-m", "base")
+
+	run("checkout", "-b", "parent")
+	write("parent.txt", "v1\n")
+	run("add", ".")
+	run("commit", "-m", "parent")
+	oldParent := run("rev-parse", "HEAD")
+
+	run("checkout", "-b", "child")
+	write("child.txt", "child\n")
+	run("add", ".")
+	run("commit", "-m", "child")
+
+	// Amend the parent, as after review feedback
+	run("checkout", "parent")
+	write("parent.txt", "v2\n")
+	run("add", ".")
+	run("commit", "--amend", "-m", "parent amended")
+	newParent := run("rev-parse", "HEAD")
+
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@test.com")
+
+	repo, err := OpenRepository(dir)
+	require.NoError(t, err)
+
+	gitDir, err := repo.GitDir()
+	require.NoError(t, err)
+	assert.True(t, filepath.IsAbs(gitDir))
+
+	forkPoint, err := repo.GetForkPoint("parent", "child")
+	require.NoError(t, err)
+	assert.Equal(t, oldParent, forkPoint)
+
+	require.NoError(t, repo.RebaseOnto("parent", forkPoint, "child"))
+	assert.Equal(t, newParent, run("rev-parse", "child~1"))
+	assert.Equal(t, "child", run("rev-parse", "--abbrev-ref", "HEAD"))
+
+	t.Run("conflict can be continued", func(t *testing.T) {
+		run("checkout", "-b", "conflicting", "main")
+		write("parent.txt", "other\n")
+		run("add", ".")
+		run("commit", "-m", "conflicting")
+
+		err := repo.RebaseOnto("parent", "main", "conflicting")
+		require.ErrorIs(t, err, ErrRebaseConflict)
+
+		inProgress, err := repo.IsRebaseInProgress()
+		require.NoError(t, err)
+		assert.True(t, inProgress)
+
+		write("parent.txt", "resolved\n")
+		run("add", "parent.txt")
+		require.NoError(t, repo.ContinueRebase())
+
+		inProgress, err = repo.IsRebaseInProgress()
+		require.NoError(t, err)
+		assert.False(t, inProgress)
+		assert.Equal(t, newParent, run("rev-parse", "conflicting~1"))
+	})
+
+	t.Run("conflict can be aborted", func(t *testing.T) {
+		run("checkout", "-b", "aborted", "main")
+		write("parent.txt", "another\n")
+		run("add", ".")
+		run("commit", "-m", "aborted")
+		before := run("rev-parse", "HEAD")
+
+		require.ErrorIs(t, repo.RebaseOnto("parent", "main", "aborted"), ErrRebaseConflict)
+		require.NoError(t, repo.AbortRebase())
+		assert.Equal(t, before, run("rev-parse", "aborted"))
+	})
+
+	t.Run("set branch ref", func(t *testing.T) {
+		require.NoError(t, repo.SetBranchRef("parent", oldParent))
+		assert.Equal(t, oldParent, run("rev-parse", "parent"))
+		assert.Error(t, repo.SetBranchRef("", oldParent))
+	})
+}
+
+func TestCherryPickAndUpdateRef(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@test.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@test.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v failed: %s", args, output)
+		return strings.TrimSpace(string(output))
+	}
+	commit := func(file, content, message string) string {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0644))
+		run("add", ".")
+		run("commit", "-m", message)
+		return run("rev-parse", "HEAD")
+	}
+
+	run("init", "-b", "main")
+	base := commit("base.txt", "base\n", "base")
+	run("checkout", "-b", "feature")
+	first := commit("a.txt", "a\n", "first")
+	second := commit("b.txt", "b\n", "second")
+	conflicting := commit("a.txt", "changed\n", "conflicting")
+
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@test.com")
+
+	repo, err := OpenRepository(dir)
+	require.NoError(t, err)
+
+	t.Run("backup ref", func(t *testing.T) {
+		require.NoError(t, repo.UpdateRef("refs/gh-arc/backup/feature", conflicting))
+		assert.Equal(t, conflicting, run("rev-parse", "refs/gh-arc/backup/feature"))
+		assert.Error(t, repo.UpdateRef("", conflicting))
+	})
+
+	t.Run("picks commits in a new order", func(t *testing.T) {
+		require.NoError(t, repo.CheckoutDetached(base))
+		require.NoError(t, repo.CherryPick(second, first))
+
+		assert.Equal(t, "first", run("log", "-1", "--format=%s"))
+		assert.Equal(t, "second", run("log", "-1", "--format=%s", "HEAD~1"))
+		assert.Equal(t, "HEAD", run("rev-parse", "--abbrev-ref", "HEAD"))
+	})
+
+	t.Run("conflict can be aborted", func(t *testing.T) {
+		require.NoError(t, repo.CheckoutDetached(base))
+		before := run("rev-parse", "HEAD")
+
+		require.ErrorIs(t, repo.CherryPick(conflicting), ErrCherryPickConflict)
+		inProgress, err := repo.IsCherryPickInProgress()
+		require.NoError(t, err)
+		assert.True(t, inProgress)
+
+		require.NoError(t, repo.AbortCherryPick())
+		inProgress, err = repo.IsCherryPickInProgress()
+		require.NoError(t, err)
+		assert.False(t, inProgress)
+		assert.Equal(t, before, run("rev-parse", "HEAD"))
+	})
+}
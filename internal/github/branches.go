@@ -0,0 +1,360 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+// branchPRBatchSize is the number of branches looked up per GraphQL query.
+// Each branch is an aliased connection, so this keeps query cost bounded.
+const branchPRBatchSize = 50
+
+// prMetadataPageSize is the page size of the review and check connections
+// of a PR. Further pages are fetched per PR, see completeMetadata.
+const prMetadataPageSize = 100
+
+// pageInfoFields selects what is needed to follow a connection
+const pageInfoFields = `
+          pageInfo { hasNextPage endCursor }`
+
+// reviewNodeFields, checkContextNodeFields and reviewRequestNodeFields select
+// the nodes of the review, check and review request connections of a PR
+const (
+	reviewNodeFields = `
+          nodes { author { login } state submittedAt }`
+
+	checkContextNodeFields = `
+                  nodes {
+                    __typename
+                    ... on CheckRun { name status conclusion startedAt completedAt }
+                    ... on StatusContext { context state createdAt }
+                  }`
+
+	reviewRequestNodeFields = `
+          nodes {
+            requestedReviewer {
+              __typename
+              ... on User { login }
+              ... on Mannequin { login }
+              ... on Team { slug }
+            }
+          }`
+)
+
+// prMetadataFields selects the reviews and check results of a PR, which the
+// REST API only returns through one extra call per PR each.
+const prMetadataFields = `
+        latestReviews(first: 50) {` + reviewNodeFields + pageInfoFields + `
+        }
+        commits(last: 1) {
+          nodes {
+            commit {
+              statusCheckRollup {
+                contexts(first: 100) {` + checkContextNodeFields + pageInfoFields + `
+                }
+              }
+            }
+          }
+        }`
+
+// branchPRFields selects the PR data needed to render branch status,
+// including reviews and check results, so no per-PR follow-up calls are needed.
+const branchPRFields = `
+      nodes {
+        id
+        number
+        title
+        state
+        isDraft
+        url
+        createdAt
+        updatedAt
+        mergedAt
+        author { login }
+        headRefName
+        headRefOid
+        baseRefName
+        headRepository { name nameWithOwner owner { login } }` + prMetadataFields + `
+      }`
+
+// graphQLPullRequest mirrors the PR fields selected by branchPRFields.
+type graphQLPullRequest struct {
+	ID        string     `json:"id"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"` // OPEN, CLOSED, MERGED
+	IsDraft   bool       `json:"isDraft"`
+	URL       string     `json:"url"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	MergedAt  *time.Time `json:"mergedAt"`
+	Author    *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	HeadRefName    string `json:"headRefName"`
+	HeadRefOid     string `json:"headRefOid"`
+	BaseRefName    string `json:"baseRefName"`
+	HeadRepository *struct {
+		Name          string `json:"name"`
+		NameWithOwner string `json:"nameWithOwner"`
+		Owner         struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"headRepository"`
+	LatestReviews struct {
+		Nodes []struct {
+			Author *struct {
+				Login string `json:"login"`
+			} `json:"author"`
+			State       string    `json:"state"`
+			SubmittedAt time.Time `json:"submittedAt"`
+		} `json:"nodes"`
+		PageInfo graphQLPageInfo `json:"pageInfo"`
+	} `json:"latestReviews"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					Contexts struct {
+						Nodes    []graphQLCheckContext `json:"nodes"`
+						PageInfo graphQLPageInfo       `json:"pageInfo"`
+					} `json:"contexts"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer *struct {
+				Typename string `json:"__typename"`
+				Login    string `json:"login"`
+				Slug     string `json:"slug"`
+			} `json:"requestedReviewer"`
+		} `json:"nodes"`
+		PageInfo graphQLPageInfo `json:"pageInfo"`
+	} `json:"reviewRequests"`
+}
+
+// graphQLPageInfo tells whether a connection has more pages
+type graphQLPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// graphQLCheckContext is either a CheckRun or a legacy StatusContext.
+type graphQLCheckContext struct {
+	Typename    string    `json:"__typename"`
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+	Conclusion  string    `json:"conclusion"`
+	StartedAt   time.Time `json:"startedAt"`
+	CompletedAt time.Time `json:"completedAt"`
+	Context     string    `json:"context"`
+	State       string    `json:"state"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// FindPRsForBranches looks up the pull request for each of the given head
+// branches, batching all lookups into as few GraphQL queries as possible.
+// Open PRs are preferred over merged ones; closed-unmerged PRs and PRs from
+// forks are ignored. Branches without a PR are absent from the returned map.
+// Reviews and Checks are populated on the returned PRs.
+func (c *Client) FindPRsForBranches(ctx context.Context, owner, repo string, branches []string) (map[string]*PullRequest, error) {
+	result := make(map[string]*PullRequest, len(branches))
+
+	for start := 0; start < len(branches); start += branchPRBatchSize {
+		end := start + branchPRBatchSize
+		if end > len(branches) {
+			end = len(branches)
+		}
+
+		if err := c.findPRsForBranchBatch(ctx, owner, repo, branches[start:end], result); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Debug().
+		Int("branches", len(branches)).
+		Int("found", len(result)).
+		Msg("Looked up pull requests for branches")
+
+	return result, nil
+}
+
+func (c *Client) findPRsForBranchBatch(ctx context.Context, owner, repo string, branches []string, result map[string]*PullRequest) error {
+	query, variables := buildBranchPRQuery(owner, repo, branches)
+
+	var response struct {
+		Repository map[string]struct {
+			Nodes []graphQLPullRequest `json:"nodes"`
+		} `json:"repository"`
+	}
+	if err := c.DoGraphQL(ctx, query, variables, &response); err != nil {
+		return fmt.Errorf("failed to look up pull requests for branches: %w", err)
+	}
+
+	fullName := owner + "/" + repo
+	for i, branch := range branches {
+		conn, ok := response.Repository[fmt.Sprintf("b%d", i)]
+		if !ok {
+			continue
+		}
+
+		var selected *graphQLPullRequest
+		for j := range conn.Nodes {
+			node := &conn.Nodes[j]
+			if node.HeadRepository == nil || !strings.EqualFold(node.HeadRepository.NameWithOwner, fullName) {
+				continue
+			}
+			if node.State == "OPEN" {
+				selected = node
+				break
+			}
+			if node.State == "MERGED" && selected == nil {
+				selected = node
+			}
+		}
+
+		if selected != nil {
+			if err := c.completeMetadata(ctx, owner, repo, selected); err != nil {
+				return err
+			}
+			result[branch] = selected.toPullRequest()
+		}
+	}
+
+	return nil
+}
+
+// buildBranchPRQuery builds one query with an aliased pullRequests connection
+// per branch (b0, b1, ...), each filtered by head ref name.
+func buildBranchPRQuery(owner, repo string, branches []string) (string, map[string]interface{}) {
+	variables := map[string]interface{}{
+		"owner": owner,
+		"name":  repo,
+	}
+
+	var params, fields strings.Builder
+	params.WriteString("$owner: String!, $name: String!")
+	for i, branch := range branches {
+		fmt.Fprintf(&params, ", $h%d: String!", i)
+		variables[fmt.Sprintf("h%d", i)] = branch
+
+		fmt.Fprintf(&fields, "\n    b%d: pullRequests(headRefName: $h%d, first: 5, states: [OPEN, MERGED], orderBy: {field: UPDATED_AT, direction: DESC}) {%s\n    }", i, i, branchPRFields)
+	}
+
+	query := fmt.Sprintf("query BranchPullRequests(%s) {\n  repository(owner: $owner, name: $name) {%s\n  }\n}", params.String(), fields.String())
+	return query, variables
+}
+
+// toPullRequest converts the GraphQL shape into the REST-based PullRequest.
+func (g *graphQLPullRequest) toPullRequest() *PullRequest {
+	pr := &PullRequest{
+		Number:    g.Number,
+		NodeID:    g.ID,
+		Title:     g.Title,
+		State:     "open",
+		Draft:     g.IsDraft,
+		CreatedAt: g.CreatedAt,
+		UpdatedAt: g.UpdatedAt,
+		MergedAt:  g.MergedAt,
+		HTMLURL:   g.URL,
+		Head:      PRBranch{Ref: g.HeadRefName, SHA: g.HeadRefOid},
+		Base:      PRBranch{Ref: g.BaseRefName},
+		Reviews:   []PRReview{},
+		Checks:    []PRCheck{},
+	}
+	if g.State != "OPEN" {
+		pr.State = "closed"
+	}
+	if g.Author != nil {
+		pr.User = PRUser{Login: g.Author.Login}
+	}
+	if g.HeadRepository != nil {
+		pr.Head.Repo = PRRepository{
+			Name:     g.HeadRepository.Name,
+			FullName: g.HeadRepository.NameWithOwner,
+			Owner:    PRUser{Login: g.HeadRepository.Owner.Login},
+		}
+	}
+
+	pr.Reviews = append(pr.Reviews, g.reviews()...)
+	pr.Checks = append(pr.Checks, g.checks()...)
+	pr.Reviewers = g.reviewers()
+
+	return pr
+}
+
+// reviews converts the latest review of each reviewer
+func (g *graphQLPullRequest) reviews() []PRReview {
+	var reviews []PRReview
+	for _, review := range g.LatestReviews.Nodes {
+		r := PRReview{State: review.State, SubmittedAt: review.SubmittedAt}
+		if review.Author != nil {
+			r.User = PRUser{Login: review.Author.Login}
+		}
+		reviews = append(reviews, r)
+	}
+	return reviews
+}
+
+// checks converts the check runs and status contexts of the head commit
+func (g *graphQLPullRequest) checks() []PRCheck {
+	var checks []PRCheck
+	for _, node := range g.Commits.Nodes {
+		if node.Commit.StatusCheckRollup == nil {
+			continue
+		}
+		for _, ctx := range node.Commit.StatusCheckRollup.Contexts.Nodes {
+			checks = append(checks, ctx.toPRCheck())
+		}
+	}
+	return checks
+}
+
+// reviewers converts the pending review requests the way the REST
+// requested_reviewers endpoint reports them: users by login, teams by slug
+func (g *graphQLPullRequest) reviewers() []PRReviewer {
+	var reviewers []PRReviewer
+	for _, node := range g.ReviewRequests.Nodes {
+		requested := node.RequestedReviewer
+		if requested == nil {
+			continue
+		}
+		if requested.Typename == "Team" {
+			reviewers = append(reviewers, PRReviewer{Login: requested.Slug, Type: "Team"})
+		} else if requested.Login != "" {
+			reviewers = append(reviewers, PRReviewer{Login: requested.Login, Type: "User"})
+		}
+	}
+	return reviewers
+}
+
+// toPRCheck maps a check run or status context onto the REST check-run shape
+// understood by DeterminePRStatus.
+func (g *graphQLCheckContext) toPRCheck() PRCheck {
+	if g.Typename == "StatusContext" {
+		check := PRCheck{Name: g.Context, StartedAt: g.CreatedAt}
+		switch g.State {
+		case "SUCCESS":
+			check.Status, check.Conclusion = "completed", "success"
+		case "FAILURE", "ERROR":
+			check.Status, check.Conclusion = "completed", "failure"
+		default: // PENDING, EXPECTED
+			check.Status = "in_progress"
+		}
+		return check
+	}
+
+	return PRCheck{
+		Name:        g.Name,
+		Status:      strings.ToLower(g.Status),
+		Conclusion:  strings.ToLower(g.Conclusion),
+		StartedAt:   g.StartedAt,
+		CompletedAt: g.CompletedAt,
+	}
+}
@@ -0,0 +1,199 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// newTestGraphQLClient creates a Client whose GraphQL requests are served by handler.
+func newTestGraphQLClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	client, server := newTestClient(t, handler)
+
+	graphqlClient, err := api.NewGraphQLClient(api.ClientOptions{
+		Host:      "github.com",
+		AuthToken: "test-token",
+		Transport: &redirectTransport{server: server},
+	})
+	if err != nil {
+		t.Fatalf("failed to create test GraphQL client: %v", err)
+	}
+	client.graphqlClient = graphqlClient
+	client.config.MaxRetries = 0
+
+	return client
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func TestFindPRsForBranches(t *testing.T) {
+	t.Run("batches branches and selects open over merged", func(t *testing.T) {
+		var requests []graphQLRequest
+
+		client := newTestGraphQLClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req graphQLRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			requests = append(requests, req)
+
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"data": {"repository": {
+				"b0": {"nodes": [
+					{"number": 7, "state": "MERGED", "mergedAt": "2026-01-01T00:00:00Z", "headRefName": "feature/a", "headRefOid": "old", "baseRefName": "main",
+					 "headRepository": {"name": "repo", "nameWithOwner": "owner/repo", "owner": {"login": "owner"}}},
+					{"number": 9, "state": "OPEN", "isDraft": true, "title": "Feature A", "url": "https://github.com/owner/repo/pull/9",
+					 "headRefName": "feature/a", "headRefOid": "abc", "baseRefName": "feature/base",
+					 "author": {"login": "alice"},
+					 "headRepository": {"name": "repo", "nameWithOwner": "owner/repo", "owner": {"login": "owner"}},
+					 "latestReviews": {"nodes": [{"author": {"login": "bob"}, "state": "APPROVED"}]},
+					 "commits": {"nodes": [{"commit": {"statusCheckRollup": {"contexts": {"nodes": [
+						{"__typename": "CheckRun", "name": "build", "status": "COMPLETED", "conclusion": "SUCCESS"},
+						{"__typename": "StatusContext", "context": "ci/legacy", "state": "PENDING"}
+					 ]}}}}]}}
+				]},
+				"b1": {"nodes": [
+					{"number": 3, "state": "MERGED", "mergedAt": "2026-01-02T00:00:00Z", "headRefName": "feature/b", "headRefOid": "def", "baseRefName": "main",
+					 "headRepository": {"name": "repo", "nameWithOwner": "owner/repo", "owner": {"login": "owner"}}}
+				]},
+				"b2": {"nodes": [
+					{"number": 4, "state": "OPEN", "headRefName": "feature/c", "headRefOid": "fork", "baseRefName": "main",
+					 "headRepository": {"name": "repo", "nameWithOwner": "someone/repo", "owner": {"login": "someone"}}}
+				]}
+			}}}`)
+		}))
+
+		prs, err := client.FindPRsForBranches(context.Background(), "owner", "repo", []string{"feature/a", "feature/b", "feature/c"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(requests) != 1 {
+			t.Fatalf("expected 1 batched request, got %d", len(requests))
+		}
+		if !strings.Contains(requests[0].Query, "b2: pullRequests(headRefName: $h2") {
+			t.Errorf("query missing aliased connection for third branch:\n%s", requests[0].Query)
+		}
+		if requests[0].Variables["h1"] != "feature/b" {
+			t.Errorf("h1 = %v, want feature/b", requests[0].Variables["h1"])
+		}
+
+		a := prs["feature/a"]
+		if a == nil || a.Number != 9 {
+			t.Fatalf("feature/a: expected open PR #9, got %+v", a)
+		}
+		if a.State != "open" || !a.Draft || a.IsMerged() {
+			t.Errorf("feature/a: unexpected state %q draft=%v merged=%v", a.State, a.Draft, a.IsMerged())
+		}
+		if a.Base.Ref != "feature/base" || a.Head.SHA != "abc" || a.User.Login != "alice" {
+			t.Errorf("feature/a: unexpected refs %+v", a)
+		}
+		status := DeterminePRStatus(a.Reviews, a.Checks)
+		if status.ReviewStatus != "approved" {
+			t.Errorf("ReviewStatus = %q, want approved", status.ReviewStatus)
+		}
+		if status.CheckStatus != "in_progress" {
+			t.Errorf("CheckStatus = %q, want in_progress", status.CheckStatus)
+		}
+
+		b := prs["feature/b"]
+		if b == nil || b.Number != 3 || !b.IsMerged() || b.State != "closed" {
+			t.Errorf("feature/b: expected merged PR #3, got %+v", b)
+		}
+
+		if _, ok := prs["feature/c"]; ok {
+			t.Error("feature/c: PRs from forks should be ignored")
+		}
+	})
+
+	t.Run("splits large branch lists into batches", func(t *testing.T) {
+		requests := 0
+		client := newTestGraphQLClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"data": {"repository": {}}}`)
+		}))
+
+		branches := make([]string, branchPRBatchSize+1)
+		for i := range branches {
+			branches[i] = fmt.Sprintf("branch-%d", i)
+		}
+
+		prs, err := client.FindPRsForBranches(context.Background(), "owner", "repo", branches)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if requests != 2 {
+			t.Errorf("expected 2 requests, got %d", requests)
+		}
+		if len(prs) != 0 {
+			t.Errorf("expected no PRs, got %d", len(prs))
+		}
+	})
+
+	t.Run("no branches makes no request", func(t *testing.T) {
+		client := newTestGraphQLClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}))
+
+		prs, err := client.FindPRsForBranches(context.Background(), "owner", "repo", nil)
+		if err != nil || len(prs) != 0 {
+			t.Errorf("expected empty result, got %v, %v", prs, err)
+		}
+	})
+
+	t.Run("follows further pages of checks", func(t *testing.T) {
+		requests := 0
+		client := newTestGraphQLClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			var req graphQLRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+
+			w.Header().Set("Content-Type", "application/json")
+			if req.Variables["after"] == nil {
+				fmt.Fprint(w, `{"data": {"repository": {"b0": {"nodes": [
+					{"number": 9, "state": "OPEN", "headRefName": "a", "baseRefName": "main",
+					 "headRepository": {"name": "repo", "nameWithOwner": "owner/repo", "owner": {"login": "owner"}},
+					 "commits": {"nodes": [{"commit": {"statusCheckRollup": {"contexts": {
+						"nodes": [{"__typename": "CheckRun", "name": "build", "status": "COMPLETED", "conclusion": "SUCCESS"}],
+						"pageInfo": {"hasNextPage": true, "endCursor": "c1"}}}}}]}}
+				]}}}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data": {"repository": {"pullRequest": {
+				"commits": {"nodes": [{"commit": {"statusCheckRollup": {"contexts": {
+					"nodes": [{"__typename": "CheckRun", "name": "lint", "status": "COMPLETED", "conclusion": "FAILURE"}],
+					"pageInfo": {"hasNextPage": false}}}}}]}}}}}`)
+		}))
+
+		prs, err := client.FindPRsForBranches(context.Background(), "owner", "repo", []string{"a"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if requests != 2 {
+			t.Errorf("expected 2 requests, got %d", requests)
+		}
+		if status := DeterminePRStatus(prs["a"].Reviews, prs["a"].Checks); status.CheckStatus != "failure" {
+			t.Errorf("CheckStatus = %q, want the failing check of the second page", status.CheckStatus)
+		}
+	})
+
+	t.Run("GraphQL errors are returned", func(t *testing.T) {
+		client := newTestGraphQLClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"errors": [{"message": "Something went wrong"}]}`)
+		}))
+
+		_, err := client.FindPRsForBranches(context.Background(), "owner", "repo", []string{"a"})
+		if err == nil || !strings.Contains(err.Error(), "failed to look up pull requests for branches") {
+			t.Errorf("expected lookup error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package github
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/serpro69/gh-arc/internal/cache"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+// resultCache is the cache of command results.
+type resultCache interface {
+	Delete(key string) error
+	Clear() error
+}
+
+// pullRequestSubresources are the cached GET endpoints below a pull request
+var pullRequestSubresources = []string{"", "/reviews", "/requested_reviewers"}
+
+// cacheKeyIndex maps a resource to the cache keys of the responses that
+// contain it. The zero value is ready to use.
+type cacheKeyIndex struct {
+	mu   sync.Mutex
+	keys map[string]map[string]struct{}
+}
+
+// track records that key holds a response of resource
+func (i *cacheKeyIndex) track(resource, key string) {
+	if resource == "" {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.keys == nil {
+		i.keys = make(map[string]map[string]struct{})
+	}
+	if i.keys[resource] == nil {
+		i.keys[resource] = make(map[string]struct{})
+	}
+	i.keys[resource][key] = struct{}{}
+}
+
+// take returns and forgets the keys recorded for resource
+func (i *cacheKeyIndex) take(resource string) []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var keys []string
+	for key := range i.keys[resource] {
+		keys = append(keys, key)
+	}
+	delete(i.keys, resource)
+	return keys
+}
+
+// cacheResource returns the resource a GET of path reads, or "" for paths
+// no write invalidates. The PR list of a repository is
+// "repos/{owner}/{repo}/pulls", whatever its query; a single PR and its
+// reviews and requested reviewers are "repos/{owner}/{repo}/pulls/{number}".
+func cacheResource(path string) string {
+	path, _, _ = strings.Cut(strings.Trim(path, "/"), "?")
+
+	parts := strings.Split(path, "/")
+	if len(parts) < 4 || parts[0] != "repos" || parts[3] != "pulls" {
+		return ""
+	}
+	if len(parts) == 4 {
+		return path
+	}
+	return strings.Join(parts[:5], "/")
+}
+
+// affectedResources returns the resources a successful write to path
+// changes. Creating a PR changes the list; changing a PR, including merging
+// it or requesting reviewers, changes the PR and the list it appears in.
+func affectedResources(path string) []string {
+	resource := cacheResource(path)
+	if resource == "" {
+		return nil
+	}
+
+	if strings.HasSuffix(resource, "/pulls") {
+		return []string{resource}
+	}
+	list := resource[:strings.LastIndex(resource, "/")]
+	return []string{list, resource}
+}
+
+// invalidateCache removes the cached responses a write to path made stale.
+// Responses of a single PR are removed by key, so entries cached on disk by
+// an earlier invocation go too; list responses vary by query and are
+// removed as far as this client cached them. Anything missed is still
+// revalidated with its ETag before use.
+func (c *Client) invalidateCache(path string) {
+	var keys []string
+	for _, resource := range affectedResources(path) {
+		if strings.HasSuffix(resource, "/pulls") {
+			c.invalidateResults(resource)
+		} else {
+			for _, sub := range pullRequestSubresources {
+				keys = append(keys, GenerateCacheKey("GET", resource+sub, nil))
+			}
+		}
+		keys = append(keys, c.cacheKeys.take(resource)...)
+	}
+
+	if c.cache != nil {
+		for _, key := range keys {
+			c.cache.Delete(key)
+		}
+	}
+
+	if len(keys) > 0 {
+		logger.Debug().
+			Str("path", path).
+			Int("keys", len(keys)).
+			Msg("Invalidated cached responses")
+	}
+}
+
+// invalidateResults removes the cached PR list of gh arc list for the
+// repository of list, a "repos/{owner}/{repo}/pulls" resource
+func (c *Client) invalidateResults(list string) {
+	if c.results == nil {
+		return
+	}
+
+	parts := strings.Split(list, "/")
+	if err := c.results.Delete(cache.PullRequestsKey(parts[1], parts[2])); err != nil {
+		logger.Debug().Err(err).Str("resource", list).Msg("Failed to invalidate cached PR list")
+	}
+}
@@ -0,0 +1,193 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCacheResource(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"repos/o/r/pulls?state=open&page=1", "repos/o/r/pulls"},
+		{"repos/o/r/pulls", "repos/o/r/pulls"},
+		{"repos/o/r/pulls/7", "repos/o/r/pulls/7"},
+		{"repos/o/r/pulls/7/reviews", "repos/o/r/pulls/7"},
+		{"repos/o/r/pulls/7/merge", "repos/o/r/pulls/7"},
+		{"repos/o/r/commits/abc/check-runs", ""},
+		{"repos/o/r/issues/7/comments", ""},
+		{"user", ""},
+	}
+
+	for _, tt := range tests {
+		if got := cacheResource(tt.path); got != tt.want {
+			t.Errorf("cacheResource(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAffectedResources(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"repos/o/r/pulls", []string{"repos/o/r/pulls"}},
+		{"repos/o/r/pulls/7", []string{"repos/o/r/pulls", "repos/o/r/pulls/7"}},
+		{"repos/o/r/pulls/7/merge", []string{"repos/o/r/pulls", "repos/o/r/pulls/7"}},
+		{"repos/o/r/issues/7/comments", nil},
+	}
+
+	for _, tt := range tests {
+		if got := affectedResources(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("affectedResources(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+type mockResultCache struct {
+	deleted []string
+	cleared bool
+}
+
+func (m *mockResultCache) Delete(key string) error {
+	m.deleted = append(m.deleted, key)
+	return nil
+}
+
+func (m *mockResultCache) Clear() error {
+	m.cleared = true
+	return nil
+}
+
+func TestWritesInvalidateCache(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repos/owner/repo/pulls":
+			_, _ = w.Write([]byte(`[{"number": 7}]`))
+		case r.Method == "GET":
+			_, _ = w.Write([]byte(`{"number": 7}`))
+		case r.URL.Path == "/repos/owner/repo/pulls":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number": 8}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+
+	ctx := context.Background()
+	listKey := GenerateCacheKey("GET", "repos/owner/repo/pulls?state=open&sort=updated&direction=desc&per_page=30&page=1", nil)
+	prKey := GenerateCacheKey("GET", "repos/owner/repo/pulls/7", nil)
+	otherKey := GenerateCacheKey("GET", "repos/owner/repo/pulls/9", nil)
+
+	setup := func(t *testing.T) (*Client, *MemoryCache) {
+		client, _ := newTestClient(t, handler)
+		cache := NewMemoryCache(time.Minute)
+		t.Cleanup(cache.Stop)
+		client.cache = cache
+		client.results = &mockResultCache{}
+
+		if _, err := client.GetPullRequests(ctx, "owner", "repo", nil); err != nil {
+			t.Fatalf("GetPullRequests: %v", err)
+		}
+		if _, err := client.GetPullRequest(ctx, "owner", "repo", 7); err != nil {
+			t.Fatalf("GetPullRequest: %v", err)
+		}
+		cache.Set(otherKey, "other", time.Minute)
+		cache.Set(GenerateCacheKey("GET", "repos/owner/repo/pulls/7/reviews", nil), "from an earlier run", time.Minute)
+		return client, cache
+	}
+
+	cached := func(cache Cache, key string) bool {
+		_, found := cache.Get(key)
+		return found
+	}
+
+	t.Run("updating a PR", func(t *testing.T) {
+		client, cache := setup(t)
+
+		if err := client.UpdatePRBase(ctx, "owner", "repo", 7, "main"); err != nil {
+			t.Fatalf("UpdatePRBase: %v", err)
+		}
+
+		if cached(cache, listKey) {
+			t.Error("expected the PR list to be invalidated")
+		}
+		if cached(cache, prKey) {
+			t.Error("expected the PR to be invalidated")
+		}
+		if cached(cache, GenerateCacheKey("GET", "repos/owner/repo/pulls/7/reviews", nil)) {
+			t.Error("expected the reviews to be invalidated even though this client did not cache them")
+		}
+		if !cached(cache, otherKey) {
+			t.Error("expected other PRs to stay cached")
+		}
+	})
+
+	t.Run("creating a PR", func(t *testing.T) {
+		client, cache := setup(t)
+
+		var created PullRequest
+		if err := client.Do(ctx, "POST", "repos/owner/repo/pulls", map[string]string{"title": "t"}, &created); err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+
+		if cached(cache, listKey) {
+			t.Error("expected the PR list to be invalidated")
+		}
+		if !cached(cache, prKey) {
+			t.Error("expected existing PRs to stay cached")
+		}
+		if got := client.results.(*mockResultCache).deleted; !reflect.DeepEqual(got, []string{"prs:owner:repo:open"}) {
+			t.Errorf("deleted results = %v, want the PR list of gh arc list", got)
+		}
+	})
+
+	t.Run("merging a PR", func(t *testing.T) {
+		client, cache := setup(t)
+
+		if _, err := client.MergePullRequest(ctx, "owner", "repo", 7, &MergeOptions{Method: "squash"}); err != nil {
+			t.Fatalf("MergePullRequest: %v", err)
+		}
+
+		if cached(cache, listKey) || cached(cache, prKey) {
+			t.Error("expected the PR and the list to be invalidated")
+		}
+	})
+
+	t.Run("commenting changes nothing cached", func(t *testing.T) {
+		client, cache := setup(t)
+
+		if err := client.CommentOnPullRequest(ctx, "owner", "repo", 7, "hi"); err != nil {
+			t.Fatalf("CommentOnPullRequest: %v", err)
+		}
+
+		if !cached(cache, listKey) || !cached(cache, prKey) {
+			t.Error("expected the cache to be untouched")
+		}
+		if got := client.results.(*mockResultCache).deleted; len(got) != 0 {
+			t.Errorf("deleted results = %v, want none", got)
+		}
+	})
+}
+
+func TestClearCache(t *testing.T) {
+	cache := NewMemoryCache(time.Minute)
+	defer cache.Stop()
+	results := &mockResultCache{}
+	client := &Client{cache: cache, results: results}
+
+	cache.Set("key", "value", time.Minute)
+	client.ClearCache()
+
+	if _, found := cache.Get("key"); found {
+		t.Error("expected responses to be cleared")
+	}
+	if !results.cleared {
+		t.Error("expected cached results to be cleared")
+	}
+}
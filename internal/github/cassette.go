@@ -0,0 +1,294 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrOffline is returned for requests that cannot be answered without the
+// network: in offline mode, or when a cassette has no matching recording.
+var ErrOffline = errors.New("not available offline")
+
+// redacted replaces secrets in recorded bodies
+const redacted = "REDACTED"
+
+// recordedHeaders are the response headers kept in cassettes. Everything
+// else, such as cookies, request IDs and rate limit counters, is either
+// sensitive or differs on every run.
+var recordedHeaders = []string{"Content-Type", "ETag", "Link", "Location"}
+
+// Cassette is a recording of HTTP interactions with GitHub
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and the response GitHub gave to it
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest identifies a request. Credentials and headers are never
+// recorded, and the URL is kept without its host.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"` // Path and query, e.g. /repos/owner/repo/pulls?state=open
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response as replayed
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// LoadCassette reads the cassette at path
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to path, replacing it atomically
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Recorder is an http.RoundTripper that sends requests to GitHub and
+// records every interaction to a cassette file. Requests are recorded
+// unconditional, so replaying does not depend on a cache, and the token of
+// the request is redacted from recorded bodies.
+type Recorder struct {
+	base http.RoundTripper
+	path string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates a Recorder that sends requests through base and
+// writes the cassette to path after every interaction
+func NewRecorder(path string, base http.RoundTripper) *Recorder {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Recorder{base: base, path: path}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Del("If-None-Match")
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := requestToken(req)
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.RequestURI(),
+			Body:   redact(canonicalBody(body), secret),
+		},
+		Response: RecordedResponse{
+			Status: resp.StatusCode,
+			Header: http.Header{},
+			Body:   redact(string(respBody), secret),
+		},
+	}
+	for _, name := range recordedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			interaction.Response.Header.Set(name, value)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	if err := r.cassette.Save(r.path); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper that answers requests from a cassette
+// and never touches the network. Requests match a recording by method, path,
+// query and body; recordings are used in order, and once all matching ones
+// were used the last one answers again. A request without a recording
+// fails with ErrOffline.
+type Replayer struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewReplayer creates a Replayer for the cassette at path
+func NewReplayer(path string) (*Replayer, error) {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewCassetteReplayer(cassette), nil
+}
+
+// NewCassetteReplayer creates a Replayer for a cassette in memory
+func NewCassetteReplayer(cassette *Cassette) *Replayer {
+	return &Replayer{
+		cassette: cassette,
+		used:     make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	want := RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.RequestURI(),
+		Body:   canonicalBody(body),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := -1
+	for i, interaction := range r.cassette.Interactions {
+		if !sameRequest(interaction.Request, want) {
+			continue
+		}
+		match = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("%w: no recording of %s %s", ErrOffline, want.Method, want.URL)
+	}
+	r.used[match] = true
+
+	recorded := r.cassette.Interactions[match].Response
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// offlineTransport fails every request, for offline mode without a cassette
+type offlineTransport struct{}
+
+// RoundTrip implements http.RoundTripper
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrOffline, req.Method, req.URL.RequestURI())
+}
+
+// sameRequest reports whether a request matches a recorded one
+func sameRequest(recorded, req RecordedRequest) bool {
+	return recorded.Method == req.Method && recorded.URL == req.URL && recorded.Body == req.Body
+}
+
+// readBody reads and replaces *body, so the request or response can still
+// be sent or read
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// canonicalBody returns a JSON body with sorted keys and no insignificant
+// whitespace, so equal requests match however they were encoded
+func canonicalBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return string(body)
+	}
+	return string(canonical)
+}
+
+// requestToken returns the token a request authenticates with
+func requestToken(req *http.Request) string {
+	authorization := req.Header.Get("Authorization")
+	if _, token, ok := strings.Cut(authorization, " "); ok {
+		return token
+	}
+	return authorization
+}
+
+// redact removes secret from s
+func redact(s, secret string) string {
+	if secret == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, secret, redacted)
+}
@@ -0,0 +1,214 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	var gotIfNoneMatch []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIfNoneMatch = append(gotIfNoneMatch, r.Header.Get("If-None-Match"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Write([]byte(`{"url":"https://example.com?token=test-token"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "record.json")
+	recorder := NewRecorder(path, &redirectTransport{server: server})
+
+	req, _ := http.NewRequest("POST", "https://api.github.com/repos/owner/repo/pulls?x=1", strings.NewReader(`{"title": "T",  "base":"main"}`))
+	req.Header.Set("Authorization", "token test-token")
+	req.Header.Set("If-None-Match", `"old"`)
+	resp, err := recorder.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if gotIfNoneMatch[0] != "" {
+		t.Errorf("If-None-Match = %q, want requests recorded unconditional", gotIfNoneMatch[0])
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette() error = %v", err)
+	}
+	if len(cassette.Interactions) != 1 {
+		t.Fatalf("Expected 1 interaction, got %d", len(cassette.Interactions))
+	}
+
+	got := cassette.Interactions[0]
+	wantRequest := RecordedRequest{Method: "POST", URL: "/repos/owner/repo/pulls?x=1", Body: `{"base":"main","title":"T"}`}
+	if got.Request != wantRequest {
+		t.Errorf("Request = %+v, want %+v", got.Request, wantRequest)
+	}
+	if got.Response.Status != 200 {
+		t.Errorf("Status = %d, want 200", got.Response.Status)
+	}
+	if strings.Contains(got.Response.Body, "test-token") {
+		t.Errorf("Expected the token to be redacted, got %s", got.Response.Body)
+	}
+	if len(got.Response.Header) != 2 || got.Response.Header.Get("ETag") != `"abc"` {
+		t.Errorf("Header = %v, want only Content-Type and ETag", got.Response.Header)
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	replayer := NewCassetteReplayer(&Cassette{Interactions: []Interaction{
+		{
+			Request:  RecordedRequest{Method: "GET", URL: "/repos/owner/repo/pulls/1"},
+			Response: RecordedResponse{Status: 200, Body: `{"state":"open"}`},
+		},
+		{
+			Request:  RecordedRequest{Method: "GET", URL: "/repos/owner/repo/pulls/1"},
+			Response: RecordedResponse{Status: 200, Body: `{"state":"closed"}`},
+		},
+		{
+			Request:  RecordedRequest{Method: "PATCH", URL: "/repos/owner/repo/pulls/1", Body: `{"base":"main","state":"closed"}`},
+			Response: RecordedResponse{Status: 422, Header: http.Header{"Content-Type": {"application/json"}}, Body: `{"message":"Validation Failed"}`},
+		},
+	}})
+
+	roundTrip := func(method, body string) (int, string, error) {
+		t.Helper()
+		req, _ := http.NewRequest(method, "https://api.github.com/repos/owner/repo/pulls/1", strings.NewReader(body))
+		resp, err := replayer.RoundTrip(req)
+		if err != nil {
+			return 0, "", err
+		}
+		defer resp.Body.Close()
+		data, _ := readBody(&resp.Body)
+		return resp.StatusCode, string(data), nil
+	}
+
+	t.Run("recordings are used in order", func(t *testing.T) {
+		for _, want := range []string{`{"state":"open"}`, `{"state":"closed"}`, `{"state":"closed"}`} {
+			_, body, err := roundTrip("GET", "")
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			if body != want {
+				t.Errorf("body = %s, want %s", body, want)
+			}
+		}
+	})
+
+	t.Run("bodies match however they are encoded", func(t *testing.T) {
+		status, _, err := roundTrip("PATCH", `{ "state": "closed", "base": "main" }`)
+		if err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+		if status != 422 {
+			t.Errorf("status = %d, want 422", status)
+		}
+	})
+
+	t.Run("miss", func(t *testing.T) {
+		_, _, err := roundTrip("PATCH", `{"state":"open"}`)
+		if !errors.Is(err, ErrOffline) {
+			t.Errorf("RoundTrip() error = %v, want ErrOffline", err)
+		}
+	})
+}
+
+func TestRecordAndReplayThroughClient(t *testing.T) {
+	t.Setenv("GH_TOKEN", "test-token")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"number":42,"title":"Add feature","state":"open"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "client.json")
+	recording, err := NewClient(
+		WithHTTPClient(&http.Client{Transport: NewRecorder(path, &redirectTransport{server: server})}),
+		WithoutCache(),
+		WithRepository("owner", "repo"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	recorded, err := recording.GetPullRequest(context.Background(), "owner", "repo", 42)
+	if err != nil {
+		t.Fatalf("GetPullRequest() error = %v", err)
+	}
+	server.Close()
+
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+	replaying, err := NewClient(
+		WithHTTPClient(&http.Client{Transport: replayer}),
+		WithOffline(),
+		WithoutCache(),
+		WithRepository("owner", "repo"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	replayed, err := replaying.GetPullRequest(context.Background(), "owner", "repo", 42)
+	if err != nil {
+		t.Fatalf("GetPullRequest() error = %v", err)
+	}
+	if replayed.Number != recorded.Number || replayed.Title != recorded.Title {
+		t.Errorf("replayed %+v, recorded %+v", replayed, recorded)
+	}
+
+	_, err = replaying.GetPullRequest(context.Background(), "owner", "repo", 43)
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("GetPullRequest() error = %v, want ErrOffline", err)
+	}
+}
+
+func TestOfflineClient(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+
+	cache := NewMemoryCache(time.Minute)
+	defer cache.Stop()
+	cache.SetWithETag(GenerateCacheKey("GET", "repos/owner/repo/pulls/1", nil), map[string]interface{}{"number": 1}, `"abc"`, time.Minute)
+
+	client, err := NewClient(WithOffline(), WithCache(cache), WithRepository("owner", "repo"))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	t.Run("answers from the cache", func(t *testing.T) {
+		var pr PullRequest
+		if err := client.Do(context.Background(), "GET", "repos/owner/repo/pulls/1", nil, &pr); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		if pr.Number != 1 {
+			t.Errorf("Number = %d, want 1", pr.Number)
+		}
+	})
+
+	t.Run("fails clearly on a miss", func(t *testing.T) {
+		err := client.Do(context.Background(), "GET", "repos/owner/repo/pulls/2", nil, nil)
+		if !errors.Is(err, ErrOffline) {
+			t.Fatalf("Do() error = %v, want ErrOffline", err)
+		}
+		if want := "not available offline: GET /repos/owner/repo/pulls/2"; err.Error() != want {
+			t.Errorf("Do() error = %q, want %q", err, want)
+		}
+	})
+
+	t.Run("never writes", func(t *testing.T) {
+		err := client.Do(context.Background(), "PATCH", "repos/owner/repo/pulls/1", map[string]string{"state": "closed"}, nil)
+		if !errors.Is(err, ErrOffline) {
+			t.Errorf("Do() error = %v, want ErrOffline", err)
+		}
+	})
+}
@@ -4,14 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
+	"net/url"
 	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
 	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/serpro69/gh-arc/internal/cache"
 	"github.com/serpro69/gh-arc/internal/logger"
 )
 
@@ -53,8 +56,13 @@ type Config struct {
 	// EnableCache enables response caching
 	EnableCache bool
 
-	// HTTPClient is the underlying HTTP client (optional)
+	// HTTPClient is the underlying HTTP client (optional). Its transport
+	// sends all requests, e.g. a Recorder or Replayer.
 	HTTPClient *http.Client
+
+	// Offline answers requests from the HTTPClient's transport or the cache
+	// and never from the network
+	Offline bool
 }
 
 // DefaultConfig returns a Config with default values
@@ -89,6 +97,17 @@ type Client struct {
 
 	// circuitBreaker prevents excessive retries
 	circuitBreaker *CircuitBreaker
+
+	// cacheKeys remembers which cached responses belong to which resource,
+	// so writes can invalidate them
+	cacheKeys cacheKeyIndex
+
+	// results caches command results built from responses, such as the
+	// PR list shown by gh arc list
+	results resultCache
+
+	// governor paces requests by the remaining rate limit quotas
+	governor *RateLimitGovernor
 }
 
 // Repository represents a GitHub repository context
@@ -145,36 +164,33 @@ func (c *Client) Repository() *Repository {
 	return c.repo
 }
 
+// RateLimit returns the last known quota of a rate limit resource, one of
+// RateLimitCore, RateLimitSearch and RateLimitGraphQL
+func (c *Client) RateLimit(resource string) (RateLimitInfo, bool) {
+	if c.governor == nil {
+		return RateLimitInfo{}, false
+	}
+	return c.governor.Limit(resource)
+}
+
 // NewClient creates a new GitHub client with the specified options
 // It automatically detects the current repository context and sets up authentication
 func NewClient(opts ...ClientOption) (*Client, error) {
-	// Create default REST client
-	restClient, err := api.DefaultRESTClient()
-	if err != nil {
-		return nil, NewAuthenticationError("failed to create REST client", err)
-	}
-
-	// Create default GraphQL client
-	graphqlClient, err := api.DefaultGraphQLClient()
-	if err != nil {
-		return nil, NewAuthenticationError("failed to create GraphQL client", err)
-	}
-
 	// Initialize default config
 	config := DefaultConfig()
 
-	// Initialize client with defaults
+	// Initialize client with defaults; the API clients are created once
+	// the options have chosen the transport
 	client := &Client{
-		restClient:     restClient,
-		graphqlClient:  graphqlClient,
 		config:         config,
 		cache:          &NoOpCache{},                        // Will be replaced below if caching is enabled
 		circuitBreaker: NewCircuitBreaker(5, 1*time.Minute), // 5 failures, 1 minute reset
+		governor:       NewRateLimitGovernor(),              // Paces every request by the rate limit quotas
 	}
 
-	// Enable caching if configured
-	if config.EnableCache {
-		client.cache = NewMemoryCache(1 * time.Minute) // Cleanup interval
+	// Results cached by commands go stale on writes, like responses do
+	if results, err := cache.New(); err == nil {
+		client.results = results
 	}
 
 	// Try to detect current repository context (may fail if not in a repo)
@@ -192,9 +208,79 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 		}
 	}
 
+	if err := client.initAPIClients(); err != nil {
+		return nil, err
+	}
+
+	// Enable caching if configured and no cache was given
+	if _, noop := client.cache.(*NoOpCache); noop && client.config.EnableCache {
+		client.cache = newDefaultCache()
+	}
+
 	return client, nil
 }
 
+// initAPIClients creates the REST and GraphQL clients. Requests go through
+// the transport of Config.HTTPClient if one is set, or fail offline.
+func (c *Client) initAPIClients() error {
+	var base http.RoundTripper = http.DefaultTransport
+	if c.config.Offline {
+		base = offlineTransport{}
+	}
+
+	apiOpts := api.ClientOptions{}
+	if httpClient := c.config.HTTPClient; httpClient != nil {
+		if httpClient.Transport != nil {
+			base = httpClient.Transport
+		}
+		apiOpts.Timeout = httpClient.Timeout
+	}
+
+	// Offline no token is needed, but the API clients insist on one
+	if c.config.Offline {
+		host, _ := auth.DefaultHost()
+		if token, _ := auth.TokenForHost(host); token == "" {
+			apiOpts.AuthToken = redacted
+		}
+	}
+
+	transport := newRateLimitTransport(base, c.governor)
+
+	// REST requests can be conditional
+	restOpts := apiOpts
+	restOpts.Transport = newConditionalTransport(transport)
+	restClient, err := api.NewRESTClient(restOpts)
+	if err != nil {
+		return NewAuthenticationError("failed to create REST client", err)
+	}
+
+	graphqlOpts := apiOpts
+	graphqlOpts.Transport = transport
+	graphqlClient, err := api.NewGraphQLClient(graphqlOpts)
+	if err != nil {
+		return NewAuthenticationError("failed to create GraphQL client", err)
+	}
+
+	c.restClient = restClient
+	c.graphqlClient = graphqlClient
+	return nil
+}
+
+// newDefaultCache returns the cache used unless another one is configured:
+// a TieredCache for the authenticated user, falling back to memory only
+// when the cache directory is unusable.
+func newDefaultCache() Cache {
+	host, _ := auth.DefaultHost()
+	token, _ := auth.TokenForHost(host)
+
+	cache, err := NewTieredCache(TieredCacheOptions{Host: host, User: token})
+	if err != nil {
+		logger.Debug().Err(err).Msg("Disk cache unavailable, caching in memory only")
+		return NewMemoryCache(1 * time.Minute) // Cleanup interval
+	}
+	return cache
+}
+
 // User represents a GitHub user
 type User struct {
 	Login string
@@ -302,10 +388,16 @@ func (c *Client) Do(ctx context.Context, method, path string, body interface{},
 		}
 
 		// Execute the REST request with conditional headers
-		err := c.doRequest(ctx, method, path, bodyReader, cachedETag, response)
+		etag, err := c.doRequest(ctx, method, path, bodyReader, cachedETag, response)
+
+		// Offline, answer from the cache what the transport cannot
+		if err != nil && c.config.Offline && errors.Is(err, ErrOffline) {
+			return c.offlineResponse(method, cacheKey, err, response)
+		}
 
 		// Handle 304 Not Modified - return cached response
-		if err != nil && strings.Contains(err.Error(), "304") {
+		var httpErr *api.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotModified {
 			if c.cache != nil {
 				if cachedResponse, found := c.cache.Get(cacheKey); found {
 					// Copy cached response to output parameter
@@ -328,11 +420,15 @@ func (c *Client) Do(ctx context.Context, method, path string, body interface{},
 		if err == nil {
 			c.circuitBreaker.RecordSuccess()
 
+			// Writes make cached responses of the resources they touch stale
+			if method != "GET" {
+				c.invalidateCache(path)
+			}
+
 			// Cache the response if this was a GET request
 			if c.cache != nil && method == "GET" && response != nil {
-				// Try to extract ETag from response headers
-				// Note: ETag extraction happens in doRequest via response headers
-				c.cache.SetWithETag(cacheKey, response, "", c.config.CacheTTL)
+				c.cache.SetWithETag(cacheKey, response, etag, c.config.CacheTTL)
+				c.cacheKeys.track(cacheResource(path), cacheKey)
 				logger.Debug().
 					Str("cacheKey", cacheKey).
 					Msg("Cached successful response")
@@ -384,32 +480,91 @@ func (c *Client) Do(ctx context.Context, method, path string, body interface{},
 	return fmt.Errorf("max retries exceeded with no error")
 }
 
-// doRequest performs the actual HTTP request with conditional headers
-func (c *Client) doRequest(ctx context.Context, method, path string, body io.Reader, etag string, response interface{}) error {
-	// Build request options
-	opts := []string{method, path}
+// offlineResponse answers a request the offline transport could not from
+// the cache, or fails with err
+func (c *Client) offlineResponse(method, cacheKey string, err error, response interface{}) error {
+	// Drop the URL the HTTP client wraps the error in, it is in err already
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
 
+	if method != "GET" || c.cache == nil {
+		return err
+	}
+	cachedResponse, found := c.cache.Get(cacheKey)
+	if !found {
+		return err
+	}
+
+	if response != nil {
+		if err := copyResponse(cachedResponse, response); err != nil {
+			return fmt.Errorf("failed to use cached response: %w", err)
+		}
+	}
+	logger.Debug().
+		Str("cacheKey", cacheKey).
+		Msg("Using cached response offline")
+	return nil
+}
+
+// doRequest performs the actual HTTP request with conditional headers and
+// returns the ETag of the response. A 304 Not Modified is returned as an
+// error, like every other non-2xx status.
+func (c *Client) doRequest(ctx context.Context, method, path string, body io.Reader, etag string, response interface{}) (string, error) {
 	// Add If-None-Match header if we have a cached ETag
-	var headers map[string]string
 	if etag != "" {
-		headers = map[string]string{
-			"If-None-Match": etag,
-		}
+		ctx = withIfNoneMatch(ctx, etag)
 	}
 
-	// Execute REST request
-	if body != nil {
-		if headers != nil {
-			return c.restClient.DoWithContext(ctx, opts[0], opts[1], body, response)
-		}
-		return c.restClient.DoWithContext(ctx, opts[0], opts[1], body, response)
+	resp, err := c.restClient.RequestWithContext(ctx, method, path, body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent || response == nil {
+		return resp.Header.Get("ETag"), nil
 	}
 
-	// GET request without body
-	if headers != nil {
-		return c.restClient.DoWithContext(ctx, opts[0], opts[1], nil, response)
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return "", err
+	}
+
+	return resp.Header.Get("ETag"), nil
+}
+
+// ifNoneMatchKey carries the ETag of a conditional request in its context
+type ifNoneMatchKey struct{}
+
+// withIfNoneMatch marks the request made with ctx as conditional on etag
+func withIfNoneMatch(ctx context.Context, etag string) context.Context {
+	return context.WithValue(ctx, ifNoneMatchKey{}, etag)
+}
+
+// conditionalTransport sets If-None-Match on requests whose context carries
+// an ETag. The REST client has no per-request headers, so this is how Do
+// turns a cached ETag into a conditional request.
+type conditionalTransport struct {
+	base http.RoundTripper
+}
+
+// newConditionalTransport wraps base so it sends conditional requests
+func newConditionalTransport(base http.RoundTripper) http.RoundTripper {
+	return &conditionalTransport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if etag, ok := req.Context().Value(ifNoneMatchKey{}).(string); ok && etag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", etag)
 	}
-	return c.restClient.DoWithContext(ctx, opts[0], opts[1], nil, response)
+	return t.base.RoundTrip(req)
 }
 
 // copyResponse copies cached response data to the output parameter
@@ -584,6 +739,27 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithRateLimitWait makes the client sleep until a used-up rate limit resets,
+// showing a countdown on out, instead of failing requests
+func WithRateLimitWait(out io.Writer) ClientOption {
+	return func(c *Client) error {
+		if c.governor != nil {
+			c.governor.SetWait(true, out)
+		}
+		return nil
+	}
+}
+
+// WithOffline keeps the client off the network. Requests are answered by the
+// transport of WithHTTPClient, e.g. a Replayer, and GET requests it cannot
+// answer from the cache; anything else fails with ErrOffline.
+func WithOffline() ClientOption {
+	return func(c *Client) error {
+		c.config.Offline = true
+		return nil
+	}
+}
+
 // WithRepository sets the repository context manually
 func WithRepository(owner, name string) ClientOption {
 	return func(c *Client) error {
@@ -602,9 +778,23 @@ func (c *Client) CacheStats() CacheStats {
 	return c.cache.Stats()
 }
 
-// ClearCache clears all cached entries
+// ClearCache clears all cached entries, including cached command results
 func (c *Client) ClearCache() {
 	c.cache.Clear()
+	if c.results != nil {
+		if err := c.results.Clear(); err != nil {
+			logger.Debug().Err(err).Msg("Failed to clear cached results")
+		}
+	}
+}
+
+// CacheDir returns the directory of the on-disk cache, or "" when responses
+// are only cached in memory
+func (c *Client) CacheDir() string {
+	if disk, ok := c.cache.(interface{ Dir() string }); ok {
+		return disk.Dir()
+	}
+	return ""
 }
 
 // InvalidateCacheKey removes a specific key from the cache
@@ -615,9 +805,9 @@ func (c *Client) InvalidateCacheKey(key string) {
 // Close stops the client and cleans up resources
 // Should be called when the client is no longer needed
 func (c *Client) Close() error {
-	// Stop the cache cleanup goroutine if using MemoryCache
-	if memCache, ok := c.cache.(*MemoryCache); ok {
-		memCache.Stop()
+	// Stop the cache cleanup goroutine of the in-memory cache
+	if stopper, ok := c.cache.(interface{ Stop() }); ok {
+		stopper.Stop()
 	}
 	return nil
 }
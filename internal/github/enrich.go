@@ -0,0 +1,202 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+// enrichPRBatchSize is the number of PRs enriched per GraphQL query. Each PR
+// selects up to 50 reviews, 100 review requests and 100 check contexts, so
+// this keeps a query well below GitHub's node limit.
+const enrichPRBatchSize = 25
+
+// enrichPRFields selects the metadata EnrichPullRequests fills in
+const enrichPRFields = `
+        number` + prMetadataFields + `
+        reviewRequests(first: 100) {` + reviewRequestNodeFields + pageInfoFields + `
+        }`
+
+// reviewsPageFields, checksPageFields and reviewRequestsPageFields select
+// one further page of a metadata connection, for fetchPRMetadataPage
+const (
+	reviewsPageFields = `
+        latestReviews(first: %d, after: $after) {` + reviewNodeFields + pageInfoFields + `
+        }`
+
+	checksPageFields = `
+        commits(last: 1) {
+          nodes {
+            commit {
+              statusCheckRollup {
+                contexts(first: %d, after: $after) {` + checkContextNodeFields + pageInfoFields + `
+                }
+              }
+            }
+          }
+        }`
+
+	reviewRequestsPageFields = `
+        reviewRequests(first: %d, after: $after) {` + reviewRequestNodeFields + pageInfoFields + `
+        }`
+)
+
+// enrichPullRequestsGraphQL fills Reviews, Checks and Reviewers of prs with
+// batched GraphQL queries. It returns the PRs it could not fill: those
+// GitHub did not return, and on error those of the failed and later batches.
+func (c *Client) enrichPullRequestsGraphQL(ctx context.Context, owner, repo string, prs []*PullRequest) ([]*PullRequest, error) {
+	var missing []*PullRequest
+
+	for start := 0; start < len(prs); start += enrichPRBatchSize {
+		end := start + enrichPRBatchSize
+		if end > len(prs) {
+			end = len(prs)
+		}
+
+		batchMissing, err := c.enrichPullRequestBatch(ctx, owner, repo, prs[start:end])
+		if err != nil {
+			return append(missing, prs[start:]...), err
+		}
+		missing = append(missing, batchMissing...)
+	}
+
+	logger.Debug().
+		Int("count", len(prs)).
+		Int("missing", len(missing)).
+		Msg("Enriched pull requests with GraphQL")
+
+	return missing, nil
+}
+
+func (c *Client) enrichPullRequestBatch(ctx context.Context, owner, repo string, prs []*PullRequest) ([]*PullRequest, error) {
+	query, variables := buildEnrichPRQuery(owner, repo, prs)
+
+	var response struct {
+		Repository map[string]*graphQLPullRequest `json:"repository"`
+	}
+	if err := c.DoGraphQL(ctx, query, variables, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch pull request metadata: %w", err)
+	}
+
+	var missing []*PullRequest
+	for i, pr := range prs {
+		node := response.Repository[fmt.Sprintf("p%d", i)]
+		if node == nil || node.Number != pr.Number {
+			missing = append(missing, pr)
+			continue
+		}
+
+		// A PR whose remaining pages cannot be fetched is left to REST
+		// rather than reported with checks missing
+		if err := c.completeMetadata(ctx, owner, repo, node); err != nil {
+			logger.Debug().Err(err).Int("number", pr.Number).Msg("Failed to fetch remaining PR metadata")
+			missing = append(missing, pr)
+			continue
+		}
+
+		pr.Reviews = node.reviews()
+		pr.Checks = node.checks()
+		pr.Reviewers = node.reviewers()
+	}
+
+	return missing, nil
+}
+
+// buildEnrichPRQuery builds one query with an aliased pullRequest field per
+// PR (p0, p1, ...), each selected by number.
+func buildEnrichPRQuery(owner, repo string, prs []*PullRequest) (string, map[string]interface{}) {
+	variables := map[string]interface{}{
+		"owner": owner,
+		"name":  repo,
+	}
+
+	var params, fields strings.Builder
+	params.WriteString("$owner: String!, $name: String!")
+	for i, pr := range prs {
+		fmt.Fprintf(&params, ", $n%d: Int!", i)
+		variables[fmt.Sprintf("n%d", i)] = pr.Number
+
+		fmt.Fprintf(&fields, "\n    p%d: pullRequest(number: $n%d) {%s\n    }", i, i, enrichPRFields)
+	}
+
+	query := fmt.Sprintf("query EnrichPullRequests(%s) {\n  repository(owner: $owner, name: $name) {%s\n  }\n}", params.String(), fields.String())
+	return query, variables
+}
+
+// completeMetadata fetches the pages of the review, check and review request
+// connections of g that did not fit in the first one, so a PR with many
+// checks is never reported with some of them missing.
+func (c *Client) completeMetadata(ctx context.Context, owner, repo string, g *graphQLPullRequest) error {
+	reviews := &g.LatestReviews
+	for reviews.PageInfo.HasNextPage {
+		page, err := c.fetchPRMetadataPage(ctx, owner, repo, g.Number, reviews.PageInfo.EndCursor, reviewsPageFields)
+		if err != nil {
+			return err
+		}
+		reviews.Nodes = append(reviews.Nodes, page.LatestReviews.Nodes...)
+		reviews.PageInfo = page.LatestReviews.PageInfo
+	}
+
+	for i := range g.Commits.Nodes {
+		rollup := g.Commits.Nodes[i].Commit.StatusCheckRollup
+		if rollup == nil {
+			continue
+		}
+		for rollup.Contexts.PageInfo.HasNextPage {
+			page, err := c.fetchPRMetadataPage(ctx, owner, repo, g.Number, rollup.Contexts.PageInfo.EndCursor, checksPageFields)
+			if err != nil {
+				return err
+			}
+			if len(page.Commits.Nodes) == 0 || page.Commits.Nodes[0].Commit.StatusCheckRollup == nil {
+				return fmt.Errorf("head commit of PR #%d changed while fetching checks", g.Number)
+			}
+			contexts := page.Commits.Nodes[0].Commit.StatusCheckRollup.Contexts
+			rollup.Contexts.Nodes = append(rollup.Contexts.Nodes, contexts.Nodes...)
+			rollup.Contexts.PageInfo = contexts.PageInfo
+		}
+	}
+
+	requests := &g.ReviewRequests
+	for requests.PageInfo.HasNextPage {
+		page, err := c.fetchPRMetadataPage(ctx, owner, repo, g.Number, requests.PageInfo.EndCursor, reviewRequestsPageFields)
+		if err != nil {
+			return err
+		}
+		requests.Nodes = append(requests.Nodes, page.ReviewRequests.Nodes...)
+		requests.PageInfo = page.ReviewRequests.PageInfo
+	}
+
+	return nil
+}
+
+// fetchPRMetadataPage fetches one page of a connection of a PR. selection
+// selects the connection, with a %d for the page size and $after as cursor.
+func (c *Client) fetchPRMetadataPage(ctx context.Context, owner, repo string, number int, after, selection string) (*graphQLPullRequest, error) {
+	query := fmt.Sprintf(`query PullRequestMetadataPage($owner: String!, $name: String!, $number: Int!, $after: String!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {`+selection+`
+    }
+  }
+}`, prMetadataPageSize)
+	variables := map[string]interface{}{
+		"owner":  owner,
+		"name":   repo,
+		"number": number,
+		"after":  after,
+	}
+
+	var response struct {
+		Repository struct {
+			PullRequest *graphQLPullRequest `json:"pullRequest"`
+		} `json:"repository"`
+	}
+	if err := c.DoGraphQL(ctx, query, variables, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch more metadata of PR #%d: %w", number, err)
+	}
+	if response.Repository.PullRequest == nil {
+		return nil, fmt.Errorf("pull request #%d not found", number)
+	}
+	return response.Repository.PullRequest, nil
+}
@@ -0,0 +1,256 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// prMetadataNode renders the GraphQL metadata of one PR
+func prMetadataNode(number int) string {
+	return fmt.Sprintf(`{"number": %d,
+		"latestReviews": {"nodes": [{"author": {"login": "bob"}, "state": "CHANGES_REQUESTED"}]},
+		"reviewRequests": {"nodes": [
+			{"requestedReviewer": {"__typename": "User", "login": "carol"}},
+			{"requestedReviewer": {"__typename": "Team", "slug": "core"}},
+			{"requestedReviewer": null}
+		]},
+		"commits": {"nodes": [{"commit": {"statusCheckRollup": {"contexts": {"nodes": [
+			{"__typename": "CheckRun", "name": "build", "status": "COMPLETED", "conclusion": "SUCCESS"}
+		]}}}}]}}`, number)
+}
+
+// restMetadataHandler serves the per-PR REST endpoints and counts the calls
+func restMetadataHandler(w http.ResponseWriter, r *http.Request) bool {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/reviews"):
+		fmt.Fprint(w, `[{"id": 1, "user": {"login": "dave"}, "state": "APPROVED"}]`)
+	case strings.HasSuffix(r.URL.Path, "/requested_reviewers"):
+		fmt.Fprint(w, `{"users": [], "teams": []}`)
+	case strings.HasSuffix(r.URL.Path, "/check-runs"):
+		fmt.Fprint(w, `{"total_count": 0, "check_runs": []}`)
+	default:
+		return false
+	}
+	return true
+}
+
+func TestEnrichPullRequests(t *testing.T) {
+	ctx := context.Background()
+
+	newPRs := func(n int) []*PullRequest {
+		prs := make([]*PullRequest, n)
+		for i := range prs {
+			prs[i] = &PullRequest{Number: i + 1, Head: PRBranch{SHA: fmt.Sprintf("sha%d", i+1)}}
+		}
+		return prs
+	}
+
+	t.Run("fetches metadata in batched GraphQL queries", func(t *testing.T) {
+		var requests []graphQLRequest
+		var mu sync.Mutex
+		restCalls := 0
+
+		client := newTestGraphQLClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if r.URL.Path != "/graphql" {
+				restCalls++
+				restMetadataHandler(w, r)
+				return
+			}
+
+			var req graphQLRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			requests = append(requests, req)
+
+			var nodes []string
+			for name, value := range req.Variables {
+				if strings.HasPrefix(name, "n") && name != "name" {
+					nodes = append(nodes, fmt.Sprintf(`"p%s": %s`, name[1:], prMetadataNode(int(value.(float64)))))
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"data": {"repository": {%s}}}`, strings.Join(nodes, ","))
+		}))
+
+		prs := newPRs(enrichPRBatchSize + 1)
+		if err := client.EnrichPullRequests(ctx, "owner", "repo", prs); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(requests) != 2 {
+			t.Fatalf("expected 2 batched requests, got %d", len(requests))
+		}
+		if restCalls != 0 {
+			t.Errorf("expected no REST calls, got %d", restCalls)
+		}
+		if !strings.Contains(requests[0].Query, "p1: pullRequest(number: $n1)") {
+			t.Errorf("query missing aliased field for second PR:\n%s", requests[0].Query)
+		}
+		if requests[1].Variables["n0"] != float64(enrichPRBatchSize+1) {
+			t.Errorf("second batch n0 = %v, want %d", requests[1].Variables["n0"], enrichPRBatchSize+1)
+		}
+
+		pr := prs[enrichPRBatchSize]
+		status := DeterminePRStatus(pr.Reviews, pr.Checks)
+		if status.ReviewStatus != "changes_requested" || status.CheckStatus != "success" {
+			t.Errorf("status = %+v, want changes_requested and success", status)
+		}
+		want := []PRReviewer{{Login: "carol", Type: "User"}, {Login: "core", Type: "Team"}}
+		if fmt.Sprint(pr.Reviewers) != fmt.Sprint(want) {
+			t.Errorf("Reviewers = %v, want %v", pr.Reviewers, want)
+		}
+	})
+
+	t.Run("falls back to REST for PRs GraphQL did not return", func(t *testing.T) {
+		var restPaths []string
+		var mu sync.Mutex
+
+		client := newTestGraphQLClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if r.URL.Path != "/graphql" {
+				restPaths = append(restPaths, r.URL.Path)
+				restMetadataHandler(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"data": {"repository": {"p0": %s, "p1": null}}}`, prMetadataNode(1))
+		}))
+
+		prs := newPRs(2)
+		if err := client.EnrichPullRequests(ctx, "owner", "repo", prs); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(restPaths) != 3 {
+			t.Errorf("expected 3 REST calls for PR #2, got %v", restPaths)
+		}
+		for _, path := range restPaths {
+			if strings.Contains(path, "/pulls/1/") || strings.Contains(path, "sha1") {
+				t.Errorf("unexpected REST call for PR #1: %s", path)
+			}
+		}
+		if len(prs[1].Reviews) != 1 || prs[1].Reviews[0].User.Login != "dave" {
+			t.Errorf("PR #2 reviews = %+v, want the REST review", prs[1].Reviews)
+		}
+	})
+
+	t.Run("falls back to REST when GraphQL fails", func(t *testing.T) {
+		var mu sync.Mutex
+		restCalls := 0
+
+		client := newTestGraphQLClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if r.URL.Path != "/graphql" {
+				restCalls++
+				restMetadataHandler(w, r)
+				return
+			}
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+
+		prs := newPRs(2)
+		if err := client.EnrichPullRequests(ctx, "owner", "repo", prs); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if restCalls != 6 {
+			t.Errorf("expected 3 REST calls per PR, got %d", restCalls)
+		}
+		for _, pr := range prs {
+			if len(pr.Reviews) != 1 {
+				t.Errorf("PR #%d was not enriched: %+v", pr.Number, pr.Reviews)
+			}
+		}
+	})
+
+	t.Run("follows further pages of checks", func(t *testing.T) {
+		var requests []graphQLRequest
+
+		client := newTestGraphQLClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req graphQLRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			requests = append(requests, req)
+
+			w.Header().Set("Content-Type", "application/json")
+			if req.Variables["after"] == nil {
+				fmt.Fprint(w, `{"data": {"repository": {"p0": {"number": 1,
+					"commits": {"nodes": [{"commit": {"statusCheckRollup": {"contexts": {
+						"nodes": [{"__typename": "CheckRun", "name": "build", "status": "COMPLETED", "conclusion": "SUCCESS"}],
+						"pageInfo": {"hasNextPage": true, "endCursor": "c1"}}}}}]}}}}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data": {"repository": {"pullRequest": {
+				"commits": {"nodes": [{"commit": {"statusCheckRollup": {"contexts": {
+					"nodes": [{"__typename": "StatusContext", "context": "deploy", "state": "FAILURE"}],
+					"pageInfo": {"hasNextPage": false, "endCursor": "c2"}}}}}]}}}}}`)
+		}))
+
+		prs := newPRs(1)
+		if err := client.EnrichPullRequests(ctx, "owner", "repo", prs); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(requests) != 2 {
+			t.Fatalf("expected a request for the second page, got %d requests", len(requests))
+		}
+		if requests[1].Variables["after"] != "c1" || requests[1].Variables["number"] != float64(1) {
+			t.Errorf("second page variables = %v", requests[1].Variables)
+		}
+		if len(prs[0].Checks) != 2 {
+			t.Fatalf("Checks = %+v, want both pages", prs[0].Checks)
+		}
+		if status := DeterminePRStatus(prs[0].Reviews, prs[0].Checks); status.CheckStatus != "failure" {
+			t.Errorf("CheckStatus = %q, want the failing check of the second page", status.CheckStatus)
+		}
+	})
+
+	t.Run("falls back to REST when further pages fail", func(t *testing.T) {
+		var mu sync.Mutex
+		restCalls := 0
+
+		client := newTestGraphQLClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if r.URL.Path != "/graphql" {
+				restCalls++
+				restMetadataHandler(w, r)
+				return
+			}
+
+			var req graphQLRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.Variables["after"] != nil {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"data": {"repository": {"p0": {"number": 1,
+				"latestReviews": {"nodes": [], "pageInfo": {"hasNextPage": true, "endCursor": "r1"}}}}}}`)
+		}))
+
+		prs := newPRs(1)
+		if err := client.EnrichPullRequests(ctx, "owner", "repo", prs); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if restCalls != 3 {
+			t.Errorf("expected 3 REST calls for the truncated PR, got %d", restCalls)
+		}
+		if len(prs[0].Reviews) != 1 || prs[0].Reviews[0].User.Login != "dave" {
+			t.Errorf("Reviews = %+v, want the REST review", prs[0].Reviews)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package github
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile blocks until it holds an flock on f, shared or exclusive.
+func lockFile(f *os.File, exclusive bool) error {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	return syscall.Flock(int(f.Fd()), how)
+}
+
+// unlockFile releases the lock taken by lockFile.
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
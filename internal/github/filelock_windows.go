@@ -0,0 +1,12 @@
+//go:build windows
+
+package github
+
+import "os"
+
+// lockFile is a no-op on Windows; cache entries are still replaced
+// atomically, so readers never see a partial file.
+func lockFile(f *os.File, exclusive bool) error { return nil }
+
+// unlockFile is a no-op on Windows.
+func unlockFile(f *os.File) error { return nil }
@@ -17,18 +17,19 @@ var ErrBranchProtectionPermissionDenied = errors.New("insufficient permissions t
 
 // PullRequest represents a GitHub pull request with all relevant information
 type PullRequest struct {
-	Number    int       `json:"number"`
-	NodeID    string    `json:"node_id"` // GraphQL global node ID
-	Title     string    `json:"title"`
-	Body      string    `json:"body"`
-	State     string    `json:"state"` // open, closed
-	Draft     bool      `json:"draft"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	User      PRUser    `json:"user"`
-	Head      PRBranch  `json:"head"`
-	Base      PRBranch  `json:"base"`
-	HTMLURL   string    `json:"html_url"`
+	Number    int        `json:"number"`
+	NodeID    string     `json:"node_id"` // GraphQL global node ID
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	State     string     `json:"state"` // open, closed
+	Draft     bool       `json:"draft"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	MergedAt  *time.Time `json:"merged_at,omitempty"`
+	User      PRUser     `json:"user"`
+	Head      PRBranch   `json:"head"`
+	Base      PRBranch   `json:"base"`
+	HTMLURL   string     `json:"html_url"`
 
 	// Additional fields populated by separate API calls
 	Reviews   []PRReview   `json:"-"` // Not included in list PR response
@@ -36,6 +37,11 @@ type PullRequest struct {
 	Reviewers []PRReviewer `json:"-"` // Not included in list PR response
 }
 
+// IsMerged returns true if the pull request has been merged
+func (pr *PullRequest) IsMerged() bool {
+	return pr.MergedAt != nil
+}
+
 // PRUser represents a user associated with a pull request
 type PRUser struct {
 	Login string `json:"login"`
@@ -97,6 +103,7 @@ type PullRequestListOptions struct {
 	Direction string // asc, desc (default: desc)
 	PerPage   int    // Results per page (default: 30, max: 100)
 	Page      int    // Page number (default: 1)
+	Head      string // Only PRs from this head, as "owner:branch" (default: any)
 }
 
 // DefaultPullRequestListOptions returns options with sensible defaults
@@ -120,6 +127,9 @@ func (c *Client) GetPullRequests(ctx context.Context, owner, repo string, opts *
 	// Build query parameters
 	path := fmt.Sprintf("repos/%s/%s/pulls?state=%s&sort=%s&direction=%s&per_page=%d&page=%d",
 		owner, repo, opts.State, opts.Sort, opts.Direction, opts.PerPage, opts.Page)
+	if opts.Head != "" {
+		path += "&head=" + url.QueryEscape(opts.Head)
+	}
 
 	logger.Debug().
 		Str("owner", owner).
@@ -127,77 +137,20 @@ func (c *Client) GetPullRequests(ctx context.Context, owner, repo string, opts *
 		Str("path", path).
 		Msg("Fetching pull requests")
 
-	// Check circuit breaker
-	if !c.circuitBreaker.Allow() {
-		return nil, fmt.Errorf("circuit breaker is open, requests are temporarily blocked")
-	}
-
-	// Create retry policy
-	policy := &RetryPolicy{
-		MaxRetries: c.config.MaxRetries,
-		BaseDelay:  c.config.BaseDelay,
-		MaxDelay:   c.config.MaxDelay,
-	}
-
+	// Do retries transient failures and revalidates the cached list
 	var prs []*PullRequest
-	var lastErr error
-
-	// Execute with retry logic
-	for attempt := 0; attempt <= policy.MaxRetries; attempt++ {
-		// Make the API request
-		err := c.restClient.Get(path, &prs)
-
-		if err == nil {
-			// Success
-			c.circuitBreaker.RecordSuccess()
-			logger.Debug().
-				Int("count", len(prs)).
-				Msg("Successfully fetched pull requests")
-			return prs, nil
-		}
-
-		lastErr = err
-
-		// Check if error is retryable
-		if !IsRetryableError(err) {
-			c.circuitBreaker.RecordFailure()
-			logger.Error().
-				Err(err).
-				Msg("Non-retryable error fetching pull requests")
-			return nil, fmt.Errorf("failed to fetch pull requests: %w", err)
-		}
-
-		// If this was the last attempt, don't wait
-		if attempt == policy.MaxRetries {
-			break
-		}
-
-		// Calculate backoff delay
-		backoff := policy.calculateBackoff(attempt)
-
-		logger.Debug().
-			Int("attempt", attempt+1).
-			Dur("backoff", backoff).
-			Msg("Retrying pull request fetch after error")
-
-		// Wait before retrying, respecting context cancellation
-		select {
-		case <-ctx.Done():
-			c.circuitBreaker.RecordFailure()
-			return nil, fmt.Errorf("request canceled: %w", ctx.Err())
-		case <-time.After(backoff):
-			// Continue to next attempt
-		}
+	if err := c.Do(ctx, "GET", path, nil, &prs); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to fetch pull requests")
+		return nil, fmt.Errorf("failed to fetch pull requests: %w", err)
 	}
 
-	// All retries exhausted
-	c.circuitBreaker.RecordFailure()
-	logger.Error().
-		Err(lastErr).
-		Int("attempts", policy.MaxRetries+1).
-		Msg("Max retries exceeded fetching pull requests")
+	logger.Debug().
+		Int("count", len(prs)).
+		Msg("Successfully fetched pull requests")
 
-	return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
+	return prs, nil
 }
 
 // GetPullRequestsWithPagination fetches all pull requests across multiple pages
@@ -323,7 +276,7 @@ func (c *Client) GetPullRequestReviews(ctx context.Context, owner, repo string,
 		Msg("Fetching PR reviews")
 
 	var reviews []PRReview
-	err := c.restClient.Get(path, &reviews)
+	err := c.Do(ctx, "GET", path, nil, &reviews)
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -392,7 +345,7 @@ func (c *Client) GetPullRequestRequestedReviewers(ctx context.Context, owner, re
 		} `json:"teams"`
 	}
 
-	err := c.restClient.Get(path, &response)
+	err := c.Do(ctx, "GET", path, nil, &response)
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -609,15 +562,42 @@ func (c *Client) EnrichPullRequest(ctx context.Context, owner, repo string, pr *
 	return nil
 }
 
-// EnrichPullRequests enriches multiple pull requests with metadata in parallel
+// EnrichPullRequests enriches multiple pull requests with reviews, checks
+// and requested reviewers. The metadata of all PRs is fetched with a few
+// batched GraphQL queries; PRs those could not fill are enriched over REST.
 func (c *Client) EnrichPullRequests(ctx context.Context, owner, repo string, prs []*PullRequest) error {
 	if len(prs) == 0 {
 		return nil
 	}
 
+	remaining := prs
+	if c.graphqlClient != nil {
+		missing, err := c.enrichPullRequestsGraphQL(ctx, owner, repo, prs)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			logger.Warn().
+				Err(err).
+				Int("count", len(missing)).
+				Msg("Batched PR enrichment failed, falling back to REST")
+		}
+		remaining = missing
+	}
+
+	if len(remaining) == 0 {
+		return nil
+	}
+	return c.enrichPullRequestsREST(ctx, owner, repo, remaining)
+}
+
+// enrichPullRequestsREST enriches multiple pull requests with metadata in
+// parallel, with three REST calls per PR
+func (c *Client) enrichPullRequestsREST(ctx context.Context, owner, repo string, prs []*PullRequest) error {
 	// Use a semaphore to limit concurrent API calls
-	// GitHub API has rate limits, so we don't want to overwhelm it
-	maxConcurrent := 5
+	// GitHub API has rate limits, so we don't want to overwhelm it,
+	// and one PR at a time once the quota runs low
+	maxConcurrent := c.governor.Concurrency(RateLimitCore, 5)
 	semaphore := make(chan struct{}, maxConcurrent)
 	errChan := make(chan error, len(prs))
 	doneChan := make(chan struct{})
@@ -721,6 +701,24 @@ func (c *Client) FindExistingPRForCurrentBranch(ctx context.Context, branchName
 	return c.FindExistingPR(ctx, c.repo.Owner, c.repo.Name, branchName)
 }
 
+// GetPullRequest fetches a single pull request by number, in any state
+func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+	logger.Debug().
+		Str("owner", owner).
+		Str("repo", repo).
+		Int("pr", number).
+		Msg("Fetching pull request")
+
+	path := fmt.Sprintf("repos/%s/%s/pulls/%d", owner, repo, number)
+
+	var pr PullRequest
+	if err := c.Do(ctx, "GET", path, nil, &pr); err != nil {
+		return nil, fmt.Errorf("failed to fetch PR #%d: %w", number, err)
+	}
+
+	return &pr, nil
+}
+
 // DetectBaseChanged checks if the base branch of an existing PR differs from the detected base
 // Returns true if the base has changed and needs updating
 func DetectBaseChanged(existingPR *PullRequest, detectedBase string) bool {
@@ -779,6 +777,47 @@ func (c *Client) UpdatePRBaseForCurrentRepo(ctx context.Context, number int, new
 	return c.UpdatePRBase(ctx, c.repo.Owner, c.repo.Name, number, newBase)
 }
 
+// ClosePullRequest closes a pull request without merging it
+// PATCH /repos/{owner}/{repo}/pulls/{number}
+func (c *Client) ClosePullRequest(ctx context.Context, owner, repo string, number int) error {
+	logger.Info().
+		Int("pr", number).
+		Msg("Closing pull request")
+
+	path := fmt.Sprintf("repos/%s/%s/pulls/%d", owner, repo, number)
+
+	payload := UpdatePRRequest{State: "closed"}
+	if err := c.Do(ctx, "PATCH", path, payload, nil); err != nil {
+		return fmt.Errorf("failed to close PR #%d: %w", number, err)
+	}
+
+	return nil
+}
+
+// CommentOnPullRequest adds a comment to the conversation of a pull request.
+// PR conversations are issue comments in the GitHub API.
+// POST /repos/{owner}/{repo}/issues/{number}/comments
+func (c *Client) CommentOnPullRequest(ctx context.Context, owner, repo string, number int, body string) error {
+	if body == "" {
+		return fmt.Errorf("comment body is required")
+	}
+
+	logger.Debug().
+		Int("pr", number).
+		Msg("Commenting on pull request")
+
+	path := fmt.Sprintf("repos/%s/%s/issues/%d/comments", owner, repo, number)
+
+	payload := map[string]interface{}{
+		"body": body,
+	}
+	if err := c.Do(ctx, "POST", path, payload, nil); err != nil {
+		return fmt.Errorf("failed to comment on PR #%d: %w", number, err)
+	}
+
+	return nil
+}
+
 // MarkPRReadyForReview marks a draft pull request as ready for review
 // This uses the GitHub GraphQL API since there's no REST endpoint for this
 // Requires the PR to have a NodeID populated
@@ -2479,7 +2479,7 @@ func newTestClient(t *testing.T, handler http.Handler) (*Client, *httptest.Serve
 	restClient, err := api.NewRESTClient(api.ClientOptions{
 		Host:      "github.com",
 		AuthToken: "test-token",
-		Transport: &redirectTransport{server: server},
+		Transport: newConditionalTransport(&redirectTransport{server: server}),
 	})
 	if err != nil {
 		server.Close()
@@ -2950,3 +2950,143 @@ func TestGetRequiredStatusChecksForCurrentRepo(t *testing.T) {
 		}
 	})
 }
+
+func TestGetPullRequest(t *testing.T) {
+	t.Run("fetches PR with fork head", func(t *testing.T) {
+		var gotMethod, gotPath string
+
+		client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotMethod = r.Method
+			gotPath = r.URL.Path
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{
+				"number": 42,
+				"title": "Fix parser",
+				"state": "open",
+				"head": {"ref": "fix", "sha": "abc123", "repo": {"name": "repo", "full_name": "contrib/repo", "owner": {"login": "contrib"}}},
+				"base": {"ref": "main", "sha": "def456", "repo": {"name": "repo", "full_name": "owner/repo", "owner": {"login": "owner"}}}
+			}`))
+		}))
+
+		pr, err := client.GetPullRequest(context.Background(), "owner", "repo", 42)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotMethod != "GET" {
+			t.Errorf("method = %q, want GET", gotMethod)
+		}
+		if !strings.HasSuffix(gotPath, "/repos/owner/repo/pulls/42") {
+			t.Errorf("path = %q, want suffix /repos/owner/repo/pulls/42", gotPath)
+		}
+		if pr.Number != 42 || pr.Head.Ref != "fix" || pr.Head.SHA != "abc123" {
+			t.Errorf("unexpected PR: %+v", pr)
+		}
+		if pr.Head.Repo.FullName != "contrib/repo" {
+			t.Errorf("Head.Repo.FullName = %q, want contrib/repo", pr.Head.Repo.FullName)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+		}))
+
+		_, err := client.GetPullRequest(context.Background(), "owner", "repo", 999)
+		if err == nil {
+			t.Fatal("expected error for missing PR")
+		}
+		if !strings.Contains(err.Error(), "#999") {
+			t.Errorf("error = %q, want PR number", err.Error())
+		}
+	})
+}
+
+func TestClosePullRequest(t *testing.T) {
+	var gotMethod, gotPath string
+	var gotBody map[string]interface{}
+
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"number": 42, "state": "closed"}`))
+	}))
+
+	if err := client.ClosePullRequest(context.Background(), "owner", "repo", 42); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotMethod != "PATCH" {
+		t.Errorf("method = %q, want PATCH", gotMethod)
+	}
+	if !strings.HasSuffix(gotPath, "/repos/owner/repo/pulls/42") {
+		t.Errorf("path = %q, want suffix /repos/owner/repo/pulls/42", gotPath)
+	}
+	if gotBody["state"] != "closed" || len(gotBody) != 1 {
+		t.Errorf("body = %v, want only state=closed", gotBody)
+	}
+}
+
+func TestCommentOnPullRequest(t *testing.T) {
+	t.Run("posts an issue comment", func(t *testing.T) {
+		var gotMethod, gotPath string
+		var gotBody map[string]interface{}
+
+		client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotMethod = r.Method
+			gotPath = r.URL.Path
+			_ = json.NewDecoder(r.Body).Decode(&gotBody)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		}))
+
+		if err := client.CommentOnPullRequest(context.Background(), "owner", "repo", 42, "Folded into #41"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotMethod != "POST" {
+			t.Errorf("method = %q, want POST", gotMethod)
+		}
+		if !strings.HasSuffix(gotPath, "/repos/owner/repo/issues/42/comments") {
+			t.Errorf("path = %q, want suffix /repos/owner/repo/issues/42/comments", gotPath)
+		}
+		if gotBody["body"] != "Folded into #41" {
+			t.Errorf("body = %v, want %q", gotBody["body"], "Folded into #41")
+		}
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("no request expected")
+		}))
+
+		if err := client.CommentOnPullRequest(context.Background(), "owner", "repo", 42, ""); err == nil {
+			t.Fatal("expected error for empty comment")
+		}
+	})
+}
+
+func TestGetPullRequestsHeadFilter(t *testing.T) {
+	var gotQuery map[string][]string
+
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+
+	opts := DefaultPullRequestListOptions()
+	opts.State = "closed"
+	opts.Head = "owner:feature/x"
+	if _, err := client.GetPullRequests(context.Background(), "owner", "repo", opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := gotQuery["head"]; len(got) != 1 || got[0] != "owner:feature/x" {
+		t.Errorf("head = %v, want owner:feature/x", got)
+	}
+	if got := gotQuery["state"]; len(got) != 1 || got[0] != "closed" {
+		t.Errorf("state = %v, want closed", got)
+	}
+}
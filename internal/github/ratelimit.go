@@ -1,9 +1,42 @@
 package github
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"golang.org/x/term"
+
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+// Rate limit resources, each with its own quota
+const (
+	RateLimitCore    = "core"
+	RateLimitSearch  = "search"
+	RateLimitGraphQL = "graphql"
+)
+
+const (
+	// rateLimitLowFraction is the share of a quota below which requests are
+	// spread out over the time left until the reset
+	rateLimitLowFraction = 0.1
+
+	// maxThrottleDelay caps the pause between requests while a quota is low
+	maxThrottleDelay = 2 * time.Second
+
+	// maxRateLimitRetries is how often one request is sent again after
+	// waiting out a rate limit
+	maxRateLimitRetries = 2
+
+	// rateLimitResetMargin is added to reset times, which have second precision
+	rateLimitResetMargin = 1 * time.Second
 )
 
 // RateLimitInfo contains information about GitHub API rate limits
@@ -60,3 +93,289 @@ func (r *RateLimitInfo) String() string {
 	return "rate limit: " + strconv.Itoa(r.Remaining) + "/" + strconv.Itoa(r.Limit) +
 		" (resets at " + r.Reset.Format(time.RFC3339) + ")"
 }
+
+// RateLimitGovernor tracks the quota of each rate limit resource from the
+// responses the client receives, and paces requests so they do not run into
+// the limits: requests are spread out while a quota is low, wait out a
+// secondary rate limit's Retry-After, and when a quota is used up either
+// fail with a RateLimitError or, if waiting is enabled, sleep until the reset.
+type RateLimitGovernor struct {
+	mu         sync.Mutex
+	limits     map[string]RateLimitInfo
+	retryAfter time.Time // No requests before this time (secondary rate limit)
+
+	wait        bool      // Sleep until the reset instead of failing
+	out         io.Writer // Where the countdown of a long wait is shown
+	resetMargin time.Duration
+}
+
+// NewRateLimitGovernor creates a governor that fails requests once a quota is
+// used up. Use SetWait to sleep until the reset instead.
+func NewRateLimitGovernor() *RateLimitGovernor {
+	return &RateLimitGovernor{
+		limits:      make(map[string]RateLimitInfo),
+		out:         io.Discard,
+		resetMargin: rateLimitResetMargin,
+	}
+}
+
+// SetWait makes the governor sleep until a used-up quota resets, showing a
+// countdown on out, instead of failing the request
+func (g *RateLimitGovernor) SetWait(wait bool, out io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.wait = wait
+	if out == nil {
+		out = io.Discard
+	}
+	g.out = out
+}
+
+// Limit returns the last known quota of resource
+func (g *RateLimitGovernor) Limit(resource string) (RateLimitInfo, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	info, ok := g.limits[resource]
+	return info, ok
+}
+
+// Observe records the quota reported by resp, a response to a request for
+// resource, and the Retry-After of a secondary rate limit
+func (g *RateLimitGovernor) Observe(resource string, resp *http.Response) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if name := resp.Header.Get("X-RateLimit-Resource"); name != "" {
+		resource = name
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "" {
+		g.limits[resource] = *ParseRateLimitHeaders(resp.Header)
+	}
+
+	if retryAfter := parseRetryAfter(resp); retryAfter > 0 {
+		until := time.Now().Add(retryAfter)
+		if until.After(g.retryAfter) {
+			g.retryAfter = until
+		}
+		logger.Debug().
+			Dur("retryAfter", retryAfter).
+			Msg("Hit secondary rate limit")
+	}
+}
+
+// Wait blocks until a request for resource may be sent. It returns a
+// RateLimitError when the quota is used up and waiting is disabled.
+func (g *RateLimitGovernor) Wait(ctx context.Context, resource string) error {
+	g.mu.Lock()
+	info, known := g.limits[resource]
+	retryAfter := g.retryAfter
+	wait := g.wait
+	g.mu.Unlock()
+
+	if d := time.Until(retryAfter); d > 0 {
+		if err := g.sleep(ctx, d, "GitHub asked to slow down"); err != nil {
+			return err
+		}
+	}
+
+	if !known || info.Limit == 0 {
+		return nil
+	}
+
+	untilReset := info.TimeUntilReset()
+	if untilReset <= 0 {
+		return nil
+	}
+
+	if info.Remaining <= 0 {
+		if !wait {
+			return NewRateLimitError(resource+" quota used up", info.Limit, info.Remaining, info.Reset.Unix(), nil)
+		}
+		if err := g.sleep(ctx, untilReset+g.resetMargin, fmt.Sprintf("GitHub %s rate limit reached", resource)); err != nil {
+			return err
+		}
+
+		// Assume a fresh quota until the next response reports it
+		g.mu.Lock()
+		if current, ok := g.limits[resource]; ok && current.Reset.Equal(info.Reset) {
+			current.Remaining = current.Limit
+			g.limits[resource] = current
+		}
+		g.mu.Unlock()
+		return nil
+	}
+
+	if g.low(info) {
+		delay := untilReset / time.Duration(info.Remaining+1)
+		if delay > maxThrottleDelay {
+			delay = maxThrottleDelay
+		}
+		logger.Debug().
+			Str("resource", resource).
+			Int("remaining", info.Remaining).
+			Dur("delay", delay).
+			Msg("Rate limit quota low, throttling")
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return nil
+}
+
+// Concurrency returns how many requests for resource should run at once,
+// at most max. A low quota allows one at a time.
+func (g *RateLimitGovernor) Concurrency(resource string, max int) int {
+	if g == nil {
+		return max
+	}
+
+	info, ok := g.Limit(resource)
+	if ok && info.Limit > 0 && g.low(info) && info.TimeUntilReset() > 0 {
+		return 1
+	}
+	return max
+}
+
+// low reports whether a quota is close to being used up
+func (g *RateLimitGovernor) low(info RateLimitInfo) bool {
+	return float64(info.Remaining) < float64(info.Limit)*rateLimitLowFraction
+}
+
+// sleep waits for d, showing why and for how long. On a terminal the
+// remaining time counts down every second.
+func (g *RateLimitGovernor) sleep(ctx context.Context, d time.Duration, reason string) error {
+	g.mu.Lock()
+	out := g.out
+	g.mu.Unlock()
+
+	logger.Info().Dur("wait", d).Msg(reason)
+
+	file, ok := out.(*os.File)
+	if !ok || !term.IsTerminal(int(file.Fd())) {
+		fmt.Fprintf(out, "⏳ %s, waiting %s\n", reason, d.Round(time.Second))
+
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+
+	deadline := time.Now().Add(d)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	defer fmt.Fprint(out, "\r\033[K")
+
+	for {
+		left := time.Until(deadline)
+		if left <= 0 {
+			return nil
+		}
+		fmt.Fprintf(out, "\r\033[K⏳ %s, resuming in %s", reason, left.Round(time.Second))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-time.After(left):
+		}
+	}
+}
+
+// parseRetryAfter returns the Retry-After of a rate limited response, or 0
+func parseRetryAfter(resp *http.Response) time.Duration {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0
+	}
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// rateLimitResource returns the quota a request to path counts against
+func rateLimitResource(path string) string {
+	switch {
+	case strings.HasSuffix(path, "/graphql"):
+		return RateLimitGraphQL
+	case strings.HasPrefix(path, "/search/") || strings.Contains(path, "/api/v3/search/"):
+		return RateLimitSearch
+	default:
+		return RateLimitCore
+	}
+}
+
+// rateLimitTransport passes every request through a RateLimitGovernor and
+// sends a request again after waiting out the rate limit it ran into
+type rateLimitTransport struct {
+	base     http.RoundTripper
+	governor *RateLimitGovernor
+}
+
+// newRateLimitTransport wraps base so its requests are paced by governor
+func newRateLimitTransport(base http.RoundTripper, governor *RateLimitGovernor) http.RoundTripper {
+	return &rateLimitTransport{base: base, governor: governor}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resource := rateLimitResource(req.URL.Path)
+
+	for attempt := 0; ; attempt++ {
+		if err := t.governor.Wait(req.Context(), resource); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		t.governor.Observe(resource, resp)
+
+		if attempt == maxRateLimitRetries || !t.governor.canWaitOut(resp) {
+			return resp, nil
+		}
+
+		// Send the request again once Wait lets it through
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+// canWaitOut reports whether resp was refused by a rate limit that Wait
+// will wait out before the next request
+func (g *RateLimitGovernor) canWaitOut(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	if parseRetryAfter(resp) > 0 {
+		return true
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.wait && resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
@@ -1,7 +1,13 @@
 package github
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -143,3 +149,193 @@ func TestRateLimitInfoString(t *testing.T) {
 		}
 	})
 }
+
+func rateLimitResponse(status int, headers map[string]string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: http.Header{}}
+	for k, v := range headers {
+		resp.Header.Set(k, v)
+	}
+	return resp
+}
+
+func TestRateLimitGovernor(t *testing.T) {
+	ctx := context.Background()
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	t.Run("tracks each resource separately", func(t *testing.T) {
+		g := NewRateLimitGovernor()
+		g.Observe(RateLimitCore, rateLimitResponse(200, map[string]string{
+			"X-RateLimit-Limit": "5000", "X-RateLimit-Remaining": "4000", "X-RateLimit-Reset": reset,
+		}))
+		g.Observe(RateLimitCore, rateLimitResponse(200, map[string]string{
+			"X-RateLimit-Resource": "search",
+			"X-RateLimit-Limit":    "30", "X-RateLimit-Remaining": "29", "X-RateLimit-Reset": reset,
+		}))
+
+		core, _ := g.Limit(RateLimitCore)
+		search, _ := g.Limit(RateLimitSearch)
+		if core.Remaining != 4000 || search.Remaining != 29 {
+			t.Errorf("core = %d, search = %d; want 4000 and 29", core.Remaining, search.Remaining)
+		}
+		if _, ok := g.Limit(RateLimitGraphQL); ok {
+			t.Error("expected no GraphQL quota yet")
+		}
+	})
+
+	t.Run("fails once a quota is used up", func(t *testing.T) {
+		g := NewRateLimitGovernor()
+		g.Observe(RateLimitGraphQL, rateLimitResponse(200, map[string]string{
+			"X-RateLimit-Limit": "5000", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset,
+		}))
+
+		err := g.Wait(ctx, RateLimitGraphQL)
+		var rateLimitErr *RateLimitError
+		if !errors.As(err, &rateLimitErr) {
+			t.Fatalf("Wait = %v, want a RateLimitError", err)
+		}
+		if err := g.Wait(ctx, RateLimitCore); err != nil {
+			t.Errorf("core quota is not used up: %v", err)
+		}
+	})
+
+	t.Run("sleeps until the reset when waiting", func(t *testing.T) {
+		var out bytes.Buffer
+		g := NewRateLimitGovernor()
+		g.SetWait(true, &out)
+		g.resetMargin = 0
+		g.limits[RateLimitCore] = RateLimitInfo{Limit: 5000, Remaining: 0, Reset: time.Now().Add(50 * time.Millisecond)}
+
+		start := time.Now()
+		if err := g.Wait(ctx, RateLimitCore); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+			t.Errorf("waited %s, want until the reset", elapsed)
+		}
+		if !strings.Contains(out.String(), "GitHub core rate limit reached") {
+			t.Errorf("countdown = %q", out.String())
+		}
+		if info, _ := g.Limit(RateLimitCore); info.Remaining != 5000 {
+			t.Errorf("Remaining = %d after the reset, want 5000", info.Remaining)
+		}
+	})
+
+	t.Run("gives up waiting when the context ends", func(t *testing.T) {
+		g := NewRateLimitGovernor()
+		g.SetWait(true, nil)
+		g.limits[RateLimitCore] = RateLimitInfo{Limit: 5000, Remaining: 0, Reset: time.Now().Add(time.Hour)}
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		if err := g.Wait(ctx, RateLimitCore); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Wait = %v, want deadline exceeded", err)
+		}
+	})
+
+	t.Run("low quota limits concurrency", func(t *testing.T) {
+		g := NewRateLimitGovernor()
+		if got := g.Concurrency(RateLimitCore, 5); got != 5 {
+			t.Errorf("Concurrency = %d without a known quota, want 5", got)
+		}
+
+		g.limits[RateLimitCore] = RateLimitInfo{Limit: 5000, Remaining: 100, Reset: time.Now().Add(time.Hour)}
+		if got := g.Concurrency(RateLimitCore, 5); got != 1 {
+			t.Errorf("Concurrency = %d with a low quota, want 1", got)
+		}
+
+		var nilGovernor *RateLimitGovernor
+		if got := nilGovernor.Concurrency(RateLimitCore, 5); got != 5 {
+			t.Errorf("Concurrency = %d without a governor, want 5", got)
+		}
+	})
+}
+
+func TestRateLimitResource(t *testing.T) {
+	tests := map[string]string{
+		"/graphql":                          RateLimitGraphQL,
+		"/api/graphql":                      RateLimitGraphQL,
+		"/search/issues":                    RateLimitSearch,
+		"/api/v3/search/issues":             RateLimitSearch,
+		"/repos/owner/repo/pulls":           RateLimitCore,
+		"/api/v3/repos/owner/repo/pulls/12": RateLimitCore,
+	}
+
+	for path, want := range tests {
+		if got := rateLimitResource(path); got != want {
+			t.Errorf("rateLimitResource(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	newTransport := func(t *testing.T, handler http.HandlerFunc) (*http.Client, *RateLimitGovernor, *httptest.Server) {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+		governor := NewRateLimitGovernor()
+		return &http.Client{Transport: newRateLimitTransport(http.DefaultTransport, governor)}, governor, server
+	}
+
+	t.Run("honours Retry-After of a secondary rate limit", func(t *testing.T) {
+		var bodies []string
+		client, _, server := newTransport(t, func(w http.ResponseWriter, r *http.Request) {
+			var buf bytes.Buffer
+			_, _ = buf.ReadFrom(r.Body)
+			bodies = append(bodies, buf.String())
+			if len(bodies) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		start := time.Now()
+		resp, err := client.Post(server.URL+"/repos/o/r/pulls", "application/json", strings.NewReader(`{"title":"t"}`))
+		if err != nil {
+			t.Fatalf("Post: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("status = %d, want the retried request's 201", resp.StatusCode)
+		}
+		if time.Since(start) < time.Second {
+			t.Error("expected the request to wait out Retry-After")
+		}
+		if len(bodies) != 2 || bodies[1] != `{"title":"t"}` {
+			t.Errorf("bodies = %q, want the body sent twice", bodies)
+		}
+	})
+
+	t.Run("used-up quota fails without a request", func(t *testing.T) {
+		requests := 0
+		client, governor, server := newTransport(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+		})
+
+		resp, err := client.Get(server.URL + "/repos/o/r/pulls")
+		if err != nil {
+			t.Fatalf("first request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("status = %d, want 403 without waiting", resp.StatusCode)
+		}
+
+		_, err = client.Get(server.URL + "/repos/o/r/pulls")
+		var rateLimitErr *RateLimitError
+		if !errors.As(err, &rateLimitErr) {
+			t.Errorf("second request error = %v, want a RateLimitError", err)
+		}
+		if requests != 1 {
+			t.Errorf("requests = %d, want 1", requests)
+		}
+		if info, _ := governor.Limit(RateLimitCore); info.Limit != 5000 {
+			t.Errorf("quota = %+v, want it recorded", info)
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"math"
 	"math/rand"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -137,10 +138,13 @@ func RetryWithBackoff(ctx context.Context, policy *RetryPolicy, fn RetryFunc) (*
 	return nil, fmt.Errorf("max retries exceeded with no response")
 }
 
-// CircuitBreaker tracks failures and can temporarily stop requests
+// CircuitBreaker tracks failures and can temporarily stop requests.
+// It is safe for concurrent use.
 type CircuitBreaker struct {
-	maxFailures     int
-	resetTimeout    time.Duration
+	maxFailures  int
+	resetTimeout time.Duration
+
+	mu              sync.Mutex
 	failureCount    int
 	lastFailureTime time.Time
 	state           CircuitState
@@ -169,6 +173,9 @@ func NewCircuitBreaker(maxFailures int, resetTimeout time.Duration) *CircuitBrea
 
 // Allow checks if a request should be allowed through
 func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
 	now := time.Now()
 
 	switch cb.state {
@@ -192,12 +199,18 @@ func (cb *CircuitBreaker) Allow() bool {
 
 // RecordSuccess records a successful request
 func (cb *CircuitBreaker) RecordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
 	cb.failureCount = 0
 	cb.state = CircuitClosed
 }
 
 // RecordFailure records a failed request
 func (cb *CircuitBreaker) RecordFailure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
 	cb.failureCount++
 	cb.lastFailureTime = time.Now()
 
@@ -208,5 +221,8 @@ func (cb *CircuitBreaker) RecordFailure() {
 
 // State returns the current state of the circuit breaker
 func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
 	return cb.state
 }
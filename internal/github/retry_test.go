@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 )
@@ -198,6 +199,30 @@ func TestCircuitBreaker(t *testing.T) {
 			t.Error("Expected Allow() to return true for closed circuit")
 		}
 	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		cb := NewCircuitBreaker(100, 1*time.Second)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				cb.Allow()
+				if i%2 == 0 {
+					cb.RecordFailure()
+				} else {
+					cb.RecordSuccess()
+				}
+				cb.State()
+			}(i)
+		}
+		wg.Wait()
+
+		if cb.State() != CircuitClosed {
+			t.Errorf("Expected CircuitClosed, got %v", cb.State())
+		}
+	})
 }
 
 func TestRetryWithBackoff(t *testing.T) {
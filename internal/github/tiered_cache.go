@@ -0,0 +1,427 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+const (
+	// DefaultDiskCacheMaxSize is the default size limit of the on-disk tier
+	// for one user and host
+	DefaultDiskCacheMaxSize int64 = 50 << 20
+
+	// DefaultDiskCacheMaxAge is how long an entry is kept on disk at most
+	DefaultDiskCacheMaxAge = 7 * 24 * time.Hour
+
+	// diskCacheSubdir is where HTTP responses live under the user cache directory
+	diskCacheSubdir = "gh-arc/http"
+
+	// diskCacheLockFile guards one namespace directory across processes
+	diskCacheLockFile = ".lock"
+)
+
+// TieredCacheOptions configures a TieredCache
+type TieredCacheOptions struct {
+	Dir     string        // Root directory; empty means <user cache dir>/gh-arc/http
+	Host    string        // GitHub host the responses come from
+	User    string        // Identifies the account, e.g. its token; only a hash is stored
+	MaxSize int64         // Size limit of the on-disk tier in bytes (default: 50 MiB)
+	MaxAge  time.Duration // Age limit of on-disk entries (default: 7 days)
+}
+
+// TieredCache is a Cache with an in-memory first tier in front of a
+// file-backed second tier, so cached responses and their ETags outlive a
+// single gh arc invocation.
+//
+// Entries are stored per host and per user, one JSON file per key, and the
+// directory is locked while it is read or written so concurrent gh arc
+// processes do not see partial files. An entry with an ETag is kept past its
+// TTL until it is pruned: it is only used after GitHub confirmed it with a
+// 304 Not Modified, which does not count against the rate limit.
+type TieredCache struct {
+	memory  *MemoryCache
+	dir     string
+	maxSize int64
+	maxAge  time.Duration
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+}
+
+// diskEntry is the on-disk form of a cached response
+type diskEntry struct {
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	ETag      string          `json:"etag,omitempty"`
+	StoredAt  time.Time       `json:"stored_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// expired reports whether the entry may no longer be used. Entries with an
+// ETag are revalidated before use, so only the age limit applies to them.
+func (e *diskEntry) expired(maxAge time.Duration) bool {
+	now := time.Now()
+	if now.Sub(e.StoredAt) > maxAge {
+		return true
+	}
+	return e.ETag == "" && now.After(e.ExpiresAt)
+}
+
+// NewTieredCache creates a TieredCache and prunes its on-disk tier.
+func NewTieredCache(opts TieredCacheOptions) (*TieredCache, error) {
+	root := opts.Dir
+	if root == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate user cache directory: %w", err)
+		}
+		root = filepath.Join(userCacheDir, diskCacheSubdir)
+	}
+
+	dir := filepath.Join(root, cacheNamespace(opts.Host, opts.User))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	cache := &TieredCache{
+		memory:  NewMemoryCache(1 * time.Minute),
+		dir:     dir,
+		maxSize: opts.MaxSize,
+		maxAge:  opts.MaxAge,
+	}
+	if cache.maxSize <= 0 {
+		cache.maxSize = DefaultDiskCacheMaxSize
+	}
+	if cache.maxAge <= 0 {
+		cache.maxAge = DefaultDiskCacheMaxAge
+	}
+
+	if err := cache.Prune(); err != nil {
+		logger.Debug().Err(err).Str("dir", dir).Msg("Failed to prune disk cache")
+	}
+
+	return cache, nil
+}
+
+// cacheNamespace returns the directory holding the entries of one user on
+// one host. The user is hashed so tokens never end up in paths.
+func cacheNamespace(host, user string) string {
+	if host == "" {
+		host = "github.com"
+	}
+	host = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' {
+			return '_'
+		}
+		return r
+	}, strings.ToLower(host))
+
+	sum := sha256.Sum256([]byte(user))
+	return filepath.Join(host, hex.EncodeToString(sum[:8]))
+}
+
+// Dir returns the directory of the on-disk tier.
+func (t *TieredCache) Dir() string {
+	return t.dir
+}
+
+// Stop stops the background cleanup of the in-memory tier
+func (t *TieredCache) Stop() {
+	t.memory.Stop()
+}
+
+// Get retrieves a cached value by key, from memory or else from disk.
+// Values read from disk are returned as json.RawMessage.
+func (t *TieredCache) Get(key string) (interface{}, bool) {
+	if value, found := t.memory.Get(key); found {
+		t.hits.Add(1)
+		return value, true
+	}
+
+	entry := t.read(key)
+	if entry == nil {
+		t.misses.Add(1)
+		return nil, false
+	}
+
+	if ttl := time.Until(entry.ExpiresAt); ttl > 0 {
+		t.memory.SetWithETag(key, entry.Value, entry.ETag, ttl)
+	}
+	t.hits.Add(1)
+	return entry.Value, true
+}
+
+// Set stores a value in the cache with a TTL
+func (t *TieredCache) Set(key string, value interface{}, ttl time.Duration) {
+	t.SetWithETag(key, value, "", ttl)
+}
+
+// GetETag retrieves the ETag for a cached key, from memory or else from disk
+func (t *TieredCache) GetETag(key string) (string, bool) {
+	if etag, found := t.memory.GetETag(key); found && etag != "" {
+		return etag, true
+	}
+
+	entry := t.read(key)
+	if entry == nil || entry.ETag == "" {
+		return "", false
+	}
+	return entry.ETag, true
+}
+
+// SetWithETag stores a value with its ETag in both tiers. Values that cannot
+// be encoded as JSON are only kept in memory.
+func (t *TieredCache) SetWithETag(key string, value interface{}, etag string, ttl time.Duration) {
+	t.memory.SetWithETag(key, value, etag, ttl)
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		logger.Debug().Err(err).Str("key", key).Msg("Not caching value on disk")
+		return
+	}
+
+	now := time.Now()
+	entry := diskEntry{
+		Key:       key,
+		Value:     data,
+		ETag:      etag,
+		StoredAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := t.write(&entry); err != nil {
+		logger.Debug().Err(err).Str("key", key).Msg("Failed to write disk cache entry")
+	}
+}
+
+// Delete removes a value from both tiers
+func (t *TieredCache) Delete(key string) {
+	t.memory.Delete(key)
+
+	_ = t.withLock(true, func() error {
+		err := os.Remove(t.path(key))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Debug().Err(err).Str("key", key).Msg("Failed to delete disk cache entry")
+		}
+		return nil
+	})
+}
+
+// Clear removes all values of this user and host from both tiers
+func (t *TieredCache) Clear() {
+	t.memory.Clear()
+
+	err := t.withLock(true, func() error {
+		files, err := t.entryFiles()
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			_ = os.Remove(file.path)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Debug().Err(err).Str("dir", t.dir).Msg("Failed to clear disk cache")
+	}
+
+	t.hits.Store(0)
+	t.misses.Store(0)
+	t.evictions.Store(0)
+}
+
+// Stats returns cache statistics. Size is the number of entries on disk,
+// which includes every entry held in memory.
+func (t *TieredCache) Stats() CacheStats {
+	hits := t.hits.Load()
+	misses := t.misses.Load()
+
+	size := 0
+	_ = t.withLock(false, func() error {
+		files, err := t.entryFiles()
+		size = len(files)
+		return err
+	})
+
+	var hitRate float64
+	if total := hits + misses; total > 0 {
+		hitRate = float64(hits) / float64(total) * 100
+	}
+
+	return CacheStats{
+		Hits:      hits,
+		Misses:    misses,
+		Evictions: t.evictions.Load(),
+		Size:      size,
+		HitRate:   hitRate,
+	}
+}
+
+// Prune removes on-disk entries older than the age limit, then the oldest
+// entries until the tier fits in its size limit.
+func (t *TieredCache) Prune() error {
+	return t.withLock(true, func() error {
+		files, err := t.entryFiles()
+		if err != nil {
+			return err
+		}
+
+		// Oldest first
+		sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+		var total int64
+		for _, file := range files {
+			total += file.size
+		}
+
+		cutoff := time.Now().Add(-t.maxAge)
+		removed := 0
+		for _, file := range files {
+			if !file.modTime.Before(cutoff) && total <= t.maxSize {
+				continue
+			}
+			if err := os.Remove(file.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			total -= file.size
+			removed++
+		}
+
+		if removed > 0 {
+			t.evictions.Add(int64(removed))
+			logger.Debug().
+				Int("removed", removed).
+				Int64("bytes", total).
+				Str("dir", t.dir).
+				Msg("Pruned disk cache")
+		}
+		return nil
+	})
+}
+
+// read returns the usable on-disk entry for key, or nil. Expired and
+// unreadable entries are removed.
+func (t *TieredCache) read(key string) *diskEntry {
+	path := t.path(key)
+
+	var entry *diskEntry
+	var stale bool
+	_ = t.withLock(false, func() error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		var e diskEntry
+		if err := json.Unmarshal(data, &e); err != nil || e.Key != key {
+			stale = true
+			return nil
+		}
+		if e.expired(t.maxAge) {
+			stale = true
+			return nil
+		}
+		entry = &e
+		return nil
+	})
+
+	if stale {
+		_ = t.withLock(true, func() error {
+			if err := os.Remove(path); err == nil {
+				t.evictions.Add(1)
+			}
+			return nil
+		})
+	}
+	return entry
+}
+
+// write stores entry atomically: readers see either the old or the new file.
+func (t *TieredCache) write(entry *diskEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return t.withLock(true, func() error {
+		tmp, err := os.CreateTemp(t.dir, ".entry-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+
+		if _, err := tmp.Write(data); err != nil {
+			tmp.Close()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		return os.Rename(tmp.Name(), t.path(entry.Key))
+	})
+}
+
+// withLock runs fn while holding the namespace lock, shared or exclusive.
+func (t *TieredCache) withLock(exclusive bool, fn func() error) error {
+	lock, err := os.OpenFile(filepath.Join(t.dir, diskCacheLockFile), os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open cache lock: %w", err)
+	}
+	defer lock.Close()
+
+	if err := lockFile(lock, exclusive); err != nil {
+		return fmt.Errorf("failed to lock cache: %w", err)
+	}
+	defer func() { _ = unlockFile(lock) }()
+
+	return fn()
+}
+
+// path returns the file holding the entry for key
+func (t *TieredCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(t.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// cacheFile is one entry file found on disk
+type cacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// entryFiles lists the entry files of the namespace
+func (t *TieredCache) entryFiles() ([]cacheFile, error) {
+	dirEntries, err := os.ReadDir(t.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var files []cacheFile
+	for _, de := range dirEntries {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), ".json") {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cacheFile{
+			path:    filepath.Join(t.dir, de.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+	return files, nil
+}
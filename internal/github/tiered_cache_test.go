@@ -0,0 +1,282 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func newTestTieredCache(t *testing.T, dir string) *TieredCache {
+	t.Helper()
+	cache, err := NewTieredCache(TieredCacheOptions{Dir: dir, Host: "github.com", User: "token-a"})
+	if err != nil {
+		t.Fatalf("NewTieredCache: %v", err)
+	}
+	t.Cleanup(cache.Stop)
+	return cache
+}
+
+func TestTieredCache(t *testing.T) {
+	t.Run("implements Cache", func(t *testing.T) {
+		var _ Cache = (*TieredCache)(nil)
+	})
+
+	t.Run("entries survive a new process", func(t *testing.T) {
+		dir := t.TempDir()
+		first := newTestTieredCache(t, dir)
+		first.SetWithETag("GET:repos/o/r/pulls", []string{"a", "b"}, `W/"abc"`, time.Minute)
+
+		second := newTestTieredCache(t, dir)
+		etag, found := second.GetETag("GET:repos/o/r/pulls")
+		if !found || etag != `W/"abc"` {
+			t.Fatalf("GetETag = %q, %v; want W/\"abc\", true", etag, found)
+		}
+
+		value, found := second.Get("GET:repos/o/r/pulls")
+		if !found {
+			t.Fatal("expected the entry to be read from disk")
+		}
+		var got []string
+		if err := copyResponse(value, &got); err != nil {
+			t.Fatalf("copyResponse: %v", err)
+		}
+		if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+			t.Errorf("value = %v, want [a b]", got)
+		}
+	})
+
+	t.Run("entries with an ETag outlive their TTL", func(t *testing.T) {
+		dir := t.TempDir()
+		newTestTieredCache(t, dir).SetWithETag("with-etag", "v", `"e"`, time.Nanosecond)
+		newTestTieredCache(t, dir).SetWithETag("without-etag", "v", "", time.Nanosecond)
+		time.Sleep(5 * time.Millisecond)
+
+		cache := newTestTieredCache(t, dir)
+		if _, found := cache.Get("with-etag"); !found {
+			t.Error("expected entry with ETag to be kept for revalidation")
+		}
+		if _, found := cache.Get("without-etag"); found {
+			t.Error("expected expired entry without ETag to be gone")
+		}
+		if stats := cache.Stats(); stats.Size != 1 || stats.Evictions != 1 {
+			t.Errorf("stats = %+v, want size 1 and 1 eviction", stats)
+		}
+	})
+
+	t.Run("namespaced per user and host", func(t *testing.T) {
+		dir := t.TempDir()
+		newTestTieredCache(t, dir).Set("key", "secret", time.Minute)
+
+		for _, opts := range []TieredCacheOptions{
+			{Dir: dir, Host: "github.com", User: "token-b"},
+			{Dir: dir, Host: "ghe.example.com", User: "token-a"},
+		} {
+			other, err := NewTieredCache(opts)
+			if err != nil {
+				t.Fatalf("NewTieredCache: %v", err)
+			}
+			defer other.Stop()
+			if _, found := other.Get("key"); found {
+				t.Errorf("entry leaked to %s/%s", opts.Host, opts.User)
+			}
+		}
+	})
+
+	t.Run("the user is not stored in paths", func(t *testing.T) {
+		cache := newTestTieredCache(t, t.TempDir())
+		if filepath.Base(cache.Dir()) == "token-a" {
+			t.Errorf("dir = %s, want a hash of the user", cache.Dir())
+		}
+	})
+
+	t.Run("delete and clear", func(t *testing.T) {
+		dir := t.TempDir()
+		cache := newTestTieredCache(t, dir)
+		cache.Set("a", 1, time.Minute)
+		cache.Set("b", 2, time.Minute)
+
+		cache.Delete("a")
+		if _, found := newTestTieredCache(t, dir).Get("a"); found {
+			t.Error("expected a to be deleted from disk")
+		}
+
+		cache.Clear()
+		if _, found := cache.Get("b"); found {
+			t.Error("expected b to be cleared")
+		}
+		if stats := cache.Stats(); stats.Size != 0 {
+			t.Errorf("Size = %d after Clear, want 0", stats.Size)
+		}
+	})
+
+	t.Run("unencodable values stay in memory", func(t *testing.T) {
+		dir := t.TempDir()
+		cache := newTestTieredCache(t, dir)
+		cache.Set("fn", func() {}, time.Minute)
+
+		if _, found := cache.Get("fn"); !found {
+			t.Error("expected value in memory")
+		}
+		if stats := cache.Stats(); stats.Size != 0 {
+			t.Errorf("Size = %d, want nothing on disk", stats.Size)
+		}
+	})
+
+	t.Run("concurrent writers", func(t *testing.T) {
+		dir := t.TempDir()
+		caches := []*TieredCache{newTestTieredCache(t, dir), newTestTieredCache(t, dir)}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				caches[i%2].SetWithETag("shared", map[string]int{"n": i}, `"e"`, time.Minute)
+			}(i)
+		}
+		wg.Wait()
+
+		value, found := newTestTieredCache(t, dir).Get("shared")
+		if !found {
+			t.Fatal("expected the shared entry")
+		}
+		var got map[string]int
+		if err := json.Unmarshal(value.(json.RawMessage), &got); err != nil {
+			t.Errorf("entry is corrupt: %v", err)
+		}
+	})
+}
+
+func TestTieredCachePrune(t *testing.T) {
+	t.Run("removes old entries", func(t *testing.T) {
+		dir := t.TempDir()
+		cache := newTestTieredCache(t, dir)
+		cache.SetWithETag("old", "v", `"e"`, time.Minute)
+		cache.SetWithETag("new", "v", `"e"`, time.Minute)
+
+		old := time.Now().Add(-2 * DefaultDiskCacheMaxAge)
+		if err := os.Chtimes(cache.path("old"), old, old); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := cache.Prune(); err != nil {
+			t.Fatalf("Prune: %v", err)
+		}
+		if _, err := os.Stat(cache.path("old")); !os.IsNotExist(err) {
+			t.Error("expected old entry to be pruned")
+		}
+		if _, err := os.Stat(cache.path("new")); err != nil {
+			t.Error("expected new entry to be kept")
+		}
+	})
+
+	t.Run("removes the oldest entries beyond the size limit", func(t *testing.T) {
+		dir := t.TempDir()
+		cache, err := NewTieredCache(TieredCacheOptions{Dir: dir, Host: "github.com", User: "u", MaxSize: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cache.Stop()
+
+		cache.Set("a", "v", time.Minute)
+		cache.Set("b", "v", time.Minute)
+		info, err := os.Stat(cache.path("b"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cache.maxSize = info.Size()
+
+		older := time.Now().Add(-time.Hour)
+		if err := os.Chtimes(cache.path("a"), older, older); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := cache.Prune(); err != nil {
+			t.Fatalf("Prune: %v", err)
+		}
+		if _, err := os.Stat(cache.path("a")); !os.IsNotExist(err) {
+			t.Error("expected the oldest entry to be pruned")
+		}
+		if _, err := os.Stat(cache.path("b")); err != nil {
+			t.Error("expected the newest entry to be kept")
+		}
+	})
+}
+
+func TestDoConditionalRequestAcrossClients(t *testing.T) {
+	dir := t.TempDir()
+	requests := 0
+	var gotIfNoneMatch string
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		gotIfNoneMatch = r.Header.Get("If-None-Match")
+		if gotIfNoneMatch == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"number": 7, "title": "Cached"}`))
+	})
+
+	// Two clients sharing a cache directory stand in for two invocations
+	for i := 0; i < 2; i++ {
+		client, _ := newTestClient(t, handler)
+		client.cache = newTestTieredCache(t, dir)
+
+		var pr PullRequest
+		if err := client.Do(context.Background(), "GET", "repos/owner/repo/pulls/7", nil, &pr); err != nil {
+			t.Fatalf("Do #%d: %v", i+1, err)
+		}
+		if pr.Number != 7 || pr.Title != "Cached" {
+			t.Errorf("Do #%d returned %+v", i+1, pr)
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+	if gotIfNoneMatch != `"v1"` {
+		t.Errorf("second request If-None-Match = %q, want \"v1\"", gotIfNoneMatch)
+	}
+}
+
+func TestDoErrorOnURLContaining304(t *testing.T) {
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if requests > 1 {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"number": 304, "title": "Cached"}`))
+	})
+
+	client, _ := newTestClient(t, handler)
+	cache := NewMemoryCache(time.Minute)
+	t.Cleanup(cache.Stop)
+	client.cache = cache
+
+	var pr PullRequest
+	if err := client.Do(context.Background(), "GET", "repos/owner/repo/pulls/304", nil, &pr); err != nil {
+		t.Fatalf("first Do: %v", err)
+	}
+
+	err := client.Do(context.Background(), "GET", "repos/owner/repo/pulls/304", nil, &pr)
+	var httpErr *api.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Errorf("second Do error = %v, want the 404 rather than the cached response", err)
+	}
+}
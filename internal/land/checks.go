@@ -41,6 +41,7 @@ type CheckResult struct {
 	Passed            bool
 	Messages          []string
 	NeedsConfirmation bool
+	Pending           bool // Not passed yet, but nothing has failed — waiting may help
 }
 
 // PreMergeChecker runs pre-merge validations against a PR.
@@ -174,6 +175,7 @@ func (c *PreMergeChecker) CheckCI(ctx context.Context, pr *github.PullRequest, f
 		return &CheckResult{
 			Passed:   false,
 			Messages: []string{msg + " — use --force to bypass"},
+			Pending:  true,
 		}, nil
 	}
 
@@ -205,7 +207,7 @@ func (c *PreMergeChecker) CheckCI(ctx context.Context, pr *github.PullRequest, f
 	if force {
 		return &CheckResult{Passed: true, Messages: []string{msg + " (bypassed with --force)"}}, nil
 	}
-	return &CheckResult{Passed: false, Messages: []string{msg}}, nil
+	return &CheckResult{Passed: false, Messages: []string{msg}, Pending: len(failed) == 0}, nil
 }
 
 // resolveRelevantChecks returns the checks to evaluate, or nil when there are
@@ -411,6 +411,46 @@ func TestCheckCI(t *testing.T) {
 		assertMessageContains(t, result, "'tests' failed")
 		assertMessageContains(t, result, "'lint' in progress")
 		assertMessageContains(t, result, "(1/3 passed)")
+		if result.Pending {
+			t.Error("a failed check should not be pending")
+		}
+	})
+
+	t.Run("all mode/in-progress checks are pending", func(t *testing.T) {
+		cfg := defaultLandConfig()
+		cfg.RequireCI = config.CIModeAll
+		checker := newChecker(nil, &mockCheckerClient{}, cfg)
+		pr := &github.PullRequest{
+			Base: github.PRBranch{Ref: "main"},
+			Checks: []github.PRCheck{
+				{Name: "tests", Status: "in_progress"},
+				{Name: "build", Status: "completed", Conclusion: "success"},
+			},
+		}
+		result, err := checker.CheckCI(ctx, pr, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Passed {
+			t.Error("expected not passed")
+		}
+		if !result.Pending {
+			t.Error("expected pending")
+		}
+		assertMessageContains(t, result, "'tests' in progress")
+	})
+
+	t.Run("all mode/no checks yet are pending", func(t *testing.T) {
+		cfg := defaultLandConfig()
+		cfg.RequireCI = config.CIModeAll
+		checker := newChecker(nil, &mockCheckerClient{}, cfg)
+		result, err := checker.CheckCI(ctx, noChecksPR, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Passed || !result.Pending {
+			t.Errorf("expected pending, got passed=%v pending=%v", result.Passed, result.Pending)
+		}
 	})
 
 	t.Run("all mode/force bypasses", func(t *testing.T) {
@@ -0,0 +1,176 @@
+package land
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+// DependentsRepo defines git operations needed to move dependent PRs onto
+// the default branch after their parent landed.
+type DependentsRepo interface {
+	BranchExists(branchName string) (bool, error)
+	IsAncestor(ancestorRef, descendantRef string) (bool, error)
+	GetMergeBase(ref1, ref2 string) (string, error)
+	RebaseOnto(newBase, upstream, branch string) error
+	AbortRebase() error
+	CheckoutBranch(branch string) error
+	Push(ctx context.Context, branchName string) error
+	GetBranchSHA(branch string) (string, error)
+	SetBranchParent(branch, parent, parentSHA string) error
+}
+
+// DependentsClient defines GitHub operations needed to retarget dependent PRs.
+type DependentsClient interface {
+	UpdatePRBaseForCurrentRepo(ctx context.Context, number int, newBase string) error
+}
+
+// DependentResult holds the outcome of moving one dependent PR.
+type DependentResult struct {
+	PR         *github.PullRequest
+	Retargeted bool
+	Rebased    bool
+	Pushed     bool
+	Warning    string
+}
+
+// DependentsResult holds the outcome of moving all dependent PRs.
+type DependentsResult struct {
+	Dependents []DependentResult
+	Warnings   []string
+}
+
+// DependentRetargeter moves the direct dependents of a landed PR onto the
+// default branch: their base is retargeted, and their local branches are
+// rebased onto the updated default branch, dropping the landed commits.
+type DependentRetargeter struct {
+	repo     DependentsRepo
+	client   DependentsClient
+	fullName string
+}
+
+// NewDependentRetargeter creates a new DependentRetargeter.
+func NewDependentRetargeter(repo DependentsRepo, client DependentsClient, owner, name string) *DependentRetargeter {
+	return &DependentRetargeter{repo: repo, client: client, fullName: owner + "/" + name}
+}
+
+// Execute retargets and rebases each dependent of landed. With rebase false
+// (the default branch could not be updated) dependents are only retargeted.
+// Failures are captured as warnings — the merge already succeeded.
+func (d *DependentRetargeter) Execute(ctx context.Context, landed *github.PullRequest, dependents []*github.PullRequest, defaultBranch string, rebase bool) *DependentsResult {
+	results := make([]DependentResult, 0, len(dependents))
+	out := &DependentsResult{}
+	rebasedAny := false
+
+	for _, dep := range dependents {
+		result := DependentResult{PR: dep}
+
+		if dep.Base.Ref != defaultBranch {
+			if err := d.client.UpdatePRBaseForCurrentRepo(ctx, dep.Number, defaultBranch); err != nil {
+				result.Warning = fmt.Sprintf("Failed to retarget PR #%d to %s: %v — run 'gh pr edit %d --base %s' manually",
+					dep.Number, defaultBranch, err, dep.Number, defaultBranch)
+				results = append(results, result)
+				continue
+			}
+		}
+		result.Retargeted = true
+
+		if !rebase {
+			result.Warning = fmt.Sprintf("%s was not rebased because %s could not be updated — %s",
+				dep.Head.Ref, defaultBranch, manualRebaseHint(dep.Head.Ref, defaultBranch, landed.Head.SHA))
+			results = append(results, result)
+			continue
+		}
+
+		if dep.Head.Repo.FullName != "" && !strings.EqualFold(dep.Head.Repo.FullName, d.fullName) {
+			result.Warning = fmt.Sprintf("PR #%d comes from fork %s — its author needs to rebase it onto %s",
+				dep.Number, dep.Head.Repo.FullName, defaultBranch)
+			results = append(results, result)
+			continue
+		}
+
+		exists, err := d.repo.BranchExists("refs/heads/" + dep.Head.Ref)
+		if err != nil || !exists {
+			result.Warning = fmt.Sprintf("%s is not checked out locally — %s",
+				dep.Head.Ref, manualRebaseHint(dep.Head.Ref, defaultBranch, landed.Head.SHA))
+			results = append(results, result)
+			continue
+		}
+
+		rebasedAny = true
+		if warning := d.rebase(landed, dep.Head.Ref, defaultBranch); warning != "" {
+			result.Warning = warning
+			results = append(results, result)
+			continue
+		}
+		result.Rebased = true
+		d.recordParent(dep.Head.Ref, defaultBranch)
+
+		if err := d.repo.Push(ctx, dep.Head.Ref); err != nil {
+			result.Warning = fmt.Sprintf("Failed to push %s: %v — run 'git push --force-with-lease origin %s' manually",
+				dep.Head.Ref, err, dep.Head.Ref)
+		} else {
+			result.Pushed = true
+		}
+		results = append(results, result)
+	}
+
+	if rebasedAny {
+		if err := d.repo.CheckoutBranch(defaultBranch); err != nil {
+			out.Warnings = append(out.Warnings,
+				fmt.Sprintf("Failed to checkout %s: %v — run 'git checkout %s' manually", defaultBranch, err, defaultBranch))
+		}
+	}
+
+	out.Dependents = results
+	return out
+}
+
+// rebase replays only the commits of branch that are not in the landed PR
+// onto the default branch. A conflicting rebase is aborted. It returns a
+// warning on failure.
+func (d *DependentRetargeter) rebase(landed *github.PullRequest, branch, defaultBranch string) string {
+	upstream := landed.Head.SHA
+	if ok, err := d.repo.IsAncestor(upstream, branch); err != nil || !ok {
+		// The branch was built on an older version of the landed PR
+		mergeBase, err := d.repo.GetMergeBase(branch, landed.Head.SHA)
+		if err != nil {
+			return fmt.Sprintf("Failed to find where %s forked from the landed PR: %v — %s",
+				branch, err, manualRebaseHint(branch, defaultBranch, landed.Head.SHA))
+		}
+		upstream = mergeBase
+	}
+
+	if err := d.repo.RebaseOnto(defaultBranch, upstream, branch); err != nil {
+		if errors.Is(err, git.ErrRebaseConflict) {
+			if abortErr := d.repo.AbortRebase(); abortErr != nil {
+				return fmt.Sprintf("Rebasing %s stopped on conflicts and could not be aborted: %v — run 'git rebase --abort' manually",
+					branch, abortErr)
+			}
+			return fmt.Sprintf("Rebasing %s onto %s stopped on conflicts — %s",
+				branch, defaultBranch, manualRebaseHint(branch, defaultBranch, upstream))
+		}
+		return fmt.Sprintf("Failed to rebase %s: %v — %s", branch, err, manualRebaseHint(branch, defaultBranch, upstream))
+	}
+
+	return ""
+}
+
+// recordParent points the stored stack parent of a rebased dependent at the
+// default branch. Best effort: without it, base detection and restack fall
+// back to their heuristics.
+func (d *DependentRetargeter) recordParent(branch, defaultBranch string) {
+	sha, err := d.repo.GetBranchSHA(defaultBranch)
+	if err != nil {
+		sha = ""
+	}
+	_ = d.repo.SetBranchParent(branch, defaultBranch, sha)
+}
+
+func manualRebaseHint(branch, defaultBranch, upstream string) string {
+	return fmt.Sprintf("run 'git rebase --onto %s %s %s' manually", defaultBranch, truncateSHA(upstream), branch)
+}
@@ -0,0 +1,221 @@
+package land
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+type mockDependentsRepo struct {
+	local       map[string]bool
+	ancestor    bool
+	rebaseErr   error
+	pushErr     error
+	checkoutErr error
+	rebased     []string
+	aborted     bool
+	pushed      []string
+	checkouts   []string
+	parents     map[string]string
+}
+
+func (m *mockDependentsRepo) BranchExists(branchName string) (bool, error) {
+	return m.local[strings.TrimPrefix(branchName, "refs/heads/")], nil
+}
+
+func (m *mockDependentsRepo) IsAncestor(_, _ string) (bool, error) {
+	return m.ancestor, nil
+}
+
+func (m *mockDependentsRepo) GetMergeBase(_, _ string) (string, error) {
+	return "forkpoint", nil
+}
+
+func (m *mockDependentsRepo) RebaseOnto(newBase, upstream, branch string) error {
+	m.rebased = append(m.rebased, fmt.Sprintf("%s onto %s from %s", branch, newBase, upstream))
+	return m.rebaseErr
+}
+
+func (m *mockDependentsRepo) AbortRebase() error {
+	m.aborted = true
+	return nil
+}
+
+func (m *mockDependentsRepo) CheckoutBranch(branch string) error {
+	m.checkouts = append(m.checkouts, branch)
+	return m.checkoutErr
+}
+
+func (m *mockDependentsRepo) Push(_ context.Context, branchName string) error {
+	if m.pushErr != nil {
+		return m.pushErr
+	}
+	m.pushed = append(m.pushed, branchName)
+	return nil
+}
+
+func (m *mockDependentsRepo) GetBranchSHA(branch string) (string, error) {
+	return branch + "-sha", nil
+}
+
+func (m *mockDependentsRepo) SetBranchParent(branch, parent, parentSHA string) error {
+	if m.parents == nil {
+		m.parents = map[string]string{}
+	}
+	m.parents[branch] = parent + "@" + parentSHA
+	return nil
+}
+
+type mockDependentsClient struct {
+	err        error
+	retargeted []string
+}
+
+func (m *mockDependentsClient) UpdatePRBaseForCurrentRepo(_ context.Context, number int, newBase string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.retargeted = append(m.retargeted, fmt.Sprintf("#%d→%s", number, newBase))
+	return nil
+}
+
+func landedPR() *github.PullRequest {
+	return &github.PullRequest{
+		Number: 42,
+		Head:   github.PRBranch{Ref: "feature/auth", SHA: "abc1234def5678"},
+		Base:   github.PRBranch{Ref: "main"},
+	}
+}
+
+func dependentPR(number int, branch string) *github.PullRequest {
+	return &github.PullRequest{
+		Number: number,
+		Head:   github.PRBranch{Ref: branch, Repo: github.PRRepository{FullName: "owner/repo"}},
+		Base:   github.PRBranch{Ref: "feature/auth"},
+	}
+}
+
+func TestDependentRetargeter_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retargets, rebases and pushes", func(t *testing.T) {
+		repo := &mockDependentsRepo{local: map[string]bool{"child": true}, ancestor: true}
+		client := &mockDependentsClient{}
+
+		result := NewDependentRetargeter(repo, client, "owner", "repo").
+			Execute(ctx, landedPR(), []*github.PullRequest{dependentPR(43, "child")}, "main", true)
+
+		if len(result.Dependents) != 1 {
+			t.Fatalf("expected 1 result, got %d", len(result.Dependents))
+		}
+		dep := result.Dependents[0]
+		if !dep.Retargeted || !dep.Rebased || !dep.Pushed || dep.Warning != "" {
+			t.Errorf("unexpected result: %+v", dep)
+		}
+		if client.retargeted[0] != "#43→main" {
+			t.Errorf("unexpected retarget: %v", client.retargeted)
+		}
+		if repo.rebased[0] != "child onto main from abc1234def5678" {
+			t.Errorf("unexpected rebase: %v", repo.rebased)
+		}
+		if len(repo.checkouts) != 1 || repo.checkouts[0] != "main" {
+			t.Errorf("expected to return to main, got %v", repo.checkouts)
+		}
+		if repo.parents["child"] != "main@main-sha" {
+			t.Errorf("expected child's recorded parent to move to main, got %q", repo.parents["child"])
+		}
+	})
+
+	t.Run("uses merge-base when built on an older parent", func(t *testing.T) {
+		repo := &mockDependentsRepo{local: map[string]bool{"child": true}}
+
+		NewDependentRetargeter(repo, &mockDependentsClient{}, "owner", "repo").
+			Execute(ctx, landedPR(), []*github.PullRequest{dependentPR(43, "child")}, "main", true)
+
+		if repo.rebased[0] != "child onto main from forkpoint" {
+			t.Errorf("unexpected rebase: %v", repo.rebased)
+		}
+	})
+
+	t.Run("aborts on conflict", func(t *testing.T) {
+		repo := &mockDependentsRepo{
+			local:     map[string]bool{"child": true},
+			ancestor:  true,
+			rebaseErr: fmt.Errorf("failed: %w", git.ErrRebaseConflict),
+		}
+
+		result := NewDependentRetargeter(repo, &mockDependentsClient{}, "owner", "repo").
+			Execute(ctx, landedPR(), []*github.PullRequest{dependentPR(43, "child")}, "main", true)
+
+		dep := result.Dependents[0]
+		if !repo.aborted {
+			t.Error("expected rebase to be aborted")
+		}
+		if !dep.Retargeted || dep.Rebased || dep.Pushed {
+			t.Errorf("unexpected result: %+v", dep)
+		}
+		if !strings.Contains(dep.Warning, "stopped on conflicts") || !strings.Contains(dep.Warning, "git rebase --onto main abc1234 child") {
+			t.Errorf("unexpected warning: %s", dep.Warning)
+		}
+	})
+
+	t.Run("retarget failure skips rebase", func(t *testing.T) {
+		repo := &mockDependentsRepo{local: map[string]bool{"child": true}, ancestor: true}
+		client := &mockDependentsClient{err: errors.New("422")}
+
+		result := NewDependentRetargeter(repo, client, "owner", "repo").
+			Execute(ctx, landedPR(), []*github.PullRequest{dependentPR(43, "child")}, "main", true)
+
+		dep := result.Dependents[0]
+		if dep.Retargeted || len(repo.rebased) != 0 {
+			t.Errorf("expected nothing to happen after failed retarget: %+v", dep)
+		}
+		if !strings.Contains(dep.Warning, "gh pr edit 43 --base main") {
+			t.Errorf("unexpected warning: %s", dep.Warning)
+		}
+	})
+
+	t.Run("fork and remote-only branches are only retargeted", func(t *testing.T) {
+		repo := &mockDependentsRepo{local: map[string]bool{"forked": true}, ancestor: true}
+		fork := dependentPR(44, "forked")
+		fork.Head.Repo.FullName = "someone/repo"
+
+		result := NewDependentRetargeter(repo, &mockDependentsClient{}, "owner", "repo").
+			Execute(ctx, landedPR(), []*github.PullRequest{fork, dependentPR(45, "remote")}, "main", true)
+
+		if len(repo.rebased) != 0 || len(repo.checkouts) != 0 {
+			t.Errorf("expected no local changes, got rebases %v checkouts %v", repo.rebased, repo.checkouts)
+		}
+		if !strings.Contains(result.Dependents[0].Warning, "fork someone/repo") {
+			t.Errorf("unexpected fork warning: %s", result.Dependents[0].Warning)
+		}
+		if !strings.Contains(result.Dependents[1].Warning, "not checked out locally") {
+			t.Errorf("unexpected remote-only warning: %s", result.Dependents[1].Warning)
+		}
+	})
+
+	t.Run("push and checkout failures are warnings", func(t *testing.T) {
+		repo := &mockDependentsRepo{
+			local:       map[string]bool{"child": true},
+			ancestor:    true,
+			pushErr:     errors.New("rejected"),
+			checkoutErr: errors.New("locked"),
+		}
+
+		result := NewDependentRetargeter(repo, &mockDependentsClient{}, "owner", "repo").
+			Execute(ctx, landedPR(), []*github.PullRequest{dependentPR(43, "child")}, "main", true)
+
+		dep := result.Dependents[0]
+		if !dep.Rebased || dep.Pushed || !strings.Contains(dep.Warning, "Failed to push child") {
+			t.Errorf("unexpected result: %+v", dep)
+		}
+		if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "Failed to checkout main") {
+			t.Errorf("unexpected warnings: %v", result.Warnings)
+		}
+	})
+}
@@ -19,6 +19,7 @@ type LandResult struct {
 	DeletedBranch    string
 	DeletedBranchSHA string
 	DependentPRCount int
+	Dependents       []DependentResult
 	CleanupWarnings  []string
 	Messages         []string
 }
@@ -116,6 +117,24 @@ func (o *OutputStyle) PrintDependentPRs(count int) {
 	}
 }
 
+// PrintDependentResult prints what happened to one dependent PR after its
+// parent landed.
+func (o *OutputStyle) PrintDependentResult(result DependentResult, defaultBranch string) {
+	if result.Retargeted {
+		message := fmt.Sprintf("Retargeted PR #%d to %s", result.PR.Number, defaultBranch)
+		if result.Rebased {
+			message += fmt.Sprintf(", rebased %s", result.PR.Head.Ref)
+			if result.Pushed {
+				message += " and pushed"
+			}
+		}
+		o.PrintStep("✓", message)
+	}
+	if result.Warning != "" {
+		o.PrintStep("⚠", result.Warning)
+	}
+}
+
 // PrintMerged prints the successful merge step.
 func (o *OutputStyle) PrintMerged(method, baseBranch, sha string) {
 	shortSHA := truncateSHA(sha)
@@ -148,6 +167,45 @@ func (o *OutputStyle) PrintCleanupWarning(message string) {
 	o.PrintStep("⚠", message)
 }
 
+// PrintStackPlan prints the PRs land --stack is about to merge, bottom first.
+func (o *OutputStyle) PrintStackPlan(chain []*github.PullRequest, defaultBranch string) {
+	numbers := make([]string, len(chain))
+	for i, pr := range chain {
+		numbers[i] = fmt.Sprintf("#%d", pr.Number)
+	}
+	noun := "PR"
+	if len(chain) > 1 {
+		noun = "PRs"
+	}
+	o.PrintStep("✓", fmt.Sprintf("Landing %d %s into %s, bottom first: %s",
+		len(chain), noun, defaultBranch, strings.Join(numbers, " → ")))
+}
+
+// PrintStackPosition prints a header before the checks of one PR in a stack.
+func (o *OutputStyle) PrintStackPosition(position, total int, pr *github.PullRequest) {
+	fmt.Fprintf(o.writer, "\n[%d/%d] PR #%d: %q (%s)\n", position, total, pr.Number, pr.Title, pr.Head.Ref)
+}
+
+// PrintStackSummary prints what land --stack merged and, if it stopped
+// early, why and which PRs are left.
+func (o *OutputStyle) PrintStackSummary(result *StackLandResult) {
+	fmt.Fprintln(o.writer)
+	o.PrintStep("✓", fmt.Sprintf("Landed %d of %d PRs into %s",
+		len(result.Landed), len(result.Landed)+len(result.Remaining), result.DefaultBranch))
+	for _, landed := range result.Landed {
+		o.PrintDetail(fmt.Sprintf("#%d %s (%s)", landed.PR.Number, landed.PR.Title, truncateSHA(landed.MergeCommitSHA)))
+	}
+
+	if len(result.Remaining) == 0 {
+		return
+	}
+	o.PrintStep("✗", fmt.Sprintf("Stopped at PR #%d: %s", result.Remaining[0].Number, result.StopReason))
+	for _, pr := range result.Remaining {
+		o.PrintDetail(fmt.Sprintf("#%d %s (%s)", pr.Number, pr.Title, pr.Head.Ref))
+	}
+	o.PrintDetail(fmt.Sprintf("Fix the problem, check out %s and run 'gh arc land --stack' again", result.TopBranch))
+}
+
 // FormatLandResult formats a compact final summary from the land result.
 // The detailed step-by-step output is printed in real-time by Print* methods
 // during workflow execution; this produces a summary for the end of output.
@@ -172,7 +230,24 @@ func FormatLandResult(result *LandResult, style *OutputStyle) string {
 				result.DeletedBranch, result.DeletedBranch, shortSHA)))
 	}
 
-	if result.DependentPRCount > 0 {
+	if len(result.Dependents) > 0 {
+		retargeted := 0
+		for _, dep := range result.Dependents {
+			if dep.Retargeted {
+				retargeted++
+			}
+		}
+		noun := "PR"
+		if len(result.Dependents) > 1 {
+			noun = "PRs"
+		}
+		icon := "✓"
+		if retargeted < len(result.Dependents) {
+			icon = "⚠"
+		}
+		lines = append(lines, style.formatWithIcon(icon,
+			fmt.Sprintf("%d of %d dependent %s retargeted to %s", retargeted, len(result.Dependents), noun, result.DefaultBranch)))
+	} else if result.DependentPRCount > 0 {
 		noun := "PR"
 		if result.DependentPRCount > 1 {
 			noun = "PRs"
@@ -239,9 +239,107 @@ func TestPrintCleanupWarning(t *testing.T) {
 	}
 }
 
+func TestPrintDependentResult(t *testing.T) {
+	pr := &github.PullRequest{Number: 43, Head: github.PRBranch{Ref: "feature/child"}}
+
+	t.Run("rebased and pushed", func(t *testing.T) {
+		style, buf := newTestStyle()
+		style.PrintDependentResult(DependentResult{PR: pr, Retargeted: true, Rebased: true, Pushed: true}, "main")
+		if got := buf.String(); got != "✓ Retargeted PR #43 to main, rebased feature/child and pushed\n" {
+			t.Errorf("PrintDependentResult() = %q", got)
+		}
+	})
+
+	t.Run("retargeted with warning", func(t *testing.T) {
+		style, buf := newTestStyle()
+		style.PrintDependentResult(DependentResult{PR: pr, Retargeted: true, Warning: "not rebased"}, "main")
+		if got := buf.String(); got != "✓ Retargeted PR #43 to main\n⚠ not rebased\n" {
+			t.Errorf("PrintDependentResult() = %q", got)
+		}
+	})
+
+	t.Run("retarget failed", func(t *testing.T) {
+		style, buf := newTestStyle()
+		style.PrintDependentResult(DependentResult{PR: pr, Warning: "Failed to retarget"}, "main")
+		if got := buf.String(); got != "⚠ Failed to retarget\n" {
+			t.Errorf("PrintDependentResult() = %q", got)
+		}
+	})
+}
+
+func TestPrintStackPlan(t *testing.T) {
+	style, buf := newTestStyle()
+	style.PrintStackPlan([]*github.PullRequest{{Number: 1}, {Number: 2}}, "main")
+	if got := buf.String(); got != "✓ Landing 2 PRs into main, bottom first: #1 → #2\n" {
+		t.Errorf("PrintStackPlan() = %q", got)
+	}
+}
+
+func TestPrintStackSummary(t *testing.T) {
+	landed := &LandResult{
+		PR:             &github.PullRequest{Number: 1, Title: "Add model"},
+		MergeCommitSHA: "abc1234def",
+	}
+	remaining := []*github.PullRequest{
+		{Number: 2, Title: "Add API", Head: github.PRBranch{Ref: "feature/api"}},
+		{Number: 3, Title: "Add UI", Head: github.PRBranch{Ref: "feature/ui"}},
+	}
+
+	t.Run("complete", func(t *testing.T) {
+		style, buf := newTestStyle()
+		style.PrintStackSummary(&StackLandResult{DefaultBranch: "main", TopBranch: "feature/model", Landed: []*LandResult{landed}})
+		want := "\n✓ Landed 1 of 1 PRs into main\n  #1 Add model (abc1234)\n"
+		if got := buf.String(); got != want {
+			t.Errorf("PrintStackSummary() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		style, buf := newTestStyle()
+		style.PrintStackSummary(&StackLandResult{
+			DefaultBranch: "main",
+			TopBranch:     "feature/ui",
+			Landed:        []*LandResult{landed},
+			Remaining:     remaining,
+			StopReason:    "CI check failed",
+		})
+		want := "\n✓ Landed 1 of 3 PRs into main\n" +
+			"  #1 Add model (abc1234)\n" +
+			"✗ Stopped at PR #2: CI check failed\n" +
+			"  #2 Add API (feature/api)\n" +
+			"  #3 Add UI (feature/ui)\n" +
+			"  Fix the problem, check out feature/ui and run 'gh arc land --stack' again\n"
+		if got := buf.String(); got != want {
+			t.Errorf("PrintStackSummary() = %q, want %q", got, want)
+		}
+	})
+}
+
 func TestFormatLandResult(t *testing.T) {
 	style := NewOutputStyle(false)
 
+	t.Run("retargeted dependents", func(t *testing.T) {
+		result := &LandResult{
+			PR:               &github.PullRequest{Number: 42},
+			MergeMethod:      "squash",
+			MergeCommitSHA:   "abc1234",
+			DefaultBranch:    "main",
+			DependentPRCount: 2,
+			Dependents: []DependentResult{
+				{Retargeted: true, Rebased: true, Pushed: true},
+				{Warning: "Failed to retarget"},
+			},
+		}
+
+		output := FormatLandResult(result, style)
+		if !strings.Contains(output, "⚠ 1 of 2 dependent PRs retargeted to main") {
+			t.Errorf("FormatLandResult() missing retarget summary in:\n%s", output)
+		}
+		if strings.Contains(output, "may be retargeted") {
+			t.Errorf("FormatLandResult() should not guess when dependents were handled:\n%s", output)
+		}
+	})
+
 	t.Run("full result", func(t *testing.T) {
 		result := &LandResult{
 			PR: &github.PullRequest{
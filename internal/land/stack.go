@@ -0,0 +1,180 @@
+package land
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+var (
+	ErrCITimeout       = errors.New("timed out waiting for CI")
+	ErrStackNotRebased = errors.New("next PR in the stack was not rebased onto the default branch")
+	ErrStackCycle      = errors.New("stack of pull requests loops back on itself")
+)
+
+const (
+	// stackPollInterval is how often land --stack re-fetches a PR while
+	// waiting for GitHub to pick up a push and for CI to finish.
+	stackPollInterval = 15 * time.Second
+
+	defaultStackCITimeout = 30 * time.Minute
+)
+
+// StackLandResult represents the outcome of landing a stack of PRs.
+// When landing stops early, Remaining starts with the PR that failed.
+type StackLandResult struct {
+	DefaultBranch string
+	TopBranch     string
+	Landed        []*LandResult
+	Remaining     []*github.PullRequest
+	StopReason    string
+}
+
+// ExecuteStack lands the current branch's PR together with every PR below it
+// in the stack, bottom-up. Each PR goes through the same gates as a single
+// land; after it merges, the next PR is retargeted and rebased onto the
+// default branch, and its restarted CI is awaited before it lands in turn.
+//
+// Landing stops at the first failure. The partial result is returned along
+// with the error so the caller knows what was landed and what is left.
+func (w *LandWorkflow) ExecuteStack(ctx context.Context, opts *LandOptions) (*StackLandResult, error) {
+	if opts == nil {
+		opts = &LandOptions{}
+	}
+	// Rebasing the next PR onto the default branch is what makes it landable
+	landOpts := *opts
+	landOpts.NoRetarget = false
+
+	if err := w.checker.CheckCleanWorkingDir(); err != nil {
+		w.output.PrintStep("✗", "Working directory has uncommitted changes")
+		w.output.PrintDetail("Commit or stash your changes before landing")
+		return nil, err
+	}
+
+	currentBranch, err := w.repo.GetCurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	defaultBranch, err := w.repo.GetDefaultBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get default branch: %w", err)
+	}
+
+	if err := w.checker.CheckNotOnTrunk(currentBranch, defaultBranch); err != nil {
+		w.output.PrintStep("✗", fmt.Sprintf("Cannot land from %s — already on default branch", currentBranch))
+		return nil, err
+	}
+
+	chain, err := w.stackChain(ctx, currentBranch, defaultBranch)
+	if err != nil {
+		w.output.PrintStep("✗", fmt.Sprintf("Could not resolve the stack below %s", currentBranch))
+		w.output.PrintDetail(err.Error())
+		return nil, err
+	}
+	w.output.PrintStackPlan(chain, defaultBranch)
+
+	result := &StackLandResult{DefaultBranch: defaultBranch, TopBranch: currentBranch}
+	timeout := w.stackCITimeout()
+
+	for i, pr := range chain {
+		branch := pr.Head.Ref
+		w.output.PrintStackPosition(i+1, len(chain), pr)
+
+		if err := w.repo.CheckoutBranch(branch); err != nil {
+			return w.stopStack(result, chain[i:], fmt.Errorf("failed to checkout %s: %w", branch, err))
+		}
+
+		// The PR was retargeted and force-pushed when the one below it landed
+		if i > 0 {
+			if pr, err = w.checker.CheckPRExists(ctx, branch); err != nil {
+				return w.stopStack(result, chain[i:], err)
+			}
+		}
+
+		wait := &gateWait{deadline: w.now().Add(timeout), push: i > 0}
+		if pr, err = w.runGates(ctx, branch, pr, &landOpts, wait); err != nil {
+			return w.stopStack(result, chain[i:], err)
+		}
+
+		landed, err := w.mergeAndCleanup(ctx, pr, branch, defaultBranch, &landOpts)
+		if err != nil {
+			return w.stopStack(result, chain[i:], err)
+		}
+		result.Landed = append(result.Landed, landed)
+
+		if i+1 < len(chain) {
+			if err := nextInStackReady(landed, chain[i+1]); err != nil {
+				return w.stopStack(result, chain[i+1:], err)
+			}
+		}
+	}
+
+	w.output.PrintStackSummary(result)
+	return result, nil
+}
+
+// stackChain walks PR bases down from branch to the default branch and
+// returns the PRs bottom-first.
+func (w *LandWorkflow) stackChain(ctx context.Context, branch, defaultBranch string) ([]*github.PullRequest, error) {
+	var chain []*github.PullRequest
+	seen := map[string]bool{}
+
+	for {
+		pr, err := w.checker.CheckPRExists(ctx, branch)
+		if err != nil {
+			if len(chain) > 0 && errors.Is(err, ErrNoPRFound) {
+				return nil, fmt.Errorf("%w: PR #%d targets %s, which has no open pull request",
+					ErrNoPRFound, chain[0].Number, branch)
+			}
+			return nil, err
+		}
+		chain = append([]*github.PullRequest{pr}, chain...)
+		seen[branch] = true
+
+		if pr.Base.Ref == defaultBranch {
+			return chain, nil
+		}
+		if seen[pr.Base.Ref] {
+			return nil, fmt.Errorf("%w: PR #%d targets %s", ErrStackCycle, pr.Number, pr.Base.Ref)
+		}
+		branch = pr.Base.Ref
+	}
+}
+
+// nextInStackReady verifies that landing a PR left the next one retargeted,
+// rebased and pushed, which is what its CI needs to restart on.
+func nextInStackReady(landed *LandResult, next *github.PullRequest) error {
+	for _, dep := range landed.Dependents {
+		if dep.PR.Number != next.Number {
+			continue
+		}
+		if dep.Retargeted && dep.Rebased && dep.Pushed {
+			return nil
+		}
+		if dep.Warning != "" {
+			return fmt.Errorf("%w: %s", ErrStackNotRebased, dep.Warning)
+		}
+		return fmt.Errorf("%w: PR #%d", ErrStackNotRebased, next.Number)
+	}
+	return fmt.Errorf("%w: PR #%d no longer targets %s", ErrStackNotRebased, next.Number, landed.PR.Head.Ref)
+}
+
+func (w *LandWorkflow) stopStack(result *StackLandResult, remaining []*github.PullRequest, err error) (*StackLandResult, error) {
+	result.Remaining = remaining
+	result.StopReason = err.Error()
+	w.output.PrintStackSummary(result)
+	return result, err
+}
+
+// stackCITimeout returns land.stackCITimeout, which Validate has already
+// checked, falling back to the default when it is unset.
+func (w *LandWorkflow) stackCITimeout() time.Duration {
+	if d, err := time.ParseDuration(w.config.Land.StackCITimeout); err == nil && d > 0 {
+		return d
+	}
+	return defaultStackCITimeout
+}
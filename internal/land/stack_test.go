@@ -0,0 +1,246 @@
+package land
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+func stackPR(number int, branch, base, sha string, checks ...github.PRCheck) *github.PullRequest {
+	return &github.PullRequest{
+		Number: number,
+		Title:  "Part " + branch,
+		Head:   github.PRBranch{Ref: branch, SHA: sha},
+		Base:   github.PRBranch{Ref: base},
+		Reviews: []github.PRReview{
+			{User: github.PRUser{Login: "alice"}, State: "APPROVED"},
+		},
+		Checks: checks,
+	}
+}
+
+var (
+	ciPassed  = github.PRCheck{Name: "tests", Status: "completed", Conclusion: "success"}
+	ciRunning = github.PRCheck{Name: "tests", Status: "in_progress"}
+	ciFailed  = github.PRCheck{Name: "tests", Status: "completed", Conclusion: "failure"}
+)
+
+// stackFixture builds a three-PR stack part-a ← part-b ← part-c with part-c
+// checked out. After part-a lands, part-b is first seen with its old head,
+// then with the rebased head and running CI, then with CI passed; part-c
+// follows the same pattern.
+func stackFixture() (*mockWorkflowRepo, *mockWorkflowClient) {
+	repo := happyRepo()
+	repo.currentBranch = "part-c"
+	repo.localBranches = map[string]bool{"part-b": true, "part-c": true}
+	repo.headByBranch = map[string]string{
+		"part-a": "aaaaaaa1111111",
+		"part-b": "bbbbbbb2222222",
+		"part-c": "ccccccc2222222",
+	}
+
+	client := happyClient()
+	client.prsByBranch = map[string][]*github.PullRequest{
+		"part-a": {stackPR(1, "part-a", "main", "aaaaaaa1111111", ciPassed)},
+		"part-b": {
+			stackPR(2, "part-b", "part-a", "bbbbbbb1111111", ciPassed),
+			stackPR(2, "part-b", "main", "bbbbbbb1111111", ciPassed),
+			stackPR(2, "part-b", "main", "bbbbbbb2222222", ciRunning),
+			stackPR(2, "part-b", "main", "bbbbbbb2222222", ciPassed),
+		},
+		"part-c": {
+			stackPR(3, "part-c", "part-b", "ccccccc1111111", ciPassed),
+			stackPR(3, "part-c", "main", "ccccccc2222222", ciPassed),
+		},
+	}
+	client.dependentsByBranch = map[string][]*github.PullRequest{
+		"part-a": {stackPR(2, "part-b", "part-a", "bbbbbbb1111111")},
+		"part-b": {stackPR(3, "part-c", "part-b", "ccccccc1111111")},
+	}
+	return repo, client
+}
+
+func newStackTestWorkflow(repo *mockWorkflowRepo, client *mockWorkflowClient, timeout string) (*LandWorkflow, *[]time.Duration) {
+	cfg := defaultConfig()
+	cfg.Land.StackCITimeout = timeout
+	wf := newTestWorkflow(repo, client, cfg)
+
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	wf.now = func() time.Time { return clock }
+	wf.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		clock = clock.Add(d)
+		return ctx.Err()
+	}
+	return wf, &slept
+}
+
+func TestExecuteStack_LandsBottomUp(t *testing.T) {
+	repo, client := stackFixture()
+	wf, slept := newStackTestWorkflow(repo, client, "30m")
+
+	result, err := wf.ExecuteStack(context.Background(), &LandOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, outputText(wf))
+	}
+
+	if got := client.merged; len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("expected PRs merged bottom-up [1 2 3], got %v", got)
+	}
+	if len(result.Landed) != 3 || len(result.Remaining) != 0 {
+		t.Errorf("expected 3 landed and none remaining, got %d and %d", len(result.Landed), len(result.Remaining))
+	}
+	if len(repo.pushed) != 2 || repo.pushed[0] != "part-b" || repo.pushed[1] != "part-c" {
+		t.Errorf("expected part-b and part-c to be rebased and pushed, got %v", repo.pushed)
+	}
+	// One poll for part-b's stale head, one for its running CI
+	if len(*slept) != 2 {
+		t.Errorf("expected 2 polls, got %v", *slept)
+	}
+
+	out := outputText(wf)
+	if !strings.Contains(out, "Landing 3 PRs into main, bottom first: #1 → #2 → #3") {
+		t.Errorf("expected stack plan, got: %s", out)
+	}
+	if !strings.Contains(out, "Waiting for CI on PR #2") {
+		t.Errorf("expected CI wait output, got: %s", out)
+	}
+	if !strings.Contains(out, "Landed 3 of 3 PRs into main") {
+		t.Errorf("expected summary, got: %s", out)
+	}
+}
+
+func TestExecuteStack_StopsOnCIFailure(t *testing.T) {
+	repo, client := stackFixture()
+	client.prsByBranch["part-b"][3] = stackPR(2, "part-b", "main", "bbbbbbb2222222", ciFailed)
+	wf, _ := newStackTestWorkflow(repo, client, "30m")
+
+	result, err := wf.ExecuteStack(context.Background(), &LandOptions{})
+	if !errors.Is(err, ErrCIFailed) {
+		t.Fatalf("expected ErrCIFailed, got %v", err)
+	}
+
+	if len(client.merged) != 1 || client.merged[0] != 1 {
+		t.Errorf("expected only PR #1 to be merged, got %v", client.merged)
+	}
+	if len(result.Landed) != 1 || len(result.Remaining) != 2 || result.Remaining[0].Number != 2 {
+		t.Errorf("expected #1 landed and #2, #3 remaining, got %+v", result)
+	}
+
+	out := outputText(wf)
+	if !strings.Contains(out, "Landed 1 of 3 PRs into main") {
+		t.Errorf("expected partial summary, got: %s", out)
+	}
+	if !strings.Contains(out, "Stopped at PR #2") {
+		t.Errorf("expected stop reason, got: %s", out)
+	}
+	if !strings.Contains(out, "check out part-c and run 'gh arc land --stack' again") {
+		t.Errorf("expected resume hint, got: %s", out)
+	}
+}
+
+func TestExecuteStack_CITimeout(t *testing.T) {
+	repo, client := stackFixture()
+	client.prsByBranch["part-b"] = client.prsByBranch["part-b"][:3]
+	wf, slept := newStackTestWorkflow(repo, client, "1m")
+
+	result, err := wf.ExecuteStack(context.Background(), &LandOptions{})
+	if !errors.Is(err, ErrCITimeout) {
+		t.Fatalf("expected ErrCITimeout, got %v", err)
+	}
+
+	var waited time.Duration
+	for _, d := range *slept {
+		waited += d
+	}
+	if waited != time.Minute {
+		t.Errorf("expected to wait exactly the 1m timeout, waited %s", waited)
+	}
+	if len(result.Landed) != 1 || len(result.Remaining) != 2 {
+		t.Errorf("expected #1 landed and two remaining, got %+v", result)
+	}
+	if !strings.Contains(outputText(wf), "raise land.stackCITimeout") {
+		t.Errorf("expected timeout guidance, got: %s", outputText(wf))
+	}
+}
+
+func TestExecuteStack_CancelledWhileWaitingForCI(t *testing.T) {
+	repo, client := stackFixture()
+	client.prsByBranch["part-b"] = client.prsByBranch["part-b"][:3]
+	wf, _ := newStackTestWorkflow(repo, client, "30m")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	polls := 0
+	wf.sleep = func(ctx context.Context, d time.Duration) error {
+		polls++
+		cancel()
+		return sleepContext(ctx, d)
+	}
+
+	_, err := wf.ExecuteStack(ctx, &LandOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if polls != 1 {
+		t.Errorf("expected to stop at the first poll, polled %d times", polls)
+	}
+}
+
+func TestExecuteStack_NextNotRebased(t *testing.T) {
+	repo, client := stackFixture()
+	delete(repo.localBranches, "part-b")
+	wf, _ := newStackTestWorkflow(repo, client, "30m")
+
+	result, err := wf.ExecuteStack(context.Background(), &LandOptions{})
+	if !errors.Is(err, ErrStackNotRebased) {
+		t.Fatalf("expected ErrStackNotRebased, got %v", err)
+	}
+	if len(client.merged) != 1 {
+		t.Errorf("expected only the bottom PR to be merged, got %v", client.merged)
+	}
+	if len(result.Remaining) != 2 || result.Remaining[0].Number != 2 {
+		t.Errorf("expected #2 and #3 remaining, got %+v", result.Remaining)
+	}
+	if !strings.Contains(result.StopReason, "part-b is not checked out locally") {
+		t.Errorf("expected the retarget warning as stop reason, got %q", result.StopReason)
+	}
+}
+
+func TestExecuteStack_BrokenChain(t *testing.T) {
+	repo, client := stackFixture()
+	delete(client.prsByBranch, "part-a")
+	wf, _ := newStackTestWorkflow(repo, client, "30m")
+
+	result, err := wf.ExecuteStack(context.Background(), &LandOptions{})
+	if !errors.Is(err, ErrNoPRFound) {
+		t.Fatalf("expected ErrNoPRFound, got %v", err)
+	}
+	if result != nil {
+		t.Errorf("expected no result, got %+v", result)
+	}
+	if client.mergeCalled {
+		t.Error("nothing should be merged when the stack cannot be resolved")
+	}
+	if !strings.Contains(err.Error(), "PR #2 targets part-a, which has no open pull request") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestExecuteStack_DirtyWorkingDir(t *testing.T) {
+	repo, client := stackFixture()
+	repo.status.IsClean = false
+	wf, _ := newStackTestWorkflow(repo, client, "30m")
+
+	if _, err := wf.ExecuteStack(context.Background(), &LandOptions{}); !errors.Is(err, ErrDirtyWorkingDir) {
+		t.Fatalf("expected ErrDirtyWorkingDir, got %v", err)
+	}
+	if client.mergeCalled {
+		t.Error("nothing should be merged with a dirty working directory")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"golang.org/x/term"
 
@@ -19,6 +20,7 @@ import (
 type WorkflowRepo interface {
 	CheckerRepo
 	CleanupRepo
+	DependentsRepo
 	GetCurrentBranch() (string, error)
 	GetDefaultBranch() (string, error)
 }
@@ -27,16 +29,18 @@ type WorkflowRepo interface {
 type WorkflowClient interface {
 	CheckerClient
 	MergerClient
+	DependentsClient
 	EnrichPullRequest(ctx context.Context, owner, repo string, pr *github.PullRequest) error
 }
 
 // LandOptions holds the flags and options for the land command.
 type LandOptions struct {
-	Squash   bool
-	Rebase   bool
-	Force    bool
-	Edit     bool
-	NoDelete bool
+	Squash     bool
+	Rebase     bool
+	Force      bool
+	Edit       bool
+	NoDelete   bool
+	NoRetarget bool // Leave dependent PRs targeting the landed branch
 }
 
 // LandWorkflow orchestrates the entire land command sequence.
@@ -49,9 +53,15 @@ type LandWorkflow struct {
 	checker    *PreMergeChecker
 	merger     *MergeExecutor
 	cleanup    *PostMergeCleanup
+	dependents *DependentRetargeter
 	output     *OutputStyle
 	stdin      io.Reader
 	isTerminal func() bool
+
+	// Polling for land --stack; replaced in tests
+	now          func() time.Time
+	sleep        func(context.Context, time.Duration) error
+	pollInterval time.Duration
 }
 
 // NewLandWorkflow creates a new LandWorkflow with all sub-components.
@@ -65,9 +75,14 @@ func NewLandWorkflow(repo WorkflowRepo, client WorkflowClient, cfg *config.Confi
 		checker:    NewPreMergeChecker(repo, client, &cfg.Land),
 		merger:     NewMergeExecutor(client),
 		cleanup:    NewPostMergeCleanup(repo),
+		dependents: NewDependentRetargeter(repo, client, owner, name),
 		output:     NewOutputStyle(cfg.Output.Color),
 		stdin:      os.Stdin,
 		isTerminal: func() bool { return term.IsTerminal(int(os.Stdin.Fd())) },
+
+		now:          time.Now,
+		sleep:        sleepContext,
+		pollInterval: stackPollInterval,
 	}
 }
 
@@ -106,12 +121,43 @@ func (w *LandWorkflow) Execute(ctx context.Context, opts *LandOptions) (*LandRes
 	}
 	w.output.PrintPRFound(pr)
 
-	if err := w.checker.CheckLocalHeadMatchesPR(pr); err != nil {
-		w.output.PrintStep("✗", "Local HEAD does not match PR head")
-		w.output.PrintDetail("Push your changes with 'gh arc diff' or 'git push' before landing")
+	pr, err = w.runGates(ctx, currentBranch, pr, opts, nil)
+	if err != nil {
 		return nil, err
 	}
 
+	return w.mergeAndCleanup(ctx, pr, currentBranch, defaultBranch, opts)
+}
+
+// gateWait tells runGates to poll instead of failing while GitHub catches up
+// with a freshly pushed branch and its CI runs.
+type gateWait struct {
+	deadline time.Time
+	push     bool // Also wait for the PR head to match the local branch
+}
+
+// runGates runs the per-PR checks — HEAD match, approval and CI — and returns
+// the PR as last fetched. With wait set, a pending check is polled until it
+// settles or the deadline passes.
+func (w *LandWorkflow) runGates(ctx context.Context, branch string, pr *github.PullRequest, opts *LandOptions, wait *gateWait) (*github.PullRequest, error) {
+	for {
+		err := w.checker.CheckLocalHeadMatchesPR(pr)
+		if err == nil {
+			break
+		}
+		if wait == nil || !wait.push || !errors.Is(err, ErrLocalHeadMismatch) || !w.now().Before(wait.deadline) {
+			w.output.PrintStep("✗", "Local HEAD does not match PR head")
+			w.output.PrintDetail("Push your changes with 'gh arc diff' or 'git push' before landing")
+			return nil, err
+		}
+		if err := w.sleep(ctx, w.pollInterval); err != nil {
+			return nil, err
+		}
+		if pr, err = w.checker.CheckPRExists(ctx, branch); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := w.client.EnrichPullRequest(ctx, w.owner, w.name, pr); err != nil {
 		return nil, fmt.Errorf("failed to enrich pull request: %w", err)
 	}
@@ -143,19 +189,69 @@ func (w *LandWorkflow) Execute(ctx context.Context, opts *LandOptions) (*LandRes
 	if err != nil {
 		return nil, fmt.Errorf("failed to check CI: %w", err)
 	}
+	if wait != nil && ciResult.Pending {
+		pr, ciResult, err = w.waitForCI(ctx, branch, pr, ciResult, opts.Force, wait.deadline)
+		if err != nil {
+			return nil, err
+		}
+	}
 	for _, msg := range ciResult.Messages {
 		w.output.PrintCIStatus(ciResult.Passed, msg)
 	}
 	if !ciResult.Passed {
+		if wait != nil && ciResult.Pending {
+			w.output.PrintDetail(fmt.Sprintf("Gave up waiting for CI on PR #%d — raise land.stackCITimeout or re-run once checks finish", pr.Number))
+			return nil, ErrCITimeout
+		}
 		w.output.PrintDetail("Wait for checks to complete or use --force to bypass")
 		return nil, ErrCIFailed
 	}
 
-	dependentPRs, err := w.checker.CheckDependentPRs(ctx, currentBranch)
+	return pr, nil
+}
+
+// waitForCI re-fetches the PR until its CI is no longer pending or the
+// deadline passes, printing the time left on every poll.
+func (w *LandWorkflow) waitForCI(ctx context.Context, branch string, pr *github.PullRequest, result *CheckResult, force bool, deadline time.Time) (*github.PullRequest, *CheckResult, error) {
+	w.output.PrintStep("…", fmt.Sprintf("Waiting for CI on PR #%d", pr.Number))
+	for result.Pending {
+		remaining := deadline.Sub(w.now())
+		if remaining <= 0 {
+			break
+		}
+		for _, msg := range result.Messages {
+			w.output.PrintDetail(fmt.Sprintf("%s — %s left", msg, remaining.Round(time.Second)))
+		}
+		if err := w.sleep(ctx, min(w.pollInterval, remaining)); err != nil {
+			return nil, nil, err
+		}
+
+		refreshed, err := w.checker.CheckPRExists(ctx, branch)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := w.client.EnrichPullRequest(ctx, w.owner, w.name, refreshed); err != nil {
+			return nil, nil, fmt.Errorf("failed to enrich pull request: %w", err)
+		}
+		pr = refreshed
+
+		if result, err = w.checker.CheckCI(ctx, pr, force); err != nil {
+			return nil, nil, fmt.Errorf("failed to check CI: %w", err)
+		}
+	}
+	return pr, result, nil
+}
+
+// mergeAndCleanup merges a PR that passed its gates, cleans up the local
+// branch and moves its dependents onto the default branch.
+func (w *LandWorkflow) mergeAndCleanup(ctx context.Context, pr *github.PullRequest, branch, defaultBranch string, opts *LandOptions) (*LandResult, error) {
+	dependentPRs, err := w.checker.CheckDependentPRs(ctx, branch)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check dependent PRs: %w", err)
 	}
-	w.output.PrintDependentPRs(len(dependentPRs))
+	if opts.NoRetarget {
+		w.output.PrintDependentPRs(len(dependentPRs))
+	}
 
 	mergeMethod := w.resolveMergeMethod(opts)
 
@@ -171,21 +267,38 @@ func (w *LandWorkflow) Execute(ctx context.Context, opts *LandOptions) (*LandRes
 	w.output.PrintMerged(mergeMethod, pr.Base.Ref, mergeResult.SHA)
 
 	noDelete := opts.NoDelete || !w.config.Land.DeleteLocalBranch
-	cleanupResult, err := w.cleanup.Execute(defaultBranch, currentBranch, noDelete)
+	cleanupResult, err := w.cleanup.Execute(defaultBranch, branch, noDelete)
 	if err != nil {
 		return nil, fmt.Errorf("cleanup failed: %w", err)
 	}
-	w.printCleanupResult(cleanupResult, defaultBranch, currentBranch)
+	w.printCleanupResult(cleanupResult, defaultBranch, branch)
+
+	// Move direct dependents onto the default branch, so they neither target
+	// a deleted branch nor carry the pre-squash commits of this PR
+	var dependents []DependentResult
+	cleanupWarnings := cleanupResult.Warnings
+	if !opts.NoRetarget && len(dependentPRs) > 0 {
+		dependentsResult := w.dependents.Execute(ctx, pr, dependentPRs, defaultBranch, cleanupResult.Pulled)
+		for _, dep := range dependentsResult.Dependents {
+			w.output.PrintDependentResult(dep, defaultBranch)
+		}
+		for _, warning := range dependentsResult.Warnings {
+			w.output.PrintCleanupWarning(warning)
+		}
+		dependents = dependentsResult.Dependents
+		cleanupWarnings = append(cleanupWarnings, dependentsResult.Warnings...)
+	}
 
 	return &LandResult{
 		PR:               pr,
 		MergeMethod:      mergeMethod,
 		MergeCommitSHA:   mergeResult.SHA,
 		DefaultBranch:    defaultBranch,
-		DeletedBranch:    deletedBranchName(cleanupResult, currentBranch),
+		DeletedBranch:    deletedBranchName(cleanupResult, branch),
 		DeletedBranchSHA: cleanupResult.DeletedBranchSHA,
 		DependentPRCount: len(dependentPRs),
-		CleanupWarnings:  cleanupResult.Warnings,
+		Dependents:       dependents,
+		CleanupWarnings:  cleanupWarnings,
 	}, nil
 }
 
@@ -235,3 +348,16 @@ func deletedBranchName(result *CleanupResult, featureBranch string) string {
 	}
 	return ""
 }
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
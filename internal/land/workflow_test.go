@@ -28,6 +28,12 @@ type mockWorkflowRepo struct {
 	branchSHA        string
 	branchSHAErr     error
 	deleteBranchErr  error
+	localBranches    map[string]bool
+	rebaseErr        error
+	rebased          []string
+	pushed           []string
+	headByBranch     map[string]string // Overrides headSHA for the last checked out branch
+	checkedOut       []string
 }
 
 func (m *mockWorkflowRepo) GetWorkingDirectoryStatus() (*git.WorkingDirectoryStatus, error) {
@@ -35,6 +41,11 @@ func (m *mockWorkflowRepo) GetWorkingDirectoryStatus() (*git.WorkingDirectorySta
 }
 
 func (m *mockWorkflowRepo) GetHeadSHA() (string, error) {
+	if len(m.checkedOut) > 0 {
+		if sha, ok := m.headByBranch[m.checkedOut[len(m.checkedOut)-1]]; ok {
+			return sha, m.headSHAErr
+		}
+	}
 	return m.headSHA, m.headSHAErr
 }
 
@@ -46,7 +57,8 @@ func (m *mockWorkflowRepo) GetDefaultBranch() (string, error) {
 	return m.defaultBranch, m.defaultBranchErr
 }
 
-func (m *mockWorkflowRepo) CheckoutBranch(_ string) error {
+func (m *mockWorkflowRepo) CheckoutBranch(branch string) error {
+	m.checkedOut = append(m.checkedOut, branch)
 	return m.checkoutErr
 }
 
@@ -66,6 +78,39 @@ func (m *mockWorkflowRepo) DeleteLocalBranch(_ string) error {
 	return m.deleteBranchErr
 }
 
+func (m *mockWorkflowRepo) BranchExists(branchName string) (bool, error) {
+	return m.localBranches[strings.TrimPrefix(branchName, "refs/heads/")], nil
+}
+
+func (m *mockWorkflowRepo) IsAncestor(_, _ string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockWorkflowRepo) GetMergeBase(ref1, ref2 string) (string, error) {
+	return "mergebase", nil
+}
+
+func (m *mockWorkflowRepo) RebaseOnto(newBase, upstream, branch string) error {
+	if m.rebaseErr != nil {
+		return m.rebaseErr
+	}
+	m.rebased = append(m.rebased, branch+" onto "+newBase+" from "+upstream)
+	return nil
+}
+
+func (m *mockWorkflowRepo) AbortRebase() error {
+	return nil
+}
+
+func (m *mockWorkflowRepo) SetBranchParent(_, _, _ string) error {
+	return nil
+}
+
+func (m *mockWorkflowRepo) Push(_ context.Context, branchName string) error {
+	m.pushed = append(m.pushed, branchName)
+	return nil
+}
+
 type mockWorkflowClient struct {
 	pr                *github.PullRequest
 	findPRErr         error
@@ -78,13 +123,34 @@ type mockWorkflowClient struct {
 	mergeErr          error
 	mergeCalled       bool
 	mergeOpts         *github.MergeOptions
+	retargeted        []int
+	merged            []int
+
+	// Per-branch responses for stacks. Successive lookups of a branch walk
+	// its slice, repeating the last entry.
+	prsByBranch        map[string][]*github.PullRequest
+	dependentsByBranch map[string][]*github.PullRequest
 }
 
-func (m *mockWorkflowClient) FindExistingPRForCurrentBranch(_ context.Context, _ string) (*github.PullRequest, error) {
+func (m *mockWorkflowClient) FindExistingPRForCurrentBranch(_ context.Context, branchName string) (*github.PullRequest, error) {
+	if m.prsByBranch != nil {
+		prs := m.prsByBranch[branchName]
+		if len(prs) == 0 {
+			return nil, m.findPRErr
+		}
+		pr := prs[0]
+		if len(prs) > 1 {
+			m.prsByBranch[branchName] = prs[1:]
+		}
+		return pr, m.findPRErr
+	}
 	return m.pr, m.findPRErr
 }
 
-func (m *mockWorkflowClient) FindDependentPRsForCurrentBranch(_ context.Context, _ string) ([]*github.PullRequest, error) {
+func (m *mockWorkflowClient) FindDependentPRsForCurrentBranch(_ context.Context, branchName string) ([]*github.PullRequest, error) {
+	if m.dependentsByBranch != nil {
+		return m.dependentsByBranch[branchName], m.dependentPRsErr
+	}
 	return m.dependentPRs, m.dependentPRsErr
 }
 
@@ -96,12 +162,18 @@ func (m *mockWorkflowClient) EnrichPullRequest(_ context.Context, _, _ string, _
 	return m.enrichErr
 }
 
-func (m *mockWorkflowClient) MergePullRequestForCurrentRepo(_ context.Context, _ int, opts *github.MergeOptions) (*github.MergeResult, error) {
+func (m *mockWorkflowClient) MergePullRequestForCurrentRepo(_ context.Context, number int, opts *github.MergeOptions) (*github.MergeResult, error) {
 	m.mergeCalled = true
+	m.merged = append(m.merged, number)
 	m.mergeOpts = opts
 	return m.mergeResult, m.mergeErr
 }
 
+func (m *mockWorkflowClient) UpdatePRBaseForCurrentRepo(_ context.Context, number int, _ string) error {
+	m.retargeted = append(m.retargeted, number)
+	return nil
+}
+
 func defaultConfig() *config.Config {
 	return &config.Config{
 		Land: config.LandConfig{
@@ -463,10 +535,13 @@ func TestLandWorkflow_DependentPRs_Informational(t *testing.T) {
 	}
 	wf := newTestWorkflow(happyRepo(), client, defaultConfig())
 
-	result, err := wf.Execute(context.Background(), &LandOptions{})
+	result, err := wf.Execute(context.Background(), &LandOptions{NoRetarget: true})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if len(client.retargeted) != 0 {
+		t.Errorf("expected no retargeting with --no-retarget, got %v", client.retargeted)
+	}
 	if result.DependentPRCount != 1 {
 		t.Errorf("expected 1 dependent PR, got %d", result.DependentPRCount)
 	}
@@ -476,6 +551,68 @@ func TestLandWorkflow_DependentPRs_Informational(t *testing.T) {
 	}
 }
 
+func TestLandWorkflow_DependentPRs_Retargeted(t *testing.T) {
+	repo := happyRepo()
+	repo.localBranches = map[string]bool{"feature/child": true}
+	client := happyClient()
+	client.dependentPRs = []*github.PullRequest{
+		{Number: 99, Title: "Child PR", Head: github.PRBranch{Ref: "feature/child"}, Base: github.PRBranch{Ref: "feature/auth"}},
+		{Number: 100, Title: "Remote-only PR", Head: github.PRBranch{Ref: "feature/other"}, Base: github.PRBranch{Ref: "feature/auth"}},
+	}
+	wf := newTestWorkflow(repo, client, defaultConfig())
+
+	result, err := wf.Execute(context.Background(), &LandOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(client.retargeted) != 2 {
+		t.Errorf("expected both dependents to be retargeted, got %v", client.retargeted)
+	}
+	if len(repo.rebased) != 1 || repo.rebased[0] != "feature/child onto main from abc1234def5678" {
+		t.Errorf("expected feature/child to be rebased past the landed head, got %v", repo.rebased)
+	}
+	if len(repo.pushed) != 1 || repo.pushed[0] != "feature/child" {
+		t.Errorf("expected feature/child to be pushed, got %v", repo.pushed)
+	}
+	if len(result.Dependents) != 2 || !result.Dependents[0].Pushed || result.Dependents[1].Rebased {
+		t.Errorf("unexpected dependent results: %+v", result.Dependents)
+	}
+
+	out := outputText(wf)
+	if !strings.Contains(out, "Retargeted PR #99 to main, rebased feature/child and pushed") {
+		t.Errorf("expected retarget output, got: %s", out)
+	}
+	if !strings.Contains(out, "feature/other is not checked out locally") {
+		t.Errorf("expected warning for remote-only dependent, got: %s", out)
+	}
+}
+
+func TestLandWorkflow_DependentPRs_PullFailed(t *testing.T) {
+	repo := happyRepo()
+	repo.pullErr = errors.New("network down")
+	repo.localBranches = map[string]bool{"feature/child": true}
+	client := happyClient()
+	client.dependentPRs = []*github.PullRequest{
+		{Number: 99, Head: github.PRBranch{Ref: "feature/child"}, Base: github.PRBranch{Ref: "feature/auth"}},
+	}
+	wf := newTestWorkflow(repo, client, defaultConfig())
+
+	result, err := wf.Execute(context.Background(), &LandOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(client.retargeted) != 1 {
+		t.Errorf("expected dependent to be retargeted, got %v", client.retargeted)
+	}
+	if len(repo.rebased) != 0 {
+		t.Errorf("expected no rebase when trunk could not be pulled, got %v", repo.rebased)
+	}
+	if result.Dependents[0].Warning == "" {
+		t.Error("expected a warning explaining the skipped rebase")
+	}
+}
+
 // --- merge failure ---
 
 func TestLandWorkflow_MergeFailure(t *testing.T) {
@@ -0,0 +1,275 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/runner"
+)
+
+const (
+	// MegaLinterRunnerName is the runner name reported for MegaLinter results.
+	MegaLinterRunnerName = "megalinter"
+
+	// MegaLinterImage is the container image used when running through a container runtime.
+	MegaLinterImage = "oxsecurity/megalinter:v8"
+
+	// megaLinterCLI is the npm-distributed MegaLinter launcher.
+	megaLinterCLI = "mega-linter-runner"
+
+	// megaLinterReportDir is MegaLinter's default report folder, relative to the workspace.
+	megaLinterReportDir = "megalinter-reports"
+
+	// megaLinterLogsDir holds one log file per linter, named <STATUS>-<LINTER>.log.
+	megaLinterLogsDir = "linters_logs"
+
+	// megaLinterEmbeddedConfig is where the embedded (or out-of-repo) config is
+	// written so MegaLinter can read it from inside the mounted workspace.
+	megaLinterEmbeddedConfig = ".gh-arc-mega-linter.yml"
+
+	// megaLinterWorkspace is where the repository is mounted inside the container.
+	megaLinterWorkspace = "/tmp/lint"
+)
+
+// Supported values for lint.megaLinter.enabled
+const (
+	MegaLinterEnabledAuto  = "auto"
+	MegaLinterEnabledTrue  = "true"
+	MegaLinterEnabledFalse = "false"
+)
+
+var (
+	// ErrMegaLinterUnavailable is returned when MegaLinter is explicitly enabled
+	// but neither mega-linter-runner nor a container runtime is installed.
+	ErrMegaLinterUnavailable = errors.New("megalinter enabled but no mega-linter-runner or container runtime (docker, podman) found")
+)
+
+// containerRuntimes are checked in order when MegaLinter runs in a container.
+var containerRuntimes = []string{"docker", "podman"}
+
+// megaLinterLauncher describes how MegaLinter will be started.
+type megaLinterLauncher struct {
+	Command     string // mega-linter-runner, docker or podman
+	IsContainer bool   // true when Command is a container runtime
+}
+
+// detectMegaLinter finds a way to run MegaLinter, preferring a local
+// mega-linter-runner installation over a bare container runtime.
+func (w *LintWorkflow) detectMegaLinter() (*megaLinterLauncher, bool) {
+	if path, err := w.lookPath(megaLinterCLI); err == nil {
+		logger.Debug().Str("path", path).Msg("Detected local MegaLinter installation")
+		return &megaLinterLauncher{Command: megaLinterCLI}, true
+	}
+
+	for _, rt := range containerRuntimes {
+		if path, err := w.lookPath(rt); err == nil {
+			logger.Debug().Str("runtime", rt).Str("path", path).Msg("Detected container runtime for MegaLinter")
+			return &megaLinterLauncher{Command: rt, IsContainer: true}, true
+		}
+	}
+
+	return nil, false
+}
+
+// resolveMegaLinter decides whether MegaLinter should run for this invocation.
+// "true" requires a launcher; "auto" uses MegaLinter only when no custom runners
+// are configured and a launcher is available; "false" disables it.
+func (w *LintWorkflow) resolveMegaLinter() (*megaLinterLauncher, error) {
+	switch w.config.Lint.MegaLinter.Enabled {
+	case MegaLinterEnabledTrue:
+		launcher, ok := w.detectMegaLinter()
+		if !ok {
+			return nil, ErrMegaLinterUnavailable
+		}
+		return launcher, nil
+	case MegaLinterEnabledAuto, "":
+		if len(w.config.Lint.Runners) > 0 {
+			return nil, nil
+		}
+		launcher, _ := w.detectMegaLinter()
+		return launcher, nil
+	default:
+		return nil, nil
+	}
+}
+
+// buildMegaLinterRunner creates the runner config that launches MegaLinter for
+// the repository at root. The returned cleanup removes any generated config file.
+func (w *LintWorkflow) buildMegaLinterRunner(launcher *megaLinterLauncher, root string, opts *LintOptions, changedFiles []string, allMode bool) (runner.RunnerConfig, func(), error) {
+	cleanup := func() {}
+
+	configRef, generated, err := prepareMegaLinterConfig(&w.config.Lint.MegaLinter, root)
+	if err != nil {
+		return runner.RunnerConfig{}, cleanup, err
+	}
+	if generated != "" {
+		cleanup = func() {
+			if err := os.Remove(generated); err != nil && !os.IsNotExist(err) {
+				logger.Debug().Err(err).Str("path", generated).Msg("Failed to remove generated MegaLinter config")
+			}
+		}
+	}
+
+	env := []string{
+		"MEGALINTER_CONFIG=" + configRef,
+	}
+	if allMode {
+		env = append(env, "VALIDATE_ALL_CODEBASE=true")
+	} else {
+		env = append(env, "VALIDATE_ALL_CODEBASE=false", "MEGALINTER_FILES_TO_LINT="+strings.Join(changedFiles, ","))
+	}
+	if opts.Fix || w.config.Lint.MegaLinter.FixIssues {
+		env = append(env, "APPLY_FIXES=all")
+	}
+
+	var args []string
+	if launcher.IsContainer {
+		args = []string{"run", "--rm", "-v", root + ":" + megaLinterWorkspace + ":rw"}
+		for _, e := range env {
+			args = append(args, "-e", e)
+		}
+		args = append(args, MegaLinterImage)
+	} else {
+		args = []string{"--path", root}
+		for _, e := range env {
+			args = append(args, "-e", e)
+		}
+	}
+
+	reportDir := filepath.Join(root, megaLinterReportDir)
+	start := w.now()
+
+	rc := runner.RunnerConfig{
+		Name:       MegaLinterRunnerName,
+		Command:    launcher.Command,
+		Args:       args,
+		WorkingDir: root,
+		ExpandResult: func(result runner.RunResult) []runner.RunResult {
+			return expandMegaLinterResult(result, reportDir, start)
+		},
+	}
+
+	return rc, cleanup, nil
+}
+
+// prepareMegaLinterConfig returns the value for MEGALINTER_CONFIG. URLs and config
+// files inside the repository are referenced directly; the embedded default (used
+// when no config file exists) and files outside the repository are written into
+// the report folder so they are visible inside the mounted workspace. The second
+// return value is the path of the generated file, if any.
+func prepareMegaLinterConfig(mlCfg *config.MegaLinterConfig, root string) (string, string, error) {
+	configPath := mlCfg.Config
+
+	if strings.HasPrefix(configPath, "http://") || strings.HasPrefix(configPath, "https://") {
+		return configPath, "", nil
+	}
+
+	content := GetDefaultMegaLinterConfig()
+	if configPath != "" {
+		absPath := configPath
+		if !filepath.IsAbs(absPath) {
+			absPath = filepath.Join(root, configPath)
+		}
+
+		if _, err := os.Stat(absPath); err == nil {
+			if rel, err := filepath.Rel(root, absPath); err == nil && !strings.HasPrefix(rel, "..") {
+				return filepath.ToSlash(rel), "", nil
+			}
+			data, err := os.ReadFile(absPath)
+			if err != nil {
+				return "", "", fmt.Errorf("failed to read MegaLinter config %s: %w", configPath, err)
+			}
+			content = data
+		} else {
+			logger.Debug().
+				Str("config", configPath).
+				Msg("MegaLinter config not found, using embedded default")
+		}
+	}
+
+	reportDir := filepath.Join(root, megaLinterReportDir)
+	if err := os.MkdirAll(reportDir, 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create MegaLinter report directory: %w", err)
+	}
+	generated := filepath.Join(reportDir, megaLinterEmbeddedConfig)
+	if err := os.WriteFile(generated, content, 0o644); err != nil {
+		return "", "", fmt.Errorf("failed to write MegaLinter config: %w", err)
+	}
+
+	return megaLinterReportDir + "/" + megaLinterEmbeddedConfig, generated, nil
+}
+
+// expandMegaLinterResult replaces the result of the MegaLinter run with one
+// result per linter. The run's own result is kept when it failed, e.g. timed
+// out or crashed, and no failing linter report explains why, so a partial set
+// of passing reports never turns a failed run into a passed one.
+func expandMegaLinterResult(result runner.RunResult, reportDir string, since time.Time) []runner.RunResult {
+	results := parseMegaLinterReports(reportDir, since)
+	if len(results) == 0 || !result.IsFailure() {
+		return results
+	}
+
+	for _, r := range results {
+		if r.IsFailure() {
+			return results
+		}
+	}
+	return append(results, result)
+}
+
+// parseMegaLinterReports turns MegaLinter's per-linter log files into runner
+// results. Only logs written at or after since are considered, so reports left
+// over from previous runs are ignored. Returns nil if no reports were found.
+func parseMegaLinterReports(reportDir string, since time.Time) []runner.RunResult {
+	logsDir := filepath.Join(reportDir, megaLinterLogsDir)
+	entries, err := os.ReadDir(logsDir)
+	if err != nil {
+		logger.Debug().Err(err).Str("dir", logsDir).Msg("No MegaLinter linter logs found")
+		return nil
+	}
+
+	// Allow for coarse filesystem timestamp resolution
+	since = since.Truncate(time.Second)
+
+	var results []runner.RunResult
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".log") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().Before(since) {
+			continue
+		}
+
+		status, linter, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".log"), "-")
+		if !ok || linter == "" {
+			continue
+		}
+
+		result := runner.RunResult{Name: MegaLinterRunnerName + "/" + linter}
+		switch strings.ToUpper(status) {
+		case "SUCCESS", "WARNING":
+			// WARNING means issues were found but the linter is configured as non-blocking
+			result.Status = runner.StatusPassed
+		case "ERROR":
+			result.Status = runner.StatusFailed
+			result.ExitCode = 1
+		default:
+			continue
+		}
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	return results
+}
@@ -0,0 +1,311 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/runner"
+)
+
+func lookPathFinding(found ...string) func(string) (string, error) {
+	return func(file string) (string, error) {
+		if slices.Contains(found, file) {
+			return "/usr/bin/" + file, nil
+		}
+		return "", fmt.Errorf("%s: not found", file)
+	}
+}
+
+func megaLinterConfig(enabled string, runners ...config.LintRunner) *config.Config {
+	return &config.Config{Lint: config.LintConfig{
+		Runners:    runners,
+		MegaLinter: config.MegaLinterConfig{Enabled: enabled},
+	}}
+}
+
+// envArgs collects the values passed via "-e" flags.
+func envArgs(args []string) []string {
+	var env []string
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == "-e" {
+			env = append(env, args[i+1])
+		}
+	}
+	return env
+}
+
+func TestResolveMegaLinter(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     string
+		runners     []config.LintRunner
+		found       []string
+		wantCommand string
+		wantErr     error
+	}{
+		{
+			name:        "auto prefers mega-linter-runner",
+			enabled:     "auto",
+			found:       []string{"mega-linter-runner", "docker"},
+			wantCommand: "mega-linter-runner",
+		},
+		{
+			name:        "auto falls back to docker",
+			enabled:     "auto",
+			found:       []string{"docker"},
+			wantCommand: "docker",
+		},
+		{
+			name:        "auto falls back to podman",
+			enabled:     "auto",
+			found:       []string{"podman"},
+			wantCommand: "podman",
+		},
+		{
+			name:    "auto without runtime is disabled",
+			enabled: "auto",
+		},
+		{
+			name:    "auto with custom runners is disabled",
+			enabled: "auto",
+			runners: []config.LintRunner{{Name: "golangci-lint", Command: "golangci-lint"}},
+			found:   []string{"docker"},
+		},
+		{
+			name:        "true runs alongside custom runners",
+			enabled:     "true",
+			runners:     []config.LintRunner{{Name: "golangci-lint", Command: "golangci-lint"}},
+			found:       []string{"docker"},
+			wantCommand: "docker",
+		},
+		{
+			name:    "true without runtime errors",
+			enabled: "true",
+			wantErr: ErrMegaLinterUnavailable,
+		},
+		{
+			name:    "false is disabled",
+			enabled: "false",
+			found:   []string{"docker"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, _ := newTestWorkflow(defaultRepo(), &mockExecutor{}, megaLinterConfig(tt.enabled, tt.runners...))
+			w.lookPath = lookPathFinding(tt.found...)
+
+			assert.Equal(t, tt.wantCommand != "" || tt.wantErr != nil || len(tt.runners) > 0, w.Configured())
+
+			launcher, err := w.resolveMegaLinter()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.wantCommand == "" {
+				assert.Nil(t, launcher)
+				return
+			}
+			require.NotNil(t, launcher)
+			assert.Equal(t, tt.wantCommand, launcher.Command)
+		})
+	}
+}
+
+func TestLintWorkflow_MegaLinterContainer(t *testing.T) {
+	root := t.TempDir()
+	repo := defaultRepo()
+	repo.path = root
+	exec := &mockExecutor{}
+	w, _ := newTestWorkflow(repo, exec, megaLinterConfig("auto"))
+	w.lookPath = lookPathFinding("docker")
+
+	_, err := w.Execute(context.Background(), &LintOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, exec.configs, 1)
+	rc := exec.configs[0]
+	assert.Equal(t, MegaLinterRunnerName, rc.Name)
+	assert.Equal(t, "docker", rc.Command)
+	assert.Equal(t, []string{"run", "--rm", "-v", root + ":/tmp/lint:rw"}, rc.Args[:4])
+	assert.Equal(t, MegaLinterImage, rc.Args[len(rc.Args)-1])
+	assert.Empty(t, rc.FilePaths, "files are passed via MEGALINTER_FILES_TO_LINT")
+
+	env := envArgs(rc.Args)
+	assert.Contains(t, env, "MEGALINTER_FILES_TO_LINT=main.go,frontend/src/App.tsx")
+	assert.Contains(t, env, "VALIDATE_ALL_CODEBASE=false")
+	assert.Contains(t, env, "MEGALINTER_CONFIG=megalinter-reports/.gh-arc-mega-linter.yml")
+	assert.NotContains(t, env, "APPLY_FIXES=all")
+
+	// Generated config is removed once the workflow finishes
+	_, err = os.Stat(filepath.Join(root, "megalinter-reports", ".gh-arc-mega-linter.yml"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLintWorkflow_MegaLinterLocalAllAndFix(t *testing.T) {
+	root := t.TempDir()
+	repo := defaultRepo()
+	repo.path = root
+	exec := &mockExecutor{}
+	w, _ := newTestWorkflow(repo, exec, megaLinterConfig("true"))
+	w.lookPath = lookPathFinding("mega-linter-runner")
+
+	_, err := w.Execute(context.Background(), &LintOptions{All: true, Fix: true})
+	require.NoError(t, err)
+
+	require.Len(t, exec.configs, 1)
+	rc := exec.configs[0]
+	assert.Equal(t, "mega-linter-runner", rc.Command)
+	assert.Equal(t, []string{"--path", root}, rc.Args[:2])
+
+	env := envArgs(rc.Args)
+	assert.Contains(t, env, "VALIDATE_ALL_CODEBASE=true")
+	assert.Contains(t, env, "APPLY_FIXES=all")
+}
+
+func TestPrepareMegaLinterConfig(t *testing.T) {
+	t.Run("empty config writes embedded default", func(t *testing.T) {
+		root := t.TempDir()
+
+		ref, generated, err := prepareMegaLinterConfig(&config.MegaLinterConfig{}, root)
+		require.NoError(t, err)
+
+		assert.Equal(t, "megalinter-reports/.gh-arc-mega-linter.yml", ref)
+		data, err := os.ReadFile(generated)
+		require.NoError(t, err)
+		assert.Equal(t, GetDefaultMegaLinterConfig(), data)
+	})
+
+	t.Run("missing config file falls back to embedded default", func(t *testing.T) {
+		root := t.TempDir()
+
+		_, generated, err := prepareMegaLinterConfig(&config.MegaLinterConfig{Config: ".mega-linter.yml"}, root)
+		require.NoError(t, err)
+		assert.NotEmpty(t, generated)
+	})
+
+	t.Run("config inside repository is referenced directly", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".mega-linter.yml"), []byte("ENABLE: [GO]"), 0o644))
+
+		ref, generated, err := prepareMegaLinterConfig(&config.MegaLinterConfig{Config: ".mega-linter.yml"}, root)
+		require.NoError(t, err)
+
+		assert.Equal(t, ".mega-linter.yml", ref)
+		assert.Empty(t, generated)
+	})
+
+	t.Run("config outside repository is copied", func(t *testing.T) {
+		root := t.TempDir()
+		external := filepath.Join(t.TempDir(), "shared.yml")
+		require.NoError(t, os.WriteFile(external, []byte("ENABLE: [YAML]"), 0o644))
+
+		_, generated, err := prepareMegaLinterConfig(&config.MegaLinterConfig{Config: external}, root)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(generated)
+		require.NoError(t, err)
+		assert.Equal(t, "ENABLE: [YAML]", string(data))
+	})
+
+	t.Run("URL is passed through", func(t *testing.T) {
+		url := "https://example.com/.mega-linter.yml"
+
+		ref, generated, err := prepareMegaLinterConfig(&config.MegaLinterConfig{Config: url}, t.TempDir())
+		require.NoError(t, err)
+
+		assert.Equal(t, url, ref)
+		assert.Empty(t, generated)
+	})
+}
+
+func TestParseMegaLinterReports(t *testing.T) {
+	reportDir := t.TempDir()
+	logsDir := filepath.Join(reportDir, "linters_logs")
+	require.NoError(t, os.MkdirAll(logsDir, 0o755))
+
+	start := time.Now()
+	for _, name := range []string{
+		"SUCCESS-YAML_YAMLLINT.log",
+		"ERROR-GO_GOLANGCI_LINT.log",
+		"WARNING-MARKDOWN_MARKDOWNLINT.log",
+		"notes.txt",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(logsDir, name), []byte("log"), 0o644))
+	}
+
+	// Stale report from an earlier run is ignored
+	stale := filepath.Join(logsDir, "ERROR-BASH_SHELLCHECK.log")
+	require.NoError(t, os.WriteFile(stale, []byte("old"), 0o644))
+	old := start.Add(-time.Hour)
+	require.NoError(t, os.Chtimes(stale, old, old))
+
+	results := parseMegaLinterReports(reportDir, start)
+
+	require.Len(t, results, 3)
+	assert.Equal(t, "megalinter/GO_GOLANGCI_LINT", results[0].Name)
+	assert.Equal(t, runner.StatusFailed, results[0].Status)
+	assert.Equal(t, "megalinter/MARKDOWN_MARKDOWNLINT", results[1].Name)
+	assert.Equal(t, runner.StatusPassed, results[1].Status)
+	assert.Equal(t, "megalinter/YAML_YAMLLINT", results[2].Name)
+	assert.Equal(t, runner.StatusPassed, results[2].Status)
+
+	t.Run("missing logs directory", func(t *testing.T) {
+		assert.Nil(t, parseMegaLinterReports(t.TempDir(), start))
+	})
+}
+
+func TestExpandMegaLinterResult(t *testing.T) {
+	writeLogs := func(t *testing.T, names ...string) string {
+		t.Helper()
+		reportDir := t.TempDir()
+		logsDir := filepath.Join(reportDir, "linters_logs")
+		require.NoError(t, os.MkdirAll(logsDir, 0o755))
+		for _, name := range names {
+			require.NoError(t, os.WriteFile(filepath.Join(logsDir, name), []byte("log"), 0o644))
+		}
+		return reportDir
+	}
+
+	start := time.Now()
+	passed := runner.RunResult{Name: MegaLinterRunnerName, Status: runner.StatusPassed}
+	timedOut := runner.RunResult{Name: MegaLinterRunnerName, Status: runner.StatusTimeout}
+	failed := runner.RunResult{Name: MegaLinterRunnerName, Status: runner.StatusFailed, ExitCode: 1}
+
+	t.Run("passed run is replaced by the linters", func(t *testing.T) {
+		results := expandMegaLinterResult(passed, writeLogs(t, "SUCCESS-YAML_YAMLLINT.log"), start)
+		require.Len(t, results, 1)
+		assert.Equal(t, "megalinter/YAML_YAMLLINT", results[0].Name)
+	})
+
+	t.Run("failure explained by a linter", func(t *testing.T) {
+		results := expandMegaLinterResult(failed, writeLogs(t, "SUCCESS-YAML_YAMLLINT.log", "ERROR-GO_GOLANGCI_LINT.log"), start)
+		require.Len(t, results, 2)
+		for _, r := range results {
+			assert.NotEqual(t, MegaLinterRunnerName, r.Name)
+		}
+	})
+
+	t.Run("unexplained failures are kept", func(t *testing.T) {
+		for _, result := range []runner.RunResult{timedOut, failed} {
+			results := expandMegaLinterResult(result, writeLogs(t, "SUCCESS-YAML_YAMLLINT.log"), start)
+			require.Len(t, results, 2)
+			assert.Equal(t, result, results[1])
+		}
+	})
+
+	t.Run("no reports", func(t *testing.T) {
+		assert.Empty(t, expandMegaLinterResult(timedOut, t.TempDir(), start))
+	})
+}
@@ -0,0 +1,18 @@
+package lint
+
+import "github.com/serpro69/gh-arc/internal/runner"
+
+// LintOptions holds the flags and options for the lint command.
+type LintOptions struct {
+	Fix      bool // Append runner fixArgs for runners with autoFix enabled
+	All      bool // Lint all files instead of only changed ones
+	JSONMode bool
+	Verbose  bool
+}
+
+// LintResult represents the outcome of a lint workflow execution.
+type LintResult struct {
+	*runner.ExecutionResult
+	ChangedFileCount int
+	AllMode          bool
+}
@@ -0,0 +1,291 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/runner"
+)
+
+// SkipReasonNoChangedFiles is reported when there is nothing to lint.
+const SkipReasonNoChangedFiles = "no changed files"
+
+// LintRepository defines git operations needed by the lint workflow.
+type LintRepository interface {
+	Path() string
+	GetDefaultBranch() (string, error)
+	GetMergeBase(ref1, ref2 string) (string, error)
+	GetFilesChanged(base, head string) ([]git.FileChange, error)
+}
+
+// LintWorkflow orchestrates the lint command: runner resolution,
+// changed-file detection, and execution via the runner engine.
+type LintWorkflow struct {
+	repo     LintRepository
+	executor runner.Executor
+	config   *config.Config
+	out      io.Writer
+	lookPath func(file string) (string, error)
+	now      func() time.Time
+}
+
+// NewLintWorkflow creates a new LintWorkflow.
+func NewLintWorkflow(repo LintRepository, executor runner.Executor, cfg *config.Config) *LintWorkflow {
+	return &LintWorkflow{
+		repo:     repo,
+		executor: executor,
+		config:   cfg,
+		out:      os.Stdout,
+		lookPath: exec.LookPath,
+		now:      time.Now,
+	}
+}
+
+// Configured reports whether Execute has anything to run: custom runners, or
+// MegaLinter as resolved for this invocation. A required MegaLinter that is
+// unavailable counts as configured, so Execute gets to report the error.
+func (w *LintWorkflow) Configured() bool {
+	if len(w.config.Lint.Runners) > 0 {
+		return true
+	}
+	launcher, err := w.resolveMegaLinter()
+	return launcher != nil || err != nil
+}
+
+// Execute runs the lint workflow.
+func (w *LintWorkflow) Execute(ctx context.Context, opts *LintOptions) (*LintResult, error) {
+	if opts == nil {
+		opts = &LintOptions{}
+	}
+
+	logger.Debug().
+		Bool("fix", opts.Fix).
+		Bool("all", opts.All).
+		Int("runners", len(w.config.Lint.Runners)).
+		Msg("Executing lint workflow")
+
+	// Step 1: Resolve runners
+	megaLinter, err := w.resolveMegaLinter()
+	if err != nil {
+		return nil, err
+	}
+	if len(w.config.Lint.Runners) == 0 && megaLinter == nil {
+		if !opts.JSONMode {
+			w.printNoRunnersGuidance()
+		}
+		return &LintResult{
+			ExecutionResult: &runner.ExecutionResult{Runners: []runner.RunResult{}, Success: true},
+			AllMode:         opts.All,
+		}, nil
+	}
+
+	// Step 2: Detect changed files
+	allMode := opts.All
+	var changedFiles []string
+	if !allMode {
+		files, fallbackToAll, err := w.detectChangedFiles(opts)
+		if err != nil {
+			return nil, err
+		}
+		allMode = fallbackToAll
+		changedFiles = files
+
+		if !allMode && len(changedFiles) == 0 {
+			if !opts.JSONMode {
+				fmt.Fprintln(w.out, "No changed files to lint")
+			}
+			return &LintResult{
+				ExecutionResult: &runner.ExecutionResult{
+					Runners:    []runner.RunResult{},
+					Success:    true,
+					SkipReason: SkipReasonNoChangedFiles,
+				},
+			}, nil
+		}
+	}
+
+	// Step 3: Build runner configs
+	configs, skipped, err := w.buildRunnerConfigs(opts, changedFiles, allMode)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.JSONMode {
+		for _, name := range skipped {
+			fmt.Fprintf(w.out, "⚠ %s: skipped (no changed files in its workingDir)\n", name)
+		}
+	}
+	if megaLinter != nil {
+		mlRunner, cleanup, err := w.buildMegaLinterRunner(megaLinter, w.repo.Path(), opts, changedFiles, allMode)
+		defer cleanup()
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, mlRunner)
+	}
+
+	// Step 4: Execute
+	execResult, err := w.executor.Run(ctx, configs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run linters: %w", err)
+	}
+	for _, name := range skipped {
+		execResult.Runners = append(execResult.Runners, runner.RunResult{
+			Name:   name,
+			Status: runner.StatusSkipped,
+		})
+	}
+
+	return &LintResult{
+		ExecutionResult:  execResult,
+		ChangedFileCount: len(changedFiles),
+		AllMode:          allMode,
+	}, nil
+}
+
+// detectChangedFiles returns the non-deleted files changed between the merge-base
+// with the default branch and HEAD. The returned bool is true when there is no
+// common ancestor with the default branch and all files should be linted instead.
+func (w *LintWorkflow) detectChangedFiles(opts *LintOptions) ([]string, bool, error) {
+	defaultBranch, err := w.repo.GetDefaultBranch()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to determine default branch: %w", err)
+	}
+
+	mergeBase, err := w.resolveMergeBase(defaultBranch)
+	if err != nil {
+		if errors.Is(err, git.ErrNoCommonAncestor) {
+			if !opts.JSONMode {
+				fmt.Fprintf(w.out, "⚠ No common ancestor with %s, linting all files\n", defaultBranch)
+			}
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	changes, err := w.repo.GetFilesChanged(mergeBase, "HEAD")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get changed files: %w", err)
+	}
+
+	files := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if change.IsDeleted {
+			continue
+		}
+		files = append(files, change.Path)
+	}
+
+	logger.Debug().
+		Str("mergeBase", mergeBase).
+		Int("changed", len(changes)).
+		Int("lintable", len(files)).
+		Msg("Detected changed files")
+
+	return files, false, nil
+}
+
+// resolveMergeBase computes the merge-base between HEAD and the default branch,
+// preferring the remote-tracking ref and falling back to the local branch.
+func (w *LintWorkflow) resolveMergeBase(defaultBranch string) (string, error) {
+	mergeBase, err := w.repo.GetMergeBase("origin/"+defaultBranch, "HEAD")
+	if err == nil || errors.Is(err, git.ErrNoCommonAncestor) {
+		return mergeBase, err
+	}
+
+	logger.Debug().
+		Err(err).
+		Str("defaultBranch", defaultBranch).
+		Msg("Remote default branch not resolvable, falling back to local branch")
+
+	mergeBase, err = w.repo.GetMergeBase(defaultBranch, "HEAD")
+	if err == nil || errors.Is(err, git.ErrNoCommonAncestor) {
+		return mergeBase, err
+	}
+
+	return "", fmt.Errorf("failed to resolve default branch '%s' (try 'git fetch origin'): %w", defaultBranch, err)
+}
+
+// buildRunnerConfigs maps lint runners to engine configs. Runners whose
+// workingDir contains none of the changed files are returned by name as skipped.
+func (w *LintWorkflow) buildRunnerConfigs(opts *LintOptions, changedFiles []string, allMode bool) ([]runner.RunnerConfig, []string, error) {
+	root := w.repo.Path()
+	configs := make([]runner.RunnerConfig, 0, len(w.config.Lint.Runners))
+	var skipped []string
+
+	for _, lr := range w.config.Lint.Runners {
+		rc := runner.RunnerConfig{
+			Name:       lr.Name,
+			Command:    lr.Command,
+			Args:       lr.Args,
+			WorkingDir: runner.ResolveWorkingDir(root, lr.WorkingDir),
+		}
+
+		timeout, err := runner.ParseTimeout(lr.Name, lr.Timeout)
+		if err != nil {
+			return nil, nil, err
+		}
+		rc.Timeout = timeout
+
+		if opts.Fix && lr.AutoFix && len(lr.FixArgs) > 0 {
+			rc.ExtraArgs = lr.FixArgs
+		}
+
+		if !allMode {
+			rc.FilePaths = filesRelativeTo(changedFiles, root, rc.WorkingDir)
+			if len(rc.FilePaths) == 0 {
+				logger.Debug().
+					Str("runner", lr.Name).
+					Str("workingDir", lr.WorkingDir).
+					Msg("No changed files in runner working directory, skipping")
+				skipped = append(skipped, lr.Name)
+				continue
+			}
+		}
+
+		configs = append(configs, rc)
+	}
+
+	return configs, skipped, nil
+}
+
+// filesRelativeTo converts repo-relative paths into paths relative to the
+// resolved workingDir, dropping files that fall outside of it.
+func filesRelativeTo(files []string, root, workingDir string) []string {
+	if workingDir == "" || filepath.Clean(workingDir) == filepath.Clean(root) {
+		return files
+	}
+
+	result := make([]string, 0, len(files))
+	for _, file := range files {
+		rel, err := filepath.Rel(workingDir, filepath.Join(root, filepath.FromSlash(file)))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		result = append(result, filepath.ToSlash(rel))
+	}
+	return result
+}
+
+func (w *LintWorkflow) printNoRunnersGuidance() {
+	fmt.Fprintln(w.out, "No lint runners configured.")
+	fmt.Fprintln(w.out)
+	fmt.Fprintln(w.out, "Add runners to .arc.json:")
+	fmt.Fprintln(w.out, `  "lint": {`)
+	fmt.Fprintln(w.out, `    "runners": [`)
+	fmt.Fprintln(w.out, `      { "name": "golangci-lint", "command": "golangci-lint", "args": ["run"] }`)
+	fmt.Fprintln(w.out, `    ]`)
+	fmt.Fprintln(w.out, `  }`)
+	fmt.Fprintln(w.out)
+	fmt.Fprintln(w.out, "Or enable MegaLinter (requires mega-linter-runner, Docker or Podman):")
+	fmt.Fprintln(w.out, `  "lint": { "megaLinter": { "enabled": "true" } }`)
+}
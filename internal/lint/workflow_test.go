@@ -0,0 +1,324 @@
+package lint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/runner"
+)
+
+type mockLintRepo struct {
+	path          string
+	defaultBranch string
+	mergeBases    map[string]string
+	mergeBaseErrs map[string]error
+	changes       []git.FileChange
+	changesErr    error
+	gotBase       string
+}
+
+func (m *mockLintRepo) Path() string { return m.path }
+
+func (m *mockLintRepo) GetDefaultBranch() (string, error) {
+	return m.defaultBranch, nil
+}
+
+func (m *mockLintRepo) GetMergeBase(ref1, ref2 string) (string, error) {
+	if err, ok := m.mergeBaseErrs[ref1]; ok {
+		return "", err
+	}
+	if sha, ok := m.mergeBases[ref1]; ok {
+		return sha, nil
+	}
+	return "", fmt.Errorf("failed to find merge-base: fatal: Not a valid object name %s", ref1)
+}
+
+func (m *mockLintRepo) GetFilesChanged(base, head string) ([]git.FileChange, error) {
+	m.gotBase = base
+	return m.changes, m.changesErr
+}
+
+type mockExecutor struct {
+	configs []runner.RunnerConfig
+	called  bool
+	result  *runner.ExecutionResult
+}
+
+func (m *mockExecutor) Run(ctx context.Context, configs []runner.RunnerConfig) (*runner.ExecutionResult, error) {
+	m.called = true
+	m.configs = configs
+	if m.result != nil {
+		return m.result, nil
+	}
+	return &runner.ExecutionResult{Runners: []runner.RunResult{}, Success: true}, nil
+}
+
+func defaultRepo() *mockLintRepo {
+	return &mockLintRepo{
+		defaultBranch: "main",
+		mergeBases:    map[string]string{"origin/main": "abc123"},
+		changes: []git.FileChange{
+			{Path: "main.go"},
+			{Path: "frontend/src/App.tsx"},
+			{Path: "removed.go", IsDeleted: true},
+		},
+	}
+}
+
+func configWithRunners(runners ...config.LintRunner) *config.Config {
+	return &config.Config{Lint: config.LintConfig{Runners: runners}}
+}
+
+func newTestWorkflow(repo LintRepository, executor runner.Executor, cfg *config.Config) (*LintWorkflow, *bytes.Buffer) {
+	w := NewLintWorkflow(repo, executor, cfg)
+	buf := &bytes.Buffer{}
+	w.out = buf
+	w.lookPath = func(file string) (string, error) {
+		return "", fmt.Errorf("%s: not found", file)
+	}
+	return w, buf
+}
+
+func TestLintWorkflow_NoRunners(t *testing.T) {
+	t.Run("prints guidance", func(t *testing.T) {
+		exec := &mockExecutor{}
+		w, out := newTestWorkflow(defaultRepo(), exec, &config.Config{})
+
+		result, err := w.Execute(context.Background(), &LintOptions{})
+		require.NoError(t, err)
+
+		assert.True(t, result.Success)
+		assert.Empty(t, result.Runners)
+		assert.False(t, exec.called)
+		assert.Contains(t, out.String(), "No lint runners configured")
+	})
+
+	t.Run("JSON mode suppresses guidance", func(t *testing.T) {
+		w, out := newTestWorkflow(defaultRepo(), &mockExecutor{}, &config.Config{})
+
+		result, err := w.Execute(context.Background(), &LintOptions{JSONMode: true})
+		require.NoError(t, err)
+
+		assert.True(t, result.Success)
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestLintWorkflow_ChangedFiles(t *testing.T) {
+	repo := defaultRepo()
+	exec := &mockExecutor{}
+	w, _ := newTestWorkflow(repo, exec, configWithRunners(
+		config.LintRunner{Name: "golangci-lint", Command: "golangci-lint", Args: []string{"run"}},
+	))
+
+	result, err := w.Execute(context.Background(), &LintOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "abc123", repo.gotBase)
+	require.Len(t, exec.configs, 1)
+	assert.Equal(t, []string{"main.go", "frontend/src/App.tsx"}, exec.configs[0].FilePaths)
+	assert.Equal(t, 2, result.ChangedFileCount)
+	assert.False(t, result.AllMode)
+}
+
+func TestLintWorkflow_AllMode(t *testing.T) {
+	repo := defaultRepo()
+	exec := &mockExecutor{}
+	w, _ := newTestWorkflow(repo, exec, configWithRunners(
+		config.LintRunner{Name: "golangci-lint", Command: "golangci-lint"},
+	))
+
+	result, err := w.Execute(context.Background(), &LintOptions{All: true})
+	require.NoError(t, err)
+
+	require.Len(t, exec.configs, 1)
+	assert.Empty(t, exec.configs[0].FilePaths)
+	assert.Empty(t, repo.gotBase, "changed-file detection should be skipped")
+	assert.True(t, result.AllMode)
+}
+
+func TestLintWorkflow_Fix(t *testing.T) {
+	exec := &mockExecutor{}
+	w, _ := newTestWorkflow(defaultRepo(), exec, configWithRunners(
+		config.LintRunner{Name: "fixer", Command: "golangci-lint", FixArgs: []string{"--fix"}, AutoFix: true},
+		config.LintRunner{Name: "no-autofix", Command: "eslint", FixArgs: []string{"--fix"}, AutoFix: false},
+	))
+
+	_, err := w.Execute(context.Background(), &LintOptions{Fix: true})
+	require.NoError(t, err)
+
+	require.Len(t, exec.configs, 2)
+	assert.Equal(t, []string{"--fix"}, exec.configs[0].ExtraArgs)
+	assert.Empty(t, exec.configs[1].ExtraArgs)
+}
+
+func TestLintWorkflow_FixNotRequested(t *testing.T) {
+	exec := &mockExecutor{}
+	w, _ := newTestWorkflow(defaultRepo(), exec, configWithRunners(
+		config.LintRunner{Name: "fixer", Command: "golangci-lint", FixArgs: []string{"--fix"}, AutoFix: true},
+	))
+
+	_, err := w.Execute(context.Background(), &LintOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, exec.configs, 1)
+	assert.Empty(t, exec.configs[0].ExtraArgs)
+}
+
+func TestLintWorkflow_NoChangedFiles(t *testing.T) {
+	repo := defaultRepo()
+	repo.changes = []git.FileChange{{Path: "gone.go", IsDeleted: true}}
+	exec := &mockExecutor{}
+	w, out := newTestWorkflow(repo, exec, configWithRunners(
+		config.LintRunner{Name: "golangci-lint", Command: "golangci-lint"},
+	))
+
+	result, err := w.Execute(context.Background(), &LintOptions{})
+	require.NoError(t, err)
+
+	assert.False(t, exec.called)
+	assert.True(t, result.Success)
+	assert.Equal(t, SkipReasonNoChangedFiles, result.SkipReason)
+	assert.Contains(t, out.String(), "No changed files to lint")
+}
+
+func TestLintWorkflow_MergeBaseFallbackToLocal(t *testing.T) {
+	repo := defaultRepo()
+	repo.mergeBases = map[string]string{"main": "local123"}
+	exec := &mockExecutor{}
+	w, _ := newTestWorkflow(repo, exec, configWithRunners(
+		config.LintRunner{Name: "golangci-lint", Command: "golangci-lint"},
+	))
+
+	_, err := w.Execute(context.Background(), &LintOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "local123", repo.gotBase)
+}
+
+func TestLintWorkflow_MergeBaseUnresolvable(t *testing.T) {
+	repo := defaultRepo()
+	repo.mergeBases = map[string]string{}
+	exec := &mockExecutor{}
+	w, _ := newTestWorkflow(repo, exec, configWithRunners(
+		config.LintRunner{Name: "golangci-lint", Command: "golangci-lint"},
+	))
+
+	_, err := w.Execute(context.Background(), &LintOptions{})
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "git fetch origin")
+	assert.False(t, exec.called)
+}
+
+func TestLintWorkflow_NoCommonAncestorFallsBackToAll(t *testing.T) {
+	repo := defaultRepo()
+	repo.mergeBaseErrs = map[string]error{
+		"origin/main": fmt.Errorf("%w between origin/main and HEAD", git.ErrNoCommonAncestor),
+	}
+	exec := &mockExecutor{}
+	w, out := newTestWorkflow(repo, exec, configWithRunners(
+		config.LintRunner{Name: "golangci-lint", Command: "golangci-lint"},
+	))
+
+	result, err := w.Execute(context.Background(), &LintOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, exec.configs, 1)
+	assert.Empty(t, exec.configs[0].FilePaths)
+	assert.True(t, result.AllMode)
+	assert.Contains(t, out.String(), "⚠ No common ancestor")
+}
+
+func TestLintWorkflow_Timeout(t *testing.T) {
+	t.Run("valid timeout", func(t *testing.T) {
+		exec := &mockExecutor{}
+		w, _ := newTestWorkflow(defaultRepo(), exec, configWithRunners(
+			config.LintRunner{Name: "golangci-lint", Command: "golangci-lint", Timeout: "90s"},
+		))
+
+		_, err := w.Execute(context.Background(), &LintOptions{})
+		require.NoError(t, err)
+
+		require.Len(t, exec.configs, 1)
+		assert.Equal(t, 90*time.Second, exec.configs[0].Timeout)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		exec := &mockExecutor{}
+		w, _ := newTestWorkflow(defaultRepo(), exec, configWithRunners(
+			config.LintRunner{Name: "golangci-lint", Command: "golangci-lint", Timeout: "5min"},
+		))
+
+		_, err := w.Execute(context.Background(), &LintOptions{})
+		require.Error(t, err)
+
+		assert.Contains(t, err.Error(), "invalid timeout '5min' for runner 'golangci-lint'")
+		assert.False(t, exec.called)
+	})
+}
+
+func TestLintWorkflow_WorkingDirNormalization(t *testing.T) {
+	repo := defaultRepo()
+	repo.path = "/repo"
+	exec := &mockExecutor{}
+	w, out := newTestWorkflow(repo, exec, configWithRunners(
+		config.LintRunner{Name: "eslint", Command: "eslint", WorkingDir: "frontend/"},
+		config.LintRunner{Name: "docs", Command: "markdownlint", WorkingDir: "docs"},
+	))
+
+	result, err := w.Execute(context.Background(), &LintOptions{})
+	require.NoError(t, err)
+
+	// File inside workingDir is adjusted, file outside is dropped
+	require.Len(t, exec.configs, 1)
+	assert.Equal(t, "/repo/frontend", exec.configs[0].WorkingDir)
+	assert.Equal(t, []string{"src/App.tsx"}, exec.configs[0].FilePaths)
+
+	// Runner with no files in its workingDir is reported as skipped
+	require.Len(t, result.Runners, 1)
+	assert.Equal(t, "docs", result.Runners[0].Name)
+	assert.Equal(t, runner.StatusSkipped, result.Runners[0].Status)
+	assert.Contains(t, out.String(), "docs: skipped")
+}
+
+func TestLintWorkflow_AbsoluteWorkingDir(t *testing.T) {
+	repo := defaultRepo()
+	repo.path = "/repo"
+	exec := &mockExecutor{}
+	w, _ := newTestWorkflow(repo, exec, configWithRunners(
+		config.LintRunner{Name: "eslint", Command: "eslint", WorkingDir: "/repo/frontend"},
+	))
+
+	result, err := w.Execute(context.Background(), &LintOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, exec.configs, 1)
+	assert.Equal(t, "/repo/frontend", exec.configs[0].WorkingDir)
+	assert.Equal(t, []string{"src/App.tsx"}, exec.configs[0].FilePaths)
+	for _, r := range result.Runners {
+		assert.NotEqual(t, runner.StatusSkipped, r.Status, r.Name)
+	}
+}
+
+func TestLintWorkflow_ChangedFilesError(t *testing.T) {
+	repo := defaultRepo()
+	repo.changesErr = errors.New("boom")
+	w, _ := newTestWorkflow(repo, &mockExecutor{}, configWithRunners(
+		config.LintRunner{Name: "golangci-lint", Command: "golangci-lint"},
+	))
+
+	_, err := w.Execute(context.Background(), &LintOptions{})
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "failed to get changed files"))
+}
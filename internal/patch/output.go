@@ -0,0 +1,59 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// PrintResult writes a summary of the patched pull requests.
+func PrintResult(out io.Writer, result *PatchResult) {
+	for _, p := range result.Patched {
+		source := p.RemoteRef
+		if p.Fork != "" {
+			source = fmt.Sprintf("%s, fork %s", p.RemoteRef, p.Fork)
+		}
+
+		switch p.Action {
+		case ActionCreated:
+			fmt.Fprintf(out, "✓ PR #%d %q: created branch %s tracking %s\n", p.Number, p.Title, p.Branch, source)
+		case ActionUpdated:
+			fmt.Fprintf(out, "✓ PR #%d %q: fast-forwarded %s to %s\n", p.Number, p.Title, p.Branch, shortSHA(p.HeadSHA))
+		case ActionUpToDate:
+			fmt.Fprintf(out, "✓ PR #%d %q: %s is up to date\n", p.Number, p.Title, p.Branch)
+		case ActionApplied:
+			fmt.Fprintf(out, "✓ PR #%d %q: applied onto current branch (from %s)\n", p.Number, p.Title, source)
+		case ActionEmpty:
+			fmt.Fprintf(out, "⚠ PR #%d %q: no changes to apply\n", p.Number, p.Title)
+		}
+	}
+
+	if result.ParentsSkipped {
+		if result.Applied {
+			fmt.Fprintln(out, "⚠ Stacked parent PRs were not applied; the result is missing their changes")
+		} else {
+			fmt.Fprintln(out, "⚠ Stacked parent PRs were not checked out as local branches")
+		}
+	}
+
+	if len(result.Patched) == 0 {
+		return
+	}
+	if result.Applied {
+		fmt.Fprintln(out, "\nChanges are staged but not committed. Review with 'git diff --cached'.")
+	} else {
+		fmt.Fprintf(out, "\nNow on branch %s\n", result.Patched[len(result.Patched)-1].Branch)
+	}
+}
+
+// FormatJSON returns the result as indented JSON.
+func FormatJSON(result *PatchResult) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,63 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintResult(t *testing.T) {
+	t.Run("checkout", func(t *testing.T) {
+		result := &PatchResult{
+			Patched: []PatchedPR{
+				{Number: 1, Title: "Base", Action: ActionUpToDate, Branch: "feature-a"},
+				{Number: 2, Title: "Fix", Action: ActionCreated, Branch: "contrib-fix", RemoteRef: "contrib/fix", Fork: "contrib/repo"},
+			},
+		}
+
+		var buf bytes.Buffer
+		PrintResult(&buf, result)
+		out := buf.String()
+
+		assert.Contains(t, out, `✓ PR #1 "Base": feature-a is up to date`)
+		assert.Contains(t, out, `✓ PR #2 "Fix": created branch contrib-fix tracking contrib/fix, fork contrib/repo`)
+		assert.Contains(t, out, "Now on branch contrib-fix")
+	})
+
+	t.Run("apply with skipped parents", func(t *testing.T) {
+		result := &PatchResult{
+			Applied:        true,
+			ParentsSkipped: true,
+			Patched:        []PatchedPR{{Number: 3, Title: "Top", Action: ActionApplied, RemoteRef: "origin/top"}},
+		}
+
+		var buf bytes.Buffer
+		PrintResult(&buf, result)
+		out := buf.String()
+
+		assert.Contains(t, out, `✓ PR #3 "Top": applied onto current branch (from origin/top)`)
+		assert.Contains(t, out, "missing their changes")
+		assert.Contains(t, out, "staged but not committed")
+	})
+
+	t.Run("fast-forward shows short sha", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintResult(&buf, &PatchResult{Patched: []PatchedPR{
+			{Number: 4, Title: "Up", Action: ActionUpdated, Branch: "up", HeadSHA: "0123456789abcdef"},
+		}})
+		assert.Contains(t, buf.String(), "fast-forwarded up to 0123456")
+	})
+}
+
+func TestFormatJSON(t *testing.T) {
+	data, err := FormatJSON(&PatchResult{Patched: []PatchedPR{{Number: 1, Action: ActionCreated, Branch: "a"}}})
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, false, decoded["applied"])
+	assert.Len(t, decoded["patched"], 1)
+}
@@ -0,0 +1,72 @@
+package patch
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ParsePRRef parses a PR reference given as a number ("123", "#123") or a
+// pull request URL ("https://github.com/owner/repo/pull/123/files"). URLs
+// must point at the owner/repo repository.
+func ParsePRRef(ref, owner, repo string) (int, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return 0, fmt.Errorf("%w: empty reference", ErrInvalidPRRef)
+	}
+
+	if !strings.Contains(ref, "/") {
+		number, err := strconv.Atoi(strings.TrimPrefix(ref, "#"))
+		if err != nil || number <= 0 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidPRRef, ref)
+		}
+		return number, nil
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil || u.Host == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidPRRef, ref)
+	}
+
+	// /owner/repo/pull/123[/files|/commits|...]
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || parts[2] != "pull" {
+		return 0, fmt.Errorf("%w: %q is not a pull request URL", ErrInvalidPRRef, ref)
+	}
+
+	number, err := strconv.Atoi(parts[3])
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidPRRef, ref)
+	}
+
+	if !strings.EqualFold(parts[0], owner) || !strings.EqualFold(parts[1], repo) {
+		return 0, fmt.Errorf("%w: %s/%s is not %s/%s", ErrRepoMismatch, parts[0], parts[1], owner, repo)
+	}
+
+	return number, nil
+}
+
+// forkRemoteURL derives the URL of a fork from the origin URL by swapping the
+// owner/name path, so the fork is fetched over the same protocol and host.
+func forkRemoteURL(originURL, fullName string) string {
+	trimmed := strings.TrimSuffix(strings.TrimSuffix(originURL, "/"), ".git")
+	if i := strings.LastIndex(trimmed, "/"); i > 0 {
+		if j := strings.LastIndexAny(trimmed[:i], "/:"); j >= 0 {
+			return trimmed[:j+1] + fullName + ".git"
+		}
+	}
+	return "https://github.com/" + fullName + ".git"
+}
+
+// remoteRepoName returns the owner/name path of a remote URL, or "" when the
+// URL has no such path. It accepts the same forms as forkRemoteURL.
+func remoteRepoName(remoteURL string) string {
+	trimmed := strings.TrimSuffix(strings.TrimSuffix(remoteURL, "/"), ".git")
+	if i := strings.LastIndex(trimmed, "/"); i > 0 {
+		if j := strings.LastIndexAny(trimmed[:i], "/:"); j >= 0 {
+			return trimmed[j+1:]
+		}
+	}
+	return ""
+}
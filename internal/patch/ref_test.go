@@ -0,0 +1,70 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePRRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     string
+		want    int
+		wantErr error
+	}{
+		{name: "number", ref: "42", want: 42},
+		{name: "hash number", ref: "#42", want: 42},
+		{name: "url", ref: "https://github.com/owner/repo/pull/42", want: 42},
+		{name: "url with tab", ref: "https://github.com/Owner/Repo/pull/42/files", want: 42},
+		{name: "enterprise url", ref: "https://ghe.example.com/owner/repo/pull/7", want: 7},
+		{name: "empty", ref: " ", wantErr: ErrInvalidPRRef},
+		{name: "not a number", ref: "abc", wantErr: ErrInvalidPRRef},
+		{name: "zero", ref: "0", wantErr: ErrInvalidPRRef},
+		{name: "issue url", ref: "https://github.com/owner/repo/issues/42", wantErr: ErrInvalidPRRef},
+		{name: "other repo", ref: "https://github.com/other/repo/pull/42", wantErr: ErrRepoMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePRRef(tt.ref, "owner", "repo")
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestForkRemoteURL(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/owner/repo.git":     "https://github.com/contrib/repo.git",
+		"https://github.com/owner/repo":         "https://github.com/contrib/repo.git",
+		"git@github.com:owner/repo.git":         "git@github.com:contrib/repo.git",
+		"ssh://git@ghe.example.com/owner/repo/": "ssh://git@ghe.example.com/contrib/repo.git",
+		"":                                      "https://github.com/contrib/repo.git",
+	}
+
+	for origin, want := range tests {
+		assert.Equal(t, want, forkRemoteURL(origin, "contrib/repo"), origin)
+	}
+}
+
+func TestRemoteRepoName(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/contrib/repo.git":     "contrib/repo",
+		"git@github.com:contrib/repo.git":         "contrib/repo",
+		"ssh://git@ghe.example.com/contrib/repo/": "contrib/repo",
+		"/srv/git/repo":                           "git/repo",
+		"":                                        "",
+	}
+
+	for remoteURL, want := range tests {
+		assert.Equal(t, want, remoteRepoName(remoteURL), remoteURL)
+	}
+}
@@ -0,0 +1,41 @@
+package patch
+
+import "github.com/serpro69/gh-arc/internal/github"
+
+// Patch actions reported per PR
+const (
+	ActionCreated  = "created"    // New local tracking branch
+	ActionUpdated  = "updated"    // Existing local branch fast-forwarded
+	ActionUpToDate = "up-to-date" // Existing local branch already at the PR head
+	ActionApplied  = "applied"    // Diff applied onto the current branch
+	ActionEmpty    = "empty"      // PR has no changes to apply
+)
+
+// PatchOptions contains options for the patch command
+type PatchOptions struct {
+	Ref     string // PR number, #number, or PR URL
+	Apply   bool   // Apply the diff onto the current branch instead of checking out a branch
+	Branch  string // Local branch name for the requested PR; empty derives it from the PR head
+	Stack   bool   // Patch stacked parent PRs too, without prompting
+	NoStack bool   // Never patch stacked parent PRs, without prompting
+}
+
+// PatchedPR records what was done for one pull request.
+type PatchedPR struct {
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	Action    string `json:"action"`
+	Branch    string `json:"branch,omitempty"`     // Local branch (checkout mode)
+	RemoteRef string `json:"remote_ref,omitempty"` // Fetched remote-tracking ref
+	HeadSHA   string `json:"head_sha"`
+	Fork      string `json:"fork,omitempty"` // owner/name of the head repository when it is a fork
+}
+
+// PatchResult contains the outcome of the patch workflow.
+type PatchResult struct {
+	PR             *github.PullRequest   `json:"-"`
+	Parents        []*github.PullRequest `json:"-"`       // Stacked parent PRs, closest to trunk first
+	Patched        []PatchedPR           `json:"patched"` // In the order they were patched
+	Applied        bool                  `json:"applied"` // True in --apply mode
+	ParentsSkipped bool                  `json:"parents_skipped,omitempty"`
+}
@@ -0,0 +1,428 @@
+package patch
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+// maxStackDepth bounds the parent walk so a base cycle can't loop forever.
+const maxStackDepth = 20
+
+var (
+	// ErrInvalidPRRef is returned when the PR argument is not a number or PR URL.
+	ErrInvalidPRRef = errors.New("invalid pull request reference")
+
+	// ErrRepoMismatch is returned when a PR URL points at another repository.
+	ErrRepoMismatch = errors.New("pull request belongs to a different repository")
+
+	// ErrDirtyWorkingDir is returned when the working directory has uncommitted changes.
+	ErrDirtyWorkingDir = errors.New("working directory has uncommitted changes")
+
+	// ErrBranchDiverged is returned when a local branch with the target name
+	// exists and can't be fast-forwarded to the PR head.
+	ErrBranchDiverged = errors.New("local branch has diverged from the pull request")
+
+	// ErrApplyFailed is returned when a PR diff doesn't apply onto the current branch.
+	ErrApplyFailed = errors.New("failed to apply pull request diff")
+
+	// ErrForkRemoteMismatch is returned when the remote named after a fork's
+	// owner points at a different repository than the PR's head.
+	ErrForkRemoteMismatch = errors.New("remote points at a different repository")
+)
+
+// PatchRepository defines git operations needed by the patch workflow.
+type PatchRepository interface {
+	GetWorkingDirectoryStatus() (*git.WorkingDirectoryStatus, error)
+	GetDefaultBranch() (string, error)
+	GetGitConfig(key string) (string, error)
+	AddRemote(name, url string) error
+	Fetch(ctx context.Context, remote string, refspecs ...string) error
+	BranchExists(branchName string) (bool, error)
+	GetBranchSHA(branch string) (string, error)
+	IsAncestor(ancestorRef, descendantRef string) (bool, error)
+	CheckoutTrackingBranch(branchName, remoteBranch string) error
+	CheckoutBranch(branch string) error
+	FastForward(ref string) error
+	GetMergeBase(ref1, ref2 string) (string, error)
+	GetBinaryDiff(base, head string) (string, error)
+	ApplyPatch(patch string) error
+}
+
+// PatchGitHubClient defines GitHub operations needed by the patch workflow.
+type PatchGitHubClient interface {
+	GetPullRequest(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error)
+	FindExistingPR(ctx context.Context, owner, repo, branchName string) (*github.PullRequest, error)
+}
+
+// PatchWorkflow brings another author's pull request into the local working
+// copy, either as a tracking branch or as a diff applied onto HEAD.
+type PatchWorkflow struct {
+	repo       PatchRepository
+	client     PatchGitHubClient
+	owner      string
+	name       string
+	out        io.Writer
+	stdin      io.Reader
+	isTerminal func() bool
+}
+
+// NewPatchWorkflow creates a new PatchWorkflow.
+func NewPatchWorkflow(repo PatchRepository, client PatchGitHubClient, owner, name string) *PatchWorkflow {
+	return &PatchWorkflow{
+		repo:       repo,
+		client:     client,
+		owner:      owner,
+		name:       name,
+		out:        os.Stdout,
+		stdin:      os.Stdin,
+		isTerminal: func() bool { return term.IsTerminal(int(os.Stdin.Fd())) },
+	}
+}
+
+// Execute runs the patch workflow.
+func (w *PatchWorkflow) Execute(ctx context.Context, opts *PatchOptions) (*PatchResult, error) {
+	if opts == nil {
+		opts = &PatchOptions{}
+	}
+
+	logger.Debug().
+		Str("ref", opts.Ref).
+		Bool("apply", opts.Apply).
+		Str("branch", opts.Branch).
+		Msg("Executing patch workflow")
+
+	// Step 1: Resolve the PR reference
+	number, err := ParsePRRef(opts.Ref, w.owner, w.name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Refuse to touch a dirty tree
+	status, err := w.repo.GetWorkingDirectoryStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check working directory: %w", err)
+	}
+	if !status.IsClean {
+		return nil, ErrDirtyWorkingDir
+	}
+
+	// Step 3: Fetch PR metadata
+	pr, err := w.client.GetPullRequest(ctx, w.owner, w.name, number)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PatchResult{
+		PR:      pr,
+		Applied: opts.Apply,
+	}
+
+	// Step 4: Detect stacked parents and decide whether to include them
+	defaultBranch, err := w.repo.GetDefaultBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine default branch: %w", err)
+	}
+
+	result.Parents, err = w.findParents(ctx, pr, defaultBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []*github.PullRequest{pr}
+	if len(result.Parents) > 0 {
+		include, err := w.includeParents(pr, result.Parents, opts)
+		if err != nil {
+			return nil, err
+		}
+		if include {
+			targets = append(append([]*github.PullRequest{}, result.Parents...), pr)
+		} else {
+			result.ParentsSkipped = true
+		}
+	}
+
+	// Step 5: Patch bottom-up so each branch or diff lands on its parent
+	for _, target := range targets {
+		branch := ""
+		if target == pr {
+			branch = opts.Branch
+		}
+
+		var patched *PatchedPR
+		if opts.Apply {
+			patched, err = w.applyPR(ctx, target)
+		} else {
+			patched, err = w.checkoutPR(ctx, target, branch)
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Patched = append(result.Patched, *patched)
+	}
+
+	return result, nil
+}
+
+// findParents walks PR bases up to the default branch and returns the open
+// PRs the given PR is stacked on, closest to trunk first.
+func (w *PatchWorkflow) findParents(ctx context.Context, pr *github.PullRequest, defaultBranch string) ([]*github.PullRequest, error) {
+	var parents []*github.PullRequest
+	seen := map[int]bool{pr.Number: true}
+
+	base := pr.Base.Ref
+	for depth := 0; base != defaultBranch && depth < maxStackDepth; depth++ {
+		parent, err := w.client.FindExistingPR(ctx, w.owner, w.name, base)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up parent PR for %s: %w", base, err)
+		}
+		if parent == nil || seen[parent.Number] {
+			break
+		}
+		seen[parent.Number] = true
+		parents = append([]*github.PullRequest{parent}, parents...)
+		base = parent.Base.Ref
+	}
+
+	if len(parents) > 0 {
+		logger.Debug().
+			Int("pr", pr.Number).
+			Int("parents", len(parents)).
+			Msg("Detected stacked pull request")
+	}
+	return parents, nil
+}
+
+// includeParents decides whether stacked parents are patched too, prompting
+// when neither --stack nor --no-stack was given.
+func (w *PatchWorkflow) includeParents(pr *github.PullRequest, parents []*github.PullRequest, opts *PatchOptions) (bool, error) {
+	if opts.Stack {
+		return true, nil
+	}
+	if opts.NoStack {
+		return false, nil
+	}
+
+	numbers := make([]string, 0, len(parents))
+	for _, p := range parents {
+		numbers = append(numbers, fmt.Sprintf("#%d", p.Number))
+	}
+	fmt.Fprintf(w.out, "⚠ PR #%d is stacked on %s\n", pr.Number, strings.Join(numbers, " → "))
+
+	if !w.isTerminal() {
+		fmt.Fprintln(w.out, "  Non-interactive environment — patching only the requested PR (use --stack to include parents)")
+		return false, nil
+	}
+
+	fmt.Fprint(w.out, "  Patch parent PRs too? [y/N] ")
+	reader := bufio.NewReader(w.stdin)
+	line, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(strings.ToLower(line)) == "y", nil
+}
+
+// checkoutPR fetches the PR head and checks it out as a local tracking
+// branch. An existing branch is fast-forwarded if it hasn't diverged.
+func (w *PatchWorkflow) checkoutPR(ctx context.Context, pr *github.PullRequest, branch string) (*PatchedPR, error) {
+	remoteRef, fork, err := w.fetchHead(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	if branch == "" {
+		branch = localBranchName(pr, fork)
+	}
+
+	patched := &PatchedPR{
+		Number:    pr.Number,
+		Title:     pr.Title,
+		Branch:    branch,
+		RemoteRef: remoteRef,
+		HeadSHA:   pr.Head.SHA,
+		Fork:      fork,
+	}
+
+	exists, err := w.repo.BranchExists(branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check branch %s: %w", branch, err)
+	}
+	if !exists {
+		if err := w.repo.CheckoutTrackingBranch(branch, remoteRef); err != nil {
+			return nil, err
+		}
+		patched.Action = ActionCreated
+		return patched, nil
+	}
+
+	localSHA, err := w.repo.GetBranchSHA(branch)
+	if err != nil {
+		return nil, err
+	}
+	remoteSHA, err := w.repo.GetBranchSHA(remoteRef)
+	if err != nil {
+		return nil, err
+	}
+	patched.HeadSHA = remoteSHA
+
+	if localSHA == remoteSHA {
+		if err := w.repo.CheckoutBranch(branch); err != nil {
+			return nil, err
+		}
+		patched.Action = ActionUpToDate
+		return patched, nil
+	}
+
+	canFastForward, err := w.repo.IsAncestor(localSHA, remoteSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s with %s: %w", branch, remoteRef, err)
+	}
+	if !canFastForward {
+		return nil, fmt.Errorf("%w: %s (use --branch to check out PR #%d under another name)", ErrBranchDiverged, branch, pr.Number)
+	}
+
+	if err := w.repo.CheckoutBranch(branch); err != nil {
+		return nil, err
+	}
+	if err := w.repo.FastForward(remoteRef); err != nil {
+		return nil, err
+	}
+	patched.Action = ActionUpdated
+	return patched, nil
+}
+
+// applyPR applies the PR's changes, relative to its merge-base with the PR
+// base branch, onto the current branch without committing them.
+func (w *PatchWorkflow) applyPR(ctx context.Context, pr *github.PullRequest) (*PatchedPR, error) {
+	remoteRef, fork, err := w.fetchHead(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	baseRef := "origin/" + pr.Base.Ref
+	if err := w.repo.Fetch(ctx, "origin", trackingRefspec("origin", pr.Base.Ref)); err != nil {
+		return nil, fmt.Errorf("failed to fetch base branch %s: %w", pr.Base.Ref, err)
+	}
+
+	mergeBase, err := w.repo.GetMergeBase(baseRef, remoteRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge-base of PR #%d: %w", pr.Number, err)
+	}
+
+	diff, err := w.repo.GetBinaryDiff(mergeBase, remoteRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff of PR #%d: %w", pr.Number, err)
+	}
+
+	patched := &PatchedPR{
+		Number:    pr.Number,
+		Title:     pr.Title,
+		RemoteRef: remoteRef,
+		HeadSHA:   pr.Head.SHA,
+		Fork:      fork,
+		Action:    ActionApplied,
+	}
+
+	if strings.TrimSpace(diff) == "" {
+		patched.Action = ActionEmpty
+		return patched, nil
+	}
+
+	if err := w.repo.ApplyPatch(diff); err != nil {
+		return nil, fmt.Errorf("%w #%d: %v", ErrApplyFailed, pr.Number, err)
+	}
+	return patched, nil
+}
+
+// fetchHead fetches the PR head branch and returns its remote-tracking ref.
+// Heads in forks are fetched from a remote named after the fork owner, which
+// is added on first use. If the fork was deleted, the head is fetched from
+// the base repository's pull/<n>/head ref instead. fork is the head
+// repository's owner/name, or empty when the head lives in this repository.
+func (w *PatchWorkflow) fetchHead(ctx context.Context, pr *github.PullRequest) (remoteRef, fork string, err error) {
+	headRepo := pr.Head.Repo.FullName
+
+	switch {
+	case headRepo == "":
+		ref := fmt.Sprintf("origin/pr/%d", pr.Number)
+		refspec := fmt.Sprintf("+refs/pull/%d/head:refs/remotes/%s", pr.Number, ref)
+		if err := w.repo.Fetch(ctx, "origin", refspec); err != nil {
+			return "", "", fmt.Errorf("failed to fetch PR #%d: %w", pr.Number, err)
+		}
+		return ref, "", nil
+
+	case strings.EqualFold(headRepo, w.owner+"/"+w.name):
+		if err := w.repo.Fetch(ctx, "origin", trackingRefspec("origin", pr.Head.Ref)); err != nil {
+			return "", "", fmt.Errorf("failed to fetch PR #%d head %s: %w", pr.Number, pr.Head.Ref, err)
+		}
+		return "origin/" + pr.Head.Ref, "", nil
+
+	default:
+		remote, err := w.ensureForkRemote(pr.Head.Repo)
+		if err != nil {
+			return "", "", err
+		}
+		if err := w.repo.Fetch(ctx, remote, trackingRefspec(remote, pr.Head.Ref)); err != nil {
+			return "", "", fmt.Errorf("failed to fetch PR #%d head %s from %s: %w", pr.Number, pr.Head.Ref, headRepo, err)
+		}
+		return remote + "/" + pr.Head.Ref, headRepo, nil
+	}
+}
+
+// ensureForkRemote returns the name of the remote for a fork, adding it when
+// it doesn't exist yet. An existing remote of that name must point at the
+// fork, so the PR head is never fetched from an unrelated repository.
+func (w *PatchWorkflow) ensureForkRemote(repo github.PRRepository) (string, error) {
+	remote := repo.Owner.Login
+	if remote == "" {
+		remote = strings.SplitN(repo.FullName, "/", 2)[0]
+	}
+
+	if existing, err := w.repo.GetGitConfig("remote." + remote + ".url"); err == nil && existing != "" {
+		if !strings.EqualFold(remoteRepoName(existing), repo.FullName) {
+			return "", fmt.Errorf("%w: %s is %s, expected %s", ErrForkRemoteMismatch, remote, existing, repo.FullName)
+		}
+		return remote, nil
+	}
+
+	originURL, err := w.repo.GetGitConfig("remote.origin.url")
+	if err != nil {
+		originURL = ""
+	}
+	url := forkRemoteURL(originURL, repo.FullName)
+
+	if err := w.repo.AddRemote(remote, url); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(w.out, "✓ Added remote %s (%s)\n", remote, url)
+	return remote, nil
+}
+
+// localBranchName returns the default local branch name for a PR. Fork
+// branches are prefixed with the owner so they don't collide with (or shadow)
+// branches of the same name in this repository.
+func localBranchName(pr *github.PullRequest, fork string) string {
+	switch {
+	case pr.Head.Repo.FullName == "":
+		return fmt.Sprintf("pr-%d", pr.Number)
+	case fork != "":
+		return strings.SplitN(fork, "/", 2)[0] + "-" + pr.Head.Ref
+	default:
+		return pr.Head.Ref
+	}
+}
+
+func trackingRefspec(remote, branch string) string {
+	return fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", branch, remote, branch)
+}
@@ -0,0 +1,422 @@
+package patch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+type mockPatchRepo struct {
+	dirty       bool
+	config      map[string]string
+	remotes     map[string]string // added remotes
+	fetched     []string          // "remote refspec"
+	fetchErr    error
+	branches    map[string]string // branch/ref -> SHA
+	ancestors   map[string]bool   // "ancestor..descendant"
+	checkedOut  []string
+	tracking    map[string]string // local branch -> remote ref
+	fastForward []string
+	diffs       map[string]string // "base..head" -> diff
+	applied     []string
+	applyErr    error
+}
+
+func newMockPatchRepo() *mockPatchRepo {
+	return &mockPatchRepo{
+		config:   map[string]string{"remote.origin.url": "git@github.com:owner/repo.git"},
+		remotes:  map[string]string{},
+		branches: map[string]string{},
+		tracking: map[string]string{},
+		diffs:    map[string]string{},
+	}
+}
+
+func (m *mockPatchRepo) GetWorkingDirectoryStatus() (*git.WorkingDirectoryStatus, error) {
+	return &git.WorkingDirectoryStatus{IsClean: !m.dirty}, nil
+}
+
+func (m *mockPatchRepo) GetDefaultBranch() (string, error) { return "main", nil }
+
+func (m *mockPatchRepo) GetGitConfig(key string) (string, error) {
+	if v, ok := m.config[key]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("config key %s not found", key)
+}
+
+func (m *mockPatchRepo) AddRemote(name, url string) error {
+	m.remotes[name] = url
+	m.config["remote."+name+".url"] = url
+	return nil
+}
+
+func (m *mockPatchRepo) Fetch(ctx context.Context, remote string, refspecs ...string) error {
+	m.fetched = append(m.fetched, remote+" "+strings.Join(refspecs, " "))
+	return m.fetchErr
+}
+
+func (m *mockPatchRepo) BranchExists(branchName string) (bool, error) {
+	_, ok := m.branches[branchName]
+	return ok, nil
+}
+
+func (m *mockPatchRepo) GetBranchSHA(branch string) (string, error) {
+	if sha, ok := m.branches[branch]; ok {
+		return sha, nil
+	}
+	return "", fmt.Errorf("unknown ref %s", branch)
+}
+
+func (m *mockPatchRepo) IsAncestor(ancestorRef, descendantRef string) (bool, error) {
+	return m.ancestors[ancestorRef+".."+descendantRef], nil
+}
+
+func (m *mockPatchRepo) CheckoutTrackingBranch(branchName, remoteBranch string) error {
+	m.tracking[branchName] = remoteBranch
+	m.checkedOut = append(m.checkedOut, branchName)
+	return nil
+}
+
+func (m *mockPatchRepo) CheckoutBranch(branch string) error {
+	m.checkedOut = append(m.checkedOut, branch)
+	return nil
+}
+
+func (m *mockPatchRepo) FastForward(ref string) error {
+	m.fastForward = append(m.fastForward, ref)
+	return nil
+}
+
+func (m *mockPatchRepo) GetMergeBase(ref1, ref2 string) (string, error) {
+	return "mb:" + ref1, nil
+}
+
+func (m *mockPatchRepo) GetBinaryDiff(base, head string) (string, error) {
+	return m.diffs[base+".."+head], nil
+}
+
+func (m *mockPatchRepo) ApplyPatch(patch string) error {
+	if m.applyErr != nil {
+		return m.applyErr
+	}
+	m.applied = append(m.applied, patch)
+	return nil
+}
+
+type mockPatchClient struct {
+	prs      map[int]*github.PullRequest
+	byBranch map[string]*github.PullRequest
+}
+
+func (m *mockPatchClient) GetPullRequest(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error) {
+	if pr, ok := m.prs[number]; ok {
+		return pr, nil
+	}
+	return nil, fmt.Errorf("failed to fetch PR #%d: not found", number)
+}
+
+func (m *mockPatchClient) FindExistingPR(ctx context.Context, owner, repo, branchName string) (*github.PullRequest, error) {
+	return m.byBranch[branchName], nil
+}
+
+func samePR(number int, head, base string) *github.PullRequest {
+	return &github.PullRequest{
+		Number: number,
+		Title:  "PR " + head,
+		Head: github.PRBranch{Ref: head, SHA: "sha-" + head, Repo: github.PRRepository{
+			Name: "repo", FullName: "owner/repo", Owner: github.PRUser{Login: "owner"},
+		}},
+		Base: github.PRBranch{Ref: base},
+	}
+}
+
+// stackedClient returns a client with #3 (feature-c) → #2 (feature-b) → #1 (feature-a) → main.
+func stackedClient() *mockPatchClient {
+	a, b, c := samePR(1, "feature-a", "main"), samePR(2, "feature-b", "feature-a"), samePR(3, "feature-c", "feature-b")
+	return &mockPatchClient{
+		prs:      map[int]*github.PullRequest{1: a, 2: b, 3: c},
+		byBranch: map[string]*github.PullRequest{"feature-a": a, "feature-b": b, "feature-c": c},
+	}
+}
+
+func newTestWorkflow(repo *mockPatchRepo, client *mockPatchClient, stdin string, terminal bool) (*PatchWorkflow, *bytes.Buffer) {
+	var out bytes.Buffer
+	w := NewPatchWorkflow(repo, client, "owner", "repo")
+	w.out = &out
+	w.stdin = strings.NewReader(stdin)
+	w.isTerminal = func() bool { return terminal }
+	return w, &out
+}
+
+func TestPatchWorkflowCheckout(t *testing.T) {
+	t.Run("creates tracking branch", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		w, _ := newTestWorkflow(repo, stackedClient(), "", false)
+
+		result, err := w.Execute(context.Background(), &PatchOptions{Ref: "1"})
+		require.NoError(t, err)
+
+		require.Len(t, result.Patched, 1)
+		assert.Equal(t, ActionCreated, result.Patched[0].Action)
+		assert.Equal(t, "feature-a", result.Patched[0].Branch)
+		assert.Equal(t, "origin/feature-a", repo.tracking["feature-a"])
+		assert.Equal(t, []string{"origin +refs/heads/feature-a:refs/remotes/origin/feature-a"}, repo.fetched)
+		assert.Empty(t, result.Parents)
+	})
+
+	t.Run("fork head is fetched from an added remote", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		pr := samePR(5, "fix", "main")
+		pr.Head.Repo = github.PRRepository{Name: "repo", FullName: "contrib/repo", Owner: github.PRUser{Login: "contrib"}}
+		client := &mockPatchClient{prs: map[int]*github.PullRequest{5: pr}}
+		w, out := newTestWorkflow(repo, client, "", false)
+
+		result, err := w.Execute(context.Background(), &PatchOptions{Ref: "https://github.com/owner/repo/pull/5"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "git@github.com:contrib/repo.git", repo.remotes["contrib"])
+		assert.Equal(t, []string{"contrib +refs/heads/fix:refs/remotes/contrib/fix"}, repo.fetched)
+		assert.Equal(t, "contrib/fix", repo.tracking["contrib-fix"])
+		assert.Equal(t, "contrib/repo", result.Patched[0].Fork)
+		assert.Contains(t, out.String(), "Added remote contrib")
+	})
+
+	t.Run("existing fork remote is reused", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		repo.config["remote.contrib.url"] = "https://example.com/contrib/repo.git"
+		pr := samePR(5, "fix", "main")
+		pr.Head.Repo = github.PRRepository{FullName: "contrib/repo", Owner: github.PRUser{Login: "contrib"}}
+		w, _ := newTestWorkflow(repo, &mockPatchClient{prs: map[int]*github.PullRequest{5: pr}}, "", false)
+
+		_, err := w.Execute(context.Background(), &PatchOptions{Ref: "5"})
+		require.NoError(t, err)
+		assert.Empty(t, repo.remotes)
+	})
+
+	t.Run("existing remote for another repository is refused", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		repo.config["remote.contrib.url"] = "git@github.com:contrib/other.git"
+		pr := samePR(5, "fix", "main")
+		pr.Head.Repo = github.PRRepository{FullName: "contrib/repo", Owner: github.PRUser{Login: "contrib"}}
+		w, _ := newTestWorkflow(repo, &mockPatchClient{prs: map[int]*github.PullRequest{5: pr}}, "", false)
+
+		_, err := w.Execute(context.Background(), &PatchOptions{Ref: "5"})
+		assert.ErrorIs(t, err, ErrForkRemoteMismatch)
+		assert.Empty(t, repo.fetched)
+		assert.Empty(t, repo.tracking)
+	})
+
+	t.Run("deleted fork falls back to pull ref", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		pr := samePR(6, "fix", "main")
+		pr.Head.Repo = github.PRRepository{}
+		w, _ := newTestWorkflow(repo, &mockPatchClient{prs: map[int]*github.PullRequest{6: pr}}, "", false)
+
+		_, err := w.Execute(context.Background(), &PatchOptions{Ref: "6"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"origin +refs/pull/6/head:refs/remotes/origin/pr/6"}, repo.fetched)
+		assert.Equal(t, "origin/pr/6", repo.tracking["pr-6"])
+	})
+
+	t.Run("custom branch name", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		w, _ := newTestWorkflow(repo, stackedClient(), "", false)
+
+		_, err := w.Execute(context.Background(), &PatchOptions{Ref: "1", Branch: "review/a"})
+		require.NoError(t, err)
+		assert.Equal(t, "origin/feature-a", repo.tracking["review/a"])
+	})
+
+	t.Run("existing branch is fast-forwarded", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		repo.branches = map[string]string{"feature-a": "old", "origin/feature-a": "new"}
+		repo.ancestors = map[string]bool{"old..new": true}
+		w, _ := newTestWorkflow(repo, stackedClient(), "", false)
+
+		result, err := w.Execute(context.Background(), &PatchOptions{Ref: "1"})
+		require.NoError(t, err)
+		assert.Equal(t, ActionUpdated, result.Patched[0].Action)
+		assert.Equal(t, []string{"feature-a"}, repo.checkedOut)
+		assert.Equal(t, []string{"origin/feature-a"}, repo.fastForward)
+	})
+
+	t.Run("existing branch at head is up to date", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		repo.branches = map[string]string{"feature-a": "same", "origin/feature-a": "same"}
+		w, _ := newTestWorkflow(repo, stackedClient(), "", false)
+
+		result, err := w.Execute(context.Background(), &PatchOptions{Ref: "1"})
+		require.NoError(t, err)
+		assert.Equal(t, ActionUpToDate, result.Patched[0].Action)
+		assert.Empty(t, repo.fastForward)
+	})
+
+	t.Run("diverged branch is refused", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		repo.branches = map[string]string{"feature-a": "mine", "origin/feature-a": "theirs"}
+		w, _ := newTestWorkflow(repo, stackedClient(), "", false)
+
+		_, err := w.Execute(context.Background(), &PatchOptions{Ref: "1"})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrBranchDiverged))
+		assert.Empty(t, repo.checkedOut)
+	})
+
+	t.Run("dirty tree is refused", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		repo.dirty = true
+		w, _ := newTestWorkflow(repo, stackedClient(), "", false)
+
+		_, err := w.Execute(context.Background(), &PatchOptions{Ref: "1"})
+		assert.True(t, errors.Is(err, ErrDirtyWorkingDir))
+		assert.Empty(t, repo.fetched)
+	})
+
+	t.Run("invalid reference", func(t *testing.T) {
+		w, _ := newTestWorkflow(newMockPatchRepo(), stackedClient(), "", false)
+
+		_, err := w.Execute(context.Background(), &PatchOptions{Ref: "nope"})
+		assert.True(t, errors.Is(err, ErrInvalidPRRef))
+	})
+
+	t.Run("fetch failure", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		repo.fetchErr = errors.New("network down")
+		w, _ := newTestWorkflow(repo, stackedClient(), "", false)
+
+		_, err := w.Execute(context.Background(), &PatchOptions{Ref: "1"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "network down")
+	})
+}
+
+func TestPatchWorkflowStack(t *testing.T) {
+	t.Run("--stack patches parents bottom-up", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		w, _ := newTestWorkflow(repo, stackedClient(), "", false)
+
+		result, err := w.Execute(context.Background(), &PatchOptions{Ref: "3", Stack: true})
+		require.NoError(t, err)
+
+		require.Len(t, result.Parents, 2)
+		assert.Equal(t, 1, result.Parents[0].Number)
+		assert.Equal(t, 2, result.Parents[1].Number)
+		assert.Equal(t, []string{"feature-a", "feature-b", "feature-c"}, repo.checkedOut)
+		assert.False(t, result.ParentsSkipped)
+	})
+
+	t.Run("--no-stack skips parents", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		w, out := newTestWorkflow(repo, stackedClient(), "", true)
+
+		result, err := w.Execute(context.Background(), &PatchOptions{Ref: "3", NoStack: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"feature-c"}, repo.checkedOut)
+		assert.True(t, result.ParentsSkipped)
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("prompt accepted", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		w, out := newTestWorkflow(repo, stackedClient(), "y\n", true)
+
+		_, err := w.Execute(context.Background(), &PatchOptions{Ref: "2"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"feature-a", "feature-b"}, repo.checkedOut)
+		assert.Contains(t, out.String(), "PR #2 is stacked on #1")
+	})
+
+	t.Run("prompt declined", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		w, _ := newTestWorkflow(repo, stackedClient(), "n\n", true)
+
+		result, err := w.Execute(context.Background(), &PatchOptions{Ref: "3"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"feature-c"}, repo.checkedOut)
+		assert.True(t, result.ParentsSkipped)
+	})
+
+	t.Run("non-interactive skips parents", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		w, out := newTestWorkflow(repo, stackedClient(), "y\n", false)
+
+		_, err := w.Execute(context.Background(), &PatchOptions{Ref: "3"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"feature-c"}, repo.checkedOut)
+		assert.Contains(t, out.String(), "--stack")
+	})
+
+	t.Run("base cycle terminates", func(t *testing.T) {
+		a, b := samePR(1, "x", "y"), samePR(2, "y", "x")
+		client := &mockPatchClient{
+			prs:      map[int]*github.PullRequest{1: a, 2: b},
+			byBranch: map[string]*github.PullRequest{"x": a, "y": b},
+		}
+		w, _ := newTestWorkflow(newMockPatchRepo(), client, "", false)
+
+		result, err := w.Execute(context.Background(), &PatchOptions{Ref: "1", NoStack: true})
+		require.NoError(t, err)
+		require.Len(t, result.Parents, 1)
+		assert.Equal(t, 2, result.Parents[0].Number)
+	})
+}
+
+func TestPatchWorkflowApply(t *testing.T) {
+	t.Run("applies diff against merge-base with base", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		repo.diffs["mb:origin/main..origin/feature-a"] = "diff --git a/x b/x\n"
+		w, _ := newTestWorkflow(repo, stackedClient(), "", false)
+
+		result, err := w.Execute(context.Background(), &PatchOptions{Ref: "1", Apply: true})
+		require.NoError(t, err)
+
+		assert.True(t, result.Applied)
+		assert.Equal(t, ActionApplied, result.Patched[0].Action)
+		assert.Equal(t, []string{"diff --git a/x b/x\n"}, repo.applied)
+		assert.Contains(t, repo.fetched, "origin +refs/heads/main:refs/remotes/origin/main")
+		assert.Empty(t, repo.checkedOut)
+	})
+
+	t.Run("stacked apply applies parents first", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		repo.diffs["mb:origin/main..origin/feature-a"] = "A"
+		repo.diffs["mb:origin/feature-a..origin/feature-b"] = "B"
+		w, _ := newTestWorkflow(repo, stackedClient(), "", false)
+
+		_, err := w.Execute(context.Background(), &PatchOptions{Ref: "2", Apply: true, Stack: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"A", "B"}, repo.applied)
+	})
+
+	t.Run("empty diff", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		w, _ := newTestWorkflow(repo, stackedClient(), "", false)
+
+		result, err := w.Execute(context.Background(), &PatchOptions{Ref: "1", Apply: true})
+		require.NoError(t, err)
+		assert.Equal(t, ActionEmpty, result.Patched[0].Action)
+		assert.Empty(t, repo.applied)
+	})
+
+	t.Run("apply conflict", func(t *testing.T) {
+		repo := newMockPatchRepo()
+		repo.diffs["mb:origin/main..origin/feature-a"] = "A"
+		repo.applyErr = errors.New("patch does not apply")
+		w, _ := newTestWorkflow(repo, stackedClient(), "", false)
+
+		_, err := w.Execute(context.Background(), &PatchOptions{Ref: "1", Apply: true})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrApplyFailed))
+		assert.Contains(t, err.Error(), "#1")
+	})
+}
@@ -0,0 +1,39 @@
+package runner
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// ParseTimeout parses the configured timeout of a runner. An empty value
+// means no timeout.
+func ParseTimeout(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout '%s' for runner '%s': use Go duration format like '5m' or '300s'", value, name)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("invalid timeout '%s' for runner '%s': must not be negative", value, name)
+	}
+	return timeout, nil
+}
+
+// ResolveWorkingDir anchors a runner's workingDir at the repository root so
+// runners behave the same regardless of the directory gh-arc is invoked from.
+func ResolveWorkingDir(root, workingDir string) string {
+	if root == "" {
+		return workingDir
+	}
+	if workingDir == "" {
+		return root
+	}
+	if filepath.IsAbs(workingDir) {
+		return workingDir
+	}
+	return filepath.Join(root, workingDir)
+}
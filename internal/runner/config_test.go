@@ -0,0 +1,36 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeout(t *testing.T) {
+	timeout, err := ParseTimeout("go-test", "5m")
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, timeout)
+
+	timeout, err = ParseTimeout("go-test", "")
+	require.NoError(t, err)
+	assert.Zero(t, timeout)
+
+	_, err = ParseTimeout("go-test", "5min")
+	assert.EqualError(t, err, "invalid timeout '5min' for runner 'go-test': use Go duration format like '5m' or '300s'")
+
+	_, err = ParseTimeout("go-test", "-1s")
+	assert.EqualError(t, err, "invalid timeout '-1s' for runner 'go-test': must not be negative")
+}
+
+func TestResolveWorkingDir(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	abs := filepath.Join(t.TempDir(), "elsewhere")
+
+	assert.Equal(t, root, ResolveWorkingDir(root, ""))
+	assert.Equal(t, filepath.Join(root, "web"), ResolveWorkingDir(root, "web"))
+	assert.Equal(t, abs, ResolveWorkingDir(root, abs))
+	assert.Equal(t, "web", ResolveWorkingDir("", "web"))
+}
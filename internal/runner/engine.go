@@ -0,0 +1,189 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+// waitDelay bounds how long Run waits for I/O after a runner is killed.
+const waitDelay = 2 * time.Second
+
+// Engine executes runner configurations sequentially as subprocesses,
+// streaming their output and collecting per-runner results.
+type Engine struct {
+	opts EngineOptions
+}
+
+// NewEngine creates a new runner engine. Nil writers default to os.Stdout/os.Stderr.
+func NewEngine(opts EngineOptions) *Engine {
+	if opts.Stdout == nil {
+		opts.Stdout = os.Stdout
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+	return &Engine{opts: opts}
+}
+
+// Run executes all configs in order. Every runner is executed regardless of
+// individual failures so a single invocation reports everything that's wrong.
+func (e *Engine) Run(ctx context.Context, configs []RunnerConfig) (*ExecutionResult, error) {
+	result := &ExecutionResult{
+		Runners: []RunResult{},
+		Success: true,
+	}
+
+	if len(configs) == 0 {
+		return result, nil
+	}
+
+	for _, cfg := range configs {
+		if err := ctx.Err(); err != nil {
+			return result, fmt.Errorf("runner execution interrupted: %w", err)
+		}
+
+		if !e.opts.JSONMode {
+			PrintBanner(e.opts.Stdout, cfg.Name)
+		}
+
+		runResults := []RunResult{e.runOne(ctx, cfg)}
+		if cfg.ExpandResult != nil {
+			if expanded := cfg.ExpandResult(runResults[0]); len(expanded) > 0 {
+				runResults = expanded
+			}
+		}
+
+		if !e.opts.JSONMode {
+			fmt.Fprintln(e.opts.Stdout)
+		}
+		for _, runResult := range runResults {
+			result.Runners = append(result.Runners, runResult)
+			if runResult.IsFailure() {
+				result.Success = false
+			}
+			if !e.opts.JSONMode {
+				PrintResult(e.opts.Stdout, runResult)
+			}
+		}
+		if !e.opts.JSONMode {
+			fmt.Fprintln(e.opts.Stdout)
+		}
+	}
+
+	if !e.opts.JSONMode {
+		PrintSummary(e.opts.Stdout, result)
+	}
+
+	return result, nil
+}
+
+// runOne executes a single runner and classifies its outcome.
+func (e *Engine) runOne(ctx context.Context, cfg RunnerConfig) RunResult {
+	args := buildArgs(cfg)
+
+	runCtx := ctx
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, cfg.Command, args...)
+	// Don't block on output pipes held open by orphaned grandchildren after a kill
+	cmd.WaitDelay = waitDelay
+	configureProcessGroup(cmd)
+	if cfg.WorkingDir != "" {
+		cmd.Dir = cfg.WorkingDir
+	}
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	if e.opts.JSONMode {
+		cmd.Stdout = &stdoutBuf
+		cmd.Stderr = &stderrBuf
+	} else {
+		cmd.Stdout = e.opts.Stdout
+		cmd.Stderr = e.opts.Stderr
+	}
+
+	logger.Debug().
+		Str("runner", cfg.Name).
+		Str("command", cfg.Command).
+		Strs("args", args).
+		Str("workingDir", cfg.WorkingDir).
+		Dur("timeout", cfg.Timeout).
+		Msg("Executing runner")
+
+	start := time.Now()
+	err := cmd.Run()
+	duration := time.Since(start)
+
+	result := RunResult{
+		Name:     cfg.Name,
+		Duration: duration,
+	}
+
+	switch {
+	case err == nil:
+		result.Status = StatusPassed
+	case errors.Is(err, exec.ErrNotFound):
+		result.Status = StatusNotFound
+		result.ExitCode = -1
+		result.Err = fmt.Errorf("command not found: %s", cfg.Command)
+	case cfg.Timeout > 0 && errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		result.Status = StatusTimeout
+		result.ExitCode = -1
+		result.Err = fmt.Errorf("timed out after %s", cfg.Timeout)
+	default:
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.Status = StatusFailed
+			result.ExitCode = exitErr.ExitCode()
+		} else {
+			result.Status = StatusError
+			result.ExitCode = -1
+			result.Err = err
+		}
+	}
+
+	logger.Debug().
+		Str("runner", cfg.Name).
+		Str("status", string(result.Status)).
+		Int("exitCode", result.ExitCode).
+		Dur("duration", duration).
+		Msg("Runner finished")
+
+	if e.opts.JSONMode && e.opts.Verbose && result.Status != StatusPassed {
+		logCapturedOutput(cfg.Name, &stdoutBuf, &stderrBuf)
+	}
+
+	return result
+}
+
+// buildArgs assembles the full argument list: Args + ExtraArgs + FilePaths.
+func buildArgs(cfg RunnerConfig) []string {
+	args := make([]string, 0, len(cfg.Args)+len(cfg.ExtraArgs)+len(cfg.FilePaths))
+	args = append(args, cfg.Args...)
+	args = append(args, cfg.ExtraArgs...)
+	args = append(args, cfg.FilePaths...)
+	return args
+}
+
+// logCapturedOutput emits captured runner output at debug level so it is still
+// reachable in JSON mode (where stdout must contain only the JSON object).
+func logCapturedOutput(name string, stdout, stderr io.Reader) {
+	out, _ := io.ReadAll(stdout)
+	errOut, _ := io.ReadAll(stderr)
+	logger.Debug().
+		Str("runner", name).
+		Str("stdout", string(out)).
+		Str("stderr", string(errOut)).
+		Msg("Captured runner output")
+}
@@ -0,0 +1,323 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEngine(jsonMode bool) (*Engine, *bytes.Buffer, *bytes.Buffer) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	engine := NewEngine(EngineOptions{
+		JSONMode: jsonMode,
+		Stdout:   stdout,
+		Stderr:   stderr,
+	})
+	return engine, stdout, stderr
+}
+
+func shellRunner(name, script string) RunnerConfig {
+	return RunnerConfig{
+		Name:    name,
+		Command: "sh",
+		Args:    []string{"-c", script},
+	}
+}
+
+func TestEngine_Run_EmptyConfigs(t *testing.T) {
+	engine, stdout, _ := newTestEngine(false)
+
+	result, err := engine.Run(context.Background(), nil)
+	require.NoError(t, err)
+
+	assert.True(t, result.Success)
+	assert.Empty(t, result.Runners)
+	assert.Empty(t, stdout.String())
+}
+
+func TestEngine_Run_Passed(t *testing.T) {
+	engine, stdout, _ := newTestEngine(false)
+
+	result, err := engine.Run(context.Background(), []RunnerConfig{
+		shellRunner("ok", "echo hello"),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Runners, 1)
+	assert.True(t, result.Success)
+	assert.Equal(t, StatusPassed, result.Runners[0].Status)
+	assert.Equal(t, 0, result.Runners[0].ExitCode)
+
+	out := stdout.String()
+	assert.Contains(t, out, "▶ Running ok...")
+	assert.Contains(t, out, "hello")
+	assert.Contains(t, out, "✓ ok: passed")
+	assert.Contains(t, out, "✓ 1 runner passed")
+}
+
+func TestEngine_Run_Failed(t *testing.T) {
+	engine, stdout, _ := newTestEngine(false)
+
+	result, err := engine.Run(context.Background(), []RunnerConfig{
+		shellRunner("bad", "exit 3"),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Runners, 1)
+	assert.False(t, result.Success)
+	assert.Equal(t, StatusFailed, result.Runners[0].Status)
+	assert.Equal(t, 3, result.Runners[0].ExitCode)
+	assert.Contains(t, stdout.String(), "✗ bad: failed (exit code 3)")
+}
+
+func TestEngine_Run_CommandNotFound(t *testing.T) {
+	engine, stdout, _ := newTestEngine(false)
+
+	result, err := engine.Run(context.Background(), []RunnerConfig{
+		{Name: "missing", Command: "gh-arc-definitely-not-a-command"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Runners, 1)
+	assert.False(t, result.Success)
+	assert.Equal(t, StatusNotFound, result.Runners[0].Status)
+	require.Error(t, result.Runners[0].Err)
+	assert.Contains(t, stdout.String(), "✗ missing: command not found")
+}
+
+func TestEngine_Run_Timeout(t *testing.T) {
+	engine, stdout, _ := newTestEngine(false)
+
+	cfg := shellRunner("slow", "exec sleep 5")
+	cfg.Timeout = 100 * time.Millisecond
+
+	result, err := engine.Run(context.Background(), []RunnerConfig{cfg})
+	require.NoError(t, err)
+
+	require.Len(t, result.Runners, 1)
+	assert.False(t, result.Success)
+	assert.Equal(t, StatusTimeout, result.Runners[0].Status)
+	assert.Contains(t, stdout.String(), "✗ slow: timed out after 100ms")
+}
+
+func TestEngine_Run_TimeoutKillsChildProcesses(t *testing.T) {
+	engine, _, _ := newTestEngine(true)
+
+	// The shell forks sleep as a grandchild which keeps the output pipe open
+	cfg := shellRunner("nested", "sleep 5; true")
+	cfg.Timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	result, err := engine.Run(context.Background(), []RunnerConfig{cfg})
+	require.NoError(t, err)
+
+	assert.Equal(t, StatusTimeout, result.Runners[0].Status)
+	assert.Less(t, time.Since(start), waitDelay, "timeout should not wait for orphaned children")
+}
+
+func TestEngine_Run_CancelledContext(t *testing.T) {
+	engine, _, _ := newTestEngine(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := engine.Run(ctx, []RunnerConfig{shellRunner("never", "exit 0")})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, result.Runners)
+}
+
+func TestEngine_Run_SummaryBreakdown(t *testing.T) {
+	engine, stdout, _ := newTestEngine(false)
+
+	slow := shellRunner("slow", "exec sleep 5")
+	slow.Timeout = 100 * time.Millisecond
+
+	result, err := engine.Run(context.Background(), []RunnerConfig{
+		shellRunner("ok", "exit 0"),
+		shellRunner("bad", "exit 1"),
+		slow,
+		{Name: "missing", Command: "gh-arc-definitely-not-a-command"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.FailedCount())
+	assert.Equal(t, 1, result.CountByStatus(StatusTimeout))
+	assert.Equal(t, 1, result.CountByStatus(StatusNotFound))
+	assert.Contains(t, stdout.String(), "✗ 3 of 4 runners failed (1 failed, 1 timed out, 1 not found)")
+}
+
+func TestEngine_Run_MixedResults(t *testing.T) {
+	engine, stdout, _ := newTestEngine(false)
+
+	result, err := engine.Run(context.Background(), []RunnerConfig{
+		shellRunner("first", "exit 1"),
+		shellRunner("second", "exit 0"),
+		shellRunner("third", "exit 2"),
+	})
+	require.NoError(t, err)
+
+	// All runners execute regardless of earlier failures
+	require.Len(t, result.Runners, 3)
+	assert.False(t, result.Success)
+	assert.Equal(t, 2, result.FailedCount())
+	assert.Equal(t, StatusPassed, result.Runners[1].Status)
+	assert.Contains(t, stdout.String(), "✗ 2 of 3 runners failed")
+}
+
+func TestEngine_Run_ArgumentOrder(t *testing.T) {
+	engine, stdout, _ := newTestEngine(false)
+
+	// "$@" echoes positional args: Args (after the script and $0) + ExtraArgs + FilePaths
+	cfg := RunnerConfig{
+		Name:      "args",
+		Command:   "sh",
+		Args:      []string{"-c", `echo "$@"`, "sh", "--base"},
+		ExtraArgs: []string{"--fix"},
+		FilePaths: []string{"a.go", "b.go"},
+	}
+
+	result, err := engine.Run(context.Background(), []RunnerConfig{cfg})
+	require.NoError(t, err)
+	require.True(t, result.Success)
+
+	assert.Contains(t, stdout.String(), "--base --fix a.go b.go")
+}
+
+func TestEngine_Run_WorkingDir(t *testing.T) {
+	engine, stdout, _ := newTestEngine(false)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "marker.txt"), []byte("found-marker"), 0o644))
+
+	cfg := shellRunner("cat", "cat marker.txt")
+	cfg.WorkingDir = dir
+
+	result, err := engine.Run(context.Background(), []RunnerConfig{cfg})
+	require.NoError(t, err)
+
+	assert.True(t, result.Success)
+	assert.Contains(t, stdout.String(), "found-marker")
+}
+
+func TestEngine_Run_JSONModeSuppressesOutput(t *testing.T) {
+	engine, stdout, stderr := newTestEngine(true)
+
+	result, err := engine.Run(context.Background(), []RunnerConfig{
+		shellRunner("noisy", "echo out; echo err >&2; exit 1"),
+	})
+	require.NoError(t, err)
+
+	assert.False(t, result.Success)
+	assert.Empty(t, stdout.String())
+	assert.Empty(t, stderr.String())
+}
+
+func TestFormatJSON(t *testing.T) {
+	t.Run("runners executed", func(t *testing.T) {
+		result := &ExecutionResult{
+			Success: false,
+			Runners: []RunResult{
+				{Name: "golangci-lint", Status: StatusFailed, ExitCode: 1, Duration: 1450 * time.Millisecond},
+				{Name: "eslint", Status: StatusPassed, ExitCode: 0, Duration: 320 * time.Millisecond},
+			},
+		}
+
+		data, err := FormatJSON(result, "lint")
+		require.NoError(t, err)
+
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &decoded))
+
+		assert.Equal(t, "lint", decoded["command"])
+		assert.Equal(t, false, decoded["success"])
+		assert.NotContains(t, decoded, "skipped")
+
+		runners := decoded["runners"].([]interface{})
+		require.Len(t, runners, 2)
+		first := runners[0].(map[string]interface{})
+		assert.Equal(t, "golangci-lint", first["name"])
+		assert.Equal(t, "failed", first["status"])
+		assert.Equal(t, float64(1), first["exit_code"])
+		assert.Equal(t, float64(1450), first["duration_ms"])
+	})
+
+	t.Run("no runners", func(t *testing.T) {
+		data, err := FormatJSON(&ExecutionResult{Success: true}, "unit")
+		require.NoError(t, err)
+
+		compact := strings.Join(strings.Fields(string(data)), "")
+		assert.Equal(t, `{"command":"unit","success":true,"runners":[]}`, compact)
+	})
+
+	t.Run("timeout and not found statuses", func(t *testing.T) {
+		result := &ExecutionResult{
+			Runners: []RunResult{
+				{Name: "slow", Status: StatusTimeout, ExitCode: -1, Err: errors.New("timed out after 5m0s")},
+				{Name: "missing", Status: StatusNotFound, ExitCode: -1, Err: errors.New("command not found: jest")},
+			},
+		}
+
+		data, err := FormatJSON(result, "unit")
+		require.NoError(t, err)
+
+		s := string(data)
+		assert.Contains(t, s, `"status": "timeout"`)
+		assert.Contains(t, s, `"status": "not_found"`)
+		assert.Contains(t, s, `"error": "timed out after 5m0s"`)
+	})
+
+	t.Run("skipped", func(t *testing.T) {
+		data, err := FormatJSON(&ExecutionResult{Success: true, SkipReason: "no changed files"}, "lint")
+		require.NoError(t, err)
+
+		assert.Contains(t, string(data), `"skipped": "no changed files"`)
+	})
+}
+
+func TestEngine_Run_ExpandResult(t *testing.T) {
+	t.Run("expanded results replace the runner result", func(t *testing.T) {
+		engine, stdout, _ := newTestEngine(false)
+
+		cfg := shellRunner("aggregate", "exit 1")
+		cfg.ExpandResult = func(result RunResult) []RunResult {
+			return []RunResult{
+				{Name: "aggregate/A", Status: StatusPassed},
+				{Name: "aggregate/B", Status: StatusFailed, ExitCode: 1},
+			}
+		}
+
+		result, err := engine.Run(context.Background(), []RunnerConfig{cfg})
+		require.NoError(t, err)
+
+		require.Len(t, result.Runners, 2)
+		assert.False(t, result.Success)
+		assert.Contains(t, stdout.String(), "✓ aggregate/A: passed")
+		assert.Contains(t, stdout.String(), "✗ aggregate/B: failed (exit code 1)")
+		assert.Contains(t, stdout.String(), "✗ 1 of 2 runners failed")
+	})
+
+	t.Run("empty expansion keeps original result", func(t *testing.T) {
+		engine, _, _ := newTestEngine(true)
+
+		cfg := shellRunner("aggregate", "exit 2")
+		cfg.ExpandResult = func(result RunResult) []RunResult { return nil }
+
+		result, err := engine.Run(context.Background(), []RunnerConfig{cfg})
+		require.NoError(t, err)
+
+		require.Len(t, result.Runners, 1)
+		assert.Equal(t, "aggregate", result.Runners[0].Name)
+		assert.Equal(t, 2, result.Runners[0].ExitCode)
+	})
+}
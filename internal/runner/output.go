@@ -0,0 +1,124 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// jsonRunner is the JSON representation of a single runner result.
+type jsonRunner struct {
+	Name       string `json:"name"`
+	Status     Status `json:"status"`
+	ExitCode   int    `json:"exit_code"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// jsonResult is the JSON representation of an execution result.
+type jsonResult struct {
+	Command string       `json:"command"`
+	Success bool         `json:"success"`
+	Runners []jsonRunner `json:"runners"`
+	Skipped string       `json:"skipped,omitempty"`
+}
+
+// PrintBanner prints the banner shown before a runner starts.
+func PrintBanner(w io.Writer, name string) {
+	fmt.Fprintf(w, "▶ Running %s...\n", name)
+}
+
+// PrintResult prints the ✓/✗ summary line for a single runner.
+func PrintResult(w io.Writer, result RunResult) {
+	duration := formatDuration(result)
+	switch result.Status {
+	case StatusPassed:
+		fmt.Fprintf(w, "✓ %s: passed %s\n", result.Name, duration)
+	case StatusFailed:
+		fmt.Fprintf(w, "✗ %s: failed (exit code %d) %s\n", result.Name, result.ExitCode, duration)
+	case StatusTimeout:
+		fmt.Fprintf(w, "✗ %s: %v\n", result.Name, result.Err)
+	case StatusNotFound:
+		fmt.Fprintf(w, "✗ %s: %v\n", result.Name, result.Err)
+		fmt.Fprintln(w, "  Install the tool or check the runner's command in .arc.json")
+	case StatusSkipped:
+		fmt.Fprintf(w, "⚠ %s: skipped\n", result.Name)
+	default:
+		fmt.Fprintf(w, "✗ %s: %v %s\n", result.Name, result.Err, duration)
+	}
+}
+
+// PrintSummary prints the overall summary after all runners have finished.
+func PrintSummary(w io.Writer, result *ExecutionResult) {
+	total := len(result.Runners)
+	failed := result.FailedCount()
+
+	fmt.Fprintln(w, "━━━")
+	if failed == 0 {
+		fmt.Fprintf(w, "✓ %d %s passed\n", total, pluralize(total, "runner", "runners"))
+		return
+	}
+	fmt.Fprintf(w, "✗ %d of %d %s failed%s\n", failed, total, pluralize(total, "runner", "runners"), failureBreakdown(result))
+}
+
+// failureBreakdown describes how runners failed, e.g. " (1 failed, 1 timed out)".
+// It is empty when every failure is an ordinary non-zero exit.
+func failureBreakdown(result *ExecutionResult) string {
+	failed := result.CountByStatus(StatusFailed)
+	if failed == result.FailedCount() {
+		return ""
+	}
+
+	var parts []string
+	if failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", failed))
+	}
+	if n := result.CountByStatus(StatusTimeout); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d timed out", n))
+	}
+	if n := result.CountByStatus(StatusNotFound); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d not found", n))
+	}
+	if n := result.CountByStatus(StatusError); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d errored", n))
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// FormatJSON serializes an execution result for --json output.
+// The command parameter identifies the invoking command ("lint" or "unit").
+func FormatJSON(result *ExecutionResult, command string) ([]byte, error) {
+	out := jsonResult{
+		Command: command,
+		Success: result.Success,
+		Runners: make([]jsonRunner, 0, len(result.Runners)),
+		Skipped: result.SkipReason,
+	}
+
+	for _, r := range result.Runners {
+		jr := jsonRunner{
+			Name:       r.Name,
+			Status:     r.Status,
+			ExitCode:   r.ExitCode,
+			DurationMs: r.Duration.Milliseconds(),
+		}
+		if r.Err != nil {
+			jr.Error = r.Err.Error()
+		}
+		out.Runners = append(out.Runners, jr)
+	}
+
+	return json.MarshalIndent(out, "", "  ")
+}
+
+func formatDuration(result RunResult) string {
+	return fmt.Sprintf("[%.1fs]", result.Duration.Seconds())
+}
+
+func pluralize(count int, singular, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package runner
+
+import "os/exec"
+
+// configureProcessGroup is a no-op on Windows; the default cancellation
+// kills only the direct child process.
+func configureProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Status represents the outcome of a single runner execution.
+// It is a string type so it serializes cleanly to JSON.
+type Status string
+
+// Runner status constants
+const (
+	StatusPassed   Status = "passed"
+	StatusFailed   Status = "failed"
+	StatusTimeout  Status = "timeout"
+	StatusNotFound Status = "not_found"
+	StatusError    Status = "error"
+	StatusSkipped  Status = "skipped"
+)
+
+// RunnerConfig is the unified execution configuration for a single runner.
+// Lint and unit workflows convert their config types into RunnerConfig.
+type RunnerConfig struct {
+	Name       string
+	Command    string
+	Args       []string
+	ExtraArgs  []string // Appended after Args (e.g. lint --fix args)
+	WorkingDir string
+	Timeout    time.Duration // Zero means no timeout
+	FilePaths  []string      // Appended after ExtraArgs (e.g. changed files for lint)
+
+	// ExpandResult optionally replaces the runner's single result with finer-grained
+	// results (e.g. one per linter parsed from an aggregator's report files).
+	// Returning an empty slice keeps the original result.
+	ExpandResult func(result RunResult) []RunResult
+}
+
+// RunResult is the outcome of a single runner execution.
+type RunResult struct {
+	Name     string
+	Status   Status
+	ExitCode int
+	Duration time.Duration
+	Err      error // Set for StatusTimeout, StatusNotFound and StatusError
+}
+
+// IsFailure reports whether the runner outcome should fail the execution.
+func (r RunResult) IsFailure() bool {
+	return r.Status != StatusPassed && r.Status != StatusSkipped
+}
+
+// ExecutionResult aggregates the results of all executed runners.
+type ExecutionResult struct {
+	Runners    []RunResult
+	Success    bool
+	SkipReason string // Set when execution was skipped (e.g. "no changed files")
+}
+
+// FailedCount returns the number of runners that did not pass.
+func (r *ExecutionResult) FailedCount() int {
+	count := 0
+	for _, run := range r.Runners {
+		if run.IsFailure() {
+			count++
+		}
+	}
+	return count
+}
+
+// CountByStatus returns the number of runners with the given status.
+func (r *ExecutionResult) CountByStatus(status Status) int {
+	count := 0
+	for _, run := range r.Runners {
+		if run.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// EngineOptions configures the runner engine.
+type EngineOptions struct {
+	JSONMode bool
+	Verbose  bool
+	Stdout   io.Writer // Defaults to os.Stdout
+	Stderr   io.Writer // Defaults to os.Stderr
+}
+
+// Executor runs a list of runner configurations.
+// Engine satisfies this interface; workflows depend on it for mock injection.
+type Executor interface {
+	Run(ctx context.Context, configs []RunnerConfig) (*ExecutionResult, error)
+}
@@ -0,0 +1,514 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+var (
+	// ErrFoldIntoTrunk is returned when folding a branch whose PR is still
+	// open into trunk. That is a merge, which is what land is for.
+	ErrFoldIntoTrunk = errors.New("cannot fold an open PR into trunk")
+
+	// ErrFoldDiverged is returned when the parent has commits the folded
+	// branch is not based on, so the parent cannot simply move to its tip.
+	ErrFoldDiverged = errors.New("branch is not based on the tip of its parent")
+
+	// ErrFoldConflict is returned when a stacked branch does not rebase
+	// cleanly. Every branch is left as it was before the fold.
+	ErrFoldConflict = errors.New("stacked branch does not rebase cleanly")
+)
+
+// FoldMode is what happens to the commits of the branch taken out of a stack.
+type FoldMode string
+
+const (
+	FoldModeFold FoldMode = "fold" // The commits move into the parent branch
+	FoldModeDrop FoldMode = "drop" // The commits are removed from the stack
+)
+
+// FoldRepository defines git operations needed by the fold workflow.
+type FoldRepository interface {
+	GetWorkingDirectoryStatus() (*git.WorkingDirectoryStatus, error)
+	GetCurrentBranch() (string, error)
+	GetDefaultBranch() (string, error)
+	BranchExists(branchName string) (bool, error)
+	ListBranches(includeRemote bool) ([]git.BranchInfo, error)
+	GetBranchSHA(branch string) (string, error)
+	IsAncestor(ancestorRef, descendantRef string) (bool, error)
+	GetMergeBase(ref1, ref2 string) (string, error)
+	GetForkPoint(upstream, branch string) (string, error)
+	GetBranchParent(branch string) (string, error)
+	GetBranchParentSHA(branch string) (string, error)
+	SetBranchParent(branch, parent, parentSHA string) error
+	RebaseOnto(newBase, upstream, branch string) error
+	AbortRebase() error
+	SetBranchRef(branch, sha string) error
+	UpdateRef(ref, sha string) error
+	CheckoutBranch(branch string) error
+	CheckoutDetached(ref string) error
+	DeleteLocalBranch(branch string) error
+	Fetch(ctx context.Context, remote string, refspecs ...string) error
+	Push(ctx context.Context, branchName string) error
+}
+
+// FoldGitHubClient defines GitHub operations needed by the fold workflow.
+type FoldGitHubClient interface {
+	FindPRsForBranches(ctx context.Context, owner, repo string, branches []string) (map[string]*github.PullRequest, error)
+	FindDependentPRs(ctx context.Context, owner, repo, baseBranch string) ([]*github.PullRequest, error)
+	GetPullRequests(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, error)
+	UpdatePRBase(ctx context.Context, owner, repo string, number int, newBase string) error
+	CommentOnPullRequest(ctx context.Context, owner, repo string, number int, body string) error
+	ClosePullRequest(ctx context.Context, owner, repo string, number int) error
+}
+
+// FoldWorkflow takes a branch out of the middle of a stack, either folding
+// its commits into its parent or dropping them, and restacks its children
+// on the parent.
+type FoldWorkflow struct {
+	repo   FoldRepository
+	client FoldGitHubClient
+	owner  string
+	name   string
+}
+
+// NewFoldWorkflow creates a new FoldWorkflow.
+func NewFoldWorkflow(repo FoldRepository, client FoldGitHubClient, owner, name string) *FoldWorkflow {
+	return &FoldWorkflow{
+		repo:   repo,
+		client: client,
+		owner:  owner,
+		name:   name,
+	}
+}
+
+// Execute runs the fold workflow.
+//
+// Folding an open PR fast-forwards the parent to the branch, so the children
+// already sit on top of it. When the PR was merged into its parent, or the
+// branch is dropped, every child is rebased onto the parent without the
+// branch's commits. All local work happens before anything is pushed; a
+// conflict restores every branch. The branch's tip is saved under
+// refs/gh-arc/backup/ before the local branch is deleted.
+func (w *FoldWorkflow) Execute(ctx context.Context, opts *FoldOptions) (*FoldResult, error) {
+	if opts == nil || opts.Mode == "" {
+		return nil, fmt.Errorf("fold mode is required")
+	}
+
+	logger.Debug().
+		Str("mode", string(opts.Mode)).
+		Str("branch", opts.Branch).
+		Msg("Executing fold workflow")
+
+	// Step 1: Resolve the branch, its PR and its parent
+	status, err := w.repo.GetWorkingDirectoryStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check working directory: %w", err)
+	}
+	if !status.IsClean {
+		return nil, ErrDirtyWorkingDir
+	}
+
+	currentBranch, err := w.repo.GetCurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	branch := opts.Branch
+	if branch == "" {
+		branch = currentBranch
+	}
+
+	trunk, err := w.repo.GetDefaultBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine default branch: %w", err)
+	}
+	if branch == trunk {
+		return nil, fmt.Errorf("%w: %s", ErrOnTrunk, trunk)
+	}
+
+	exists, err := w.repo.BranchExists("refs/heads/" + branch)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrBranchNotFound, branch)
+	}
+
+	parent, err := w.repo.GetBranchParent(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	prs, err := w.client.FindPRsForBranches(ctx, w.owner, w.name, []string{branch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the pull request for %s: %w", branch, err)
+	}
+	pr := prs[branch]
+	if pr == nil {
+		pr = w.closedPR(ctx, branch)
+	}
+
+	if parent == "" && pr != nil {
+		parent = pr.Base.Ref
+	}
+	if parent == "" {
+		parent = trunk
+	}
+
+	merged := pr != nil && pr.IsMerged()
+	if opts.Mode == FoldModeFold && parent == trunk && !merged {
+		return nil, fmt.Errorf("%w: use gh arc land to merge %s", ErrFoldIntoTrunk, branch)
+	}
+
+	children, err := findChildren(ctx, w.repo, w.client, w.owner, w.name, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	oldTip, err := w.repo.GetBranchSHA(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FoldResult{
+		Mode:       opts.Mode,
+		Branch:     branch,
+		Parent:     parent,
+		Backup:     backupRefPrefix + branch,
+		BackupSHA:  oldTip,
+		Rebased:    []string{},
+		UpToDate:   []string{},
+		Skipped:    []RestackSkip{},
+		Retargeted: []int{},
+		Pushed:     []string{},
+		Warnings:   []string{},
+	}
+	if pr != nil {
+		result.PR = pr.Number
+	}
+
+	// Step 2: Work out where the parent ends up
+	base, moveParent, err := w.newParentTip(ctx, opts.Mode, branch, parent, oldTip, merged)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.repo.UpdateRef(result.Backup, oldTip); err != nil {
+		return nil, fmt.Errorf("failed to back up %s: %w", branch, err)
+	}
+
+	// Step 3: Move the parent and rebase the children, off any branch that
+	// is about to be rewritten
+	if err := w.repo.CheckoutDetached(oldTip); err != nil {
+		return nil, err
+	}
+
+	undo := &foldUndo{original: currentBranch, detachAt: oldTip, oldTips: map[string]string{}}
+	if moveParent {
+		if tip, err := w.repo.GetBranchSHA(parent); err == nil {
+			undo.oldTips[parent] = tip
+		} else {
+			undo.created = parent
+		}
+		if err := w.repo.SetBranchRef(parent, base); err != nil {
+			w.rollback(undo)
+			return nil, err
+		}
+		result.ParentMoved = true
+	}
+
+	for _, child := range children {
+		if err := w.rebaseChild(child.branch, branch, oldTip, base, undo, result); err != nil {
+			w.rollback(undo)
+			return nil, err
+		}
+	}
+
+	// Step 4: Push the moved parent before GitHub is told about the fold
+	if moveParent && parent != trunk {
+		if err := w.repo.Push(ctx, parent); err != nil {
+			w.rollback(undo)
+			return nil, fmt.Errorf("failed to push %s: %w", parent, err)
+		}
+		result.Pushed = append(result.Pushed, parent)
+	}
+
+	for _, child := range append(append([]string{}, result.Rebased...), result.UpToDate...) {
+		if err := w.repo.SetBranchParent(child, parent, base); err != nil {
+			logger.Warn().Err(err).Str("branch", child).Msg("Failed to record parent branch")
+		}
+	}
+
+	// Step 5: Leave the folded branch and delete it; the backup ref keeps it
+	next := currentBranch
+	if next == branch {
+		next = parent
+	}
+	if err := w.repo.CheckoutBranch(next); err != nil {
+		return nil, err
+	}
+	result.CurrentBranch = next
+
+	if err := w.repo.DeleteLocalBranch(branch); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to delete %s: %v", branch, err))
+	} else {
+		result.Deleted = true
+	}
+
+	for _, child := range result.Rebased {
+		if err := w.repo.Push(ctx, child); err != nil {
+			logger.Warn().Err(err).Str("branch", child).Msg("Failed to push rebased branch")
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to push %s: %v", child, err))
+			continue
+		}
+		result.Pushed = append(result.Pushed, child)
+	}
+
+	// Step 6: Retarget the children's PRs and leave a note on the old PR
+	for _, child := range children {
+		if child.pr == nil {
+			continue
+		}
+		if err := w.client.UpdatePRBase(ctx, w.owner, w.name, child.pr.Number, parent); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to retarget PR #%d to %s: %v", child.pr.Number, parent, err))
+			continue
+		}
+		result.Retargeted = append(result.Retargeted, child.pr.Number)
+	}
+
+	if pr != nil {
+		w.notePR(ctx, pr, result)
+	}
+
+	// Branches stacked on a rebased child still sit on its old commits
+	for _, child := range result.Rebased {
+		grandchildren, err := findChildren(ctx, w.repo, w.client, w.owner, w.name, child)
+		if err != nil {
+			logger.Warn().Err(err).Str("branch", child).Msg("Failed to look for stacked branches")
+			continue
+		}
+		if len(grandchildren) > 0 {
+			result.NeedsRestack = append(result.NeedsRestack, child)
+		}
+	}
+
+	logger.Info().
+		Str("mode", string(opts.Mode)).
+		Str("branch", branch).
+		Str("parent", parent).
+		Strs("rebased", result.Rebased).
+		Msg("Took branch out of stack")
+
+	return result, nil
+}
+
+// closedPR returns the most recently closed PR of branch, if any. Abandoned
+// PRs are the usual reason to drop a branch, but FindPRsForBranches ignores
+// PRs that were closed without merging.
+func (w *FoldWorkflow) closedPR(ctx context.Context, branch string) *github.PullRequest {
+	prs, err := w.client.GetPullRequests(ctx, w.owner, w.name, &github.PullRequestListOptions{
+		State:     "closed",
+		Sort:      "updated",
+		Direction: "desc",
+		PerPage:   1,
+		Page:      1,
+		Head:      w.owner + ":" + branch,
+	})
+	if err != nil {
+		logger.Debug().Err(err).Str("branch", branch).Msg("Failed to look for a closed PR")
+		return nil
+	}
+	if len(prs) == 0 {
+		return nil
+	}
+	return prs[0]
+}
+
+// newParentTip returns the commit the children end up on and whether the
+// parent branch has to move there. A merged PR's changes are already in the
+// parent on GitHub, so the parent is brought up to date with origin.
+func (w *FoldWorkflow) newParentTip(ctx context.Context, mode FoldMode, branch, parent, oldTip string, merged bool) (string, bool, error) {
+	if merged {
+		if err := w.repo.Fetch(ctx, "origin", fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", parent, parent)); err != nil {
+			return "", false, fmt.Errorf("failed to fetch %s: %w", parent, err)
+		}
+		remoteTip, err := w.repo.GetBranchSHA("origin/" + parent)
+		if err != nil {
+			return "", false, err
+		}
+
+		localTip, err := w.repo.GetBranchSHA(parent)
+		if err != nil {
+			// Not checked out locally; create it at the remote tip
+			return remoteTip, true, nil
+		}
+		ahead, err := w.repo.IsAncestor(localTip, remoteTip)
+		if err != nil {
+			return "", false, err
+		}
+		return remoteTip, ahead && localTip != remoteTip, nil
+	}
+
+	parentTip, err := w.repo.GetBranchSHA(parent)
+	if err != nil {
+		return "", false, fmt.Errorf("%w: parent %s of %s is not checked out locally", ErrBranchNotFound, parent, branch)
+	}
+
+	if mode == FoldModeDrop {
+		return parentTip, false, nil
+	}
+
+	based, err := w.repo.IsAncestor(parentTip, oldTip)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to compare %s with %s: %w", branch, parent, err)
+	}
+	if !based {
+		return "", false, fmt.Errorf("%w: run gh arc stack restack %s first", ErrFoldDiverged, parent)
+	}
+	return oldTip, true, nil
+}
+
+// rebaseChild moves child onto base, leaving out the commits it shares with
+// the folded branch. Children that are not checked out are only retargeted.
+func (w *FoldWorkflow) rebaseChild(child, branch, oldTip, base string, undo *foldUndo, result *FoldResult) error {
+	exists, err := w.repo.BranchExists("refs/heads/" + child)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		result.Skipped = append(result.Skipped, RestackSkip{Branch: child, Reason: "not checked out locally"})
+		return nil
+	}
+
+	upstream, err := w.upstreamFor(child, branch, oldTip)
+	if err != nil {
+		return err
+	}
+	if upstream == base {
+		result.UpToDate = append(result.UpToDate, child)
+		return nil
+	}
+
+	childTip, err := w.repo.GetBranchSHA(child)
+	if err != nil {
+		return err
+	}
+	undo.oldTips[child] = childTip
+
+	logger.Info().
+		Str("branch", child).
+		Str("onto", base).
+		Str("upstream", upstream).
+		Msg("Rebasing stacked branch")
+
+	if err := w.repo.RebaseOnto(base, upstream, child); err != nil {
+		if errors.Is(err, git.ErrRebaseConflict) {
+			return fmt.Errorf("%w: %s", ErrFoldConflict, child)
+		}
+		return err
+	}
+	result.Rebased = append(result.Rebased, child)
+	return nil
+}
+
+// upstreamFor returns the commit after which child's own commits start: the
+// tip of the folded branch it was last stacked on, its fork point, or the
+// merge-base with the folded branch.
+func (w *FoldWorkflow) upstreamFor(child, branch, oldTip string) (string, error) {
+	if parent, err := w.repo.GetBranchParent(child); err == nil && parent == branch {
+		if sha, err := w.repo.GetBranchParentSHA(child); err == nil && sha != "" {
+			if ok, err := w.repo.IsAncestor(sha, child); err == nil && ok {
+				return sha, nil
+			}
+		}
+	}
+
+	if forkPoint, err := w.repo.GetForkPoint(branch, child); err == nil && forkPoint != "" {
+		return forkPoint, nil
+	}
+
+	mergeBase, err := w.repo.GetMergeBase(oldTip, child)
+	if err != nil {
+		return "", fmt.Errorf("failed to find where %s forked from %s: %w", child, branch, err)
+	}
+	return mergeBase, nil
+}
+
+// foldUndo records what a fold changed locally, so it can be rolled back.
+type foldUndo struct {
+	original string            // Branch checked out before the fold
+	detachAt string            // Commit to stand on while branches are reset
+	oldTips  map[string]string // Old tips of the branches moved so far
+	created  string            // Parent branch created at the remote tip
+}
+
+// rollback aborts a stopped rebase and puts every branch back where it was.
+// Nothing has been pushed at this point.
+func (w *FoldWorkflow) rollback(undo *foldUndo) {
+	if err := w.repo.AbortRebase(); err != nil {
+		logger.Debug().Err(err).Msg("No rebase to abort")
+	}
+	if err := w.repo.CheckoutDetached(undo.detachAt); err != nil {
+		logger.Warn().Err(err).Msg("Failed to detach HEAD")
+	}
+	for branch, tip := range undo.oldTips {
+		if err := w.repo.SetBranchRef(branch, tip); err != nil {
+			logger.Warn().Err(err).Str("branch", branch).Msg("Failed to restore branch")
+		}
+	}
+	if undo.created != "" {
+		if err := w.repo.DeleteLocalBranch(undo.created); err != nil {
+			logger.Warn().Err(err).Str("branch", undo.created).Msg("Failed to delete branch")
+		}
+	}
+	if err := w.repo.CheckoutBranch(undo.original); err != nil {
+		logger.Warn().Err(err).Str("branch", undo.original).Msg("Failed to return to original branch")
+	}
+}
+
+// notePR comments on the PR of the folded or dropped branch with where its
+// changes went, and closes it unless GitHub already did.
+func (w *FoldWorkflow) notePR(ctx context.Context, pr *github.PullRequest, result *FoldResult) {
+	if err := w.client.CommentOnPullRequest(ctx, w.owner, w.name, pr.Number, foldComment(result)); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to comment on PR #%d: %v", pr.Number, err))
+	} else {
+		result.Commented = true
+	}
+
+	if pr.State != "open" {
+		return
+	}
+	if err := w.client.ClosePullRequest(ctx, w.owner, w.name, pr.Number); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to close PR #%d: %v", pr.Number, err))
+		return
+	}
+	result.Closed = true
+}
+
+// foldComment is left on the PR taken out of the stack.
+func foldComment(result *FoldResult) string {
+	var sb strings.Builder
+
+	if result.Mode == FoldModeFold {
+		fmt.Fprintf(&sb, "Folded into `%s` with `gh arc stack fold`; these changes continue there.", result.Parent)
+	} else {
+		fmt.Fprintf(&sb, "Dropped from the stack with `gh arc stack drop`; these changes are no longer part of it.")
+	}
+
+	if len(result.Retargeted) > 0 {
+		refs := make([]string, len(result.Retargeted))
+		for i, number := range result.Retargeted {
+			refs[i] = fmt.Sprintf("#%d", number)
+		}
+		fmt.Fprintf(&sb, "\n\nThe PRs stacked on this one now target `%s`: %s.", result.Parent, strings.Join(refs, ", "))
+	}
+
+	return sb.String()
+}
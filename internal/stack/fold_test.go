@@ -0,0 +1,286 @@
+package stack
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+type mockFoldRepo struct {
+	*mockRestackRepo
+	refs    map[string]string
+	fetched []string
+	deleted []string
+}
+
+func (m *mockFoldRepo) ListBranches(includeRemote bool) ([]git.BranchInfo, error) {
+	var names []string
+	for name := range m.tips {
+		if !strings.HasPrefix(name, "origin/") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	branches := make([]git.BranchInfo, len(names))
+	for i, name := range names {
+		branches[i] = git.BranchInfo{Name: name}
+	}
+	return branches, nil
+}
+
+func (m *mockFoldRepo) UpdateRef(ref, sha string) error {
+	m.refs[ref] = sha
+	return nil
+}
+
+func (m *mockFoldRepo) CheckoutDetached(ref string) error {
+	m.current = ""
+	m.checkouts = append(m.checkouts, "detached@"+ref)
+	return nil
+}
+
+func (m *mockFoldRepo) DeleteLocalBranch(branch string) error {
+	m.deleted = append(m.deleted, branch)
+	delete(m.tips, branch)
+	return nil
+}
+
+func (m *mockFoldRepo) Fetch(ctx context.Context, remote string, refspecs ...string) error {
+	m.fetched = append(m.fetched, refspecs...)
+	return nil
+}
+
+type mockFoldClient struct {
+	prs         []*github.PullRequest
+	closedPRs   []*github.PullRequest // Closed without merging, only listed on request
+	baseUpdates map[int]string
+	comments    map[int]string
+	closed      []int
+}
+
+func (m *mockFoldClient) FindPRsForBranches(ctx context.Context, owner, repo string, branches []string) (map[string]*github.PullRequest, error) {
+	found := map[string]*github.PullRequest{}
+	for _, pr := range m.prs {
+		for _, branch := range branches {
+			if pr.Head.Ref == branch {
+				found[branch] = pr
+			}
+		}
+	}
+	return found, nil
+}
+
+func (m *mockFoldClient) FindDependentPRs(ctx context.Context, owner, repo, baseBranch string) ([]*github.PullRequest, error) {
+	var deps []*github.PullRequest
+	for _, pr := range m.prs {
+		if pr.Base.Ref == baseBranch && pr.State == "open" {
+			deps = append(deps, pr)
+		}
+	}
+	return deps, nil
+}
+
+func (m *mockFoldClient) GetPullRequests(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, error) {
+	var prs []*github.PullRequest
+	for _, pr := range m.closedPRs {
+		if opts.Head == owner+":"+pr.Head.Ref {
+			prs = append(prs, pr)
+		}
+	}
+	return prs, nil
+}
+
+func (m *mockFoldClient) UpdatePRBase(ctx context.Context, owner, repo string, number int, newBase string) error {
+	m.baseUpdates[number] = newBase
+	return nil
+}
+
+func (m *mockFoldClient) CommentOnPullRequest(ctx context.Context, owner, repo string, number int, body string) error {
+	m.comments[number] = body
+	return nil
+}
+
+func (m *mockFoldClient) ClosePullRequest(ctx context.Context, owner, repo string, number int) error {
+	m.closed = append(m.closed, number)
+	return nil
+}
+
+func openPR(number int, head, base string) *github.PullRequest {
+	pr := newPR(number, head, base)
+	pr.State = "open"
+	return pr
+}
+
+// newFoldFixture builds main ← a ← b ← c with b checked out. Each branch
+// was last stacked on the current tip of its parent.
+func newFoldFixture(t *testing.T) (*mockFoldRepo, *mockFoldClient) {
+	restack := newMockRestackRepo(t)
+	restack.current = "b"
+	restack.tips = map[string]string{"main": "main-sha", "a": "a-sha", "b": "b-sha", "c": "c-sha"}
+	restack.parents = map[string]string{"a": "main@main-sha", "b": "a@a-sha", "c": "b@b-sha"}
+	restack.ancestors = map[string]bool{
+		"main-sha..a-sha": true,
+		"a-sha..b-sha":    true,
+		"b-sha..c-sha":    true,
+	}
+
+	repo := &mockFoldRepo{mockRestackRepo: restack, refs: map[string]string{}}
+	client := &mockFoldClient{
+		prs:         []*github.PullRequest{openPR(1, "a", "main"), openPR(2, "b", "a"), openPR(3, "c", "b")},
+		baseUpdates: map[int]string{},
+		comments:    map[int]string{},
+	}
+	return repo, client
+}
+
+func TestFoldWorkflow(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("drop rebases the children without the branch", func(t *testing.T) {
+		repo, client := newFoldFixture(t)
+		repo.tips["d"] = "d-sha"
+		repo.parents["d"] = "c@c-sha"
+
+		result, err := NewFoldWorkflow(repo, client, "owner", "repo").Execute(ctx, &FoldOptions{Mode: FoldModeDrop})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"c onto a-sha from b-sha"}, repo.rebases)
+		assert.Equal(t, []string{"c"}, result.Rebased)
+		assert.Equal(t, "a@a-sha", repo.parents["c"])
+		assert.Equal(t, []string{"c"}, repo.pushed)
+		assert.Equal(t, map[int]string{3: "a"}, client.baseUpdates)
+		assert.Equal(t, []int{2}, client.closed)
+		assert.Contains(t, client.comments[2], "Dropped from the stack")
+		assert.Contains(t, client.comments[2], "now target `a`: #3.")
+
+		assert.Equal(t, "b-sha", repo.refs["refs/gh-arc/backup/b"])
+		assert.Equal(t, []string{"b"}, repo.deleted)
+		assert.Equal(t, "a", repo.current, "leaves the deleted branch for its parent")
+		assert.Equal(t, "a-sha", repo.tips["a"], "the parent does not move")
+		assert.Equal(t, []string{"c"}, result.NeedsRestack)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("fold moves the parent to the branch", func(t *testing.T) {
+		repo, client := newFoldFixture(t)
+		repo.current = "c"
+
+		result, err := NewFoldWorkflow(repo, client, "owner", "repo").Execute(ctx, &FoldOptions{Mode: FoldModeFold, Branch: "b"})
+		require.NoError(t, err)
+
+		assert.True(t, result.ParentMoved)
+		assert.Equal(t, "b-sha", repo.tips["a"])
+		assert.Empty(t, repo.rebases)
+		assert.Equal(t, []string{"c"}, result.UpToDate)
+		assert.Equal(t, "a@b-sha", repo.parents["c"])
+		assert.Equal(t, []string{"a"}, repo.pushed)
+		assert.Equal(t, map[int]string{3: "a"}, client.baseUpdates)
+		assert.Contains(t, client.comments[2], "Folded into `a`")
+		assert.Equal(t, []int{2}, client.closed)
+		assert.Equal(t, "c", repo.current)
+		assert.Empty(t, result.NeedsRestack)
+	})
+
+	t.Run("fold of a PR merged into its parent", func(t *testing.T) {
+		repo, client := newFoldFixture(t)
+		mergedAt := time.Now()
+		client.prs[1].MergedAt = &mergedAt
+		client.prs[1].State = "closed"
+		repo.tips["origin/a"] = "a-merged"
+		repo.ancestors["a-sha..a-merged"] = true
+
+		result, err := NewFoldWorkflow(repo, client, "owner", "repo").Execute(ctx, &FoldOptions{Mode: FoldModeFold})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"+refs/heads/a:refs/remotes/origin/a"}, repo.fetched)
+		assert.Equal(t, "a-merged", repo.tips["a"], "the parent catches up with origin")
+		assert.Equal(t, []string{"c onto a-merged from b-sha"}, repo.rebases)
+		assert.Equal(t, []string{"a", "c"}, repo.pushed)
+		assert.Equal(t, map[int]string{3: "a"}, client.baseUpdates)
+		assert.True(t, result.Commented)
+		assert.False(t, result.Closed)
+		assert.Empty(t, client.closed)
+	})
+
+	t.Run("drop of a PR closed without merging", func(t *testing.T) {
+		repo, client := newFoldFixture(t)
+		abandoned := client.prs[1]
+		abandoned.State = "closed"
+		client.prs = []*github.PullRequest{client.prs[0], client.prs[2]}
+		client.closedPRs = []*github.PullRequest{abandoned}
+
+		result, err := NewFoldWorkflow(repo, client, "owner", "repo").Execute(ctx, &FoldOptions{Mode: FoldModeDrop})
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, result.PR)
+		assert.Contains(t, client.comments, 2)
+		assert.Empty(t, client.closed, "already closed")
+	})
+
+	t.Run("conflict restores every branch", func(t *testing.T) {
+		repo, client := newFoldFixture(t)
+		repo.conflicts["c"] = true
+
+		_, err := NewFoldWorkflow(repo, client, "owner", "repo").Execute(ctx, &FoldOptions{Mode: FoldModeDrop})
+		assert.ErrorIs(t, err, ErrFoldConflict)
+
+		assert.Equal(t, "c-sha", repo.tips["c"])
+		assert.Equal(t, "b-sha", repo.tips["b"])
+		assert.Equal(t, "b", repo.current)
+		assert.Empty(t, repo.deleted)
+		assert.Empty(t, repo.pushed)
+		assert.Empty(t, client.baseUpdates)
+		assert.Empty(t, client.comments)
+	})
+
+	t.Run("conflict after folding restores the parent", func(t *testing.T) {
+		repo, client := newFoldFixture(t)
+		repo.parents["c"] = "b@b-old"
+		repo.conflicts["c"] = true
+
+		_, err := NewFoldWorkflow(repo, client, "owner", "repo").Execute(ctx, &FoldOptions{Mode: FoldModeFold})
+		assert.ErrorIs(t, err, ErrFoldConflict)
+		assert.Equal(t, "a-sha", repo.tips["a"])
+		assert.Empty(t, repo.pushed)
+	})
+
+	t.Run("refuses to fold an open PR into trunk", func(t *testing.T) {
+		repo, client := newFoldFixture(t)
+
+		_, err := NewFoldWorkflow(repo, client, "owner", "repo").Execute(ctx, &FoldOptions{Mode: FoldModeFold, Branch: "a"})
+		assert.ErrorIs(t, err, ErrFoldIntoTrunk)
+	})
+
+	t.Run("refuses to fold onto a parent that moved", func(t *testing.T) {
+		repo, client := newFoldFixture(t)
+		repo.ancestors["a-sha..b-sha"] = false
+
+		_, err := NewFoldWorkflow(repo, client, "owner", "repo").Execute(ctx, &FoldOptions{Mode: FoldModeFold})
+		assert.ErrorIs(t, err, ErrFoldDiverged)
+		assert.Empty(t, repo.refs, "nothing is backed up")
+	})
+
+	t.Run("refuses a dirty working tree", func(t *testing.T) {
+		repo, client := newFoldFixture(t)
+		repo.dirty = true
+
+		_, err := NewFoldWorkflow(repo, client, "owner", "repo").Execute(ctx, &FoldOptions{Mode: FoldModeDrop})
+		assert.ErrorIs(t, err, ErrDirtyWorkingDir)
+	})
+
+	t.Run("unknown branch", func(t *testing.T) {
+		repo, client := newFoldFixture(t)
+
+		_, err := NewFoldWorkflow(repo, client, "owner", "repo").Execute(ctx, &FoldOptions{Mode: FoldModeDrop, Branch: "nope"})
+		assert.ErrorIs(t, err, ErrBranchNotFound)
+	})
+}
@@ -0,0 +1,138 @@
+package stack
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+// Node is an open pull request in the stack graph, keyed by its head branch.
+type Node struct {
+	Branch   string
+	PR       *github.PullRequest
+	Parent   *Node // nil when the PR targets a branch that is not another PR's head
+	Children []*Node
+}
+
+// Base returns the branch the node's PR targets.
+func (n *Node) Base() string {
+	return n.PR.Base.Ref
+}
+
+// Depth returns the number of PR ancestors of the node.
+func (n *Node) Depth() int {
+	depth := 0
+	for p := n.Parent; p != nil; p = p.Parent {
+		depth++
+	}
+	return depth
+}
+
+// Root returns the bottom-most PR of the stack containing the node.
+func (n *Node) Root() *Node {
+	root := n
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	return root
+}
+
+// Graph links open pull requests whose base is another open PR's head
+// branch. Because each PR has exactly one base, the graph is a forest: the
+// roots target trunk (or a branch without a PR) and stacks grow from them.
+type Graph struct {
+	Roots []*Node
+	nodes map[string]*Node
+}
+
+// BuildGraph builds the stack graph from open pull requests. PRs from forks
+// are ignored: their head branch names live in another repository and cannot
+// be the base of a PR here.
+func BuildGraph(prs []*github.PullRequest, owner, repo string) *Graph {
+	g := &Graph{nodes: make(map[string]*Node)}
+
+	fullName := owner + "/" + repo
+	for _, pr := range prs {
+		if pr.Head.Repo.FullName != "" && !strings.EqualFold(pr.Head.Repo.FullName, fullName) {
+			continue
+		}
+		if _, exists := g.nodes[pr.Head.Ref]; exists {
+			continue
+		}
+		g.nodes[pr.Head.Ref] = &Node{Branch: pr.Head.Ref, PR: pr}
+	}
+
+	for _, node := range g.nodes {
+		parent, ok := g.nodes[node.Base()]
+		if !ok || createsCycle(node, parent) {
+			g.Roots = append(g.Roots, node)
+			continue
+		}
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+
+	sortNodes(g.Roots)
+	for _, node := range g.nodes {
+		sortNodes(node.Children)
+	}
+
+	return g
+}
+
+// createsCycle reports whether making parent the parent of node would close
+// a loop, which GitHub allows (A targets B while B targets A) but a stack
+// cannot represent.
+func createsCycle(node, parent *Node) bool {
+	for p := parent; p != nil; p = p.Parent {
+		if p == node {
+			return true
+		}
+	}
+	return false
+}
+
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].PR.Number < nodes[j].PR.Number
+	})
+}
+
+// Node returns the node for the given head branch, or nil.
+func (g *Graph) Node(branch string) *Node {
+	return g.nodes[branch]
+}
+
+// Len returns the number of PRs in the graph.
+func (g *Graph) Len() int {
+	return len(g.nodes)
+}
+
+// Walk visits root and all of its descendants in topological order: every
+// parent is visited before its children, siblings in PR number order.
+func Walk(root *Node, visit func(*Node)) {
+	visit(root)
+	for _, child := range root.Children {
+		Walk(child, visit)
+	}
+}
+
+// Descendants returns all nodes above n in topological order, excluding n.
+func (n *Node) Descendants() []*Node {
+	var nodes []*Node
+	for _, child := range n.Children {
+		Walk(child, func(d *Node) { nodes = append(nodes, d) })
+	}
+	return nodes
+}
+
+// Ancestors returns the PR ancestors of n from the bottom of the stack up,
+// excluding n.
+func (n *Node) Ancestors() []*Node {
+	var nodes []*Node
+	for p := n.Parent; p != nil; p = p.Parent {
+		nodes = append([]*Node{p}, nodes...)
+	}
+	return nodes
+}
@@ -0,0 +1,82 @@
+package stack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+func newPR(number int, head, base string) *github.PullRequest {
+	return &github.PullRequest{
+		Number: number,
+		Title:  "PR " + head,
+		Head: github.PRBranch{
+			Ref:  head,
+			SHA:  head + "-sha",
+			Repo: github.PRRepository{FullName: "owner/repo"},
+		},
+		Base: github.PRBranch{Ref: base, SHA: base + "-sha"},
+	}
+}
+
+func branches(nodes []*Node) []string {
+	names := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		names = append(names, n.Branch)
+	}
+	return names
+}
+
+func TestBuildGraph(t *testing.T) {
+	prs := []*github.PullRequest{
+		newPR(4, "d", "b"),
+		newPR(2, "b", "a"),
+		newPR(1, "a", "main"),
+		newPR(3, "c", "a"),
+		newPR(5, "solo", "main"),
+		newPR(6, "release-fix", "release"),
+	}
+
+	g := BuildGraph(prs, "owner", "repo")
+	require.Equal(t, 6, g.Len())
+	assert.Equal(t, []string{"a", "solo", "release-fix"}, branches(g.Roots))
+
+	a := g.Node("a")
+	require.NotNil(t, a)
+	assert.Nil(t, a.Parent)
+	assert.Equal(t, []string{"b", "c"}, branches(a.Children))
+	assert.Equal(t, []string{"b", "d", "c"}, branches(a.Descendants()))
+
+	d := g.Node("d")
+	assert.Equal(t, 2, d.Depth())
+	assert.Equal(t, a, d.Root())
+	assert.Equal(t, []string{"a", "b"}, branches(d.Ancestors()))
+
+	var walked []string
+	Walk(a, func(n *Node) { walked = append(walked, n.Branch) })
+	assert.Equal(t, []string{"a", "b", "d", "c"}, walked)
+
+	assert.Nil(t, g.Node("missing"))
+}
+
+func TestBuildGraph_IgnoresForks(t *testing.T) {
+	fork := newPR(2, "a", "main")
+	fork.Head.Repo.FullName = "someone/repo"
+
+	g := BuildGraph([]*github.PullRequest{fork, newPR(1, "a", "main"), newPR(3, "b", "a")}, "owner", "repo")
+	require.Equal(t, 2, g.Len())
+	assert.Equal(t, 1, g.Node("a").PR.Number)
+	assert.Equal(t, g.Node("a"), g.Node("b").Parent)
+}
+
+func TestBuildGraph_BreaksCycles(t *testing.T) {
+	g := BuildGraph([]*github.PullRequest{newPR(1, "a", "b"), newPR(2, "b", "a")}, "owner", "repo")
+	require.Len(t, g.Roots, 1)
+
+	root := g.Roots[0]
+	assert.Len(t, root.Children, 1)
+	assert.Equal(t, root, root.Children[0].Parent)
+}
@@ -0,0 +1,222 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+// Markers delimiting the stack table in PR bodies. Everything between them
+// belongs to gh-arc and is rewritten whenever the stack changes; the rest of
+// the body is never touched.
+const (
+	linksStart = "<!-- gh-arc:stack -->"
+	linksEnd   = "<!-- /gh-arc:stack -->"
+
+	// emptyBody replaces a body that held nothing but the stack table, since
+	// an empty body is left out of the update and would keep the table.
+	emptyBody = "_No description provided._"
+)
+
+// StackLink is one row of the stack table.
+type StackLink struct {
+	Number int
+	Title  string
+	State  string
+	Depth  int // Number of PR ancestors, used to indent branched stacks
+}
+
+// FormatStackLinks renders the stack table listing every PR of a stack,
+// bottom first, with an arrow at the PR the body belongs to. Rows are only
+// indented when the stack branches, since depth is implied by order in a
+// linear stack.
+func FormatStackLinks(links []StackLink, current int) string {
+	branched := false
+	for i, link := range links {
+		if i > 0 && link.Depth <= links[i-1].Depth {
+			branched = true
+			break
+		}
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(linksStart + "\n")
+	sb.WriteString("**Stack** (bottom first):\n\n")
+	sb.WriteString("| | PR | Title | State |\n")
+	sb.WriteString("| --- | --- | --- | --- |\n")
+	for _, link := range links {
+		marker := ""
+		if link.Number == current {
+			marker = "👉"
+		}
+		number := fmt.Sprintf("#%d", link.Number)
+		if branched && link.Depth > 0 {
+			number = strings.Repeat("&nbsp;&nbsp;", link.Depth-1) + "↳ " + number
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", marker, number, escapeTableCell(link.Title), link.State)
+	}
+	sb.WriteString(linksEnd)
+
+	return sb.String()
+}
+
+func escapeTableCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// prState returns the state shown for a PR in the stack table. Stacks are
+// built from open PRs only, so a PR is either a draft or open.
+func prState(pr *github.PullRequest) string {
+	if pr.Draft {
+		return "Draft"
+	}
+	return "Open"
+}
+
+// ReplaceStackLinks replaces the stack table in body with block, or
+// appends block when body has none. Applying the same block twice leaves the
+// body unchanged.
+func ReplaceStackLinks(body, block string) string {
+	if start, end, ok := findStackLinks(body); ok {
+		return body[:start] + block + body[end:]
+	}
+
+	body = strings.TrimRight(body, "\n")
+	if body == "" {
+		return block
+	}
+	return body + "\n\n" + block
+}
+
+// RemoveStackLinks removes the stack table from body together with the blank
+// lines that separated it from the surrounding text.
+func RemoveStackLinks(body string) string {
+	start, end, ok := findStackLinks(body)
+	if !ok {
+		return body
+	}
+
+	before := strings.TrimRight(body[:start], "\r\n")
+	after := strings.TrimLeft(body[end:], "\r\n")
+	if before == "" || after == "" {
+		return before + after
+	}
+	return before + "\n\n" + after
+}
+
+// findStackLinks returns the bounds of the stack table in body. A start
+// marker without an end marker is not a table.
+func findStackLinks(body string) (int, int, bool) {
+	start := strings.Index(body, linksStart)
+	if start < 0 {
+		return 0, 0, false
+	}
+	end := strings.Index(body[start:], linksEnd)
+	if end < 0 {
+		return 0, 0, false
+	}
+	return start, start + end + len(linksEnd), true
+}
+
+// LinkGitHubClient defines GitHub operations needed to keep stack tables up
+// to date.
+type LinkGitHubClient interface {
+	PullRequestLister
+	UpdatePullRequest(ctx context.Context, owner, repo string, number int, title, body string, draft *bool, parentPR *github.PullRequest) (*github.PullRequest, error)
+}
+
+// LinkSyncer rewrites the stack table in the bodies of stacked PRs.
+type LinkSyncer struct {
+	client LinkGitHubClient
+	owner  string
+	name   string
+}
+
+// NewLinkSyncer creates a new LinkSyncer.
+func NewLinkSyncer(client LinkGitHubClient, owner, name string) *LinkSyncer {
+	return &LinkSyncer{
+		client: client,
+		owner:  owner,
+		name:   name,
+	}
+}
+
+// Sync rewrites the stack table in every open PR of the stacks containing
+// the given branches and returns the numbers of the PRs it updated. A PR
+// that is no longer stacked with any other loses its table. PRs whose body
+// is already up to date are not touched, so syncing twice is a no-op.
+//
+// Every PR is attempted; the errors of the ones that failed are joined.
+func (s *LinkSyncer) Sync(ctx context.Context, branches ...string) ([]int, error) {
+	if len(branches) == 0 {
+		return nil, nil
+	}
+
+	graph, err := LoadGraph(ctx, s.client, s.owner, s.name)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []int
+	var errs []error
+	seenRoots := map[*Node]bool{}
+
+	for _, branch := range branches {
+		node := graph.Node(branch)
+		if node == nil {
+			logger.Debug().Str("branch", branch).Msg("No open PR to link")
+			continue
+		}
+		root := node.Root()
+		if seenRoots[root] {
+			continue
+		}
+		seenRoots[root] = true
+
+		var nodes []*Node
+		var links []StackLink
+		Walk(root, func(n *Node) {
+			nodes = append(nodes, n)
+			links = append(links, StackLink{
+				Number: n.PR.Number,
+				Title:  n.PR.Title,
+				State:  prState(n.PR),
+				Depth:  n.Depth(),
+			})
+		})
+
+		for _, n := range nodes {
+			body := n.PR.Body
+			next := RemoveStackLinks(body)
+			if len(nodes) > 1 {
+				next = ReplaceStackLinks(body, FormatStackLinks(links, n.PR.Number))
+			}
+			if next == "" {
+				next = emptyBody
+			}
+			if next == body {
+				continue
+			}
+
+			if _, err := s.client.UpdatePullRequest(ctx, s.owner, s.name, n.PR.Number, "", next, nil, nil); err != nil {
+				logger.Warn().Err(err).Int("pr", n.PR.Number).Msg("Failed to update stack table")
+				errs = append(errs, fmt.Errorf("PR #%d: %w", n.PR.Number, err))
+				continue
+			}
+			updated = append(updated, n.PR.Number)
+		}
+	}
+
+	logger.Debug().
+		Ints("updated", updated).
+		Int("failed", len(errs)).
+		Msg("Synced stack tables")
+
+	return updated, errors.Join(errs...)
+}
@@ -0,0 +1,189 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+func TestFormatStackLinks(t *testing.T) {
+	t.Run("linear stack", func(t *testing.T) {
+		links := []StackLink{
+			{Number: 1, Title: "Add API", State: "Open"},
+			{Number: 2, Title: "Use | API", State: "Draft", Depth: 1},
+			{Number: 3, Title: "Polish", State: "Open", Depth: 2},
+		}
+
+		expected := "<!-- gh-arc:stack -->\n" +
+			"**Stack** (bottom first):\n\n" +
+			"| | PR | Title | State |\n" +
+			"| --- | --- | --- | --- |\n" +
+			"|  | #1 | Add API | Open |\n" +
+			"| 👉 | #2 | Use \\| API | Draft |\n" +
+			"|  | #3 | Polish | Open |\n" +
+			"<!-- /gh-arc:stack -->"
+		assert.Equal(t, expected, FormatStackLinks(links, 2))
+	})
+
+	t.Run("branched stack is indented", func(t *testing.T) {
+		links := []StackLink{
+			{Number: 1, Title: "a", State: "Open"},
+			{Number: 2, Title: "b", State: "Open", Depth: 1},
+			{Number: 3, Title: "c", State: "Open", Depth: 2},
+			{Number: 4, Title: "b2", State: "Open", Depth: 1},
+		}
+
+		block := FormatStackLinks(links, 1)
+		assert.Contains(t, block, "|  | ↳ #2 | b | Open |\n")
+		assert.Contains(t, block, "|  | &nbsp;&nbsp;↳ #3 | c | Open |\n")
+		assert.Contains(t, block, "|  | ↳ #4 | b2 | Open |\n")
+	})
+}
+
+func TestReplaceStackLinks(t *testing.T) {
+	block := FormatStackLinks([]StackLink{{Number: 1}, {Number: 2, Depth: 1}}, 1)
+
+	t.Run("appends", func(t *testing.T) {
+		assert.Equal(t, "Summary\n\n"+block, ReplaceStackLinks("Summary\n", block))
+		assert.Equal(t, block, ReplaceStackLinks("", block))
+	})
+
+	t.Run("replaces and is idempotent", func(t *testing.T) {
+		old := FormatStackLinks([]StackLink{{Number: 1}}, 1)
+		body := "Summary\n\n" + old + "\n\nFooter"
+
+		updated := ReplaceStackLinks(body, block)
+		assert.Equal(t, "Summary\n\n"+block+"\n\nFooter", updated)
+		assert.Equal(t, updated, ReplaceStackLinks(updated, block))
+	})
+
+	t.Run("unterminated block is left alone", func(t *testing.T) {
+		body := "Summary\n\n" + linksStart + "\nbroken"
+		assert.Equal(t, body+"\n\n"+block, ReplaceStackLinks(body, block))
+	})
+}
+
+func TestRemoveStackLinks(t *testing.T) {
+	block := FormatStackLinks([]StackLink{{Number: 1}, {Number: 2, Depth: 1}}, 1)
+
+	assert.Equal(t, "Summary\n\nFooter", RemoveStackLinks("Summary\n\n"+block+"\n\nFooter"))
+	assert.Equal(t, "Summary", RemoveStackLinks("Summary\n\n"+block+"\n"))
+	assert.Equal(t, "", RemoveStackLinks(block))
+	assert.Equal(t, "No table", RemoveStackLinks("No table"))
+}
+
+type mockLinkClient struct {
+	prs     []*github.PullRequest
+	updates map[int]string
+	failing map[int]bool
+}
+
+func (m *mockLinkClient) GetPullRequestsWithPagination(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, error) {
+	return m.prs, nil
+}
+
+func (m *mockLinkClient) UpdatePullRequest(ctx context.Context, owner, repo string, number int, title, body string, draft *bool, parentPR *github.PullRequest) (*github.PullRequest, error) {
+	if m.failing[number] {
+		return nil, errors.New("boom")
+	}
+	m.updates[number] = body
+	for _, pr := range m.prs {
+		if pr.Number == number {
+			pr.Body = body
+		}
+	}
+	return &github.PullRequest{Number: number, Body: body}, nil
+}
+
+// newLinkFixture has the stack main <- a <- b plus an unrelated PR for c.
+func newLinkFixture() *mockLinkClient {
+	a := newPR(1, "a", "main")
+	a.Body = "Body of a"
+	b := newPR(2, "b", "a")
+	b.Draft = true
+	return &mockLinkClient{
+		prs:     []*github.PullRequest{a, b, newPR(3, "c", "main")},
+		updates: map[int]string{},
+		failing: map[int]bool{},
+	}
+}
+
+func TestLinkSyncer(t *testing.T) {
+	ctx := context.Background()
+	links := []StackLink{
+		{Number: 1, Title: "PR a", State: "Open"},
+		{Number: 2, Title: "PR b", State: "Draft", Depth: 1},
+	}
+
+	t.Run("writes the table into every PR of the stack", func(t *testing.T) {
+		client := newLinkFixture()
+
+		updated, err := NewLinkSyncer(client, "owner", "repo").Sync(ctx, "b")
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, updated)
+		assert.Equal(t, "Body of a\n\n"+FormatStackLinks(links, 1), client.updates[1])
+		assert.Equal(t, FormatStackLinks(links, 2), client.updates[2])
+		assert.NotContains(t, client.updates, 3, "unrelated PRs are left alone")
+	})
+
+	t.Run("syncing twice changes nothing", func(t *testing.T) {
+		client := newLinkFixture()
+		syncer := NewLinkSyncer(client, "owner", "repo")
+
+		_, err := syncer.Sync(ctx, "a")
+		require.NoError(t, err)
+
+		client.updates = map[int]string{}
+		updated, err := syncer.Sync(ctx, "a", "b")
+		require.NoError(t, err)
+		assert.Empty(t, updated)
+		assert.Empty(t, client.updates)
+	})
+
+	t.Run("removes the table from a PR left alone", func(t *testing.T) {
+		client := newLinkFixture()
+		client.prs[2].Body = "Body of c\n\n" + FormatStackLinks(links, 3)
+
+		updated, err := NewLinkSyncer(client, "owner", "repo").Sync(ctx, "c")
+		require.NoError(t, err)
+		assert.Equal(t, []int{3}, updated)
+		assert.Equal(t, "Body of c", client.updates[3])
+	})
+
+	t.Run("replaces a body that was only the table", func(t *testing.T) {
+		client := newLinkFixture()
+		client.prs[2].Body = FormatStackLinks(links, 3)
+		syncer := NewLinkSyncer(client, "owner", "repo")
+
+		updated, err := syncer.Sync(ctx, "c")
+		require.NoError(t, err)
+		assert.Equal(t, []int{3}, updated)
+		assert.Equal(t, emptyBody, client.updates[3])
+
+		updated, err = syncer.Sync(ctx, "c")
+		require.NoError(t, err)
+		assert.Empty(t, updated)
+	})
+
+	t.Run("keeps going when an update fails", func(t *testing.T) {
+		client := newLinkFixture()
+		client.failing[1] = true
+
+		updated, err := NewLinkSyncer(client, "owner", "repo").Sync(ctx, "b")
+		assert.ErrorContains(t, err, "PR #1: boom")
+		assert.Equal(t, []int{2}, updated)
+	})
+
+	t.Run("branch without an open PR", func(t *testing.T) {
+		client := newLinkFixture()
+
+		updated, err := NewLinkSyncer(client, "owner", "repo").Sync(ctx, "gone")
+		require.NoError(t, err)
+		assert.Empty(t, updated)
+	})
+}
@@ -0,0 +1,342 @@
+package stack
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+var (
+	// ErrStackEnd is returned by up and down when there is no branch to move to.
+	ErrStackEnd = errors.New("no branch to move to")
+
+	// ErrAmbiguousChild is returned when a branch has several children and
+	// there is no terminal to ask which one to follow.
+	ErrAmbiguousChild = errors.New("branch has several children")
+)
+
+// Direction is where a navigation command moves within a stack.
+type Direction string
+
+const (
+	DirectionUp     Direction = "up"     // To a child branch
+	DirectionDown   Direction = "down"   // To the parent branch
+	DirectionTop    Direction = "top"    // To the last branch of the stack
+	DirectionBottom Direction = "bottom" // To the first branch above trunk
+)
+
+// NavigateRepository defines git operations needed by the navigate workflow.
+type NavigateRepository interface {
+	GetWorkingDirectoryStatus() (*git.WorkingDirectoryStatus, error)
+	GetCurrentBranch() (string, error)
+	GetDefaultBranch() (string, error)
+	ListBranches(includeRemote bool) ([]git.BranchInfo, error)
+	GetBranchParent(branch string) (string, error)
+	CheckoutBranch(branch string) error
+}
+
+// NavigateGitHubClient defines GitHub operations needed by the navigate workflow.
+type NavigateGitHubClient interface {
+	FindPRsForBranches(ctx context.Context, owner, repo string, branches []string) (map[string]*github.PullRequest, error)
+	FindDependentPRs(ctx context.Context, owner, repo, baseBranch string) ([]*github.PullRequest, error)
+}
+
+// NavigateWorkflow checks out the child, parent, top or bottom branch of
+// the current stack.
+type NavigateWorkflow struct {
+	repo       NavigateRepository
+	client     NavigateGitHubClient
+	owner      string
+	name       string
+	out        io.Writer
+	stdin      io.Reader
+	reader     *bufio.Reader // Shared by every prompt of one run
+	isTerminal func() bool
+}
+
+// NewNavigateWorkflow creates a new NavigateWorkflow.
+func NewNavigateWorkflow(repo NavigateRepository, client NavigateGitHubClient, owner, name string) *NavigateWorkflow {
+	return &NavigateWorkflow{
+		repo:       repo,
+		client:     client,
+		owner:      owner,
+		name:       name,
+		out:        os.Stdout,
+		stdin:      os.Stdin,
+		isTerminal: func() bool { return term.IsTerminal(int(os.Stdin.Fd())) },
+	}
+}
+
+// stackChild is a branch stacked directly on another one.
+type stackChild struct {
+	branch string
+	pr     *github.PullRequest // Open PR targeting the parent, nil for local-only branches
+}
+
+// Execute runs the navigate workflow.
+func (w *NavigateWorkflow) Execute(ctx context.Context, opts *NavigateOptions) (*NavigateResult, error) {
+	if opts == nil || opts.Direction == "" {
+		return nil, fmt.Errorf("navigation direction is required")
+	}
+
+	logger.Debug().
+		Str("direction", string(opts.Direction)).
+		Msg("Executing navigate workflow")
+
+	status, err := w.repo.GetWorkingDirectoryStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check working directory: %w", err)
+	}
+	if !status.IsClean {
+		return nil, ErrDirtyWorkingDir
+	}
+
+	currentBranch, err := w.repo.GetCurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	trunk, err := w.repo.GetDefaultBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine default branch: %w", err)
+	}
+
+	if currentBranch == trunk {
+		return nil, fmt.Errorf("%w: %s", ErrOnTrunk, trunk)
+	}
+
+	result := &NavigateResult{
+		Direction: opts.Direction,
+		From:      currentBranch,
+		To:        currentBranch,
+		Path:      []string{},
+	}
+
+	switch opts.Direction {
+	case DirectionUp:
+		child, err := w.nextChild(ctx, currentBranch)
+		if err != nil {
+			return nil, err
+		}
+		if child == "" {
+			return nil, fmt.Errorf("%w: nothing is stacked on %s", ErrStackEnd, currentBranch)
+		}
+		result.Path = append(result.Path, child)
+
+	case DirectionDown:
+		parent, err := w.parentOf(ctx, currentBranch, trunk)
+		if err != nil {
+			return nil, err
+		}
+		result.Path = append(result.Path, parent)
+
+	case DirectionTop:
+		seen := map[string]bool{currentBranch: true}
+		for branch := currentBranch; ; {
+			child, err := w.nextChild(ctx, branch)
+			if err != nil {
+				return nil, err
+			}
+			if child == "" || seen[child] {
+				break
+			}
+			seen[child] = true
+			result.Path = append(result.Path, child)
+			branch = child
+		}
+
+	case DirectionBottom:
+		seen := map[string]bool{currentBranch: true}
+		for branch := currentBranch; ; {
+			parent, err := w.parentOf(ctx, branch, trunk)
+			if err != nil {
+				return nil, err
+			}
+			if parent == trunk || seen[parent] {
+				break
+			}
+			seen[parent] = true
+			result.Path = append(result.Path, parent)
+			branch = parent
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown navigation direction %q", opts.Direction)
+	}
+
+	if len(result.Path) == 0 {
+		return result, nil
+	}
+
+	target := result.Path[len(result.Path)-1]
+	if err := w.repo.CheckoutBranch(target); err != nil {
+		return nil, err
+	}
+	result.To = target
+	result.Moved = true
+
+	logger.Info().
+		Str("from", result.From).
+		Str("to", result.To).
+		Str("direction", string(opts.Direction)).
+		Msg("Moved within stack")
+
+	return result, nil
+}
+
+// parentOf returns the branch that branch is stacked on: its recorded arc
+// parent, then the base of its open PR, falling back to trunk.
+func (w *NavigateWorkflow) parentOf(ctx context.Context, branch, trunk string) (string, error) {
+	recorded, err := w.repo.GetBranchParent(branch)
+	if err != nil {
+		return "", err
+	}
+	if recorded != "" {
+		return recorded, nil
+	}
+
+	prs, err := w.client.FindPRsForBranches(ctx, w.owner, w.name, []string{branch})
+	if err != nil {
+		return "", fmt.Errorf("failed to find the pull request for %s: %w", branch, err)
+	}
+	if pr := prs[branch]; pr != nil && !pr.IsMerged() {
+		return pr.Base.Ref, nil
+	}
+
+	return trunk, nil
+}
+
+// nextChild returns the branch stacked on branch, asking which one to follow
+// when there are several. It returns an empty string when nothing is stacked
+// on branch.
+func (w *NavigateWorkflow) nextChild(ctx context.Context, branch string) (string, error) {
+	children, err := w.childrenOf(ctx, branch)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(children) {
+	case 0:
+		return "", nil
+	case 1:
+		return children[0].branch, nil
+	}
+	return w.chooseChild(branch, children)
+}
+
+// childrenOf collects the branches stacked directly on branch.
+func (w *NavigateWorkflow) childrenOf(ctx context.Context, branch string) ([]stackChild, error) {
+	return findChildren(ctx, w.repo, w.client, w.owner, w.name, branch)
+}
+
+// childRepository defines the git operations needed to find stacked branches.
+type childRepository interface {
+	ListBranches(includeRemote bool) ([]git.BranchInfo, error)
+	GetBranchParent(branch string) (string, error)
+}
+
+// childGitHubClient defines the GitHub operations needed to find stacked branches.
+type childGitHubClient interface {
+	FindDependentPRs(ctx context.Context, owner, repo, baseBranch string) ([]*github.PullRequest, error)
+}
+
+// findChildren collects the open PRs targeting branch and the local branches
+// whose recorded parent is branch. A recorded parent overrides the PR base,
+// so a PR whose branch was re-parented locally is not a child.
+func findChildren(ctx context.Context, repo childRepository, client childGitHubClient, owner, name, branch string) ([]stackChild, error) {
+	var children []stackChild
+	seen := map[string]bool{}
+
+	deps, err := client.FindDependentPRs(ctx, owner, name, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find PRs based on %s: %w", branch, err)
+	}
+
+	fullName := owner + "/" + name
+	for _, pr := range deps {
+		if pr.Head.Repo.FullName != "" && !strings.EqualFold(pr.Head.Repo.FullName, fullName) {
+			continue
+		}
+		recorded, err := repo.GetBranchParent(pr.Head.Ref)
+		if err != nil {
+			return nil, err
+		}
+		if recorded != "" && recorded != branch {
+			continue
+		}
+		if !seen[pr.Head.Ref] {
+			seen[pr.Head.Ref] = true
+			children = append(children, stackChild{branch: pr.Head.Ref, pr: pr})
+		}
+	}
+
+	locals, err := repo.ListBranches(false)
+	if err != nil {
+		return nil, err
+	}
+	for _, local := range locals {
+		if seen[local.Name] || local.Name == branch {
+			continue
+		}
+		recorded, err := repo.GetBranchParent(local.Name)
+		if err != nil {
+			return nil, err
+		}
+		if recorded == branch {
+			seen[local.Name] = true
+			children = append(children, stackChild{branch: local.Name})
+		}
+	}
+
+	sort.Slice(children, func(i, j int) bool { return children[i].branch < children[j].branch })
+	return children, nil
+}
+
+// chooseChild asks which of several children to follow.
+func (w *NavigateWorkflow) chooseChild(branch string, children []stackChild) (string, error) {
+	names := make([]string, len(children))
+	for i, child := range children {
+		names[i] = child.branch
+	}
+
+	if !w.isTerminal() {
+		return "", fmt.Errorf("%w: %s has %d children (%s); check one out with git checkout",
+			ErrAmbiguousChild, branch, len(children), strings.Join(names, ", "))
+	}
+
+	fmt.Fprintf(w.out, "%s has %d children:\n", branch, len(children))
+	for i, child := range children {
+		if child.pr != nil {
+			fmt.Fprintf(w.out, "  %d) %s  #%d %s\n", i+1, child.branch, child.pr.Number, truncate(child.pr.Title, maxTitleLength))
+		} else {
+			fmt.Fprintf(w.out, "  %d) %s\n", i+1, child.branch)
+		}
+	}
+	fmt.Fprintf(w.out, "Which one? [1-%d] ", len(children))
+
+	if w.reader == nil {
+		w.reader = bufio.NewReader(w.stdin)
+	}
+	line, err := w.reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+
+	choice, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || choice < 1 || choice > len(children) {
+		return "", fmt.Errorf("invalid choice %q: expected a number from 1 to %d", strings.TrimSpace(line), len(children))
+	}
+	return children[choice-1].branch, nil
+}
@@ -0,0 +1,248 @@
+package stack
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+type mockNavigateRepo struct {
+	dirty     bool
+	current   string
+	locals    []string
+	parents   map[string]string // recorded arc parents
+	checkouts []string
+}
+
+func (m *mockNavigateRepo) GetWorkingDirectoryStatus() (*git.WorkingDirectoryStatus, error) {
+	return &git.WorkingDirectoryStatus{IsClean: !m.dirty}, nil
+}
+
+func (m *mockNavigateRepo) GetCurrentBranch() (string, error) { return m.current, nil }
+func (m *mockNavigateRepo) GetDefaultBranch() (string, error) { return "main", nil }
+
+func (m *mockNavigateRepo) ListBranches(includeRemote bool) ([]git.BranchInfo, error) {
+	branches := make([]git.BranchInfo, len(m.locals))
+	for i, name := range m.locals {
+		branches[i] = git.BranchInfo{Name: name}
+	}
+	return branches, nil
+}
+
+func (m *mockNavigateRepo) GetBranchParent(branch string) (string, error) {
+	return m.parents[branch], nil
+}
+
+func (m *mockNavigateRepo) CheckoutBranch(branch string) error {
+	m.checkouts = append(m.checkouts, branch)
+	m.current = branch
+	return nil
+}
+
+// mockNavigateClient serves open PRs by head branch.
+type mockNavigateClient struct {
+	prs []*github.PullRequest
+}
+
+func (m *mockNavigateClient) FindPRsForBranches(ctx context.Context, owner, repo string, branches []string) (map[string]*github.PullRequest, error) {
+	found := map[string]*github.PullRequest{}
+	for _, pr := range m.prs {
+		for _, branch := range branches {
+			if pr.Head.Ref == branch {
+				found[branch] = pr
+			}
+		}
+	}
+	return found, nil
+}
+
+func (m *mockNavigateClient) FindDependentPRs(ctx context.Context, owner, repo, baseBranch string) ([]*github.PullRequest, error) {
+	var deps []*github.PullRequest
+	for _, pr := range m.prs {
+		if pr.Base.Ref == baseBranch {
+			deps = append(deps, pr)
+		}
+	}
+	return deps, nil
+}
+
+func navPR(number int, head, base string) *github.PullRequest {
+	return &github.PullRequest{
+		Number: number,
+		Title:  "Change " + head,
+		Head:   github.PRBranch{Ref: head, Repo: github.PRRepository{FullName: "owner/repo"}},
+		Base:   github.PRBranch{Ref: base},
+	}
+}
+
+// newNavigateFixture builds main ← a ← b ← c with b checked out.
+func newNavigateFixture() (*mockNavigateRepo, *mockNavigateClient) {
+	repo := &mockNavigateRepo{
+		current: "b",
+		locals:  []string{"main", "a", "b", "c"},
+		parents: map[string]string{},
+	}
+	client := &mockNavigateClient{prs: []*github.PullRequest{
+		navPR(1, "a", "main"),
+		navPR(2, "b", "a"),
+		navPR(3, "c", "b"),
+	}}
+	return repo, client
+}
+
+func newTestNavigateWorkflow(repo *mockNavigateRepo, client *mockNavigateClient, input string, tty bool) (*NavigateWorkflow, *bytes.Buffer) {
+	var out bytes.Buffer
+	w := NewNavigateWorkflow(repo, client, "owner", "repo")
+	w.out = &out
+	w.stdin = strings.NewReader(input)
+	w.isTerminal = func() bool { return tty }
+	return w, &out
+}
+
+func TestNavigateWorkflow(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("up checks out the child", func(t *testing.T) {
+		repo, client := newNavigateFixture()
+		w, _ := newTestNavigateWorkflow(repo, client, "", false)
+
+		result, err := w.Execute(ctx, &NavigateOptions{Direction: DirectionUp})
+		require.NoError(t, err)
+		assert.Equal(t, "c", result.To)
+		assert.True(t, result.Moved)
+		assert.Equal(t, []string{"c"}, repo.checkouts)
+	})
+
+	t.Run("down checks out the parent", func(t *testing.T) {
+		repo, client := newNavigateFixture()
+		w, _ := newTestNavigateWorkflow(repo, client, "", false)
+
+		result, err := w.Execute(ctx, &NavigateOptions{Direction: DirectionDown})
+		require.NoError(t, err)
+		assert.Equal(t, "a", result.To)
+	})
+
+	t.Run("down from the bottom branch goes to trunk", func(t *testing.T) {
+		repo, client := newNavigateFixture()
+		repo.current = "a"
+		w, _ := newTestNavigateWorkflow(repo, client, "", false)
+
+		result, err := w.Execute(ctx, &NavigateOptions{Direction: DirectionDown})
+		require.NoError(t, err)
+		assert.Equal(t, "main", result.To)
+	})
+
+	t.Run("recorded parent wins over the PR base", func(t *testing.T) {
+		repo, client := newNavigateFixture()
+		repo.parents["b"] = "main"
+		w, _ := newTestNavigateWorkflow(repo, client, "", false)
+
+		result, err := w.Execute(ctx, &NavigateOptions{Direction: DirectionDown})
+		require.NoError(t, err)
+		assert.Equal(t, "main", result.To)
+	})
+
+	t.Run("top and bottom walk the whole stack", func(t *testing.T) {
+		repo, client := newNavigateFixture()
+		repo.current = "a"
+		w, _ := newTestNavigateWorkflow(repo, client, "", false)
+
+		result, err := w.Execute(ctx, &NavigateOptions{Direction: DirectionTop})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b", "c"}, result.Path)
+		assert.Equal(t, []string{"c"}, repo.checkouts, "only the target is checked out")
+
+		result, err = w.Execute(ctx, &NavigateOptions{Direction: DirectionBottom})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b", "a"}, result.Path)
+		assert.Equal(t, "a", repo.current)
+	})
+
+	t.Run("already at the end", func(t *testing.T) {
+		repo, client := newNavigateFixture()
+		repo.current = "c"
+		w, _ := newTestNavigateWorkflow(repo, client, "", false)
+
+		result, err := w.Execute(ctx, &NavigateOptions{Direction: DirectionTop})
+		require.NoError(t, err)
+		assert.False(t, result.Moved)
+		assert.Empty(t, repo.checkouts)
+
+		_, err = w.Execute(ctx, &NavigateOptions{Direction: DirectionUp})
+		assert.ErrorIs(t, err, ErrStackEnd)
+	})
+
+	t.Run("finds local children without a PR", func(t *testing.T) {
+		repo, client := newNavigateFixture()
+		repo.current = "c"
+		repo.locals = append(repo.locals, "d")
+		repo.parents["d"] = "c"
+		w, _ := newTestNavigateWorkflow(repo, client, "", false)
+
+		result, err := w.Execute(ctx, &NavigateOptions{Direction: DirectionUp})
+		require.NoError(t, err)
+		assert.Equal(t, "d", result.To)
+	})
+
+	t.Run("prompts when there are several children", func(t *testing.T) {
+		repo, client := newNavigateFixture()
+		client.prs = append(client.prs, navPR(4, "b2", "a"))
+		repo.current = "a"
+		w, out := newTestNavigateWorkflow(repo, client, "2\n", true)
+
+		result, err := w.Execute(ctx, &NavigateOptions{Direction: DirectionUp})
+		require.NoError(t, err)
+		assert.Equal(t, "b2", result.To)
+		assert.Contains(t, out.String(), "a has 2 children:")
+		assert.Contains(t, out.String(), "2) b2  #4 Change b2")
+	})
+
+	t.Run("several children without a terminal", func(t *testing.T) {
+		repo, client := newNavigateFixture()
+		client.prs = append(client.prs, navPR(4, "b2", "a"))
+		repo.current = "a"
+		w, _ := newTestNavigateWorkflow(repo, client, "", false)
+
+		_, err := w.Execute(ctx, &NavigateOptions{Direction: DirectionUp})
+		assert.ErrorIs(t, err, ErrAmbiguousChild)
+		assert.Contains(t, err.Error(), "b, b2")
+		assert.Empty(t, repo.checkouts)
+	})
+
+	t.Run("invalid choice", func(t *testing.T) {
+		repo, client := newNavigateFixture()
+		client.prs = append(client.prs, navPR(4, "b2", "a"))
+		repo.current = "a"
+		w, _ := newTestNavigateWorkflow(repo, client, "7\n", true)
+
+		_, err := w.Execute(ctx, &NavigateOptions{Direction: DirectionUp})
+		assert.ErrorContains(t, err, "invalid choice")
+		assert.Empty(t, repo.checkouts)
+	})
+
+	t.Run("refuses a dirty working tree", func(t *testing.T) {
+		repo, client := newNavigateFixture()
+		repo.dirty = true
+		w, _ := newTestNavigateWorkflow(repo, client, "", false)
+
+		_, err := w.Execute(ctx, &NavigateOptions{Direction: DirectionUp})
+		assert.ErrorIs(t, err, ErrDirtyWorkingDir)
+		assert.Empty(t, repo.checkouts)
+	})
+
+	t.Run("refuses trunk", func(t *testing.T) {
+		repo, client := newNavigateFixture()
+		repo.current = "main"
+		w, _ := newTestNavigateWorkflow(repo, client, "", false)
+
+		_, err := w.Execute(ctx, &NavigateOptions{Direction: DirectionTop})
+		assert.ErrorIs(t, err, ErrOnTrunk)
+	})
+}
@@ -0,0 +1,316 @@
+package stack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/serpro69/gh-arc/internal/format"
+)
+
+// maxTitleLength is the longest PR title shown in the tree.
+const maxTitleLength = 50
+
+// PrintResult writes the stack as a tree, grouped by the branch the bottom
+// PR of each stack targets.
+func PrintResult(out io.Writer, result *StackResult, useColor bool) {
+	if len(result.Nodes) == 0 {
+		fmt.Fprintln(out, "No open pull requests")
+		return
+	}
+
+	if !result.InStack && result.CurrentBranch != "" && result.CurrentBranch != result.Trunk {
+		fmt.Fprintf(out, "⚠ %s has no open pull request; showing all stacks\n\n", result.CurrentBranch)
+	}
+
+	children := make(map[string][]StackNode)
+	var roots []StackNode
+	for _, node := range result.Nodes {
+		if node.Depth == 0 {
+			roots = append(roots, node)
+			continue
+		}
+		children[node.Base] = append(children[node.Base], node)
+	}
+
+	// Group roots by base, keeping first-seen order
+	var bases []string
+	byBase := make(map[string][]StackNode)
+	for _, root := range roots {
+		if _, seen := byBase[root.Base]; !seen {
+			bases = append(bases, root.Base)
+		}
+		byBase[root.Base] = append(byBase[root.Base], root)
+	}
+
+	for i, base := range bases {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintln(out, base)
+		printChildren(out, byBase[base], children, "", useColor)
+	}
+}
+
+func printChildren(out io.Writer, nodes []StackNode, children map[string][]StackNode, prefix string, useColor bool) {
+	for i, node := range nodes {
+		connector, indent := "├── ", "│   "
+		if i == len(nodes)-1 {
+			connector, indent = "└── ", "    "
+		}
+
+		fmt.Fprintf(out, "%s%s%s\n", prefix, connector, formatNode(node, useColor))
+		printChildren(out, children[node.Branch], children, prefix+indent, useColor)
+	}
+}
+
+func formatNode(node StackNode, useColor bool) string {
+	marker := ""
+	if node.Current {
+		marker = "* "
+	}
+	parts := []string{fmt.Sprintf("%s#%d %s", marker, node.PRNumber, node.Branch)}
+	if node.Title != "" {
+		parts = append(parts, truncate(node.Title, maxTitleLength))
+	}
+	if node.Draft {
+		parts = append(parts, "[draft]")
+	}
+	if node.ReviewStatus != "" {
+		parts = append(parts, format.ReviewStatusLabel(node.ReviewStatus, useColor))
+	}
+	if node.CheckStatus != "" {
+		parts = append(parts, format.CheckStatusLabel(node.CheckStatus, useColor))
+	}
+	if node.NeedsRebase {
+		parts = append(parts, "⚠ needs rebase")
+	}
+	return strings.Join(parts, "  ")
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// FormatJSON returns the result as indented JSON.
+func FormatJSON(result *StackResult) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// PrintRestackResult writes a summary of a restack.
+func PrintRestackResult(out io.Writer, result *RestackResult) {
+	if result.Aborted {
+		fmt.Fprintf(out, "✓ Restack aborted; branches above %s restored\n", result.Root)
+		return
+	}
+
+	if len(result.Rebased) == 0 && len(result.UpToDate) == 0 && len(result.Skipped) == 0 {
+		fmt.Fprintf(out, "No branches are stacked on %s\n", result.Root)
+	}
+	for _, branch := range result.Rebased {
+		fmt.Fprintf(out, "✓ Rebased %s\n", branch)
+	}
+	for _, branch := range result.UpToDate {
+		fmt.Fprintf(out, "✓ %s is up to date\n", branch)
+	}
+	for _, skip := range result.Skipped {
+		fmt.Fprintf(out, "⚠ Skipped %s: %s\n", skip.Branch, skip.Reason)
+	}
+
+	if len(result.Pushed) > 0 {
+		fmt.Fprintf(out, "✓ Pushed %s\n", strings.Join(result.Pushed, ", "))
+	}
+	for _, failure := range result.PushFailures {
+		fmt.Fprintf(out, "✗ Failed to push %s: %s\n", failure.Branch, failure.Reason)
+	}
+}
+
+// FormatRestackJSON returns the restack result as indented JSON.
+func FormatRestackJSON(result *RestackResult) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// PrintSubmitResult writes one line per submitted PR, bottom first.
+func PrintSubmitResult(out io.Writer, result *SubmitResult) {
+	for _, entry := range result.Entries {
+		verb := "Updated"
+		if entry.Created {
+			verb = "Created"
+		}
+
+		var notes []string
+		if entry.Pushed && !entry.Created {
+			notes = append(notes, "pushed")
+		}
+		if entry.BaseChanged {
+			notes = append(notes, "base changed")
+		}
+		suffix := ""
+		if len(notes) > 0 {
+			suffix = " (" + strings.Join(notes, ", ") + ")"
+		}
+
+		fmt.Fprintf(out, "✓ %s #%d %s → %s%s\n", verb, entry.PRNumber, entry.Branch, entry.Base, suffix)
+		if entry.PRURL != "" {
+			fmt.Fprintf(out, "  %s\n", entry.PRURL)
+		}
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(out, "⚠ %s\n", warning)
+	}
+}
+
+// FormatSubmitJSON returns the submit result as indented JSON.
+func FormatSubmitJSON(result *SubmitResult) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// PrintParentResult writes the recorded parent of a branch, or what changed.
+func PrintParentResult(out io.Writer, result *ParentResult) {
+	switch {
+	case result.Changed && result.Parent == "":
+		fmt.Fprintf(out, "✓ Removed recorded parent %s of %s\n", result.Previous, result.Branch)
+	case result.Changed:
+		fmt.Fprintf(out, "✓ %s now stacks on %s%s\n", result.Branch, result.Parent, forkPointSuffix(result.ParentSHA))
+	case result.Parent == "":
+		fmt.Fprintf(out, "%s has no recorded parent; gh arc diff detects its base automatically\n", result.Branch)
+	default:
+		fmt.Fprintf(out, "%s stacks on %s%s\n", result.Branch, result.Parent, forkPointSuffix(result.ParentSHA))
+	}
+}
+
+func forkPointSuffix(sha string) string {
+	if sha == "" {
+		return ""
+	}
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+	return " (forked at " + sha + ")"
+}
+
+// FormatParentJSON returns the parent result as indented JSON.
+func FormatParentJSON(result *ParentResult) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// PrintNavigateResult writes where a navigation command moved to.
+func PrintNavigateResult(out io.Writer, result *NavigateResult) {
+	if !result.Moved {
+		fmt.Fprintf(out, "Already at the %s of the stack (%s)\n", result.Direction, result.From)
+		return
+	}
+
+	if len(result.Path) > 1 {
+		fmt.Fprintf(out, "✓ Checked out %s, %d branches %s from %s\n",
+			result.To, len(result.Path), stepDirection(result.Direction), result.From)
+		return
+	}
+	fmt.Fprintf(out, "✓ Checked out %s (%s from %s)\n", result.To, stepDirection(result.Direction), result.From)
+}
+
+// stepDirection reports top and bottom moves as the single steps they are made of.
+func stepDirection(d Direction) Direction {
+	switch d {
+	case DirectionTop:
+		return DirectionUp
+	case DirectionBottom:
+		return DirectionDown
+	}
+	return d
+}
+
+// FormatNavigateJSON returns the navigate result as indented JSON.
+func FormatNavigateJSON(result *NavigateResult) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// PrintSplitResult writes the branches created by a split, bottom first,
+// followed by the submitted PRs.
+func PrintSplitResult(out io.Writer, result *SplitResult) {
+	how := "in place"
+	if result.Rewritten {
+		how = "with rewritten commits"
+	}
+	fmt.Fprintf(out, "✓ Split %s into %d branches on %s, %s\n", result.Original, len(result.Branches), result.Base, how)
+
+	reused := false
+	for _, b := range result.Branches {
+		noun := "commits"
+		if b.Commits == 1 {
+			noun = "commit"
+		}
+		fmt.Fprintf(out, "  %s → %s (%d %s)\n", b.Branch, b.Parent, b.Commits, noun)
+		reused = reused || b.Branch == result.Original
+	}
+
+	if !reused {
+		fmt.Fprintf(out, "  %s is unchanged\n", result.Original)
+	}
+	fmt.Fprintf(out, "  Original tip saved as %s (%s)\n", result.Backup, shortSHA(result.BackupSHA))
+
+	if result.Submit != nil {
+		fmt.Fprintln(out)
+		PrintSubmitResult(out, result.Submit)
+	}
+}
+
+// FormatSplitJSON returns the split result as indented JSON.
+func FormatSplitJSON(result *SplitResult) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// PrintFoldResult writes a summary of a fold or drop.
+func PrintFoldResult(out io.Writer, result *FoldResult) {
+	what := result.Branch
+	if result.PR != 0 {
+		what = fmt.Sprintf("%s (#%d)", result.Branch, result.PR)
+	}
+	if result.Mode == FoldModeFold {
+		fmt.Fprintf(out, "✓ Folded %s into %s\n", what, result.Parent)
+	} else {
+		fmt.Fprintf(out, "✓ Dropped %s from the stack on %s\n", what, result.Parent)
+	}
+
+	for _, branch := range result.Rebased {
+		fmt.Fprintf(out, "✓ Rebased %s onto %s\n", branch, result.Parent)
+	}
+	for _, branch := range result.UpToDate {
+		fmt.Fprintf(out, "✓ %s is up to date\n", branch)
+	}
+	for _, skip := range result.Skipped {
+		fmt.Fprintf(out, "⚠ Skipped %s: %s\n", skip.Branch, skip.Reason)
+	}
+	if len(result.Pushed) > 0 {
+		fmt.Fprintf(out, "✓ Pushed %s\n", strings.Join(result.Pushed, ", "))
+	}
+	for _, number := range result.Retargeted {
+		fmt.Fprintf(out, "✓ Retargeted #%d to %s\n", number, result.Parent)
+	}
+	switch {
+	case result.Closed:
+		fmt.Fprintf(out, "✓ Closed #%d with a note on where its changes went\n", result.PR)
+	case result.Commented:
+		fmt.Fprintf(out, "✓ Left a note on #%d\n", result.PR)
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(out, "⚠ %s\n", warning)
+	}
+
+	if result.Deleted {
+		fmt.Fprintf(out, "  Deleted %s; its tip is saved as %s (%s)\n", result.Branch, result.Backup, shortSHA(result.BackupSHA))
+	}
+	for _, branch := range result.NeedsRestack {
+		fmt.Fprintf(out, "  Run gh arc stack restack %s to update the branches stacked on it\n", branch)
+	}
+}
+
+// FormatFoldJSON returns the fold result as indented JSON.
+func FormatFoldJSON(result *FoldResult) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
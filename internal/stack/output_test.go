@@ -0,0 +1,292 @@
+package stack
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintResult(t *testing.T) {
+	t.Run("tree", func(t *testing.T) {
+		result := &StackResult{
+			Trunk:         "main",
+			CurrentBranch: "b",
+			InStack:       true,
+			Nodes: []StackNode{
+				{Branch: "a", Base: "main", PRNumber: 1, ReviewStatus: "approved", CheckStatus: "success"},
+				{Branch: "b", Base: "a", PRNumber: 2, Depth: 1, Current: true, Draft: true},
+				{Branch: "d", Base: "b", PRNumber: 4, Depth: 2, NeedsRebase: true},
+				{Branch: "c", Base: "a", PRNumber: 3, Depth: 1},
+				{Branch: "fix", Base: "release", PRNumber: 5},
+			},
+		}
+
+		var buf bytes.Buffer
+		PrintResult(&buf, result, false)
+
+		expected := "main\n" +
+			"└── #1 a  ✓ Approved  ✓ Success\n" +
+			"    ├── * #2 b  [draft]\n" +
+			"    │   └── #4 d  ⚠ needs rebase\n" +
+			"    └── #3 c\n" +
+			"\n" +
+			"release\n" +
+			"└── #5 fix\n"
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("long titles are truncated", func(t *testing.T) {
+		result := &StackResult{Nodes: []StackNode{{
+			Branch: "a", Base: "main", PRNumber: 1,
+			Title: "An extremely long pull request title that keeps going and going",
+		}}}
+
+		var buf bytes.Buffer
+		PrintResult(&buf, result, false)
+		assert.Contains(t, buf.String(), "#1 a  An extremely long pull request title that keeps g…\n")
+	})
+
+	t.Run("branch without PR", func(t *testing.T) {
+		result := &StackResult{
+			Trunk:         "main",
+			CurrentBranch: "wip",
+			All:           true,
+			Nodes:         []StackNode{{Branch: "a", Base: "main", PRNumber: 1}},
+		}
+
+		var buf bytes.Buffer
+		PrintResult(&buf, result, false)
+		assert.Contains(t, buf.String(), "⚠ wip has no open pull request; showing all stacks")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintResult(&buf, &StackResult{}, false)
+		assert.Equal(t, "No open pull requests\n", buf.String())
+	})
+}
+
+func TestFormatJSON(t *testing.T) {
+	result := &StackResult{
+		Trunk: "main",
+		Nodes: []StackNode{{Branch: "a", Base: "main", PRNumber: 1}, {Branch: "b", Base: "a", PRNumber: 2, Depth: 1}},
+		Edges: []StackEdge{{From: "a", To: "b"}},
+	}
+
+	data, err := FormatJSON(result)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+		Edges []map[string]string      `json:"edges"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Len(t, decoded.Nodes, 2)
+	assert.Equal(t, "b", decoded.Nodes[1]["branch"])
+	assert.Equal(t, []map[string]string{{"from": "a", "to": "b"}}, decoded.Edges)
+}
+
+func TestPrintRestackResult(t *testing.T) {
+	t.Run("summary", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintRestackResult(&buf, &RestackResult{
+			Root:         "a",
+			Rebased:      []string{"b"},
+			UpToDate:     []string{"c"},
+			Skipped:      []RestackSkip{{Branch: "d", Reason: "not checked out locally"}},
+			Pushed:       []string{"a", "b"},
+			PushFailures: []RestackSkip{{Branch: "e", Reason: "rejected"}},
+		})
+
+		expected := "✓ Rebased b\n" +
+			"✓ c is up to date\n" +
+			"⚠ Skipped d: not checked out locally\n" +
+			"✓ Pushed a, b\n" +
+			"✗ Failed to push e: rejected\n"
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("nothing stacked", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintRestackResult(&buf, &RestackResult{Root: "a"})
+		assert.Equal(t, "No branches are stacked on a\n", buf.String())
+	})
+
+	t.Run("aborted", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintRestackResult(&buf, &RestackResult{Root: "a", Aborted: true})
+		assert.Contains(t, buf.String(), "Restack aborted")
+	})
+}
+
+func TestPrintSubmitResult(t *testing.T) {
+	var buf bytes.Buffer
+	PrintSubmitResult(&buf, &SubmitResult{
+		Trunk: "main",
+		Entries: []SubmitEntry{
+			{Branch: "a", Base: "main", PRNumber: 1, PRURL: "https://github.com/owner/repo/pull/1", Pushed: true, BaseChanged: true},
+			{Branch: "b", Base: "a", PRNumber: 2, Created: true, Pushed: true},
+		},
+		Warnings: []string{"failed to cross-link PR #2: boom"},
+	})
+
+	expected := "✓ Updated #1 a → main (pushed, base changed)\n" +
+		"  https://github.com/owner/repo/pull/1\n" +
+		"✓ Created #2 b → a\n" +
+		"⚠ failed to cross-link PR #2: boom\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestPrintParentResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *ParentResult
+		want   string
+	}{
+		{
+			name:   "recorded",
+			result: &ParentResult{Branch: "b", Parent: "a", ParentSHA: "0123456789abcdef"},
+			want:   "b stacks on a (forked at 0123456)\n",
+		},
+		{
+			name:   "not recorded",
+			result: &ParentResult{Branch: "b"},
+			want:   "b has no recorded parent; gh arc diff detects its base automatically\n",
+		},
+		{
+			name:   "set",
+			result: &ParentResult{Branch: "b", Parent: "main", ParentSHA: "abc", Previous: "a", Changed: true},
+			want:   "✓ b now stacks on main (forked at abc)\n",
+		},
+		{
+			name:   "unset",
+			result: &ParentResult{Branch: "b", Previous: "a", Changed: true},
+			want:   "✓ Removed recorded parent a of b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			PrintParentResult(&buf, tt.result)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestPrintNavigateResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *NavigateResult
+		want   string
+	}{
+		{
+			name:   "one step",
+			result: &NavigateResult{Direction: DirectionUp, From: "a", To: "b", Path: []string{"b"}, Moved: true},
+			want:   "✓ Checked out b (up from a)\n",
+		},
+		{
+			name:   "several steps",
+			result: &NavigateResult{Direction: DirectionBottom, From: "c", To: "a", Path: []string{"b", "a"}, Moved: true},
+			want:   "✓ Checked out a, 2 branches down from c\n",
+		},
+		{
+			name:   "already there",
+			result: &NavigateResult{Direction: DirectionTop, From: "c", To: "c", Path: []string{}},
+			want:   "Already at the top of the stack (c)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			PrintNavigateResult(&buf, tt.result)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestPrintSplitResult(t *testing.T) {
+	result := &SplitResult{
+		Original:  "big",
+		Base:      "main",
+		Backup:    "refs/gh-arc/backup/big",
+		BackupSHA: "0123456789abcdef",
+		Branches: []SplitBranch{
+			{Branch: "api", Parent: "main", Commits: 1},
+			{Branch: "ui", Parent: "api", Commits: 2},
+		},
+		Submit: &SubmitResult{Entries: []SubmitEntry{{Branch: "api", Base: "main", PRNumber: 7, Created: true}}},
+	}
+
+	var buf bytes.Buffer
+	PrintSplitResult(&buf, result)
+
+	expected := "✓ Split big into 2 branches on main, in place\n" +
+		"  api → main (1 commit)\n" +
+		"  ui → api (2 commits)\n" +
+		"  big is unchanged\n" +
+		"  Original tip saved as refs/gh-arc/backup/big (0123456)\n" +
+		"\n" +
+		"✓ Created #7 api → main\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestPrintFoldResult(t *testing.T) {
+	t.Run("drop", func(t *testing.T) {
+		result := &FoldResult{
+			Mode:         FoldModeDrop,
+			Branch:       "b",
+			PR:           2,
+			Parent:       "a",
+			Backup:       "refs/gh-arc/backup/b",
+			BackupSHA:    "0123456789abcdef",
+			Rebased:      []string{"c"},
+			Skipped:      []RestackSkip{{Branch: "e", Reason: "not checked out locally"}},
+			Pushed:       []string{"c"},
+			Retargeted:   []int{3, 5},
+			Commented:    true,
+			Closed:       true,
+			Deleted:      true,
+			NeedsRestack: []string{"c"},
+			Warnings:     []string{"failed to push d: boom"},
+		}
+
+		var buf bytes.Buffer
+		PrintFoldResult(&buf, result)
+
+		expected := "✓ Dropped b (#2) from the stack on a\n" +
+			"✓ Rebased c onto a\n" +
+			"⚠ Skipped e: not checked out locally\n" +
+			"✓ Pushed c\n" +
+			"✓ Retargeted #3 to a\n" +
+			"✓ Retargeted #5 to a\n" +
+			"✓ Closed #2 with a note on where its changes went\n" +
+			"⚠ failed to push d: boom\n" +
+			"  Deleted b; its tip is saved as refs/gh-arc/backup/b (0123456)\n" +
+			"  Run gh arc stack restack c to update the branches stacked on it\n"
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("fold of a merged PR", func(t *testing.T) {
+		result := &FoldResult{
+			Mode:      FoldModeFold,
+			Branch:    "b",
+			PR:        2,
+			Parent:    "a",
+			UpToDate:  []string{"c"},
+			Commented: true,
+		}
+
+		var buf bytes.Buffer
+		PrintFoldResult(&buf, result)
+
+		expected := "✓ Folded b (#2) into a\n" +
+			"✓ c is up to date\n" +
+			"✓ Left a note on #2\n"
+		assert.Equal(t, expected, buf.String())
+	})
+}
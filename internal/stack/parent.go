@@ -0,0 +1,147 @@
+package stack
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+// ErrParentCycle is returned when a new parent would make a branch stack on
+// itself, directly or through the recorded parents of its ancestors.
+var ErrParentCycle = errors.New("parent would create a cycle")
+
+// ParentRepository defines git operations needed by the parent workflow.
+type ParentRepository interface {
+	GetCurrentBranch() (string, error)
+	BranchExists(branchName string) (bool, error)
+	GetMergeBase(ref1, ref2 string) (string, error)
+	GetBranchParent(branch string) (string, error)
+	GetBranchParentSHA(branch string) (string, error)
+	SetBranchParent(branch, parent, parentSHA string) error
+	UnsetBranchParent(branch string) error
+}
+
+// ParentWorkflow shows and edits the stack parent recorded for a branch in
+// git config (branch.<name>.arc-parent and branch.<name>.arc-parent-sha).
+type ParentWorkflow struct {
+	repo ParentRepository
+}
+
+// NewParentWorkflow creates a new ParentWorkflow.
+func NewParentWorkflow(repo ParentRepository) *ParentWorkflow {
+	return &ParentWorkflow{repo: repo}
+}
+
+// Execute runs the parent workflow.
+func (w *ParentWorkflow) Execute(opts *ParentOptions) (*ParentResult, error) {
+	if opts == nil {
+		opts = &ParentOptions{}
+	}
+
+	logger.Debug().
+		Str("branch", opts.Branch).
+		Str("set", opts.Set).
+		Bool("unset", opts.Unset).
+		Msg("Executing parent workflow")
+
+	branch := opts.Branch
+	if branch == "" {
+		current, err := w.repo.GetCurrentBranch()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current branch: %w", err)
+		}
+		branch = current
+	}
+
+	exists, err := w.repo.BranchExists("refs/heads/" + branch)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrBranchNotFound, branch)
+	}
+
+	previous, err := w.repo.GetBranchParent(branch)
+	if err != nil {
+		return nil, err
+	}
+	previousSHA, err := w.repo.GetBranchParentSHA(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ParentResult{Branch: branch, Parent: previous, ParentSHA: previousSHA}
+
+	switch {
+	case opts.Unset:
+		if previous == "" {
+			return result, nil
+		}
+		if err := w.repo.UnsetBranchParent(branch); err != nil {
+			return nil, err
+		}
+		result.Parent, result.ParentSHA = "", ""
+		result.Previous = previous
+		result.Changed = true
+
+	case opts.Set != "":
+		forkPoint, err := w.validateParent(branch, opts.Set)
+		if err != nil {
+			return nil, err
+		}
+		if err := w.repo.SetBranchParent(branch, opts.Set, forkPoint); err != nil {
+			return nil, err
+		}
+		result.Parent, result.ParentSHA = opts.Set, forkPoint
+		result.Previous = previous
+		result.Changed = true
+	}
+
+	return result, nil
+}
+
+// validateParent checks that parent exists and does not stack on branch, and
+// returns the commit where branch forked from it.
+func (w *ParentWorkflow) validateParent(branch, parent string) (string, error) {
+	if parent == branch {
+		return "", fmt.Errorf("%w: %s cannot be its own parent", ErrParentCycle, branch)
+	}
+
+	ref := "refs/heads/" + parent
+	exists, err := w.repo.BranchExists(ref)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		// A parent that is only on origin, e.g. a teammate's branch
+		ref = "origin/" + parent
+		if exists, err = w.repo.BranchExists(ref); err != nil {
+			return "", err
+		}
+	}
+	if !exists {
+		return "", fmt.Errorf("%w: %s", ErrBranchNotFound, parent)
+	}
+
+	seen := map[string]bool{}
+	for ancestor := parent; ancestor != ""; {
+		if ancestor == branch {
+			return "", fmt.Errorf("%w: %s already stacks on %s", ErrParentCycle, parent, branch)
+		}
+		if seen[ancestor] {
+			break
+		}
+		seen[ancestor] = true
+
+		if ancestor, err = w.repo.GetBranchParent(ancestor); err != nil {
+			return "", err
+		}
+	}
+
+	forkPoint, err := w.repo.GetMergeBase(branch, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to find where %s forked from %s: %w", branch, parent, err)
+	}
+	return forkPoint, nil
+}
@@ -0,0 +1,132 @@
+package stack
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockParentRepo struct {
+	current  string
+	branches map[string]bool   // refs that exist, e.g. "refs/heads/a", "origin/x"
+	parents  map[string]string // branch -> "parent@sha"
+}
+
+func newMockParentRepo() *mockParentRepo {
+	return &mockParentRepo{
+		current: "b",
+		branches: map[string]bool{
+			"refs/heads/main": true,
+			"refs/heads/a":    true,
+			"refs/heads/b":    true,
+			"origin/remote":   true,
+		},
+		parents: map[string]string{"a": "main@main-sha", "b": "a@a-sha"},
+	}
+}
+
+func (m *mockParentRepo) GetCurrentBranch() (string, error) { return m.current, nil }
+
+func (m *mockParentRepo) BranchExists(branchName string) (bool, error) {
+	return m.branches[branchName], nil
+}
+
+func (m *mockParentRepo) GetMergeBase(ref1, ref2 string) (string, error) {
+	return "mb(" + ref1 + "," + ref2 + ")", nil
+}
+
+func (m *mockParentRepo) GetBranchParent(branch string) (string, error) {
+	parent, _, _ := strings.Cut(m.parents[branch], "@")
+	return parent, nil
+}
+
+func (m *mockParentRepo) GetBranchParentSHA(branch string) (string, error) {
+	_, sha, _ := strings.Cut(m.parents[branch], "@")
+	return sha, nil
+}
+
+func (m *mockParentRepo) SetBranchParent(branch, parent, parentSHA string) error {
+	m.parents[branch] = parent + "@" + parentSHA
+	return nil
+}
+
+func (m *mockParentRepo) UnsetBranchParent(branch string) error {
+	delete(m.parents, branch)
+	return nil
+}
+
+func TestParentWorkflow(t *testing.T) {
+	t.Run("shows the current branch's parent", func(t *testing.T) {
+		result, err := NewParentWorkflow(newMockParentRepo()).Execute(nil)
+		require.NoError(t, err)
+		assert.Equal(t, &ParentResult{Branch: "b", Parent: "a", ParentSHA: "a-sha"}, result)
+	})
+
+	t.Run("shows a branch without a parent", func(t *testing.T) {
+		repo := newMockParentRepo()
+		delete(repo.parents, "a")
+
+		result, err := NewParentWorkflow(repo).Execute(&ParentOptions{Branch: "a"})
+		require.NoError(t, err)
+		assert.Empty(t, result.Parent)
+		assert.False(t, result.Changed)
+	})
+
+	t.Run("sets a local parent with its fork point", func(t *testing.T) {
+		repo := newMockParentRepo()
+
+		result, err := NewParentWorkflow(repo).Execute(&ParentOptions{Set: "main"})
+		require.NoError(t, err)
+		assert.True(t, result.Changed)
+		assert.Equal(t, "a", result.Previous)
+		assert.Equal(t, "main", result.Parent)
+		assert.Equal(t, "mb(b,refs/heads/main)", result.ParentSHA)
+		assert.Equal(t, "main@mb(b,refs/heads/main)", repo.parents["b"])
+	})
+
+	t.Run("sets a parent that only exists on origin", func(t *testing.T) {
+		repo := newMockParentRepo()
+
+		result, err := NewParentWorkflow(repo).Execute(&ParentOptions{Set: "remote"})
+		require.NoError(t, err)
+		assert.Equal(t, "mb(b,origin/remote)", result.ParentSHA)
+	})
+
+	t.Run("unsets the parent", func(t *testing.T) {
+		repo := newMockParentRepo()
+
+		result, err := NewParentWorkflow(repo).Execute(&ParentOptions{Unset: true})
+		require.NoError(t, err)
+		assert.True(t, result.Changed)
+		assert.Equal(t, "a", result.Previous)
+		assert.Empty(t, result.Parent)
+		assert.NotContains(t, repo.parents, "b")
+	})
+
+	t.Run("unsetting a missing parent changes nothing", func(t *testing.T) {
+		repo := newMockParentRepo()
+		delete(repo.parents, "b")
+
+		result, err := NewParentWorkflow(repo).Execute(&ParentOptions{Unset: true})
+		require.NoError(t, err)
+		assert.False(t, result.Changed)
+	})
+
+	t.Run("refuses cycles", func(t *testing.T) {
+		_, err := NewParentWorkflow(newMockParentRepo()).Execute(&ParentOptions{Branch: "a", Set: "b"})
+		assert.ErrorIs(t, err, ErrParentCycle)
+
+		_, err = NewParentWorkflow(newMockParentRepo()).Execute(&ParentOptions{Set: "b"})
+		assert.ErrorIs(t, err, ErrParentCycle)
+	})
+
+	t.Run("unknown branch or parent", func(t *testing.T) {
+		_, err := NewParentWorkflow(newMockParentRepo()).Execute(&ParentOptions{Branch: "nope"})
+		assert.ErrorIs(t, err, ErrBranchNotFound)
+
+		_, err = NewParentWorkflow(newMockParentRepo()).Execute(&ParentOptions{Set: "nope"})
+		assert.ErrorIs(t, err, ErrBranchNotFound)
+	})
+}
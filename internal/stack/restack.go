@@ -0,0 +1,478 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+var (
+	// ErrDirtyWorkingDir is returned when the working directory has uncommitted changes.
+	ErrDirtyWorkingDir = errors.New("working directory has uncommitted changes")
+
+	// ErrRestackInProgress is returned when a new restack is started while
+	// another one is stopped.
+	ErrRestackInProgress = errors.New("a restack is already in progress")
+
+	// ErrNoRestackInProgress is returned by --continue and --abort when there
+	// is nothing to resume.
+	ErrNoRestackInProgress = errors.New("no restack in progress")
+
+	// ErrRestackConflict is returned when a rebase stops on conflicts. The
+	// restack can be resumed once they are resolved.
+	ErrRestackConflict = errors.New("restack stopped on conflicts")
+
+	// ErrBranchNotFound is returned when the branch to restack from does not exist locally.
+	ErrBranchNotFound = errors.New("branch not found")
+)
+
+// RestackRepository defines git operations needed by the restack workflow.
+type RestackRepository interface {
+	GitDir() (string, error)
+	GetWorkingDirectoryStatus() (*git.WorkingDirectoryStatus, error)
+	GetCurrentBranch() (string, error)
+	GetDefaultBranch() (string, error)
+	BranchExists(branchName string) (bool, error)
+	GetBranchSHA(branch string) (string, error)
+	IsAncestor(ancestorRef, descendantRef string) (bool, error)
+	GetMergeBase(ref1, ref2 string) (string, error)
+	GetForkPoint(upstream, branch string) (string, error)
+	RebaseOnto(newBase, upstream, branch string) error
+	ContinueRebase() error
+	AbortRebase() error
+	IsRebaseInProgress() (bool, error)
+	SetBranchRef(branch, sha string) error
+	CheckoutBranch(branch string) error
+	Push(ctx context.Context, branchName string) error
+	GetBranchParent(branch string) (string, error)
+	GetBranchParentSHA(branch string) (string, error)
+	SetBranchParent(branch, parent, parentSHA string) error
+}
+
+// RestackGitHubClient defines GitHub operations needed by the restack workflow.
+type RestackGitHubClient interface {
+	FindDependentPRs(ctx context.Context, owner, repo, baseBranch string) ([]*github.PullRequest, error)
+}
+
+// RestackWorkflow rebases every descendant of a branch onto its updated
+// parent, bottom-up, and pushes the results.
+type RestackWorkflow struct {
+	repo   RestackRepository
+	client RestackGitHubClient
+	owner  string
+	name   string
+}
+
+// NewRestackWorkflow creates a new RestackWorkflow.
+func NewRestackWorkflow(repo RestackRepository, client RestackGitHubClient, owner, name string) *RestackWorkflow {
+	return &RestackWorkflow{
+		repo:   repo,
+		client: client,
+		owner:  owner,
+		name:   name,
+	}
+}
+
+// Execute runs the restack workflow.
+func (w *RestackWorkflow) Execute(ctx context.Context, opts *RestackOptions) (*RestackResult, error) {
+	if opts == nil {
+		opts = &RestackOptions{}
+	}
+
+	logger.Debug().
+		Str("branch", opts.Branch).
+		Bool("continue", opts.Continue).
+		Bool("abort", opts.Abort).
+		Msg("Executing restack workflow")
+
+	gitDir, err := w.repo.GitDir()
+	if err != nil {
+		return nil, err
+	}
+	statePath := restackStatePath(gitDir)
+
+	state, err := loadRestackState(statePath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case opts.Abort:
+		if state == nil {
+			return nil, ErrNoRestackInProgress
+		}
+		return w.abort(statePath, state)
+	case opts.Continue:
+		if state == nil {
+			return nil, ErrNoRestackInProgress
+		}
+	default:
+		if state != nil {
+			return nil, fmt.Errorf("%w: from %s", ErrRestackInProgress, state.Root)
+		}
+		state, err = w.plan(ctx, opts.Branch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Rebase each remaining step, saving progress so a conflict can be resumed
+	for len(state.Steps) > 0 {
+		if err := w.runStep(state); err != nil {
+			if saveErr := saveRestackState(statePath, state); saveErr != nil {
+				logger.Error().Err(saveErr).Msg("Failed to save restack state")
+			}
+			return nil, err
+		}
+		state.Steps = state.Steps[1:]
+		state.Stopped = false
+		if err := saveRestackState(statePath, state); err != nil {
+			return nil, err
+		}
+	}
+
+	return w.finish(ctx, statePath, state)
+}
+
+// plan collects the descendants of root in topological order by walking
+// dependent PRs breadth-first.
+func (w *RestackWorkflow) plan(ctx context.Context, root string) (*restackState, error) {
+	status, err := w.repo.GetWorkingDirectoryStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check working directory: %w", err)
+	}
+	if !status.IsClean {
+		return nil, ErrDirtyWorkingDir
+	}
+
+	currentBranch, err := w.repo.GetCurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if root == "" {
+		// Restacking trunk would rebase and force-push every PR that targets
+		// it, so that takes naming it explicitly
+		trunk, err := w.repo.GetDefaultBranch()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine default branch: %w", err)
+		}
+		if currentBranch == trunk {
+			return nil, fmt.Errorf("%w: %s", ErrOnTrunk, trunk)
+		}
+		root = currentBranch
+	}
+
+	exists, err := w.repo.BranchExists("refs/heads/" + root)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrBranchNotFound, root)
+	}
+
+	state := &restackState{
+		Root:           root,
+		OriginalBranch: currentBranch,
+		OldTips:        make(map[string]string),
+	}
+
+	fullName := w.owner + "/" + w.name
+	seen := map[string]bool{root: true}
+	queue := []string{root}
+	for len(queue) > 0 {
+		base := queue[0]
+		queue = queue[1:]
+
+		deps, err := w.client.FindDependentPRs(ctx, w.owner, w.name, base)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find PRs based on %s: %w", base, err)
+		}
+		sort.Slice(deps, func(i, j int) bool { return deps[i].Number < deps[j].Number })
+
+		for _, pr := range deps {
+			if pr.Head.Repo.FullName != "" && !strings.EqualFold(pr.Head.Repo.FullName, fullName) {
+				continue
+			}
+			if seen[pr.Head.Ref] {
+				continue
+			}
+			seen[pr.Head.Ref] = true
+			state.Steps = append(state.Steps, restackStep{
+				Branch:   pr.Head.Ref,
+				Parent:   base,
+				PRNumber: pr.Number,
+				BaseSHA:  pr.Base.SHA,
+			})
+			queue = append(queue, pr.Head.Ref)
+		}
+	}
+
+	logger.Info().
+		Str("root", root).
+		Int("steps", len(state.Steps)).
+		Msg("Planned restack")
+
+	return state, nil
+}
+
+// runStep rebases state.Steps[0] onto its parent, or completes it when it
+// previously stopped on conflicts.
+func (w *RestackWorkflow) runStep(state *restackState) error {
+	step := state.Steps[0]
+
+	if state.Stopped {
+		return w.resumeStep(state, step)
+	}
+
+	for _, skip := range state.Skipped {
+		if skip.Branch == step.Parent {
+			state.Skipped = append(state.Skipped, RestackSkip{Branch: step.Branch, Reason: "parent " + step.Parent + " was skipped"})
+			return nil
+		}
+	}
+
+	exists, err := w.repo.BranchExists("refs/heads/" + step.Branch)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		state.Skipped = append(state.Skipped, RestackSkip{Branch: step.Branch, Reason: "not checked out locally"})
+		return nil
+	}
+
+	parentTip, err := w.repo.GetBranchSHA(step.Parent)
+	if err != nil {
+		return err
+	}
+
+	upToDate, err := w.repo.IsAncestor(parentTip, step.Branch)
+	if err != nil {
+		return fmt.Errorf("failed to compare %s with %s: %w", step.Branch, step.Parent, err)
+	}
+	if upToDate {
+		state.UpToDate = append(state.UpToDate, step.Branch)
+		return nil
+	}
+
+	upstream, err := w.upstreamFor(state, step)
+	if err != nil {
+		return err
+	}
+
+	oldTip, err := w.repo.GetBranchSHA(step.Branch)
+	if err != nil {
+		return err
+	}
+	state.OldTips[step.Branch] = oldTip
+
+	logger.Info().
+		Str("branch", step.Branch).
+		Str("parent", step.Parent).
+		Str("upstream", upstream).
+		Msg("Rebasing branch onto parent")
+
+	if err := w.repo.RebaseOnto(parentTip, upstream, step.Branch); err != nil {
+		if errors.Is(err, git.ErrRebaseConflict) {
+			state.Stopped = true
+			return fmt.Errorf("%w: rebasing %s onto %s", ErrRestackConflict, step.Branch, step.Parent)
+		}
+		return err
+	}
+
+	w.recordParent(step.Branch, step.Parent, parentTip)
+	state.Rebased = append(state.Rebased, step.Branch)
+	return nil
+}
+
+// resumeStep finishes a step that stopped on conflicts.
+func (w *RestackWorkflow) resumeStep(state *restackState, step restackStep) error {
+	inProgress, err := w.repo.IsRebaseInProgress()
+	if err != nil {
+		return err
+	}
+
+	if inProgress {
+		if err := w.repo.ContinueRebase(); err != nil {
+			if errors.Is(err, git.ErrRebaseConflict) {
+				return fmt.Errorf("%w: rebasing %s onto %s", ErrRestackConflict, step.Branch, step.Parent)
+			}
+			return err
+		}
+	} else {
+		// The rebase was finished or abandoned by hand; only the former counts
+		done, err := w.repo.IsAncestor(step.Parent, step.Branch)
+		if err != nil {
+			return err
+		}
+		if !done {
+			return fmt.Errorf("rebase of %s onto %s was not completed; run with --abort to roll back", step.Branch, step.Parent)
+		}
+	}
+
+	if parentTip, err := w.repo.GetBranchSHA(step.Parent); err == nil {
+		w.recordParent(step.Branch, step.Parent, parentTip)
+	}
+	state.Rebased = append(state.Rebased, step.Branch)
+	return nil
+}
+
+// recordParent stores the parent tip a branch was just rebased onto, so the
+// next restack knows exactly where the branch's own commits start.
+func (w *RestackWorkflow) recordParent(branch, parent, parentTip string) {
+	if err := w.repo.SetBranchParent(branch, parent, parentTip); err != nil {
+		logger.Warn().Err(err).Str("branch", branch).Msg("Failed to record parent branch")
+	}
+}
+
+// upstreamFor returns the commit after which the step's own commits start,
+// i.e. the old position of its parent. When the parent was rebased in this
+// run its old tip is known; otherwise the parent commit recorded in git
+// config, the parent's reflog, the base SHA GitHub recorded for the PR, and
+// finally the merge-base are tried.
+func (w *RestackWorkflow) upstreamFor(state *restackState, step restackStep) (string, error) {
+	if oldTip, ok := state.OldTips[step.Parent]; ok {
+		if mergeBase, err := w.repo.GetMergeBase(step.Branch, oldTip); err == nil {
+			return mergeBase, nil
+		}
+	}
+
+	if recorded := w.recordedParentSHA(step); recorded != "" {
+		return recorded, nil
+	}
+
+	if forkPoint, err := w.repo.GetForkPoint(step.Parent, step.Branch); err == nil && forkPoint != "" {
+		return forkPoint, nil
+	}
+
+	if step.BaseSHA != "" {
+		if ok, err := w.repo.IsAncestor(step.BaseSHA, step.Branch); err == nil && ok {
+			return step.BaseSHA, nil
+		}
+	}
+
+	mergeBase, err := w.repo.GetMergeBase(step.Parent, step.Branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to find where %s forked from %s: %w", step.Branch, step.Parent, err)
+	}
+	return mergeBase, nil
+}
+
+// recordedParentSHA returns the parent commit recorded for the step's branch,
+// provided it was recorded for the same parent and is still in its history.
+func (w *RestackWorkflow) recordedParentSHA(step restackStep) string {
+	parent, err := w.repo.GetBranchParent(step.Branch)
+	if err != nil || parent != step.Parent {
+		return ""
+	}
+	sha, err := w.repo.GetBranchParentSHA(step.Branch)
+	if err != nil || sha == "" {
+		return ""
+	}
+	if ok, err := w.repo.IsAncestor(sha, step.Branch); err != nil || !ok {
+		return ""
+	}
+	return sha
+}
+
+// finish returns to the original branch and pushes the root and every
+// rebased branch. Push falls back to --force-with-lease for rewritten
+// branches (see ADR-0001).
+func (w *RestackWorkflow) finish(ctx context.Context, statePath string, state *restackState) (*RestackResult, error) {
+	result := &RestackResult{
+		Root:         state.Root,
+		Rebased:      nonNil(state.Rebased),
+		UpToDate:     nonNil(state.UpToDate),
+		Skipped:      state.Skipped,
+		Pushed:       []string{},
+		PushFailures: []RestackSkip{},
+	}
+	if result.Skipped == nil {
+		result.Skipped = []RestackSkip{}
+	}
+
+	if state.OriginalBranch != "" {
+		if err := w.repo.CheckoutBranch(state.OriginalBranch); err != nil {
+			logger.Warn().Err(err).Str("branch", state.OriginalBranch).Msg("Failed to return to original branch")
+		}
+	}
+
+	if err := removeRestackState(statePath); err != nil {
+		return nil, err
+	}
+
+	toPush := state.Rebased
+	if defaultBranch, err := w.repo.GetDefaultBranch(); err == nil && state.Root != defaultBranch {
+		toPush = append([]string{state.Root}, toPush...)
+	}
+
+	for _, branch := range toPush {
+		if err := w.repo.Push(ctx, branch); err != nil {
+			logger.Warn().Err(err).Str("branch", branch).Msg("Failed to push restacked branch")
+			result.PushFailures = append(result.PushFailures, RestackSkip{Branch: branch, Reason: err.Error()})
+			continue
+		}
+		result.Pushed = append(result.Pushed, branch)
+	}
+
+	return result, nil
+}
+
+// abort stops an in-progress rebase and resets every branch rebased so far
+// to its old tip. Nothing has been pushed at this point.
+func (w *RestackWorkflow) abort(statePath string, state *restackState) (*RestackResult, error) {
+	inProgress, err := w.repo.IsRebaseInProgress()
+	if err != nil {
+		return nil, err
+	}
+	if inProgress {
+		if err := w.repo.AbortRebase(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The root is never rewritten, so it is safe to stand on while resetting
+	if err := w.repo.CheckoutBranch(state.Root); err != nil {
+		return nil, err
+	}
+
+	for _, branch := range state.Rebased {
+		if oldTip, ok := state.OldTips[branch]; ok {
+			if err := w.repo.SetBranchRef(branch, oldTip); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if state.OriginalBranch != "" && state.OriginalBranch != state.Root {
+		if err := w.repo.CheckoutBranch(state.OriginalBranch); err != nil {
+			logger.Warn().Err(err).Str("branch", state.OriginalBranch).Msg("Failed to return to original branch")
+		}
+	}
+
+	if err := removeRestackState(statePath); err != nil {
+		return nil, err
+	}
+
+	return &RestackResult{
+		Root:         state.Root,
+		Rebased:      []string{},
+		UpToDate:     []string{},
+		Skipped:      []RestackSkip{},
+		Pushed:       []string{},
+		PushFailures: []RestackSkip{},
+		Aborted:      true,
+	}, nil
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,74 @@
+package stack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// restackStateFile is the name of the restack state file in the git directory.
+const restackStateFile = "gh-arc-restack.json"
+
+// restackStep rebases one branch onto its parent.
+type restackStep struct {
+	Branch   string `json:"branch"`
+	Parent   string `json:"parent"`
+	PRNumber int    `json:"pr_number"`
+	BaseSHA  string `json:"base_sha"` // Base SHA GitHub recorded for the PR
+}
+
+// restackState is persisted while a restack runs so that it can be resumed
+// with --continue or rolled back with --abort after a conflict.
+type restackState struct {
+	Root           string            `json:"root"`
+	OriginalBranch string            `json:"original_branch"`
+	Steps          []restackStep     `json:"steps"`   // Remaining steps in topological order
+	Stopped        bool              `json:"stopped"` // Steps[0] stopped on conflicts
+	OldTips        map[string]string `json:"old_tips"`
+	Rebased        []string          `json:"rebased"`
+	UpToDate       []string          `json:"up_to_date"`
+	Skipped        []RestackSkip     `json:"skipped"`
+}
+
+func loadRestackState(path string) (*restackState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read restack state: %w", err)
+	}
+
+	var state restackState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse restack state %s: %w", path, err)
+	}
+	if state.OldTips == nil {
+		state.OldTips = make(map[string]string)
+	}
+	return &state, nil
+}
+
+func saveRestackState(path string, state *restackState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode restack state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write restack state: %w", err)
+	}
+	return nil
+}
+
+func removeRestackState(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove restack state: %w", err)
+	}
+	return nil
+}
+
+func restackStatePath(gitDir string) string {
+	return filepath.Join(gitDir, restackStateFile)
+}
@@ -0,0 +1,312 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+type mockRestackRepo struct {
+	gitDir     string
+	dirty      bool
+	current    string
+	tips       map[string]string
+	ancestors  map[string]bool // "ancestor..descendant"
+	conflicts  map[string]bool // branches whose rebase stops on conflicts
+	inProgress bool
+	rebases    []string // "branch onto newBase from upstream"
+	pushed     []string
+	checkouts  []string
+	parents    map[string]string // branch -> "parent@sha"
+}
+
+func newMockRestackRepo(t *testing.T) *mockRestackRepo {
+	return &mockRestackRepo{
+		gitDir:    t.TempDir(),
+		current:   "a",
+		tips:      map[string]string{"main": "main-sha", "a": "a-new", "b": "b-old", "c": "c-old"},
+		ancestors: map[string]bool{},
+		conflicts: map[string]bool{},
+		parents:   map[string]string{},
+	}
+}
+
+func (m *mockRestackRepo) GitDir() (string, error) { return m.gitDir, nil }
+
+func (m *mockRestackRepo) GetWorkingDirectoryStatus() (*git.WorkingDirectoryStatus, error) {
+	return &git.WorkingDirectoryStatus{IsClean: !m.dirty}, nil
+}
+
+func (m *mockRestackRepo) GetCurrentBranch() (string, error) { return m.current, nil }
+func (m *mockRestackRepo) GetDefaultBranch() (string, error) { return "main", nil }
+
+func (m *mockRestackRepo) BranchExists(branchName string) (bool, error) {
+	_, ok := m.tips[filepath.Base(branchName)]
+	return ok, nil
+}
+
+func (m *mockRestackRepo) GetBranchSHA(branch string) (string, error) {
+	if sha, ok := m.tips[branch]; ok {
+		return sha, nil
+	}
+	return "", fmt.Errorf("unknown branch %s", branch)
+}
+
+func (m *mockRestackRepo) IsAncestor(ancestorRef, descendantRef string) (bool, error) {
+	if sha, ok := m.tips[ancestorRef]; ok {
+		ancestorRef = sha
+	}
+	if sha, ok := m.tips[descendantRef]; ok {
+		descendantRef = sha
+	}
+	return m.ancestors[ancestorRef+".."+descendantRef], nil
+}
+
+func (m *mockRestackRepo) GetMergeBase(ref1, ref2 string) (string, error) {
+	return "mb(" + ref1 + "," + ref2 + ")", nil
+}
+
+func (m *mockRestackRepo) GetForkPoint(upstream, branch string) (string, error) {
+	return "", nil
+}
+
+func (m *mockRestackRepo) RebaseOnto(newBase, upstream, branch string) error {
+	m.rebases = append(m.rebases, fmt.Sprintf("%s onto %s from %s", branch, newBase, upstream))
+	m.current = branch
+	if m.conflicts[branch] {
+		m.inProgress = true
+		return fmt.Errorf("failed to rebase %s: %w", branch, git.ErrRebaseConflict)
+	}
+	m.rebased(newBase, branch)
+	return nil
+}
+
+func (m *mockRestackRepo) rebased(newBase, branch string) {
+	m.tips[branch] = branch + "-new"
+	m.ancestors[newBase+".."+branch+"-new"] = true
+}
+
+func (m *mockRestackRepo) ContinueRebase() error {
+	m.inProgress = false
+	m.rebased(m.tips["b"], m.current)
+	return nil
+}
+
+func (m *mockRestackRepo) AbortRebase() error {
+	m.inProgress = false
+	return nil
+}
+
+func (m *mockRestackRepo) IsRebaseInProgress() (bool, error) { return m.inProgress, nil }
+
+func (m *mockRestackRepo) SetBranchRef(branch, sha string) error {
+	m.tips[branch] = sha
+	return nil
+}
+
+func (m *mockRestackRepo) CheckoutBranch(branch string) error {
+	m.current = branch
+	m.checkouts = append(m.checkouts, branch)
+	return nil
+}
+
+func (m *mockRestackRepo) Push(ctx context.Context, branchName string) error {
+	m.pushed = append(m.pushed, branchName)
+	return nil
+}
+
+func (m *mockRestackRepo) GetBranchParent(branch string) (string, error) {
+	parent, _, _ := strings.Cut(m.parents[branch], "@")
+	return parent, nil
+}
+
+func (m *mockRestackRepo) GetBranchParentSHA(branch string) (string, error) {
+	_, sha, _ := strings.Cut(m.parents[branch], "@")
+	return sha, nil
+}
+
+func (m *mockRestackRepo) SetBranchParent(branch, parent, parentSHA string) error {
+	m.parents[branch] = parent + "@" + parentSHA
+	return nil
+}
+
+type mockRestackClient struct {
+	prs []*github.PullRequest
+}
+
+func (m *mockRestackClient) FindDependentPRs(ctx context.Context, owner, repo, baseBranch string) ([]*github.PullRequest, error) {
+	var deps []*github.PullRequest
+	for _, pr := range m.prs {
+		if pr.Base.Ref == baseBranch {
+			deps = append(deps, pr)
+		}
+	}
+	return deps, nil
+}
+
+// newRestackClient returns the stack main <- a <- b <- c, plus d on a which
+// is not checked out locally and e from a fork.
+func newRestackClient() *mockRestackClient {
+	fork := newPR(5, "e", "a")
+	fork.Head.Repo.FullName = "someone/repo"
+	return &mockRestackClient{prs: []*github.PullRequest{
+		newPR(3, "c", "b"),
+		newPR(1, "a", "main"),
+		newPR(2, "b", "a"),
+		newPR(4, "d", "a"),
+		fork,
+	}}
+}
+
+func TestRestackWorkflow(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rebases descendants in order and pushes", func(t *testing.T) {
+		repo := newMockRestackRepo(t)
+		repo.ancestors["a-sha..b-old"] = true // PR base SHA recorded by GitHub
+
+		result, err := NewRestackWorkflow(repo, newRestackClient(), "owner", "repo").Execute(ctx, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "a", result.Root)
+		assert.Equal(t, []string{"b", "c"}, result.Rebased)
+		assert.Equal(t, []RestackSkip{{Branch: "d", Reason: "not checked out locally"}}, result.Skipped)
+		assert.Equal(t, []string{
+			"b onto a-new from a-sha",
+			"c onto b-new from mb(c,b-old)",
+		}, repo.rebases)
+		assert.Equal(t, []string{"a", "b", "c"}, repo.pushed)
+		assert.Equal(t, "a", repo.current)
+
+		_, err = os.Stat(filepath.Join(repo.gitDir, restackStateFile))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("prefers the recorded parent commit and refreshes it", func(t *testing.T) {
+		repo := newMockRestackRepo(t)
+		repo.parents["b"] = "a@a-recorded"
+		repo.ancestors["a-recorded..b-old"] = true
+		repo.ancestors["a-sha..b-old"] = true
+
+		_, err := NewRestackWorkflow(repo, newRestackClient(), "owner", "repo").Execute(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "b onto a-new from a-recorded", repo.rebases[0])
+		assert.Equal(t, "a@a-new", repo.parents["b"])
+		assert.Equal(t, "b@b-new", repo.parents["c"])
+	})
+
+	t.Run("ignores a parent commit recorded for another parent", func(t *testing.T) {
+		repo := newMockRestackRepo(t)
+		repo.parents["b"] = "main@main-old"
+		repo.ancestors["main-old..b-old"] = true
+		repo.ancestors["a-sha..b-old"] = true
+
+		_, err := NewRestackWorkflow(repo, newRestackClient(), "owner", "repo").Execute(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "b onto a-new from a-sha", repo.rebases[0])
+	})
+
+	t.Run("leaves up to date branches alone", func(t *testing.T) {
+		repo := newMockRestackRepo(t)
+		repo.ancestors["a-new..b-old"] = true
+		repo.ancestors["b-old..c-old"] = true
+
+		result, err := NewRestackWorkflow(repo, newRestackClient(), "owner", "repo").Execute(ctx, &RestackOptions{Branch: "a"})
+		require.NoError(t, err)
+		assert.Empty(t, result.Rebased)
+		assert.Equal(t, []string{"b", "c"}, result.UpToDate)
+		assert.Empty(t, repo.rebases)
+		assert.Equal(t, []string{"a"}, repo.pushed)
+	})
+
+	t.Run("refuses dirty working directory", func(t *testing.T) {
+		repo := newMockRestackRepo(t)
+		repo.dirty = true
+
+		_, err := NewRestackWorkflow(repo, newRestackClient(), "owner", "repo").Execute(ctx, nil)
+		assert.ErrorIs(t, err, ErrDirtyWorkingDir)
+	})
+
+	t.Run("refuses to restack trunk unless named", func(t *testing.T) {
+		repo := newMockRestackRepo(t)
+		repo.current = "main"
+		client := newRestackClient()
+
+		_, err := NewRestackWorkflow(repo, client, "owner", "repo").Execute(ctx, nil)
+		assert.ErrorIs(t, err, ErrOnTrunk)
+		assert.Empty(t, repo.rebases)
+		assert.Empty(t, repo.pushed)
+
+		result, err := NewRestackWorkflow(repo, client, "owner", "repo").Execute(ctx, &RestackOptions{Branch: "main"})
+		require.NoError(t, err)
+		assert.Equal(t, "main", result.Root)
+	})
+
+	t.Run("unknown branch", func(t *testing.T) {
+		repo := newMockRestackRepo(t)
+
+		_, err := NewRestackWorkflow(repo, newRestackClient(), "owner", "repo").Execute(ctx, &RestackOptions{Branch: "nope"})
+		assert.ErrorIs(t, err, ErrBranchNotFound)
+	})
+
+	t.Run("continue and abort without a restack", func(t *testing.T) {
+		repo := newMockRestackRepo(t)
+		w := NewRestackWorkflow(repo, newRestackClient(), "owner", "repo")
+
+		_, err := w.Execute(ctx, &RestackOptions{Continue: true})
+		assert.ErrorIs(t, err, ErrNoRestackInProgress)
+		_, err = w.Execute(ctx, &RestackOptions{Abort: true})
+		assert.ErrorIs(t, err, ErrNoRestackInProgress)
+	})
+
+	t.Run("stops on conflict and continues", func(t *testing.T) {
+		repo := newMockRestackRepo(t)
+		repo.conflicts["c"] = true
+		w := NewRestackWorkflow(repo, newRestackClient(), "owner", "repo")
+
+		_, err := w.Execute(ctx, nil)
+		require.ErrorIs(t, err, ErrRestackConflict)
+		assert.Contains(t, err.Error(), "rebasing c onto b")
+		assert.Empty(t, repo.pushed)
+		assert.FileExists(t, filepath.Join(repo.gitDir, restackStateFile))
+
+		_, err = w.Execute(ctx, nil)
+		assert.ErrorIs(t, err, ErrRestackInProgress)
+
+		result, err := w.Execute(ctx, &RestackOptions{Continue: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b", "c"}, result.Rebased)
+		assert.Equal(t, []string{"a", "b", "c"}, repo.pushed)
+		assert.Equal(t, "a", repo.current)
+		assert.NoFileExists(t, filepath.Join(repo.gitDir, restackStateFile))
+	})
+
+	t.Run("abort restores rebased branches", func(t *testing.T) {
+		repo := newMockRestackRepo(t)
+		repo.conflicts["c"] = true
+		w := NewRestackWorkflow(repo, newRestackClient(), "owner", "repo")
+
+		_, err := w.Execute(ctx, nil)
+		require.ErrorIs(t, err, ErrRestackConflict)
+		assert.Equal(t, "b-new", repo.tips["b"])
+
+		result, err := w.Execute(ctx, &RestackOptions{Abort: true})
+		require.NoError(t, err)
+		assert.True(t, result.Aborted)
+		assert.False(t, repo.inProgress)
+		assert.Equal(t, "b-old", repo.tips["b"])
+		assert.Equal(t, "c-old", repo.tips["c"])
+		assert.Equal(t, "a", repo.current)
+		assert.Empty(t, repo.pushed)
+		assert.NoFileExists(t, filepath.Join(repo.gitDir, restackStateFile))
+	})
+}
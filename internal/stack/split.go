@@ -0,0 +1,422 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/template"
+)
+
+var (
+	// ErrNothingToSplit is returned when the branch has no commits of its own
+	// or the plan keeps it as a single branch.
+	ErrNothingToSplit = errors.New("nothing to split")
+
+	// ErrInvalidSplitPlan is returned when the edited todo cannot be applied.
+	ErrInvalidSplitPlan = errors.New("invalid split plan")
+
+	// ErrSplitConflict is returned when reordered commits do not apply. All
+	// branches are left as they were before the split.
+	ErrSplitConflict = errors.New("commits do not apply in the new order")
+)
+
+// backupRefPrefix is where split keeps the original tip of a branch.
+const backupRefPrefix = "refs/gh-arc/backup/"
+
+// SplitRepository defines git operations needed by the split workflow.
+type SplitRepository interface {
+	SubmitRepository
+	GetWorkingDirectoryStatus() (*git.WorkingDirectoryStatus, error)
+	GetBranchSHA(branch string) (string, error)
+	GetHeadSHA() (string, error)
+	GetMergeBase(ref1, ref2 string) (string, error)
+	CheckoutBranch(branch string) error
+	CheckoutDetached(ref string) error
+	CherryPick(commits ...string) error
+	AbortCherryPick() error
+	SetBranchRef(branch, sha string) error
+	DeleteLocalBranch(branch string) error
+	UpdateRef(ref, sha string) error
+	SetBranchParent(branch, parent, parentSHA string) error
+}
+
+// SplitWorkflow turns the commits of one branch into a stack of branches,
+// as assigned by the user in an editor, and submits the stack.
+type SplitWorkflow struct {
+	repo       SplitRepository
+	submit     *SubmitWorkflow
+	openEditor func(string) (string, error)
+}
+
+// NewSplitWorkflow creates a new SplitWorkflow. The stack is submitted with
+// a SubmitWorkflow built from the same dependencies.
+func NewSplitWorkflow(repo SplitRepository, client SubmitGitHubClient, submitter PRSubmitter, cfg *config.Config, owner, name string) *SplitWorkflow {
+	return &SplitWorkflow{
+		repo:       repo,
+		submit:     NewSubmitWorkflow(repo, client, submitter, cfg, owner, name),
+		openEditor: template.OpenEditor,
+	}
+}
+
+// splitGroup is one branch of the split plan with its commits, oldest first.
+type splitGroup struct {
+	branch  string
+	commits []git.CommitInfo
+}
+
+// Execute runs the split workflow.
+//
+// When the plan keeps the commits in their original order, the new branches
+// simply point into the existing history. Otherwise the commits are
+// cherry-picked into the new order; if that conflicts, everything is rolled
+// back. The original tip is saved under refs/gh-arc/backup/ either way.
+func (w *SplitWorkflow) Execute(ctx context.Context, opts *SplitOptions) (*SplitResult, error) {
+	if opts == nil {
+		opts = &SplitOptions{}
+	}
+
+	logger.Debug().
+		Bool("noSubmit", opts.NoSubmit).
+		Bool("noEdit", opts.NoEdit).
+		Bool("draft", opts.Draft).
+		Msg("Executing split workflow")
+
+	// Step 1: Collect the commits of the current branch
+	status, err := w.repo.GetWorkingDirectoryStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check working directory: %w", err)
+	}
+	if !status.IsClean {
+		return nil, ErrDirtyWorkingDir
+	}
+
+	branch, err := w.repo.GetCurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	trunk, err := w.repo.GetDefaultBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine default branch: %w", err)
+	}
+	if branch == trunk {
+		return nil, ErrOnTrunk
+	}
+
+	base, err := w.submit.parentOfBranch(ctx, branch, trunk)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := w.repo.GetCommitsBetween(base, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits of %s: %w", branch, err)
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("%w: %s has no commits on top of %s", ErrNothingToSplit, branch, base)
+	}
+	// Oldest first, as in a rebase todo
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+
+	// Step 2: Let the user assign commits to branches
+	edited, err := w.openEditor(formatSplitTodo(branch, base, commits))
+	if err != nil {
+		return nil, err
+	}
+	groups, err := parseSplitTodo(edited, commits)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.validatePlan(groups, branch, base, trunk, commits); err != nil {
+		return nil, err
+	}
+
+	// Step 3: Back up the original tip and build the branches
+	tip, err := w.repo.GetBranchSHA(branch)
+	if err != nil {
+		return nil, err
+	}
+	backup := backupRefPrefix + branch
+	if err := w.repo.UpdateRef(backup, tip); err != nil {
+		return nil, fmt.Errorf("failed to back up %s: %w", branch, err)
+	}
+
+	forkPoint, err := w.repo.GetMergeBase(base, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find where %s forked from %s: %w", branch, base, err)
+	}
+
+	result := &SplitResult{
+		Original:  branch,
+		Base:      base,
+		Backup:    backup,
+		BackupSHA: tip,
+		Branches:  make([]SplitBranch, 0, len(groups)),
+		Rewritten: !keepsHistory(groups, commits),
+	}
+
+	heads, err := w.buildBranches(groups, branch, tip, forkPoint, result.Rewritten)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 4: Record the chain so diff, restack and submit follow it
+	parent, parentSHA := base, forkPoint
+	for i, g := range groups {
+		if err := w.repo.SetBranchParent(g.branch, parent, parentSHA); err != nil {
+			logger.Warn().Err(err).Str("branch", g.branch).Msg("Failed to record stack parent")
+		}
+		result.Branches = append(result.Branches, SplitBranch{
+			Branch:  g.branch,
+			Parent:  parent,
+			Head:    heads[i],
+			Commits: len(g.commits),
+		})
+		parent, parentSHA = g.branch, heads[i]
+	}
+
+	top := groups[len(groups)-1].branch
+	if err := w.repo.CheckoutBranch(top); err != nil {
+		return result, err
+	}
+
+	logger.Info().
+		Str("branch", branch).
+		Int("branches", len(groups)).
+		Bool("rewritten", result.Rewritten).
+		Msg("Split branch")
+
+	if opts.NoSubmit {
+		return result, nil
+	}
+
+	// Step 5: Submit the new stack
+	submitted, err := w.submit.Execute(ctx, &SubmitOptions{NoEdit: opts.NoEdit, Draft: opts.Draft})
+	if err != nil {
+		return result, fmt.Errorf("branches were created but submitting them failed: %w", err)
+	}
+	result.Submit = submitted
+
+	return result, nil
+}
+
+// validatePlan checks the branch names of the plan. The original branch may
+// be reused; any other branch must be new.
+func (w *SplitWorkflow) validatePlan(groups []splitGroup, branch, base, trunk string, commits []git.CommitInfo) error {
+	if len(groups) == 1 && groups[0].branch == branch && keepsHistory(groups, commits) {
+		return fmt.Errorf("%w: every commit stays on %s", ErrNothingToSplit, branch)
+	}
+
+	for _, g := range groups {
+		if g.branch == trunk || g.branch == base {
+			return fmt.Errorf("%w: %s cannot be part of the split", ErrInvalidSplitPlan, g.branch)
+		}
+		if g.branch == branch {
+			continue
+		}
+		exists, err := w.repo.BranchExists("refs/heads/" + g.branch)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("%w: branch %s already exists", ErrInvalidSplitPlan, g.branch)
+		}
+	}
+	return nil
+}
+
+// buildBranches points every group's branch at its last commit and returns
+// the heads. HEAD is detached while branches move, so the original branch
+// can be reused at any position.
+func (w *SplitWorkflow) buildBranches(groups []splitGroup, branch, tip, forkPoint string, rewrite bool) ([]string, error) {
+	start := tip
+	if rewrite {
+		start = forkPoint
+	}
+	if err := w.repo.CheckoutDetached(start); err != nil {
+		return nil, err
+	}
+
+	heads := make([]string, 0, len(groups))
+	var created []string
+	for _, g := range groups {
+		head := g.commits[len(g.commits)-1].SHA
+		if rewrite {
+			shas := make([]string, len(g.commits))
+			for i, c := range g.commits {
+				shas[i] = c.SHA
+			}
+			if err := w.repo.CherryPick(shas...); err != nil {
+				w.rollback(branch, tip, created, errors.Is(err, git.ErrCherryPickConflict))
+				if errors.Is(err, git.ErrCherryPickConflict) {
+					return nil, fmt.Errorf("%w: %s", ErrSplitConflict, g.branch)
+				}
+				return nil, err
+			}
+			var err error
+			if head, err = w.repo.GetHeadSHA(); err != nil {
+				w.rollback(branch, tip, created, false)
+				return nil, err
+			}
+		}
+
+		if err := w.repo.SetBranchRef(g.branch, head); err != nil {
+			w.rollback(branch, tip, created, false)
+			return nil, err
+		}
+		if g.branch != branch {
+			created = append(created, g.branch)
+		}
+		heads = append(heads, head)
+	}
+	return heads, nil
+}
+
+// rollback restores the original branch and deletes the branches created so
+// far. Failures are logged: the backup ref still has the original tip.
+func (w *SplitWorkflow) rollback(branch, tip string, created []string, abortPick bool) {
+	if abortPick {
+		if err := w.repo.AbortCherryPick(); err != nil {
+			logger.Warn().Err(err).Msg("Failed to abort cherry-pick")
+		}
+	}
+	if err := w.repo.SetBranchRef(branch, tip); err != nil {
+		logger.Warn().Err(err).Str("branch", branch).Msg("Failed to restore branch")
+	}
+	if err := w.repo.CheckoutBranch(branch); err != nil {
+		logger.Warn().Err(err).Str("branch", branch).Msg("Failed to check out branch")
+	}
+	for _, b := range created {
+		if err := w.repo.DeleteLocalBranch(b); err != nil {
+			logger.Warn().Err(err).Str("branch", b).Msg("Failed to delete branch")
+		}
+	}
+}
+
+// keepsHistory reports whether the plan keeps every commit in its original
+// order, so the branches can point into the existing history.
+func keepsHistory(groups []splitGroup, commits []git.CommitInfo) bool {
+	i := 0
+	for _, g := range groups {
+		for _, c := range g.commits {
+			if i >= len(commits) || commits[i].SHA != c.SHA {
+				return false
+			}
+			i++
+		}
+	}
+	return i == len(commits)
+}
+
+// formatSplitTodo renders the editor content, with every commit assigned to
+// the original branch.
+func formatSplitTodo(branch, base string, commits []git.CommitInfo) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# Split %s (%d commits) into a stack of branches on top of %s.\n", branch, len(commits), base)
+	sb.WriteString("#\n")
+	sb.WriteString("# Commands:\n")
+	sb.WriteString("#   branch <name> = start a new branch; the commits below go into it\n")
+	sb.WriteString("#   pick <commit> = put the commit into the branch above it\n")
+	sb.WriteString("#\n")
+	fmt.Fprintf(&sb, "# Branches are stacked in the order they appear, the first one on %s.\n", base)
+	sb.WriteString("# Lines can be reordered to move commits between branches, and removed to\n")
+	sb.WriteString("# drop commits; either rewrites the commits and may conflict.\n")
+	fmt.Fprintf(&sb, "# %s is backed up to %s%s first and may be reused.\n", branch, backupRefPrefix, branch)
+	sb.WriteString("#\n")
+	sb.WriteString("# Removing everything aborts the split.\n\n")
+
+	fmt.Fprintf(&sb, "branch %s\n", branch)
+	for _, c := range commits {
+		fmt.Fprintf(&sb, "pick %s %s\n", shortSHA(c.SHA), git.ParseCommitMessage(c.Message).Title)
+	}
+
+	return sb.String()
+}
+
+// parseSplitTodo reads the edited todo into groups. Commits are referred to
+// by any unambiguous prefix of their SHA.
+func parseSplitTodo(content string, commits []git.CommitInfo) ([]splitGroup, error) {
+	var groups []splitGroup
+	branches := map[string]bool{}
+	picked := map[string]bool{}
+
+	for n, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%w: line %d: %q needs an argument", ErrInvalidSplitPlan, n+1, fields[0])
+		}
+
+		switch fields[0] {
+		case "branch", "b":
+			name := fields[1]
+			if branches[name] {
+				return nil, fmt.Errorf("%w: line %d: branch %s is listed twice", ErrInvalidSplitPlan, n+1, name)
+			}
+			if len(groups) > 0 && len(groups[len(groups)-1].commits) == 0 {
+				return nil, fmt.Errorf("%w: branch %s has no commits", ErrInvalidSplitPlan, groups[len(groups)-1].branch)
+			}
+			branches[name] = true
+			groups = append(groups, splitGroup{branch: name})
+
+		case "pick", "p":
+			if len(groups) == 0 {
+				return nil, fmt.Errorf("%w: line %d: pick before the first branch", ErrInvalidSplitPlan, n+1)
+			}
+			commit, err := resolveCommit(fields[1], commits)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidSplitPlan, n+1, err)
+			}
+			if picked[commit.SHA] {
+				return nil, fmt.Errorf("%w: line %d: commit %s is picked twice", ErrInvalidSplitPlan, n+1, shortSHA(commit.SHA))
+			}
+			picked[commit.SHA] = true
+			groups[len(groups)-1].commits = append(groups[len(groups)-1].commits, commit)
+
+		default:
+			return nil, fmt.Errorf("%w: line %d: unknown command %q", ErrInvalidSplitPlan, n+1, fields[0])
+		}
+	}
+
+	if len(groups) == 0 {
+		return nil, template.ErrEditorCancelled
+	}
+	if last := groups[len(groups)-1]; len(last.commits) == 0 {
+		return nil, fmt.Errorf("%w: branch %s has no commits", ErrInvalidSplitPlan, last.branch)
+	}
+	return groups, nil
+}
+
+func resolveCommit(prefix string, commits []git.CommitInfo) (git.CommitInfo, error) {
+	var found []git.CommitInfo
+	for _, c := range commits {
+		if strings.HasPrefix(c.SHA, prefix) {
+			found = append(found, c)
+		}
+	}
+
+	switch {
+	case len(prefix) < 4 || len(found) > 1:
+		return git.CommitInfo{}, fmt.Errorf("commit %s is ambiguous", prefix)
+	case len(found) == 0:
+		return git.CommitInfo{}, fmt.Errorf("commit %s is not on the branch", prefix)
+	}
+	return found[0], nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,286 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/template"
+)
+
+type mockSplitRepo struct {
+	*mockSubmitRepo
+	dirty     bool
+	commits   []git.CommitInfo // Commits of the current branch, newest first
+	tips      map[string]string
+	head      string
+	conflicts map[string]bool // commits that do not cherry-pick cleanly
+	picked    []string
+	aborted   bool
+	refs      map[string]string
+	deleted   []string
+	checkouts []string
+}
+
+func (m *mockSplitRepo) GetWorkingDirectoryStatus() (*git.WorkingDirectoryStatus, error) {
+	return &git.WorkingDirectoryStatus{IsClean: !m.dirty}, nil
+}
+
+func (m *mockSplitRepo) GetCommitsBetween(base, head string) ([]git.CommitInfo, error) {
+	if head == "big" && base == "main" {
+		return append([]git.CommitInfo(nil), m.commits...), nil
+	}
+	return m.mockSubmitRepo.GetCommitsBetween(base, head)
+}
+
+func (m *mockSplitRepo) GetBranchSHA(branch string) (string, error) {
+	if sha, ok := m.tips[branch]; ok {
+		return sha, nil
+	}
+	return "", fmt.Errorf("unknown branch %s", branch)
+}
+
+func (m *mockSplitRepo) GetHeadSHA() (string, error) { return m.head, nil }
+
+func (m *mockSplitRepo) GetMergeBase(ref1, ref2 string) (string, error) {
+	return "fork", nil
+}
+
+func (m *mockSplitRepo) CheckoutBranch(branch string) error {
+	m.checkouts = append(m.checkouts, branch)
+	m.current = branch
+	m.head = m.tips[branch]
+	return nil
+}
+
+func (m *mockSplitRepo) CheckoutDetached(ref string) error {
+	m.checkouts = append(m.checkouts, "detached@"+ref)
+	m.current = ""
+	m.head = ref
+	return nil
+}
+
+func (m *mockSplitRepo) CherryPick(commits ...string) error {
+	for _, sha := range commits {
+		if m.conflicts[sha] {
+			return fmt.Errorf("%w: %s", git.ErrCherryPickConflict, sha)
+		}
+		m.picked = append(m.picked, sha)
+		m.head = "new-" + sha
+	}
+	return nil
+}
+
+func (m *mockSplitRepo) AbortCherryPick() error {
+	m.aborted = true
+	return nil
+}
+
+func (m *mockSplitRepo) SetBranchRef(branch, sha string) error {
+	m.tips[branch] = sha
+	m.branches[branch] = true
+	return nil
+}
+
+func (m *mockSplitRepo) DeleteLocalBranch(branch string) error {
+	m.deleted = append(m.deleted, branch)
+	delete(m.tips, branch)
+	delete(m.branches, branch)
+	return nil
+}
+
+func (m *mockSplitRepo) UpdateRef(ref, sha string) error {
+	m.refs[ref] = sha
+	return nil
+}
+
+func (m *mockSplitRepo) SetBranchParent(branch, parent, parentSHA string) error {
+	m.parents[branch] = parent
+	return nil
+}
+
+// newSplitFixture has the branch big with commits c1, c2, c3 on top of main.
+func newSplitFixture() (*mockSplitRepo, *mockSubmitClient, *mockSubmitter) {
+	repo := &mockSplitRepo{
+		mockSubmitRepo: &mockSubmitRepo{
+			current:  "big",
+			parents:  map[string]string{},
+			branches: map[string]bool{"main": true, "big": true},
+		},
+		commits: []git.CommitInfo{
+			{SHA: "c3c3c3c3", Message: "Polish"},
+			{SHA: "c2c2c2c2", Message: "Use API\n\nDetails"},
+			{SHA: "c1c1c1c1", Message: "Add API"},
+		},
+		tips:      map[string]string{"main": "main-sha", "big": "c3c3c3c3"},
+		head:      "c3c3c3c3",
+		conflicts: map[string]bool{},
+		refs:      map[string]string{},
+	}
+	client := &mockSubmitClient{
+		prs:         map[string]*github.PullRequest{},
+		bodyUpdates: map[int]string{},
+	}
+	return repo, client, &mockSubmitter{client: client, next: 10}
+}
+
+func newTestSplitWorkflow(repo *mockSplitRepo, client *mockSubmitClient, submitter *mockSubmitter, todo string) (*SplitWorkflow, *string) {
+	w := NewSplitWorkflow(repo, client, submitter, &config.Config{}, "owner", "repo")
+	var opened string
+	w.openEditor = func(content string) (string, error) {
+		opened = content
+		return todo, nil
+	}
+	return w, &opened
+}
+
+func TestSplitWorkflow(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("splits in place and submits the stack", func(t *testing.T) {
+		repo, client, submitter := newSplitFixture()
+		todo := "branch api\npick c1c1c1c Add API\n\n# comment\nbranch big\np c2c2 Use API\npick c3c3c3c Polish\n"
+		w, opened := newTestSplitWorkflow(repo, client, submitter, todo)
+
+		result, err := w.Execute(ctx, &SplitOptions{NoEdit: true})
+		require.NoError(t, err)
+
+		assert.Contains(t, *opened, "branch big\npick c1c1c1c Add API\npick c2c2c2c Use API\npick c3c3c3c Polish\n")
+		assert.False(t, result.Rewritten)
+		assert.Empty(t, repo.picked, "commits in order are not rewritten")
+		assert.Equal(t, []SplitBranch{
+			{Branch: "api", Parent: "main", Head: "c1c1c1c1", Commits: 1},
+			{Branch: "big", Parent: "api", Head: "c3c3c3c3", Commits: 2},
+		}, result.Branches)
+		assert.Equal(t, "c3c3c3c3", repo.refs["refs/gh-arc/backup/big"])
+		assert.Equal(t, "refs/gh-arc/backup/big", result.Backup)
+		assert.Equal(t, "api", repo.parents["big"])
+		assert.Equal(t, "big", repo.current)
+
+		require.NotNil(t, result.Submit)
+		require.Len(t, submitter.requests, 2)
+		assert.Equal(t, "api", submitter.requests[0].HeadBranch)
+		assert.Equal(t, "api", submitter.requests[1].BaseBranch)
+	})
+
+	t.Run("reordering cherry-picks the commits", func(t *testing.T) {
+		repo, client, submitter := newSplitFixture()
+		todo := "branch first\npick c2c2c2c2\nbranch second\npick c1c1c1c1\n"
+		w, _ := newTestSplitWorkflow(repo, client, submitter, todo)
+
+		result, err := w.Execute(ctx, &SplitOptions{NoSubmit: true})
+		require.NoError(t, err)
+
+		assert.True(t, result.Rewritten)
+		assert.Equal(t, "detached@fork", repo.checkouts[0])
+		assert.Equal(t, []string{"c2c2c2c2", "c1c1c1c1"}, repo.picked)
+		assert.Equal(t, "new-c2c2c2c2", repo.tips["first"])
+		assert.Equal(t, "new-c1c1c1c1", repo.tips["second"])
+		assert.Equal(t, "c3c3c3c3", repo.tips["big"], "the original branch is left alone when not reused")
+		assert.Empty(t, submitter.requests)
+	})
+
+	t.Run("conflict rolls everything back", func(t *testing.T) {
+		repo, client, submitter := newSplitFixture()
+		repo.conflicts["c1c1c1c1"] = true
+		todo := "branch first\npick c2c2c2c2\nbranch big\npick c1c1c1c1\npick c3c3c3c3\n"
+		w, _ := newTestSplitWorkflow(repo, client, submitter, todo)
+
+		_, err := w.Execute(ctx, nil)
+		assert.ErrorIs(t, err, ErrSplitConflict)
+		assert.True(t, repo.aborted)
+		assert.Equal(t, []string{"first"}, repo.deleted)
+		assert.Equal(t, "c3c3c3c3", repo.tips["big"])
+		assert.Equal(t, "big", repo.current)
+		assert.Empty(t, repo.parents)
+		assert.Empty(t, submitter.requests)
+	})
+
+	t.Run("unchanged plan", func(t *testing.T) {
+		repo, client, submitter := newSplitFixture()
+		w := NewSplitWorkflow(repo, client, submitter, &config.Config{}, "owner", "repo")
+		w.openEditor = func(content string) (string, error) { return content, nil }
+
+		_, err := w.Execute(ctx, nil)
+		assert.ErrorIs(t, err, ErrNothingToSplit)
+		assert.Empty(t, repo.refs, "nothing is backed up")
+	})
+
+	t.Run("empty plan cancels", func(t *testing.T) {
+		repo, client, submitter := newSplitFixture()
+		w, _ := newTestSplitWorkflow(repo, client, submitter, "# all gone\n")
+
+		_, err := w.Execute(ctx, nil)
+		assert.ErrorIs(t, err, template.ErrEditorCancelled)
+	})
+
+	t.Run("existing branch", func(t *testing.T) {
+		repo, client, submitter := newSplitFixture()
+		repo.branches["taken"] = true
+		w, _ := newTestSplitWorkflow(repo, client, submitter, "branch taken\npick c1c1\nbranch big\npick c2c2\npick c3c3\n")
+
+		_, err := w.Execute(ctx, nil)
+		assert.ErrorIs(t, err, ErrInvalidSplitPlan)
+		assert.ErrorContains(t, err, "taken already exists")
+	})
+
+	t.Run("refuses a dirty working tree", func(t *testing.T) {
+		repo, client, submitter := newSplitFixture()
+		repo.dirty = true
+		w, _ := newTestSplitWorkflow(repo, client, submitter, "")
+
+		_, err := w.Execute(ctx, nil)
+		assert.ErrorIs(t, err, ErrDirtyWorkingDir)
+	})
+}
+
+func TestParseSplitTodo(t *testing.T) {
+	commits := []git.CommitInfo{{SHA: "aaaa1111"}, {SHA: "aaaa2222"}, {SHA: "bbbb3333"}}
+
+	tests := []struct {
+		name string
+		todo string
+		want string // error substring
+	}{
+		{name: "pick before branch", todo: "pick aaaa1111\n", want: "pick before the first branch"},
+		{name: "unknown command", todo: "branch x\nsquash aaaa1111\n", want: `unknown command "squash"`},
+		{name: "missing argument", todo: "branch\n", want: "needs an argument"},
+		{name: "ambiguous commit", todo: "branch x\npick aaaa\n", want: "commit aaaa is ambiguous"},
+		{name: "too short", todo: "branch x\npick bbb\n", want: "ambiguous"},
+		{name: "unknown commit", todo: "branch x\npick cccc\n", want: "not on the branch"},
+		{name: "picked twice", todo: "branch x\npick bbbb\npick bbbb3333\n", want: "picked twice"},
+		{name: "branch twice", todo: "branch x\npick bbbb\nbranch x\npick aaaa1\n", want: "listed twice"},
+		{name: "empty branch", todo: "branch x\nbranch y\npick bbbb\n", want: "branch x has no commits"},
+		{name: "empty last branch", todo: "branch x\npick bbbb\nbranch y\n", want: "branch y has no commits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSplitTodo(tt.todo, commits)
+			assert.ErrorIs(t, err, ErrInvalidSplitPlan)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		groups, err := parseSplitTodo("b x\np aaaa2\n  pick bbbb3333 subject with spaces\nbranch y\npick aaaa1\n", commits)
+		require.NoError(t, err)
+		require.Len(t, groups, 2)
+		assert.Equal(t, "x", groups[0].branch)
+		assert.Equal(t, []string{"aaaa2222", "bbbb3333"}, []string{groups[0].commits[0].SHA, groups[0].commits[1].SHA})
+		assert.Equal(t, "aaaa1111", groups[1].commits[0].SHA)
+		assert.False(t, keepsHistory(groups, commits))
+	})
+
+	t.Run("comments only", func(t *testing.T) {
+		_, err := parseSplitTodo(strings.Repeat("# nothing\n", 3), commits)
+		assert.ErrorIs(t, err, template.ErrEditorCancelled)
+	})
+}
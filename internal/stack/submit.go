@@ -0,0 +1,401 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/diff"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/template"
+)
+
+var (
+	// ErrOnTrunk is returned when submitting from the default branch.
+	ErrOnTrunk = errors.New("current branch is the default branch")
+
+	// ErrBranchMerged is returned when a branch of the stack already has a
+	// merged PR.
+	ErrBranchMerged = errors.New("branch has already been merged")
+)
+
+// SubmitRepository defines git operations needed by the submit workflow.
+type SubmitRepository interface {
+	GetCurrentBranch() (string, error)
+	GetDefaultBranch() (string, error)
+	GetBranchParent(branch string) (string, error)
+	BranchExists(branchName string) (bool, error)
+	ListBranches(includeRemote bool) ([]git.BranchInfo, error)
+	GetCommitsBetween(base, head string) ([]git.CommitInfo, error)
+}
+
+// SubmitGitHubClient defines GitHub operations needed by the submit workflow.
+type SubmitGitHubClient interface {
+	FindPRsForBranches(ctx context.Context, owner, repo string, branches []string) (map[string]*github.PullRequest, error)
+	UpdatePRBase(ctx context.Context, owner, repo string, number int, newBase string) error
+	UpdatePullRequest(ctx context.Context, owner, repo string, number int, title, body string, draft *bool, parentPR *github.PullRequest) (*github.PullRequest, error)
+	GetPullRequestsWithPagination(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, error)
+	GetCurrentUser(ctx context.Context) (string, error)
+}
+
+// PRSubmitter creates or updates a single PR.
+type PRSubmitter interface {
+	CreateOrUpdatePR(ctx context.Context, req *diff.PRRequest) (*diff.PRResult, error)
+}
+
+// SubmitWorkflow creates or updates the PRs of every branch in the local
+// stack from trunk to the current branch, bottom-up, and writes the stack
+// table into their descriptions.
+type SubmitWorkflow struct {
+	repo       SubmitRepository
+	client     SubmitGitHubClient
+	submitter  PRSubmitter
+	links      *LinkSyncer
+	config     *config.Config
+	owner      string
+	name       string
+	openEditor func(string) (string, error)
+}
+
+// NewSubmitWorkflow creates a new SubmitWorkflow.
+func NewSubmitWorkflow(repo SubmitRepository, client SubmitGitHubClient, submitter PRSubmitter, cfg *config.Config, owner, name string) *SubmitWorkflow {
+	return &SubmitWorkflow{
+		repo:       repo,
+		client:     client,
+		submitter:  submitter,
+		links:      NewLinkSyncer(client, owner, name),
+		config:     cfg,
+		owner:      owner,
+		name:       name,
+		openEditor: template.OpenEditor,
+	}
+}
+
+// submitBranch is one branch of the local stack.
+type submitBranch struct {
+	name   string
+	parent string
+	pr     *github.PullRequest // Open PR, nil until created
+	fields *template.TemplateFields
+}
+
+// Execute runs the submit workflow.
+func (w *SubmitWorkflow) Execute(ctx context.Context, opts *SubmitOptions) (*SubmitResult, error) {
+	if opts == nil {
+		opts = &SubmitOptions{}
+	}
+
+	logger.Debug().
+		Bool("noEdit", opts.NoEdit).
+		Bool("draft", opts.Draft).
+		Bool("ready", opts.Ready).
+		Msg("Executing stack submit workflow")
+
+	// Step 1: Find the local chain from trunk to the current branch
+	currentBranch, err := w.repo.GetCurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	trunk, err := w.repo.GetDefaultBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine default branch: %w", err)
+	}
+	if currentBranch == trunk {
+		return nil, ErrOnTrunk
+	}
+
+	// The PRs of all local branches are looked up at once: they give the
+	// parents of branches without a recorded one, and the PRs to update
+	prs, err := w.findLocalPRs(ctx, trunk)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := w.localChain(currentBranch, trunk, prs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Attach existing PRs
+	for _, b := range chain {
+		pr := prs[b.name]
+		if pr == nil {
+			continue
+		}
+		if pr.IsMerged() {
+			return nil, fmt.Errorf("%w: %s (PR #%d)", ErrBranchMerged, b.name, pr.Number)
+		}
+		b.pr = pr
+	}
+
+	// Step 3: Describe the branches that have no PR yet
+	if err := w.describeNewBranches(ctx, chain, trunk, opts); err != nil {
+		return nil, err
+	}
+
+	currentUser, err := w.client.GetCurrentUser(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to get current user")
+		currentUser = ""
+	}
+
+	// Step 4: Create or update PRs bottom-up, so every parent PR exists
+	// before its child targets it
+	result := &SubmitResult{
+		Trunk:    trunk,
+		Entries:  make([]SubmitEntry, 0, len(chain)),
+		Warnings: []string{},
+	}
+	byName := make(map[string]*submitBranch, len(chain))
+	for _, b := range chain {
+		byName[b.name] = b
+
+		var parentPR *github.PullRequest
+		if parent := byName[b.parent]; parent != nil {
+			parentPR = parent.pr
+		}
+
+		entry, err := w.submitBranch(ctx, b, parentPR, currentUser, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to submit %s: %w", b.name, err)
+		}
+		result.Entries = append(result.Entries, *entry)
+	}
+
+	// Step 5: Write the stack table into every PR body
+	if _, err := w.links.Sync(ctx, currentBranch); err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			result.Warnings = append(result.Warnings, "failed to update the stack table of "+line)
+		}
+	}
+
+	return result, nil
+}
+
+// findLocalPRs looks up the PRs of every local branch but trunk in one
+// batched query.
+func (w *SubmitWorkflow) findLocalPRs(ctx context.Context, trunk string) (map[string]*github.PullRequest, error) {
+	branches, err := w.repo.ListBranches(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	names := make([]string, 0, len(branches))
+	for _, b := range branches {
+		if b.Name != trunk {
+			names = append(names, b.Name)
+		}
+	}
+	return w.client.FindPRsForBranches(ctx, w.owner, w.name, names)
+}
+
+// localChain walks from branch down to trunk following the recorded arc
+// parent, then the base of the branch's open PR in prs. The chain is
+// returned bottom first.
+func (w *SubmitWorkflow) localChain(branch, trunk string, prs map[string]*github.PullRequest) ([]*submitBranch, error) {
+	var chain []*submitBranch
+	seen := map[string]bool{}
+
+	for branch != trunk {
+		if seen[branch] {
+			return nil, fmt.Errorf("branch parents form a cycle at %s", branch)
+		}
+		seen[branch] = true
+
+		parent, err := w.parentOf(branch, trunk, prs)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, &submitBranch{name: branch, parent: parent})
+		branch = parent
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+
+	logger.Debug().Int("branches", len(chain)).Msg("Found local stack")
+	return chain, nil
+}
+
+// parentOfBranch looks up the open PR of branch alone and returns the local
+// branch it is stacked on, or trunk.
+func (w *SubmitWorkflow) parentOfBranch(ctx context.Context, branch, trunk string) (string, error) {
+	prs, err := w.client.FindPRsForBranches(ctx, w.owner, w.name, []string{branch})
+	if err != nil {
+		return "", err
+	}
+	return w.parentOf(branch, trunk, prs)
+}
+
+// parentOf returns the local branch that branch is stacked on, or trunk.
+func (w *SubmitWorkflow) parentOf(branch, trunk string, prs map[string]*github.PullRequest) (string, error) {
+	candidates := []string{}
+
+	recorded, err := w.repo.GetBranchParent(branch)
+	if err != nil {
+		return "", err
+	}
+	if recorded != "" {
+		candidates = append(candidates, recorded)
+	}
+
+	if pr := prs[branch]; pr != nil && !pr.IsMerged() {
+		candidates = append(candidates, pr.Base.Ref)
+	}
+
+	for _, parent := range candidates {
+		if parent == trunk {
+			return trunk, nil
+		}
+		exists, err := w.repo.BranchExists("refs/heads/" + parent)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return parent, nil
+		}
+		logger.Debug().Str("branch", branch).Str("parent", parent).Msg("Parent branch not found locally")
+	}
+
+	return trunk, nil
+}
+
+// describeNewBranches fills the template fields of every branch without a
+// PR, from one combined editor session or, with --no-edit, from the
+// pre-filled templates.
+func (w *SubmitWorkflow) describeNewBranches(ctx context.Context, chain []*submitBranch, trunk string, opts *SubmitOptions) error {
+	draftDefault := w.config.Diff.CreateAsDraft
+	if opts.Draft {
+		draftDefault = true
+	} else if opts.Ready {
+		draftDefault = false
+	}
+
+	var sections []template.StackSection
+	contexts := make(map[string]*template.StackingContext)
+	for _, b := range chain {
+		if b.pr != nil {
+			continue
+		}
+
+		analysisBase := b.parent
+		if b.parent == trunk {
+			if exists, err := w.repo.BranchExists("origin/" + trunk); err == nil && exists {
+				analysisBase = "origin/" + trunk
+			}
+		}
+		analysis, err := template.AnalyzeCommitsForTemplate(w.repo, analysisBase, b.name)
+		if err != nil {
+			return fmt.Errorf("failed to analyze commits of %s: %w", b.name, err)
+		}
+
+		stackingCtx := &template.StackingContext{
+			IsStacking:    b.parent != trunk,
+			BaseBranch:    b.parent,
+			CurrentBranch: b.name,
+		}
+		contexts[b.name] = stackingCtx
+
+		gen := template.NewTemplateGenerator(stackingCtx, analysis, w.config.GitHub.DefaultReviewers, w.config.Diff.LinearEnabled, draftDefault)
+		sections = append(sections, template.StackSection{Branch: b.name, Content: gen.Generate()})
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+
+	if !opts.NoEdit {
+		edited, err := w.openEditor(template.JoinStackTemplates(sections))
+		if err != nil {
+			return err
+		}
+		sections = template.SplitStackTemplate(edited)
+	}
+
+	contents := make(map[string]string, len(sections))
+	for _, section := range sections {
+		contents[section.Branch] = section.Content
+	}
+
+	for _, b := range chain {
+		if b.pr != nil {
+			continue
+		}
+
+		content, ok := contents[b.name]
+		if !ok {
+			return fmt.Errorf("%w: section for %s is missing", template.ErrTemplateValidationFailed, b.name)
+		}
+		fields, err := template.ParseTemplate(content)
+		if err != nil {
+			return fmt.Errorf("failed to parse template for %s: %w", b.name, err)
+		}
+		if valid, message := template.ValidateFieldsWithContext(fields, w.config.Diff.RequireTestPlan, contexts[b.name]); !valid {
+			return fmt.Errorf("%w for %s:\n%s", template.ErrTemplateValidationFailed, b.name, message)
+		}
+		b.fields = fields
+	}
+
+	return nil
+}
+
+// submitBranch creates the PR for b, or pushes and retargets its existing PR.
+func (w *SubmitWorkflow) submitBranch(ctx context.Context, b *submitBranch, parentPR *github.PullRequest, currentUser string, opts *SubmitOptions) (*SubmitEntry, error) {
+	entry := &SubmitEntry{Branch: b.name, Base: b.parent}
+
+	var req *diff.PRRequest
+	if existing := b.pr; existing != nil {
+		if existing.Base.Ref != b.parent {
+			if err := w.client.UpdatePRBase(ctx, w.owner, w.name, existing.Number, b.parent); err != nil {
+				return nil, err
+			}
+			entry.BaseChanged = true
+		}
+
+		draft := existing.Draft
+		if opts.Draft {
+			draft = true
+		} else if opts.Ready {
+			draft = false
+		}
+		req = &diff.PRRequest{
+			HeadBranch:  b.name,
+			BaseBranch:  b.parent,
+			Draft:       draft,
+			ExistingPR:  existing,
+			CurrentUser: currentUser,
+		}
+	} else {
+		title := b.fields.Title
+		if w.config.Diff.LinearEnabled && len(b.fields.Ref) > 0 {
+			title = fmt.Sprintf("%s [%s]", title, b.fields.Ref[0])
+		}
+		req = &diff.PRRequest{
+			Title:       title,
+			HeadBranch:  b.name,
+			BaseBranch:  b.parent,
+			Body:        diff.BuildPRBody(b.fields, ""),
+			Draft:       b.fields.Draft,
+			Reviewers:   b.fields.Reviewers,
+			ParentPR:    parentPR,
+			CurrentUser: currentUser,
+		}
+	}
+
+	res, err := w.submitter.CreateOrUpdatePR(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	b.pr = res.PR
+	entry.PRNumber = res.PR.Number
+	entry.PRURL = res.PR.HTMLURL
+	entry.Created = res.WasCreated
+	entry.Pushed = res.Pushed
+	return entry, nil
+}
@@ -0,0 +1,246 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/diff"
+	"github.com/serpro69/gh-arc/internal/git"
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/template"
+)
+
+type mockSubmitRepo struct {
+	current  string
+	parents  map[string]string
+	branches map[string]bool
+}
+
+func (m *mockSubmitRepo) GetCurrentBranch() (string, error) { return m.current, nil }
+func (m *mockSubmitRepo) GetDefaultBranch() (string, error) { return "main", nil }
+
+func (m *mockSubmitRepo) GetBranchParent(branch string) (string, error) {
+	return m.parents[branch], nil
+}
+
+func (m *mockSubmitRepo) BranchExists(branchName string) (bool, error) {
+	return m.branches[strings.TrimPrefix(branchName, "refs/heads/")], nil
+}
+
+func (m *mockSubmitRepo) ListBranches(includeRemote bool) ([]git.BranchInfo, error) {
+	var branches []git.BranchInfo
+	for name, exists := range m.branches {
+		if exists {
+			branches = append(branches, git.BranchInfo{Name: name})
+		}
+	}
+	sort.Slice(branches, func(i, j int) bool { return branches[i].Name < branches[j].Name })
+	return branches, nil
+}
+
+func (m *mockSubmitRepo) GetCommitsBetween(base, head string) ([]git.CommitInfo, error) {
+	return []git.CommitInfo{{SHA: head + "-sha", Message: "Add " + head, Date: time.Now()}}, nil
+}
+
+type mockSubmitClient struct {
+	prs         map[string]*github.PullRequest
+	lookups     [][]string // Branches of every FindPRsForBranches call
+	baseUpdates []string
+	bodyUpdates map[int]string
+}
+
+func (m *mockSubmitClient) FindPRsForBranches(ctx context.Context, owner, repo string, branches []string) (map[string]*github.PullRequest, error) {
+	m.lookups = append(m.lookups, branches)
+	result := make(map[string]*github.PullRequest)
+	for _, b := range branches {
+		if pr, ok := m.prs[b]; ok {
+			result[b] = pr
+		}
+	}
+	return result, nil
+}
+
+func (m *mockSubmitClient) UpdatePRBase(ctx context.Context, owner, repo string, number int, newBase string) error {
+	m.baseUpdates = append(m.baseUpdates, newBase)
+	return nil
+}
+
+func (m *mockSubmitClient) UpdatePullRequest(ctx context.Context, owner, repo string, number int, title, body string, draft *bool, parentPR *github.PullRequest) (*github.PullRequest, error) {
+	m.bodyUpdates[number] = body
+	return &github.PullRequest{Number: number, Body: body}, nil
+}
+
+func (m *mockSubmitClient) GetPullRequestsWithPagination(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, error) {
+	var prs []*github.PullRequest
+	for _, pr := range m.prs {
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
+func (m *mockSubmitClient) GetCurrentUser(ctx context.Context) (string, error) { return "me", nil }
+
+// mockSubmitter registers the PRs it creates with client, so they show up
+// in the open PR list.
+type mockSubmitter struct {
+	client   *mockSubmitClient
+	next     int
+	requests []*diff.PRRequest
+}
+
+func (m *mockSubmitter) CreateOrUpdatePR(ctx context.Context, req *diff.PRRequest) (*diff.PRResult, error) {
+	m.requests = append(m.requests, req)
+	if req.ExistingPR != nil {
+		return &diff.PRResult{PR: req.ExistingPR, Pushed: true}, nil
+	}
+	m.next++
+	pr := newPR(m.next, req.HeadBranch, req.BaseBranch)
+	pr.Title, pr.Body = req.Title, req.Body
+	m.client.prs[req.HeadBranch] = pr
+	return &diff.PRResult{
+		PR:         pr,
+		WasCreated: true,
+		Pushed:     true,
+	}, nil
+}
+
+// newSubmitFixture returns the local stack main <- a <- b <- c with c checked
+// out and an open PR #1 for a.
+func newSubmitFixture() (*mockSubmitRepo, *mockSubmitClient, *mockSubmitter) {
+	repo := &mockSubmitRepo{
+		current:  "c",
+		parents:  map[string]string{"a": "main", "b": "a", "c": "b"},
+		branches: map[string]bool{"main": true, "a": true, "b": true, "c": true},
+	}
+	a := newPR(1, "a", "main")
+	a.Body = "Body of a"
+	client := &mockSubmitClient{
+		prs:         map[string]*github.PullRequest{"a": a},
+		bodyUpdates: map[int]string{},
+	}
+	return repo, client, &mockSubmitter{client: client, next: 10}
+}
+
+func TestSubmitWorkflow(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{}
+
+	t.Run("creates missing PRs bottom-up and cross-links", func(t *testing.T) {
+		repo, client, submitter := newSubmitFixture()
+
+		result, err := NewSubmitWorkflow(repo, client, submitter, cfg, "owner", "repo").Execute(ctx, &SubmitOptions{NoEdit: true})
+		require.NoError(t, err)
+
+		require.Len(t, result.Entries, 3)
+		assert.Equal(t, SubmitEntry{Branch: "a", Base: "main", PRNumber: 1, Pushed: true}, result.Entries[0])
+		assert.Equal(t, SubmitEntry{Branch: "b", Base: "a", PRNumber: 11, Created: true, Pushed: true}, result.Entries[1])
+		assert.Equal(t, SubmitEntry{Branch: "c", Base: "b", PRNumber: 12, Created: true, Pushed: true}, result.Entries[2])
+		assert.Empty(t, result.Warnings)
+		assert.Empty(t, client.baseUpdates)
+
+		require.Len(t, submitter.requests, 3)
+		assert.Equal(t, "Add b", submitter.requests[1].Title)
+		assert.Equal(t, 1, submitter.requests[1].ParentPR.Number)
+		assert.Equal(t, 11, submitter.requests[2].ParentPR.Number)
+		assert.Equal(t, "me", submitter.requests[2].CurrentUser)
+
+		links := []StackLink{
+			{Number: 1, Title: "PR a", State: "Open"},
+			{Number: 11, Title: "Add b", State: "Open", Depth: 1},
+			{Number: 12, Title: "Add c", State: "Open", Depth: 2},
+		}
+		assert.Equal(t, "Body of a\n\n"+FormatStackLinks(links, 1), client.bodyUpdates[1])
+		assert.Contains(t, client.bodyUpdates[12], FormatStackLinks(links, 12))
+	})
+
+	t.Run("combined template", func(t *testing.T) {
+		repo, client, submitter := newSubmitFixture()
+		w := NewSubmitWorkflow(repo, client, submitter, cfg, "owner", "repo")
+
+		var opened string
+		w.openEditor = func(content string) (string, error) {
+			opened = content
+			return strings.ReplaceAll(content, "# Title:\nAdd c\n", "# Title:\nEdited c\n"), nil
+		}
+
+		_, err := w.Execute(ctx, nil)
+		require.NoError(t, err)
+
+		assert.Contains(t, opened, "# Branch: b\n")
+		assert.Contains(t, opened, "# Branch: c\n")
+		assert.NotContains(t, opened, "# Branch: a\n")
+		assert.Equal(t, "Add b", submitter.requests[1].Title)
+		assert.Equal(t, "Edited c", submitter.requests[2].Title)
+	})
+
+	t.Run("editor cancelled", func(t *testing.T) {
+		repo, client, submitter := newSubmitFixture()
+		w := NewSubmitWorkflow(repo, client, submitter, cfg, "owner", "repo")
+		w.openEditor = func(string) (string, error) { return "", template.ErrEditorCancelled }
+
+		_, err := w.Execute(ctx, nil)
+		assert.ErrorIs(t, err, template.ErrEditorCancelled)
+		assert.Empty(t, submitter.requests)
+	})
+
+	t.Run("validation failure submits nothing", func(t *testing.T) {
+		repo, client, submitter := newSubmitFixture()
+		strict := &config.Config{Diff: config.DiffConfig{RequireTestPlan: true}}
+
+		_, err := NewSubmitWorkflow(repo, client, submitter, strict, "owner", "repo").Execute(ctx, &SubmitOptions{NoEdit: true})
+		assert.ErrorIs(t, err, template.ErrTemplateValidationFailed)
+		assert.Empty(t, submitter.requests)
+	})
+
+	t.Run("retargets existing PR and applies draft flag", func(t *testing.T) {
+		repo, client, submitter := newSubmitFixture()
+		repo.current = "a"
+		client.prs["a"].Base.Ref = "develop"
+
+		result, err := NewSubmitWorkflow(repo, client, submitter, cfg, "owner", "repo").Execute(ctx, &SubmitOptions{Draft: true})
+		require.NoError(t, err)
+		assert.True(t, result.Entries[0].BaseChanged)
+		assert.Equal(t, []string{"main"}, client.baseUpdates)
+		assert.True(t, submitter.requests[0].Draft)
+		assert.Empty(t, client.bodyUpdates, "a single PR is not cross-linked")
+	})
+
+	t.Run("falls back to PR base without recorded parent", func(t *testing.T) {
+		repo, client, submitter := newSubmitFixture()
+		repo.current = "b"
+		repo.parents = map[string]string{}
+		client.prs["b"] = newPR(2, "b", "a")
+
+		result, err := NewSubmitWorkflow(repo, client, submitter, cfg, "owner", "repo").Execute(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Entries, 2)
+		assert.Equal(t, "a", result.Entries[0].Branch)
+		assert.Equal(t, "a", result.Entries[1].Base)
+		assert.Equal(t, [][]string{{"a", "b", "c"}}, client.lookups, "one lookup for every local branch")
+	})
+
+	t.Run("on trunk", func(t *testing.T) {
+		repo, client, submitter := newSubmitFixture()
+		repo.current = "main"
+
+		_, err := NewSubmitWorkflow(repo, client, submitter, cfg, "owner", "repo").Execute(ctx, nil)
+		assert.ErrorIs(t, err, ErrOnTrunk)
+	})
+
+	t.Run("merged branch in stack", func(t *testing.T) {
+		repo, client, submitter := newSubmitFixture()
+		merged := time.Now()
+		client.prs["a"].MergedAt = &merged
+
+		_, err := NewSubmitWorkflow(repo, client, submitter, cfg, "owner", "repo").Execute(ctx, &SubmitOptions{NoEdit: true})
+		assert.True(t, errors.Is(err, ErrBranchMerged))
+	})
+}
@@ -0,0 +1,172 @@
+package stack
+
+// StackOptions contains options for the stack command
+type StackOptions struct {
+	All bool // Show every stack instead of only the current branch's
+}
+
+// StackNode is one PR in the stack as reported by the stack command.
+type StackNode struct {
+	Branch       string `json:"branch"`
+	Base         string `json:"base"`
+	PRNumber     int    `json:"pr_number"`
+	Title        string `json:"title"`
+	URL          string `json:"url"`
+	Author       string `json:"author"`
+	Depth        int    `json:"depth"`
+	Current      bool   `json:"current"`
+	Draft        bool   `json:"draft"`
+	ReviewStatus string `json:"review_status"`
+	CheckStatus  string `json:"check_status"`
+	NeedsRebase  bool   `json:"needs_rebase"` // The PR head does not contain its parent PR head
+}
+
+// StackEdge links a parent PR to a child PR that targets its head branch.
+type StackEdge struct {
+	From string `json:"from"` // Parent head branch
+	To   string `json:"to"`   // Child head branch
+}
+
+// StackResult contains the stack graph as a node/edge list. Nodes are in
+// topological order; roots are the nodes whose base is not another node.
+type StackResult struct {
+	Trunk         string      `json:"trunk"`
+	CurrentBranch string      `json:"current_branch"`
+	Nodes         []StackNode `json:"nodes"`
+	Edges         []StackEdge `json:"edges"`
+	All           bool        `json:"all"`      // Every stack is shown
+	InStack       bool        `json:"in_stack"` // The current branch has an open PR
+}
+
+// RestackOptions contains options for the restack command
+type RestackOptions struct {
+	Branch   string // Branch whose descendants are restacked; empty means the current branch
+	Continue bool   // Resume after resolving conflicts
+	Abort    bool   // Abandon the restack and restore all branches
+}
+
+// RestackSkip records a branch that was left alone and why.
+type RestackSkip struct {
+	Branch string `json:"branch"`
+	Reason string `json:"reason"`
+}
+
+// RestackResult reports what a restack did.
+type RestackResult struct {
+	Root         string        `json:"root"`
+	Rebased      []string      `json:"rebased"`
+	UpToDate     []string      `json:"up_to_date"`
+	Skipped      []RestackSkip `json:"skipped"`
+	Pushed       []string      `json:"pushed"`
+	PushFailures []RestackSkip `json:"push_failures"`
+	Aborted      bool          `json:"aborted"`
+}
+
+// SubmitOptions contains options for the stack submit command
+type SubmitOptions struct {
+	NoEdit bool // Create new PRs from the pre-filled templates without opening an editor
+	Draft  bool // Create or update all PRs as drafts
+	Ready  bool // Create or update all PRs as ready for review
+}
+
+// SubmitEntry reports what happened to one branch of the stack.
+type SubmitEntry struct {
+	Branch      string `json:"branch"`
+	Base        string `json:"base"`
+	PRNumber    int    `json:"pr_number"`
+	PRURL       string `json:"pr_url"`
+	Created     bool   `json:"created"`
+	BaseChanged bool   `json:"base_changed"`
+	Pushed      bool   `json:"pushed"`
+}
+
+// SubmitResult reports the PRs of a submitted stack, bottom first.
+type SubmitResult struct {
+	Trunk    string        `json:"trunk"`
+	Entries  []SubmitEntry `json:"entries"`
+	Warnings []string      `json:"warnings"`
+}
+
+// ParentOptions contains options for the stack parent command
+type ParentOptions struct {
+	Branch string // Branch to show or edit; empty means the current branch
+	Set    string // Record this branch as the parent
+	Unset  bool   // Remove the recorded parent
+}
+
+// ParentResult describes the recorded parent of a branch after the command.
+type ParentResult struct {
+	Branch    string `json:"branch"`
+	Parent    string `json:"parent"`     // Empty when no parent is recorded
+	ParentSHA string `json:"parent_sha"` // Commit of the parent the branch forked from
+	Previous  string `json:"previous,omitempty"`
+	Changed   bool   `json:"changed"`
+}
+
+// NavigateOptions contains options for the up, down, top and bottom commands
+type NavigateOptions struct {
+	Direction Direction
+}
+
+// NavigateResult reports a move within a stack.
+type NavigateResult struct {
+	Direction Direction `json:"direction"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Path      []string  `json:"path"` // Branches passed through, ending with To
+	Moved     bool      `json:"moved"`
+}
+
+// SplitOptions contains options for the split command
+type SplitOptions struct {
+	NoSubmit bool // Only create the branches
+	NoEdit   bool // Create the PRs from the pre-filled templates without opening an editor
+	Draft    bool // Create the PRs as drafts
+}
+
+// SplitBranch is one branch created by a split.
+type SplitBranch struct {
+	Branch  string `json:"branch"`
+	Parent  string `json:"parent"`
+	Head    string `json:"head"`
+	Commits int    `json:"commits"`
+}
+
+// SplitResult reports the branches a split created, bottom first.
+type SplitResult struct {
+	Original  string        `json:"original"`
+	Base      string        `json:"base"`
+	Backup    string        `json:"backup"` // Ref holding the original tip
+	BackupSHA string        `json:"backup_sha"`
+	Branches  []SplitBranch `json:"branches"`
+	Rewritten bool          `json:"rewritten"` // Commits were reordered or dropped
+	Submit    *SubmitResult `json:"submit,omitempty"`
+}
+
+// FoldOptions contains options for the stack fold and stack drop commands
+type FoldOptions struct {
+	Mode   FoldMode
+	Branch string // Branch to take out of the stack; empty means the current branch
+}
+
+// FoldResult reports how a branch was taken out of its stack.
+type FoldResult struct {
+	Mode          FoldMode      `json:"mode"`
+	Branch        string        `json:"branch"`
+	PR            int           `json:"pr,omitempty"`
+	Parent        string        `json:"parent"`
+	Backup        string        `json:"backup"` // Ref holding the tip of the removed branch
+	BackupSHA     string        `json:"backup_sha"`
+	ParentMoved   bool          `json:"parent_moved"` // The parent was fast-forwarded
+	Rebased       []string      `json:"rebased"`
+	UpToDate      []string      `json:"up_to_date"`
+	Skipped       []RestackSkip `json:"skipped"`
+	Pushed        []string      `json:"pushed"`
+	Retargeted    []int         `json:"retargeted"` // Child PRs now based on the parent
+	Commented     bool          `json:"commented"`
+	Closed        bool          `json:"closed"`
+	Deleted       bool          `json:"deleted"` // The local branch was deleted
+	CurrentBranch string        `json:"current_branch"`
+	NeedsRestack  []string      `json:"needs_restack,omitempty"` // Rebased branches with branches stacked on them
+	Warnings      []string      `json:"warnings"`
+}
@@ -0,0 +1,175 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/serpro69/gh-arc/internal/github"
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+// StackRepository defines git operations needed by the stack workflow.
+type StackRepository interface {
+	GetCurrentBranch() (string, error)
+	GetDefaultBranch() (string, error)
+	IsAncestor(ancestorRef, descendantRef string) (bool, error)
+}
+
+// PullRequestLister lists pull requests, which is all LoadGraph needs.
+type PullRequestLister interface {
+	GetPullRequestsWithPagination(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, error)
+}
+
+// StackGitHubClient defines GitHub operations needed by the stack workflow.
+type StackGitHubClient interface {
+	PullRequestLister
+	EnrichPullRequests(ctx context.Context, owner, repo string, prs []*github.PullRequest) error
+}
+
+// StackWorkflow shows the graph of stacked pull requests.
+type StackWorkflow struct {
+	repo   StackRepository
+	client StackGitHubClient
+	owner  string
+	name   string
+}
+
+// NewStackWorkflow creates a new StackWorkflow.
+func NewStackWorkflow(repo StackRepository, client StackGitHubClient, owner, name string) *StackWorkflow {
+	return &StackWorkflow{
+		repo:   repo,
+		client: client,
+		owner:  owner,
+		name:   name,
+	}
+}
+
+// LoadGraph fetches all open pull requests and links them into a stack graph.
+func LoadGraph(ctx context.Context, client PullRequestLister, owner, name string) (*Graph, error) {
+	prs, err := client.GetPullRequestsWithPagination(ctx, owner, name, &github.PullRequestListOptions{
+		State:   "open",
+		PerPage: 100,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch open pull requests: %w", err)
+	}
+
+	return BuildGraph(prs, owner, name), nil
+}
+
+// Execute runs the stack workflow.
+func (w *StackWorkflow) Execute(ctx context.Context, opts *StackOptions) (*StackResult, error) {
+	if opts == nil {
+		opts = &StackOptions{}
+	}
+
+	logger.Debug().
+		Bool("all", opts.All).
+		Msg("Executing stack workflow")
+
+	// Step 1: Resolve branches. A detached HEAD simply has no current stack.
+	currentBranch, err := w.repo.GetCurrentBranch()
+	if err != nil {
+		logger.Debug().Err(err).Msg("Failed to get current branch")
+		currentBranch = ""
+	}
+
+	defaultBranch, err := w.repo.GetDefaultBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine default branch: %w", err)
+	}
+
+	// Step 2: Build the graph of open PRs
+	graph, err := LoadGraph(ctx, w.client, w.owner, w.name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StackResult{
+		Trunk:         defaultBranch,
+		CurrentBranch: currentBranch,
+		Nodes:         []StackNode{},
+		Edges:         []StackEdge{},
+	}
+
+	// Step 3: Select the current branch's stack, or every stack
+	current := graph.Node(currentBranch)
+	result.InStack = current != nil
+
+	roots := graph.Roots
+	if current != nil && !opts.All {
+		roots = []*Node{current.Root()}
+	} else {
+		result.All = true
+	}
+
+	var selected []*Node
+	for _, root := range roots {
+		Walk(root, func(n *Node) { selected = append(selected, n) })
+	}
+
+	// Step 4: Fetch review and CI state for the selected PRs only
+	prs := make([]*github.PullRequest, 0, len(selected))
+	for _, n := range selected {
+		prs = append(prs, n.PR)
+	}
+	if err := w.client.EnrichPullRequests(ctx, w.owner, w.name, prs); err != nil {
+		logger.Warn().Err(err).Msg("Failed to fetch review and check status")
+	}
+
+	// Step 5: Flatten into nodes and edges
+	for _, n := range selected {
+		result.Nodes = append(result.Nodes, w.newStackNode(n, currentBranch))
+		if n.Parent != nil {
+			result.Edges = append(result.Edges, StackEdge{From: n.Parent.Branch, To: n.Branch})
+		}
+	}
+
+	return result, nil
+}
+
+// newStackNode reports a graph node. A stacked PR needs a rebase when its
+// head does not contain its parent's head.
+func (w *StackWorkflow) newStackNode(n *Node, currentBranch string) StackNode {
+	node := StackNode{
+		Branch:   n.Branch,
+		Base:     n.Base(),
+		PRNumber: n.PR.Number,
+		Title:    n.PR.Title,
+		URL:      n.PR.HTMLURL,
+		Author:   n.PR.User.Login,
+		Depth:    n.Depth(),
+		Current:  n.Branch == currentBranch,
+		Draft:    n.PR.Draft,
+	}
+
+	if n.PR.Reviews != nil || n.PR.Checks != nil {
+		status := github.DeterminePRStatus(n.PR.Reviews, n.PR.Checks)
+		node.ReviewStatus = status.ReviewStatus
+		node.CheckStatus = status.CheckStatus
+	}
+
+	if n.Parent != nil {
+		node.NeedsRebase = w.needsRebase(n.Parent.PR.Head.SHA, n.PR.Head.SHA)
+	}
+
+	return node
+}
+
+// needsRebase reports whether the child head is missing the parent head.
+// Commits that were never fetched can't be compared, so they don't count.
+func (w *StackWorkflow) needsRebase(parentSHA, childSHA string) bool {
+	if parentSHA == "" || childSHA == "" {
+		return false
+	}
+	contains, err := w.repo.IsAncestor(parentSHA, childSHA)
+	if err != nil {
+		logger.Debug().
+			Err(err).
+			Str("parent", parentSHA).
+			Str("child", childSHA).
+			Msg("Cannot compare PR heads, assuming no rebase is needed")
+		return false
+	}
+	return !contains
+}
@@ -0,0 +1,148 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/github"
+)
+
+type mockStackRepo struct {
+	current   string
+	ancestors map[string]bool // "ancestor..descendant" -> is ancestor; missing pairs are unknown commits
+}
+
+func (m *mockStackRepo) GetCurrentBranch() (string, error) {
+	if m.current == "" {
+		return "", errors.New("detached HEAD")
+	}
+	return m.current, nil
+}
+
+func (m *mockStackRepo) GetDefaultBranch() (string, error) { return "main", nil }
+
+func (m *mockStackRepo) IsAncestor(ancestorRef, descendantRef string) (bool, error) {
+	is, ok := m.ancestors[ancestorRef+".."+descendantRef]
+	if !ok {
+		return false, errors.New("unknown commit")
+	}
+	return is, nil
+}
+
+type mockStackClient struct {
+	prs       []*github.PullRequest
+	enriched  []int
+	enrichErr error
+}
+
+func (m *mockStackClient) GetPullRequestsWithPagination(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, error) {
+	return m.prs, nil
+}
+
+func (m *mockStackClient) EnrichPullRequests(ctx context.Context, owner, repo string, prs []*github.PullRequest) error {
+	for _, pr := range prs {
+		m.enriched = append(m.enriched, pr.Number)
+		pr.Reviews = []github.PRReview{{State: "APPROVED"}}
+		pr.Checks = []github.PRCheck{{Status: "completed", Conclusion: "success"}}
+	}
+	return m.enrichErr
+}
+
+func newStackPRs() []*github.PullRequest {
+	c := newPR(3, "c", "b")
+	c.Draft = true
+	return []*github.PullRequest{newPR(1, "a", "main"), newPR(2, "b", "a"), c, newPR(9, "other", "main")}
+}
+
+// newStackRepo returns a repository in which b contains a, while b moved on
+// since c branched off it.
+func newStackRepo(current string) *mockStackRepo {
+	return &mockStackRepo{
+		current: current,
+		ancestors: map[string]bool{
+			"a-sha..b-sha": true,
+			"b-sha..c-sha": false,
+		},
+	}
+}
+
+func nodeBranches(nodes []StackNode) []string {
+	names := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		names = append(names, n.Branch)
+	}
+	return names
+}
+
+func TestStackWorkflow(t *testing.T) {
+	t.Run("current stack only", func(t *testing.T) {
+		client := &mockStackClient{prs: newStackPRs()}
+
+		result, err := NewStackWorkflow(newStackRepo("b"), client, "owner", "repo").Execute(context.Background(), nil)
+		require.NoError(t, err)
+		assert.True(t, result.InStack)
+		assert.False(t, result.All)
+		assert.Equal(t, []string{"a", "b", "c"}, nodeBranches(result.Nodes))
+		assert.Equal(t, []StackEdge{{From: "a", To: "b"}, {From: "b", To: "c"}}, result.Edges)
+		assert.Equal(t, []int{1, 2, 3}, client.enriched)
+
+		b, c := result.Nodes[1], result.Nodes[2]
+		assert.True(t, b.Current)
+		assert.False(t, b.NeedsRebase)
+		assert.Equal(t, 1, b.Depth)
+		assert.True(t, c.NeedsRebase)
+		assert.True(t, c.Draft)
+		assert.Equal(t, "approved", c.ReviewStatus)
+		assert.Equal(t, "success", c.CheckStatus)
+	})
+
+	t.Run("all stacks", func(t *testing.T) {
+		client := &mockStackClient{prs: newStackPRs()}
+
+		result, err := NewStackWorkflow(&mockStackRepo{current: "b"}, client, "owner", "repo").Execute(context.Background(), &StackOptions{All: true})
+		require.NoError(t, err)
+		assert.True(t, result.All)
+		assert.Equal(t, []string{"a", "b", "c", "other"}, nodeBranches(result.Nodes))
+	})
+
+	t.Run("branch without PR shows all stacks", func(t *testing.T) {
+		client := &mockStackClient{prs: newStackPRs()}
+
+		result, err := NewStackWorkflow(&mockStackRepo{current: "main"}, client, "owner", "repo").Execute(context.Background(), nil)
+		require.NoError(t, err)
+		assert.False(t, result.InStack)
+		assert.True(t, result.All)
+		assert.Len(t, result.Nodes, 4)
+	})
+
+	t.Run("detached HEAD", func(t *testing.T) {
+		client := &mockStackClient{prs: newStackPRs()}
+
+		result, err := NewStackWorkflow(&mockStackRepo{}, client, "owner", "repo").Execute(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Empty(t, result.CurrentBranch)
+		assert.Len(t, result.Nodes, 4)
+	})
+
+	t.Run("unfetched heads don't need a rebase", func(t *testing.T) {
+		client := &mockStackClient{prs: newStackPRs()}
+
+		result, err := NewStackWorkflow(&mockStackRepo{current: "b"}, client, "owner", "repo").Execute(context.Background(), nil)
+		require.NoError(t, err)
+		for _, n := range result.Nodes {
+			assert.False(t, n.NeedsRebase, n.Branch)
+		}
+	})
+
+	t.Run("status failure is not fatal", func(t *testing.T) {
+		client := &mockStackClient{prs: newStackPRs(), enrichErr: errors.New("rate limited")}
+
+		result, err := NewStackWorkflow(&mockStackRepo{current: "a"}, client, "owner", "repo").Execute(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Len(t, result.Nodes, 3)
+	})
+}
@@ -26,6 +26,9 @@ const (
 	markerDraft      = "# Draft:"
 	markerBaseBranch = "# Base Branch:"
 
+	// markerStackBranch starts a branch's section in a combined stack template
+	markerStackBranch = "# Branch:"
+
 	// Template section markers
 	sectionStart = "# =========="
 	sectionEnd   = "# ----------"
@@ -754,6 +757,63 @@ func ValidateFieldsWithContext(fields *TemplateFields, requireTestPlan bool, sta
 	return false, FormatValidationErrors(errs, stackingCtx)
 }
 
+// StackSection is one branch's template within a combined stack template
+type StackSection struct {
+	Branch  string
+	Content string
+}
+
+// JoinStackTemplates combines the templates of several branches into one
+// document, so a whole stack can be described in a single editor session.
+// Each section starts with a "# Branch:" line that must be left intact.
+func JoinStackTemplates(sections []StackSection) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Describe each branch of the stack below, bottom first.\n")
+	sb.WriteString("# Every section starts with a '# Branch:' line; do not edit those lines.\n")
+	for _, section := range sections {
+		sb.WriteString("\n" + markerStackBranch + " " + section.Branch + "\n")
+		sb.WriteString(section.Content)
+		if !strings.HasSuffix(section.Content, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// SplitStackTemplate splits a combined stack template back into its
+// sections, in document order. Content before the first section is ignored.
+func SplitStackTemplate(content string) []StackSection {
+	var sections []StackSection
+	var current *StackSection
+	var body strings.Builder
+
+	flush := func() {
+		if current != nil {
+			current.Content = body.String()
+			sections = append(sections, *current)
+		}
+		body.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, markerStackBranch) {
+			flush()
+			current = &StackSection{Branch: strings.TrimSpace(strings.TrimPrefix(line, markerStackBranch))}
+			continue
+		}
+		if current != nil {
+			body.WriteString(line + "\n")
+		}
+	}
+	flush()
+
+	return sections
+}
+
 // WriteTemplateTo writes template content to a writer (for testing)
 func WriteTemplateTo(w io.Writer, templateContent string) error {
 	_, err := w.Write([]byte(templateContent))
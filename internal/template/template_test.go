@@ -2129,3 +2129,49 @@ false`
 		t.Errorf("Expected base='feature/auth', got %q", base)
 	}
 }
+
+func TestStackTemplates(t *testing.T) {
+	first := NewTemplateGenerator(
+		&StackingContext{BaseBranch: "main", CurrentBranch: "feature/a"},
+		&CommitAnalysis{Title: "Add a", Summary: "Adds a"},
+		nil, false, false,
+	).Generate()
+	second := NewTemplateGenerator(
+		&StackingContext{IsStacking: true, BaseBranch: "feature/a", CurrentBranch: "feature/b"},
+		&CommitAnalysis{Title: "Add b"},
+		nil, false, true,
+	).Generate()
+
+	combined := JoinStackTemplates([]StackSection{
+		{Branch: "feature/a", Content: first},
+		{Branch: "feature/b", Content: second},
+	})
+
+	sections := SplitStackTemplate(combined)
+	if len(sections) != 2 {
+		t.Fatalf("Expected 2 sections, got %d", len(sections))
+	}
+	if sections[0].Branch != "feature/a" || sections[1].Branch != "feature/b" {
+		t.Errorf("Unexpected branches: %q, %q", sections[0].Branch, sections[1].Branch)
+	}
+
+	fields, err := ParseTemplate(sections[0].Content)
+	if err != nil {
+		t.Fatalf("ParseTemplate failed: %v", err)
+	}
+	if fields.Title != "Add a" || fields.Summary != "Adds a" || fields.Draft {
+		t.Errorf("Unexpected fields for first section: %+v", fields)
+	}
+
+	fields, err = ParseTemplate(sections[1].Content)
+	if err != nil {
+		t.Fatalf("ParseTemplate failed: %v", err)
+	}
+	if fields.Title != "Add b" || !fields.Draft {
+		t.Errorf("Unexpected fields for second section: %+v", fields)
+	}
+
+	if got := SplitStackTemplate("# nothing here\n"); len(got) != 0 {
+		t.Errorf("Expected no sections, got %d", len(got))
+	}
+}
@@ -0,0 +1,14 @@
+package unit
+
+import "github.com/serpro69/gh-arc/internal/runner"
+
+// UnitOptions holds the flags and options for the unit command.
+type UnitOptions struct {
+	JSONMode bool
+	Verbose  bool
+}
+
+// UnitResult represents the outcome of a unit workflow execution.
+type UnitResult struct {
+	*runner.ExecutionResult
+}
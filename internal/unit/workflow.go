@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/logger"
+	"github.com/serpro69/gh-arc/internal/runner"
+)
+
+// UnitRepository defines git operations needed by the unit workflow.
+type UnitRepository interface {
+	Path() string
+}
+
+// UnitWorkflow orchestrates the unit command: it converts the configured
+// test runners into engine configs and executes them.
+type UnitWorkflow struct {
+	repo     UnitRepository
+	executor runner.Executor
+	config   *config.Config
+	out      io.Writer
+}
+
+// NewUnitWorkflow creates a new UnitWorkflow. repo may be nil outside a git
+// repository, in which case working directories are relative to the current
+// directory.
+func NewUnitWorkflow(repo UnitRepository, executor runner.Executor, cfg *config.Config) *UnitWorkflow {
+	return &UnitWorkflow{
+		repo:     repo,
+		executor: executor,
+		config:   cfg,
+		out:      os.Stdout,
+	}
+}
+
+// Execute runs all configured test runners.
+func (w *UnitWorkflow) Execute(ctx context.Context, opts *UnitOptions) (*UnitResult, error) {
+	if opts == nil {
+		opts = &UnitOptions{}
+	}
+
+	logger.Debug().
+		Int("runners", len(w.config.Test.Runners)).
+		Msg("Executing unit workflow")
+
+	if len(w.config.Test.Runners) == 0 {
+		if !opts.JSONMode {
+			w.printNoRunnersGuidance()
+		}
+		return &UnitResult{
+			ExecutionResult: &runner.ExecutionResult{Runners: []runner.RunResult{}, Success: true},
+		}, nil
+	}
+
+	var root string
+	if w.repo != nil {
+		root = w.repo.Path()
+	}
+
+	configs, err := buildRunnerConfigs(root, w.config.Test.Runners)
+	if err != nil {
+		return nil, err
+	}
+
+	execResult, err := w.executor.Run(ctx, configs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run tests: %w", err)
+	}
+
+	return &UnitResult{ExecutionResult: execResult}, nil
+}
+
+// buildRunnerConfigs maps test runners to engine configs, anchoring working
+// directories at root and parsing timeouts.
+func buildRunnerConfigs(root string, runners []config.TestRunner) ([]runner.RunnerConfig, error) {
+	configs := make([]runner.RunnerConfig, 0, len(runners))
+	for _, tr := range runners {
+		timeout, err := runner.ParseTimeout(tr.Name, tr.Timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		configs = append(configs, runner.RunnerConfig{
+			Name:       tr.Name,
+			Command:    tr.Command,
+			Args:       tr.Args,
+			WorkingDir: runner.ResolveWorkingDir(root, tr.WorkingDir),
+			Timeout:    timeout,
+		})
+	}
+	return configs, nil
+}
+
+func (w *UnitWorkflow) printNoRunnersGuidance() {
+	fmt.Fprintln(w.out, "No test runners configured.")
+	fmt.Fprintln(w.out)
+	fmt.Fprintln(w.out, "Add runners to .arc.json:")
+	fmt.Fprintln(w.out, `  "test": {`)
+	fmt.Fprintln(w.out, `    "runners": [`)
+	fmt.Fprintln(w.out, `      { "name": "go-test", "command": "go", "args": ["test", "./..."], "timeout": "5m" }`)
+	fmt.Fprintln(w.out, `    ]`)
+	fmt.Fprintln(w.out, `  }`)
+}
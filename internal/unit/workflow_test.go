@@ -0,0 +1,167 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/serpro69/gh-arc/internal/config"
+	"github.com/serpro69/gh-arc/internal/runner"
+)
+
+type mockExecutor struct {
+	configs []runner.RunnerConfig
+	called  bool
+	result  *runner.ExecutionResult
+}
+
+func (m *mockExecutor) Run(ctx context.Context, configs []runner.RunnerConfig) (*runner.ExecutionResult, error) {
+	m.called = true
+	m.configs = configs
+	if m.result != nil {
+		return m.result, nil
+	}
+	return &runner.ExecutionResult{Runners: []runner.RunResult{}, Success: true}, nil
+}
+
+func configWithRunners(runners ...config.TestRunner) *config.Config {
+	return &config.Config{Test: config.TestConfig{Runners: runners}}
+}
+
+type mockRepo struct {
+	path string
+}
+
+func (m *mockRepo) Path() string { return m.path }
+
+func newTestWorkflow(executor runner.Executor, cfg *config.Config) (*UnitWorkflow, *bytes.Buffer) {
+	w := NewUnitWorkflow(nil, executor, cfg)
+	buf := &bytes.Buffer{}
+	w.out = buf
+	return w, buf
+}
+
+func TestUnitWorkflow_NoRunners(t *testing.T) {
+	t.Run("prints guidance", func(t *testing.T) {
+		exec := &mockExecutor{}
+		w, out := newTestWorkflow(exec, &config.Config{})
+
+		result, err := w.Execute(context.Background(), &UnitOptions{})
+		require.NoError(t, err)
+
+		assert.True(t, result.Success)
+		assert.Empty(t, result.Runners)
+		assert.False(t, exec.called)
+		assert.Contains(t, out.String(), "No test runners configured")
+	})
+
+	t.Run("JSON mode suppresses guidance", func(t *testing.T) {
+		w, out := newTestWorkflow(&mockExecutor{}, &config.Config{})
+
+		result, err := w.Execute(context.Background(), &UnitOptions{JSONMode: true})
+		require.NoError(t, err)
+
+		assert.True(t, result.Success)
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestUnitWorkflow_BuildsConfigs(t *testing.T) {
+	exec := &mockExecutor{}
+	w, _ := newTestWorkflow(exec, configWithRunners(
+		config.TestRunner{Name: "go-test", Command: "go", Args: []string{"test", "./..."}, Timeout: "5m"},
+		config.TestRunner{Name: "jest", Command: "npx", Args: []string{"jest"}, WorkingDir: "frontend"},
+	))
+
+	_, err := w.Execute(context.Background(), &UnitOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, exec.configs, 2)
+	assert.Equal(t, "go-test", exec.configs[0].Name)
+	assert.Equal(t, "go", exec.configs[0].Command)
+	assert.Equal(t, []string{"test", "./..."}, exec.configs[0].Args)
+	assert.Equal(t, 5*time.Minute, exec.configs[0].Timeout)
+	assert.Empty(t, exec.configs[0].FilePaths)
+
+	assert.Equal(t, "frontend", exec.configs[1].WorkingDir)
+	assert.Zero(t, exec.configs[1].Timeout, "omitted timeout means no timeout")
+}
+
+func TestUnitWorkflow_AnchorsWorkingDirAtRepoRoot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "repo")
+	abs := filepath.Join(t.TempDir(), "elsewhere")
+
+	exec := &mockExecutor{}
+	w, _ := newTestWorkflow(exec, configWithRunners(
+		config.TestRunner{Name: "go-test", Command: "go"},
+		config.TestRunner{Name: "jest", Command: "npx", WorkingDir: "frontend"},
+		config.TestRunner{Name: "other", Command: "make", WorkingDir: abs},
+	))
+	w.repo = &mockRepo{path: root}
+
+	_, err := w.Execute(context.Background(), &UnitOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, exec.configs, 3)
+	assert.Equal(t, root, exec.configs[0].WorkingDir)
+	assert.Equal(t, filepath.Join(root, "frontend"), exec.configs[1].WorkingDir)
+	assert.Equal(t, abs, exec.configs[2].WorkingDir)
+}
+
+func TestUnitWorkflow_InvalidTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout string
+		wantErr string
+	}{
+		{
+			name:    "unparseable",
+			timeout: "5min",
+			wantErr: "invalid timeout '5min' for runner 'go-test': use Go duration format like '5m' or '300s'",
+		},
+		{
+			name:    "negative",
+			timeout: "-1s",
+			wantErr: "must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &mockExecutor{}
+			w, _ := newTestWorkflow(exec, configWithRunners(
+				config.TestRunner{Name: "go-test", Command: "go", Timeout: tt.timeout},
+			))
+
+			_, err := w.Execute(context.Background(), &UnitOptions{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.False(t, exec.called)
+		})
+	}
+}
+
+func TestUnitWorkflow_ReturnsExecutorResult(t *testing.T) {
+	exec := &mockExecutor{
+		result: &runner.ExecutionResult{
+			Success: false,
+			Runners: []runner.RunResult{
+				{Name: "go-test", Status: runner.StatusTimeout},
+			},
+		},
+	}
+	w, _ := newTestWorkflow(exec, configWithRunners(
+		config.TestRunner{Name: "go-test", Command: "go"},
+	))
+
+	result, err := w.Execute(context.Background(), &UnitOptions{})
+	require.NoError(t, err)
+
+	assert.False(t, result.Success)
+	assert.Equal(t, 1, result.CountByStatus(runner.StatusTimeout))
+}
@@ -0,0 +1,30 @@
+package work
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// PrintResult writes a human-readable report of the started branch.
+func PrintResult(out io.Writer, result *WorkResult) {
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(out, "⚠ %s\n", warning)
+	}
+
+	if result.Stacked {
+		fmt.Fprintf(out, "✓ Created %s stacked on %s\n", result.Branch, result.Parent)
+		return
+	}
+
+	fmt.Fprintf(out, "✓ Created %s from %s\n", result.Branch, result.StartPoint)
+	if result.TrunkBehind > 0 {
+		fmt.Fprintf(out, "⚠ Local %s is %d commit(s) behind %s; run 'git pull' on it to catch up\n",
+			result.Parent, result.TrunkBehind, result.StartPoint)
+	}
+}
+
+// FormatJSON returns the result as indented JSON.
+func FormatJSON(result *WorkResult) ([]byte, error) {
+	return json.MarshalIndent(result, "", "  ")
+}
@@ -0,0 +1,36 @@
+package work
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintResult(t *testing.T) {
+	t.Run("from trunk", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintResult(&buf, &WorkResult{Branch: "feature", Parent: "main", StartPoint: "origin/main", TrunkBehind: 2})
+		out := buf.String()
+		assert.Contains(t, out, "✓ Created feature from origin/main")
+		assert.Contains(t, out, "Local main is 2 commit(s) behind origin/main")
+	})
+
+	t.Run("stacked", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintResult(&buf, &WorkResult{Branch: "child", Parent: "parent", StartPoint: "parent", Stacked: true, Warnings: []string{"w"}})
+		assert.Equal(t, "⚠ w\n✓ Created child stacked on parent\n", buf.String())
+	})
+}
+
+func TestFormatJSON(t *testing.T) {
+	data, err := FormatJSON(&WorkResult{Branch: "feature", StartPoint: "origin/main"})
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "feature", decoded["branch"])
+	assert.Equal(t, "origin/main", decoded["start_point"])
+}
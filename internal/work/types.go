@@ -0,0 +1,18 @@
+package work
+
+// WorkOptions contains options for the work command
+type WorkOptions struct {
+	Name  string // Branch name; empty generates one from diff.autoBranchNamePattern
+	Stack bool   // Branch from the current feature branch instead of trunk
+}
+
+// WorkResult describes the branch that was started.
+type WorkResult struct {
+	Branch      string   `json:"branch"`
+	Parent      string   `json:"parent"`       // Recorded parent branch
+	ParentSHA   string   `json:"parent_sha"`   // Recorded commit of the parent the branch starts at
+	StartPoint  string   `json:"start_point"`  // Ref the branch was created from
+	Stacked     bool     `json:"stacked"`      // Branched from a feature branch
+	TrunkBehind int      `json:"trunk_behind"` // Commits local trunk is behind origin
+	Warnings    []string `json:"warnings,omitempty"`
+}
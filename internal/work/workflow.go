@@ -0,0 +1,172 @@
+package work
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/serpro69/gh-arc/internal/logger"
+)
+
+var (
+	// ErrBranchExists is returned when the requested branch already exists.
+	ErrBranchExists = errors.New("branch already exists")
+
+	// ErrStackOnTrunk is returned when --stack is used on the default branch.
+	ErrStackOnTrunk = errors.New("cannot stack on the default branch")
+)
+
+// WorkRepository defines git operations needed by the work workflow.
+type WorkRepository interface {
+	GetCurrentBranch() (string, error)
+	GetDefaultBranch() (string, error)
+	Fetch(ctx context.Context, remote string, refspecs ...string) error
+	BranchExists(branchName string) (bool, error)
+	GetAheadBehind(branch, base string) (int, int, error)
+	CreateBranch(name string, baseBranch string) error
+	CheckoutBranch(branch string) error
+	GetBranchSHA(branch string) (string, error)
+	SetBranchParent(branch, parent, parentSHA string) error
+}
+
+// BranchNamer names new branches and guards against a stale trunk.
+// *diff.AutoBranchDetector satisfies this interface, so work and the
+// auto-branch flow of diff share diff.autoBranchNamePattern.
+type BranchNamer interface {
+	CheckStaleRemote(ctx context.Context, defaultBranch string) (bool, error)
+	ResolveBranchName() (string, error)
+}
+
+// WorkWorkflow starts a new short-lived branch, either from an up-to-date
+// trunk or, when stacking, from the current feature branch.
+type WorkWorkflow struct {
+	repo  WorkRepository
+	namer BranchNamer
+}
+
+// NewWorkWorkflow creates a new WorkWorkflow.
+func NewWorkWorkflow(repo WorkRepository, namer BranchNamer) *WorkWorkflow {
+	return &WorkWorkflow{
+		repo:  repo,
+		namer: namer,
+	}
+}
+
+// Execute runs the work workflow.
+func (w *WorkWorkflow) Execute(ctx context.Context, opts *WorkOptions) (*WorkResult, error) {
+	if opts == nil {
+		opts = &WorkOptions{}
+	}
+
+	logger.Debug().
+		Str("name", opts.Name).
+		Bool("stack", opts.Stack).
+		Msg("Executing work workflow")
+
+	defaultBranch, err := w.repo.GetDefaultBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine default branch: %w", err)
+	}
+
+	currentBranch, err := w.repo.GetCurrentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	if opts.Stack && currentBranch == defaultBranch {
+		return nil, fmt.Errorf("%w: %s", ErrStackOnTrunk, defaultBranch)
+	}
+
+	// Step 1: Bring origin's trunk up to date. The explicit refspec makes a
+	// successful fetch always move origin/<trunk> to the remote tip, even when
+	// remote.origin.fetch does not cover it. Fetching leaves the reflog
+	// untouched when nothing changed, so the stale check only runs when fetch
+	// fails.
+	result := &WorkResult{}
+	fetched := true
+	refspec := fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", defaultBranch, defaultBranch)
+	if err := w.repo.Fetch(ctx, "origin", refspec); err != nil {
+		logger.Warn().Err(err).Msg("Failed to fetch origin")
+		result.Warnings = append(result.Warnings, "could not fetch origin; starting from the last fetched state")
+		fetched = false
+
+		if _, err := w.namer.CheckStaleRemote(ctx, defaultBranch); err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 2: Choose the start point
+	if opts.Stack {
+		result.Parent = currentBranch
+		result.StartPoint = currentBranch
+		result.Stacked = true
+	} else {
+		result.Parent = defaultBranch
+		result.StartPoint = w.trunkRef(defaultBranch)
+		if result.StartPoint != defaultBranch {
+			_, behind, err := w.repo.GetAheadBehind(defaultBranch, result.StartPoint)
+			if err != nil {
+				logger.Debug().Err(err).Msg("Failed to compare local trunk with origin")
+			}
+			result.TrunkBehind = behind
+		} else if fetched {
+			// The fetch succeeded but left no remote tip to start from, so
+			// nothing guarantees the local trunk is current.
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("origin/%s not found after fetch; starting from local %s, which may be out of date", defaultBranch, defaultBranch))
+		}
+	}
+
+	// Step 3: Name the branch
+	if opts.Name != "" {
+		exists, err := w.repo.BranchExists(opts.Name)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("%w: %s", ErrBranchExists, opts.Name)
+		}
+		result.Branch = opts.Name
+	} else {
+		name, err := w.namer.ResolveBranchName()
+		if err != nil {
+			return nil, err
+		}
+		result.Branch = name
+	}
+
+	// Step 4: Create, switch to, and record the parent of the new branch
+	if err := w.repo.CreateBranch(result.Branch, result.StartPoint); err != nil {
+		return nil, err
+	}
+	if err := w.repo.CheckoutBranch(result.Branch); err != nil {
+		return nil, err
+	}
+	parentSHA, err := w.repo.GetBranchSHA(result.StartPoint)
+	if err != nil {
+		logger.Debug().Err(err).Str("startPoint", result.StartPoint).Msg("Failed to resolve start point")
+	}
+	result.ParentSHA = parentSHA
+	if err := w.repo.SetBranchParent(result.Branch, result.Parent, parentSHA); err != nil {
+		logger.Warn().Err(err).Str("branch", result.Branch).Msg("Failed to record parent branch")
+		result.Warnings = append(result.Warnings, "could not record parent branch; gh arc diff will detect the base itself")
+	}
+
+	logger.Info().
+		Str("branch", result.Branch).
+		Str("parent", result.Parent).
+		Str("startPoint", result.StartPoint).
+		Msg("Started new branch")
+
+	return result, nil
+}
+
+// trunkRef prefers the remote-tracking default branch, which was just
+// fetched, and falls back to the local default branch.
+func (w *WorkWorkflow) trunkRef(defaultBranch string) string {
+	remote := "origin/" + defaultBranch
+	if exists, err := w.repo.BranchExists(remote); err == nil && exists {
+		return remote
+	}
+	return defaultBranch
+}
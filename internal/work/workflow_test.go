@@ -0,0 +1,182 @@
+package work
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWorkRepo struct {
+	current    string
+	branches   map[string]bool
+	behind     int
+	fetchErr   error
+	fetched    []string          // remote + refspecs of each fetch
+	created    map[string]string // branch -> start point
+	checkedOut []string
+	parents    map[string]string
+	parentSHAs map[string]string
+}
+
+func newMockWorkRepo(current string) *mockWorkRepo {
+	return &mockWorkRepo{
+		current:    current,
+		branches:   map[string]bool{"main": true, "origin/main": true},
+		created:    map[string]string{},
+		parents:    map[string]string{},
+		parentSHAs: map[string]string{},
+	}
+}
+
+func (m *mockWorkRepo) GetCurrentBranch() (string, error) { return m.current, nil }
+func (m *mockWorkRepo) GetDefaultBranch() (string, error) { return "main", nil }
+
+func (m *mockWorkRepo) Fetch(ctx context.Context, remote string, refspecs ...string) error {
+	m.fetched = append(m.fetched, append([]string{remote}, refspecs...)...)
+	return m.fetchErr
+}
+
+func (m *mockWorkRepo) BranchExists(branchName string) (bool, error) {
+	return m.branches[branchName], nil
+}
+
+func (m *mockWorkRepo) GetAheadBehind(branch, base string) (int, int, error) {
+	return 0, m.behind, nil
+}
+
+func (m *mockWorkRepo) CreateBranch(name string, baseBranch string) error {
+	m.created[name] = baseBranch
+	m.branches[name] = true
+	return nil
+}
+
+func (m *mockWorkRepo) CheckoutBranch(branch string) error {
+	m.checkedOut = append(m.checkedOut, branch)
+	m.current = branch
+	return nil
+}
+
+func (m *mockWorkRepo) GetBranchSHA(branch string) (string, error) {
+	return branch + "-sha", nil
+}
+
+func (m *mockWorkRepo) SetBranchParent(branch, parent, parentSHA string) error {
+	m.parents[branch] = parent
+	m.parentSHAs[branch] = parentSHA
+	return nil
+}
+
+type mockNamer struct {
+	name       string
+	staleErr   error
+	staleCalls int
+}
+
+func (m *mockNamer) CheckStaleRemote(ctx context.Context, defaultBranch string) (bool, error) {
+	m.staleCalls++
+	return m.staleErr == nil, m.staleErr
+}
+
+func (m *mockNamer) ResolveBranchName() (string, error) { return m.name, nil }
+
+func TestWorkWorkflow(t *testing.T) {
+	t.Run("branches from origin trunk", func(t *testing.T) {
+		repo := newMockWorkRepo("main")
+		repo.behind = 3
+		namer := &mockNamer{}
+
+		result, err := NewWorkWorkflow(repo, namer).Execute(context.Background(), &WorkOptions{Name: "feature"})
+		require.NoError(t, err)
+		assert.Equal(t, "feature", result.Branch)
+		assert.Equal(t, "origin/main", result.StartPoint)
+		assert.Equal(t, "main", result.Parent)
+		assert.Equal(t, 3, result.TrunkBehind)
+		assert.False(t, result.Stacked)
+		assert.Equal(t, "origin/main", repo.created["feature"])
+		assert.Equal(t, []string{"feature"}, repo.checkedOut)
+		assert.Equal(t, "main", repo.parents["feature"])
+		assert.Equal(t, "origin/main-sha", repo.parentSHAs["feature"])
+		assert.Equal(t, "origin/main-sha", result.ParentSHA)
+		assert.Zero(t, namer.staleCalls)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("fetches trunk into its remote-tracking ref", func(t *testing.T) {
+		repo := newMockWorkRepo("main")
+
+		_, err := NewWorkWorkflow(repo, &mockNamer{}).Execute(context.Background(), &WorkOptions{Name: "feature"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"origin", "+refs/heads/main:refs/remotes/origin/main"}, repo.fetched)
+	})
+
+	t.Run("falls back to local trunk without remote", func(t *testing.T) {
+		repo := newMockWorkRepo("main")
+		delete(repo.branches, "origin/main")
+
+		result, err := NewWorkWorkflow(repo, &mockNamer{}).Execute(context.Background(), &WorkOptions{Name: "feature"})
+		require.NoError(t, err)
+		assert.Equal(t, "main", result.StartPoint)
+		require.Len(t, result.Warnings, 1)
+		assert.Contains(t, result.Warnings[0], "origin/main not found after fetch")
+	})
+
+	t.Run("generates a name from the pattern", func(t *testing.T) {
+		repo := newMockWorkRepo("main")
+
+		result, err := NewWorkWorkflow(repo, &mockNamer{name: "feature/generated"}).Execute(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "feature/generated", result.Branch)
+		assert.Contains(t, repo.created, "feature/generated")
+	})
+
+	t.Run("stacks on the current branch", func(t *testing.T) {
+		repo := newMockWorkRepo("parent")
+		repo.branches["parent"] = true
+
+		result, err := NewWorkWorkflow(repo, &mockNamer{}).Execute(context.Background(), &WorkOptions{Name: "child", Stack: true})
+		require.NoError(t, err)
+		assert.True(t, result.Stacked)
+		assert.Equal(t, "parent", result.StartPoint)
+		assert.Equal(t, "parent", repo.created["child"])
+		assert.Equal(t, "parent", repo.parents["child"])
+		assert.Equal(t, "parent-sha", repo.parentSHAs["child"])
+	})
+
+	t.Run("refuses to stack on trunk", func(t *testing.T) {
+		_, err := NewWorkWorkflow(newMockWorkRepo("main"), &mockNamer{}).Execute(context.Background(), &WorkOptions{Name: "x", Stack: true})
+		assert.ErrorIs(t, err, ErrStackOnTrunk)
+	})
+
+	t.Run("refuses an existing branch", func(t *testing.T) {
+		repo := newMockWorkRepo("main")
+		repo.branches["feature"] = true
+
+		_, err := NewWorkWorkflow(repo, &mockNamer{}).Execute(context.Background(), &WorkOptions{Name: "feature"})
+		assert.ErrorIs(t, err, ErrBranchExists)
+		assert.Empty(t, repo.checkedOut)
+	})
+
+	t.Run("checks staleness when fetch fails", func(t *testing.T) {
+		repo := newMockWorkRepo("main")
+		repo.fetchErr = errors.New("offline")
+		namer := &mockNamer{}
+
+		result, err := NewWorkWorkflow(repo, namer).Execute(context.Background(), &WorkOptions{Name: "feature"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, namer.staleCalls)
+		assert.Len(t, result.Warnings, 1)
+	})
+
+	t.Run("declining a stale remote aborts", func(t *testing.T) {
+		repo := newMockWorkRepo("main")
+		repo.fetchErr = errors.New("offline")
+		declined := errors.New("declined")
+
+		_, err := NewWorkWorkflow(repo, &mockNamer{staleErr: declined}).Execute(context.Background(), &WorkOptions{Name: "feature"})
+		assert.ErrorIs(t, err, declined)
+		assert.Empty(t, repo.created)
+	})
+}